@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+var (
+	DiffAFlag = &cli.PathFlag{
+		Name:      "a",
+		Usage:     "path of the first state, or incremental snapshot.",
+		TakesFile: true,
+		Required:  true,
+	}
+	DiffBFlag = &cli.PathFlag{
+		Name:      "b",
+		Usage:     "path of the second state, or incremental snapshot.",
+		TakesFile: true,
+		Required:  true,
+	}
+)
+
+func Diff(ctx *cli.Context) error {
+	vmType, err := vmTypeFromFlag(ctx)
+	if err != nil {
+		return err
+	}
+	a, err := loadState(vmType, ctx.Path(DiffAFlag.Name))
+	if err != nil {
+		return fmt.Errorf("invalid state a: %w", err)
+	}
+	b, err := loadState(vmType, ctx.Path(DiffBFlag.Name))
+	if err != nil {
+		return fmt.Errorf("invalid state b: %w", err)
+	}
+	changes, err := diffStates(a, b)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	return nil
+}
+
+// diffStates describes the changes from state a to state b, one line per changed field, register or memory page.
+func diffStates(a, b mipsevm.VMState) ([]string, error) {
+	fieldsA, err := stateFields(a)
+	if err != nil {
+		return nil, err
+	}
+	fieldsB, err := stateFields(b)
+	if err != nil {
+		return nil, err
+	}
+	var changes []string
+
+	keys := make([]string, 0, len(fieldsA))
+	for k := range fieldsA {
+		keys = append(keys, k)
+	}
+	for k := range fieldsB {
+		if _, ok := fieldsA[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		va, vb := fieldsA[k], fieldsB[k]
+		if bytes.Equal(va, vb) {
+			continue
+		}
+		if k == "registers" {
+			regChanges, err := diffRegisters(va, vb)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, regChanges...)
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", k, orNone(va), orNone(vb)))
+	}
+
+	pagesA, err := statePages(a)
+	if err != nil {
+		return nil, err
+	}
+	pagesB, err := statePages(b)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, diffPages(pagesA, pagesB)...)
+	return changes, nil
+}
+
+// stateFields returns the JSON encoding of each field of the state, except for the memory.
+func stateFields(state mipsevm.VMState) (map[string]json.RawMessage, error) {
+	dat, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode state: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(dat, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode state fields: %w", err)
+	}
+	delete(fields, "memory")
+	return fields, nil
+}
+
+func diffRegisters(a, b json.RawMessage) ([]string, error) {
+	var regsA, regsB []uint64
+	if err := json.Unmarshal(a, &regsA); err != nil {
+		return nil, fmt.Errorf("failed to decode registers: %w", err)
+	}
+	if err := json.Unmarshal(b, &regsB); err != nil {
+		return nil, fmt.Errorf("failed to decode registers: %w", err)
+	}
+	if len(regsA) != len(regsB) {
+		return nil, fmt.Errorf("register count mismatch: %d <> %d", len(regsA), len(regsB))
+	}
+	var changes []string
+	for i := range regsA {
+		if regsA[i] != regsB[i] {
+			changes = append(changes, fmt.Sprintf("registers[%d]: %#x -> %#x", i, regsA[i], regsB[i]))
+		}
+	}
+	return changes, nil
+}
+
+// statePages returns the memory pages of the state by page index.
+func statePages(state mipsevm.VMState) (map[uint64]*mipsevm.Page, error) {
+	pages := make(map[uint64]*mipsevm.Page)
+	switch s := state.(type) {
+	case mipsevm.FPVMState:
+		_ = s.GetMemory().ForEachPage(func(pageIndex uint32, page *mipsevm.Page) error {
+			pages[uint64(pageIndex)] = page
+			return nil
+		})
+	case *mipsevm.MIPS64State:
+		_ = s.Memory.ForEachPage(func(pageIndex uint64, page *mipsevm.Page) error {
+			pages[pageIndex] = page
+			return nil
+		})
+	default:
+		return nil, fmt.Errorf("unsupported state type %T", state)
+	}
+	return pages, nil
+}
+
+func diffPages(a, b map[uint64]*mipsevm.Page) []string {
+	indices := make([]uint64, 0, len(a))
+	for k := range a {
+		indices = append(indices, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			indices = append(indices, k)
+		}
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
+	})
+	var changes []string
+	for _, index := range indices {
+		pa, okA := a[index]
+		pb, okB := b[index]
+		addr := index << mipsevm.PageAddrSize
+		switch {
+		case !okA:
+			changes = append(changes, fmt.Sprintf("page %#x: added", addr))
+		case !okB:
+			changes = append(changes, fmt.Sprintf("page %#x: removed", addr))
+		case *pa != *pb:
+			count := 0
+			for i := range pa {
+				if pa[i] != pb[i] {
+					count++
+				}
+			}
+			changes = append(changes, fmt.Sprintf("page %#x: %d bytes changed", addr, count))
+		}
+	}
+	return changes
+}
+
+func orNone(v json.RawMessage) string {
+	if v == nil {
+		return "<none>"
+	}
+	return string(v)
+}
+
+var DiffCommand = &cli.Command{
+	Name:        "diff",
+	Usage:       "Compare two Cannon states",
+	Description: "Compare two Cannon JSON or binary states, or incremental snapshots, and report the changed fields, registers and memory pages.",
+	Action:      Diff,
+	Flags: []cli.Flag{
+		DiffAFlag,
+		DiffBFlag,
+		VMTypeFlag,
+	},
+}
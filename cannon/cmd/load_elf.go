@@ -12,7 +12,7 @@ import (
 var (
 	LoadELFPathFlag = &cli.PathFlag{
 		Name:      "path",
-		Usage:     "Path to 32-bit big-endian MIPS ELF file",
+		Usage:     "Path to 32-bit big-endian MIPS ELF file, or 64-bit big-endian MIPS64 ELF file for the MIPS64 VM type",
 		TakesFile: true,
 		Required:  true,
 	}
@@ -24,7 +24,7 @@ var (
 	}
 	LoadELFOutFlag = &cli.PathFlag{
 		Name:     "out",
-		Usage:    "Output path to write JSON state to. State is dumped to stdout if set to -. Not written if empty.",
+		Usage:    "Output path to write state to, in binary format if the path ends in .bin or .bin.gz, JSON otherwise. State is dumped to stdout as JSON if set to -. Not written if empty.",
 		Value:    "state.json",
 		Required: false,
 	}
@@ -45,16 +45,28 @@ func LoadELF(ctx *cli.Context) error {
 	if elfProgram.Machine != elf.EM_MIPS {
 		return fmt.Errorf("ELF is not big-endian MIPS R3000, but got %q", elfProgram.Machine.String())
 	}
-	state, err := mipsevm.LoadELF(elfProgram)
+	vmType, err := vmTypeFromFlag(ctx)
+	if err != nil {
+		return err
+	}
+	var state mipsevm.VMState
+	switch vmType {
+	case mtVMType:
+		state, err = mipsevm.LoadELF(elfProgram, mipsevm.NewMTState)
+	case mips64VMType:
+		state, err = mipsevm.LoadELF64(elfProgram)
+	default:
+		state, err = mipsevm.LoadELF(elfProgram, mipsevm.CreateInitialState)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to load ELF data into VM state: %w", err)
 	}
 	for _, typ := range ctx.StringSlice(LoadELFPatchFlag.Name) {
 		switch typ {
 		case "stack":
-			err = mipsevm.PatchStack(state)
+			err = patchStack(state)
 		case "go":
-			err = mipsevm.PatchGo(elfProgram, state)
+			err = patchGo(elfProgram, state)
 		default:
 			return fmt.Errorf("unrecognized form of patching: %q", typ)
 		}
@@ -69,18 +81,41 @@ func LoadELF(ctx *cli.Context) error {
 	if err := writeJSON[*mipsevm.Metadata](ctx.Path(LoadELFMetaFlag.Name), meta); err != nil {
 		return fmt.Errorf("failed to output metadata: %w", err)
 	}
-	return writeJSON[*mipsevm.State](ctx.Path(LoadELFOutFlag.Name), state)
+	return writeFile[mipsevm.VMState](ctx.Path(LoadELFOutFlag.Name), state)
+}
+
+func patchStack(state mipsevm.VMState) error {
+	switch s := state.(type) {
+	case mipsevm.FPVMState:
+		return mipsevm.PatchStack(s)
+	case *mipsevm.MIPS64State:
+		return mipsevm.PatchStack64(s)
+	default:
+		return fmt.Errorf("unsupported state type %T", state)
+	}
+}
+
+func patchGo(elfProgram *elf.File, state mipsevm.VMState) error {
+	switch s := state.(type) {
+	case mipsevm.FPVMState:
+		return mipsevm.PatchGo(elfProgram, s)
+	case *mipsevm.MIPS64State:
+		return mipsevm.PatchGo64(elfProgram, s)
+	default:
+		return fmt.Errorf("unsupported state type %T", state)
+	}
 }
 
 var LoadELFCommand = &cli.Command{
 	Name:        "load-elf",
-	Usage:       "Load ELF file into Cannon JSON state",
-	Description: "Load ELF file into Cannon JSON state, optionally patch out functions",
+	Usage:       "Load ELF file into Cannon state",
+	Description: "Load ELF file into Cannon JSON or binary state, optionally patch out functions",
 	Action:      LoadELF,
 	Flags: []cli.Flag{
 		LoadELFPathFlag,
 		LoadELFPatchFlag,
 		LoadELFOutFlag,
 		LoadELFMetaFlag,
+		VMTypeFlag,
 	},
 }
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+)
+
+// syscallNames names the syscalls of the o32 (MIPS) and n64 (MIPS64) ABIs that the Go runtime makes,
+// whether the VM supports them or not.
+var syscallNames = map[uint64]string{
+	4001: "exit",
+	4003: "read",
+	4004: "write",
+	4005: "open",
+	4006: "close",
+	4045: "brk",
+	4055: "fcntl",
+	4090: "mmap",
+	4120: "clone",
+	4162: "sched_yield",
+	4166: "nanosleep",
+	4194: "rt_sigaction",
+	4195: "rt_sigprocmask",
+	4206: "sigaltstack",
+	4218: "madvise",
+	4222: "gettid",
+	4238: "futex",
+	4240: "sched_getaffinity",
+	4246: "exit_group",
+	4263: "clock_gettime",
+	5000: "read",
+	5001: "write",
+	5002: "open",
+	5003: "close",
+	5009: "mmap",
+	5012: "brk",
+	5013: "rt_sigaction",
+	5014: "rt_sigprocmask",
+	5023: "sched_yield",
+	5027: "madvise",
+	5034: "nanosleep",
+	5055: "clone",
+	5058: "exit",
+	5070: "fcntl",
+	5129: "sigaltstack",
+	5178: "gettid",
+	5194: "futex",
+	5196: "sched_getaffinity",
+	5205: "exit_group",
+	5222: "clock_gettime",
+}
+
+func syscallName(num uint64) string {
+	if name, ok := syscallNames[num]; ok {
+		return name
+	}
+	return fmt.Sprintf("sys_%d", num)
+}
+
+type syscallSite struct {
+	pc  uint64
+	num uint64
+}
+
+type memorySample struct {
+	Step  uint64 `json:"step"`
+	Pages int    `json:"pages"`
+}
+
+// guestProfileStats summarizes a guest profile: the syscall histogram, and the memory growth over time.
+type guestProfileStats struct {
+	Steps    uint64            `json:"steps"`
+	Syscalls map[string]uint64 `json:"syscalls"`
+	Memory   []memorySample    `json:"memory"`
+}
+
+// guestProfiler profiles the program running in the VM, rather than the VM itself:
+// it counts the steps per instruction address, the syscalls made, and records the growth of the memory.
+// Addresses are only resolved to function names when the profile is written, to keep recording cheap.
+type guestProfiler struct {
+	meta *mipsevm.Metadata
+
+	steps     map[uint64]uint64
+	syscalls  map[syscallSite]uint64
+	total     uint64
+	memory    []memorySample
+	lastPages int
+}
+
+func newGuestProfiler(meta *mipsevm.Metadata) *guestProfiler {
+	return &guestProfiler{
+		meta:     meta,
+		steps:    make(map[uint64]uint64),
+		syscalls: make(map[syscallSite]uint64),
+	}
+}
+
+// record accounts for the instruction at the given pc, which the state is about to execute.
+func (p *guestProfiler) record(state mipsevm.VMState, pc uint64) {
+	insn, v0, pages := stepInfo(state)
+	p.steps[pc]++
+	p.total++
+	if opcode, fun := insn>>26, insn&0x3f; opcode == 0 && fun == 0xC {
+		p.syscalls[syscallSite{pc: pc, num: v0}]++
+	}
+	if pages > p.lastPages {
+		p.memory = append(p.memory, memorySample{Step: state.GetStep(), Pages: pages})
+		p.lastPages = pages
+	}
+}
+
+func (p *guestProfiler) stats() *guestProfileStats {
+	out := &guestProfileStats{
+		Steps:    p.total,
+		Syscalls: make(map[string]uint64),
+		Memory:   p.memory,
+	}
+	for site, count := range p.syscalls {
+		out.Syscalls[syscallName(site.num)] += count
+	}
+	return out
+}
+
+// profile builds a pprof profile of the guest program, with a flat profile of the steps per function,
+// and the syscalls per call site, labeled with the syscall name.
+func (p *guestProfiler) profile() *profile.Profile {
+	out := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "steps", Unit: "count"},
+			{Type: "syscalls", Unit: "count"},
+		},
+		DefaultSampleType: "steps",
+		PeriodType:        &profile.ValueType{Type: "steps", Unit: "count"},
+		Period:            1,
+	}
+	functions := make(map[string]*profile.Function)
+	locations := make(map[uint64]*profile.Location)
+	location := func(pc uint64) *profile.Location {
+		if loc, ok := locations[pc]; ok {
+			return loc
+		}
+		name := p.meta.LookupSymbol(pc)
+		fn, ok := functions[name]
+		if !ok {
+			fn = &profile.Function{ID: uint64(len(out.Function) + 1), Name: name, SystemName: name}
+			functions[name] = fn
+			out.Function = append(out.Function, fn)
+		}
+		loc := &profile.Location{ID: uint64(len(out.Location) + 1), Address: pc, Line: []profile.Line{{Function: fn}}}
+		locations[pc] = loc
+		out.Location = append(out.Location, loc)
+		return loc
+	}
+
+	pcs := make([]uint64, 0, len(p.steps))
+	for pc := range p.steps {
+		pcs = append(pcs, pc)
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	for _, pc := range pcs {
+		out.Sample = append(out.Sample, &profile.Sample{
+			Location: []*profile.Location{location(pc)},
+			Value:    []int64{int64(p.steps[pc]), 0},
+		})
+	}
+
+	sites := make([]syscallSite, 0, len(p.syscalls))
+	for site := range p.syscalls {
+		sites = append(sites, site)
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].pc != sites[j].pc {
+			return sites[i].pc < sites[j].pc
+		}
+		return sites[i].num < sites[j].num
+	})
+	for _, site := range sites {
+		out.Sample = append(out.Sample, &profile.Sample{
+			Location: []*profile.Location{location(site.pc)},
+			Value:    []int64{0, int64(p.syscalls[site])},
+			Label:    map[string][]string{"syscall": {syscallName(site.num)}},
+		})
+	}
+	return out
+}
+
+// writeProfile writes the pprof profile to the given path, gzipped if the path ends in .gz.
+func (p *guestProfiler) writeProfile(path string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := ioutil.NewAtomicWriterCompressed(path, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open profile output file: %w", err)
+	}
+	// Ensure we close the stream even if failures occur.
+	defer f.Close()
+	if err := p.profile().WriteUncompressed(f); err != nil {
+		return fmt.Errorf("failed to encode profile: %w", err)
+	}
+	// Closing the file causes it to be renamed to the final destination
+	// so make sure we handle any errors it returns
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to finish profile write: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"debug/elf"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+func TestGuestProfiler(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/hello.elf")
+	require.NoError(t, err)
+	state, err := mipsevm.LoadELF(elfProgram, mipsevm.CreateInitialState)
+	require.NoError(t, err)
+	require.NoError(t, mipsevm.PatchGo(elfProgram, state))
+	require.NoError(t, mipsevm.PatchStack(state))
+	meta, err := mipsevm.MakeMetadata(elfProgram)
+	require.NoError(t, err)
+	us := mipsevm.NewInstrumentedState(state, nil, io.Discard, io.Discard)
+
+	profiler := newGuestProfiler(meta)
+	for !state.Exited {
+		profiler.record(state, statePC(state))
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+
+	stats := profiler.stats()
+	require.Equal(t, state.Step, stats.Steps)
+	require.Equal(t, uint64(1), stats.Syscalls["exit_group"])
+	require.NotZero(t, stats.Syscalls["write"], "hello world is written")
+	require.NotZero(t, stats.Syscalls["mmap"])
+	require.NotEmpty(t, stats.Memory)
+	for i := 1; i < len(stats.Memory); i++ {
+		require.Greater(t, stats.Memory[i].Pages, stats.Memory[i-1].Pages)
+		require.GreaterOrEqual(t, stats.Memory[i].Step, stats.Memory[i-1].Step)
+	}
+
+	path := filepath.Join(t.TempDir(), "guest.pb.gz")
+	require.NoError(t, profiler.writeProfile(path))
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	prof, err := profile.Parse(f)
+	require.NoError(t, err)
+	require.NoError(t, prof.CheckValid())
+
+	stepsPerFunc := make(map[string]int64)
+	var totalSteps, totalSyscalls int64
+	for _, sample := range prof.Sample {
+		stepsPerFunc[sample.Location[0].Line[0].Function.Name] += sample.Value[0]
+		totalSteps += sample.Value[0]
+		totalSyscalls += sample.Value[1]
+		if sample.Value[1] > 0 {
+			require.Len(t, sample.Label["syscall"], 1)
+		}
+	}
+	require.Equal(t, int64(state.Step), totalSteps)
+	require.NotZero(t, totalSyscalls)
+	require.NotZero(t, stepsPerFunc["main.main"])
+	require.NotZero(t, stepsPerFunc["runtime.mallocgc"])
+}
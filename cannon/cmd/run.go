@@ -7,8 +7,6 @@ import (
 	"os/exec"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
 
@@ -21,14 +19,14 @@ import (
 var (
 	RunInputFlag = &cli.PathFlag{
 		Name:      "input",
-		Usage:     "path of input JSON state. Stdin if left empty.",
+		Usage:     "path of input state, or incremental snapshot. Binary if the path ends in .bin or .bin.gz, JSON otherwise.",
 		TakesFile: true,
 		Value:     "state.json",
 		Required:  true,
 	}
 	RunOutputFlag = &cli.PathFlag{
 		Name:      "output",
-		Usage:     "path of output JSON state. Not written if empty, use - to write to Stdout.",
+		Usage:     "path of output state, in binary format if the path ends in .bin or .bin.gz, JSON otherwise. Not written if empty, use - to write JSON to Stdout.",
 		TakesFile: true,
 		Value:     "out.json",
 		Required:  false,
@@ -42,7 +40,7 @@ var (
 	}
 	RunProofFmtFlag = &cli.StringFlag{
 		Name:     "proof-fmt",
-		Usage:    "format for proof data output file names. Proof data is written to stdout if -.",
+		Usage:    "format for proof data output file names, in binary format if the extension is .bin or .bin.gz, JSON otherwise. Proof data is written to stdout as JSON if -.",
 		Value:    "proof-%d.json",
 		Required: false,
 	}
@@ -54,10 +52,14 @@ var (
 	}
 	RunSnapshotFmtFlag = &cli.StringFlag{
 		Name:     "snapshot-fmt",
-		Usage:    "format for snapshot output file names.",
+		Usage:    "format for snapshot output file names, in binary format if the extension is .bin or .bin.gz, JSON otherwise.",
 		Value:    "state-%d.json",
 		Required: false,
 	}
+	RunSnapshotDeltaFlag = &cli.BoolFlag{
+		Name:  "snapshot-delta",
+		Usage: "write incremental snapshots, with only the memory pages modified since the previous snapshot. The first snapshot is a full snapshot.",
+	}
 	RunStopAtFlag = &cli.GenericFlag{
 		Name:     "stop-at",
 		Usage:    "step pattern to stop at: " + patternHelp,
@@ -80,22 +82,20 @@ var (
 		Name:  "pprof.cpu",
 		Usage: "enable pprof cpu profiling",
 	}
+	RunGuestProfileFlag = &cli.PathFlag{
+		Name:      "guest-profile",
+		Usage:     "path to write a pprof profile of the program running in the VM to, with the steps per function (resolved with --meta), and syscalls per call site. Gzipped if the path ends in .gz. Not written if empty.",
+		TakesFile: true,
+		Required:  false,
+	}
+	RunGuestProfileStatsFlag = &cli.PathFlag{
+		Name:      "guest-profile-stats",
+		Usage:     "path to write a JSON summary of the program running in the VM to, with the syscall histogram, and the memory growth over time. Not written if empty.",
+		TakesFile: true,
+		Required:  false,
+	}
 )
 
-type Proof struct {
-	Step uint64 `json:"step"`
-
-	Pre  common.Hash `json:"pre"`
-	Post common.Hash `json:"post"`
-
-	StateData hexutil.Bytes `json:"state-data"`
-	ProofData hexutil.Bytes `json:"proof-data"`
-
-	OracleKey    hexutil.Bytes `json:"oracle-key,omitempty"`
-	OracleValue  hexutil.Bytes `json:"oracle-value,omitempty"`
-	OracleOffset uint32        `json:"oracle-offset,omitempty"`
-}
-
 type rawHint string
 
 func (rh rawHint) Hint() string {
@@ -224,7 +224,11 @@ func Run(ctx *cli.Context) error {
 		defer profile.Start(profile.NoShutdownHook, profile.ProfilePath("."), profile.CPUProfile).Stop()
 	}
 
-	state, err := loadJSON[mipsevm.State](ctx.Path(RunInputFlag.Name))
+	vmType, err := vmTypeFromFlag(ctx)
+	if err != nil {
+		return err
+	}
+	state, err := loadState(vmType, ctx.Path(RunInputFlag.Name))
 	if err != nil {
 		return err
 	}
@@ -275,9 +279,29 @@ func Run(ctx *cli.Context) error {
 		}
 	}
 
-	us := mipsevm.NewInstrumentedState(state, po, outLog, errLog)
+	us, err := instrument(state, po, outLog, errLog)
+	if err != nil {
+		return err
+	}
 	proofFmt := ctx.String(RunProofFmtFlag.Name)
 	snapshotFmt := ctx.String(RunSnapshotFmtFlag.Name)
+	snapshots := &snapshotWriter{delta: ctx.Bool(RunSnapshotDeltaFlag.Name)}
+
+	var profiler *guestProfiler
+	profilePath := ctx.Path(RunGuestProfileFlag.Name)
+	profileStatsPath := ctx.Path(RunGuestProfileStatsFlag.Name)
+	if profilePath != "" || profileStatsPath != "" {
+		profiler = newGuestProfiler(meta)
+		// write the profiles on any exit, a profile of an interrupted or failed run is still useful
+		defer func() {
+			if err := profiler.writeProfile(profilePath); err != nil {
+				l.Error("failed to write guest profile", "err", err)
+			}
+			if err := writeJSON(profileStatsPath, profiler.stats()); err != nil {
+				l.Error("failed to write guest profile stats", "err", err)
+			}
+		}()
+	}
 
 	stepFn := us.Step
 	if po.cmd != nil {
@@ -285,34 +309,40 @@ func Run(ctx *cli.Context) error {
 	}
 
 	start := time.Now()
-	startStep := state.Step
+	startStep := state.GetStep()
 
 	// avoid symbol lookups every instruction by preparing a matcher func
 	sleepCheck := meta.SymbolMatcher("runtime.notesleep")
+	if vmType == mtVMType {
+		// threads of the multi-threaded VM legitimately sleep, until another thread wakes them up
+		sleepCheck = func(addr uint64) bool { return false }
+	}
 
-	for !state.Exited {
-		if state.Step%100 == 0 { // don't do the ctx err check (includes lock) too often
+	for !state.GetExited() {
+		if state.GetStep()%100 == 0 { // don't do the ctx err check (includes lock) too often
 			if err := ctx.Context.Err(); err != nil {
 				return err
 			}
 		}
 
-		step := state.Step
+		step := state.GetStep()
 
+		pc := statePC(state)
 		if infoAt(state) {
+			insn, pages, usage := debugInfo(state)
 			delta := time.Since(start)
 			l.Info("processing",
 				"step", step,
-				"pc", mipsevm.HexU32(state.PC),
-				"insn", mipsevm.HexU32(state.Memory.GetMemory(state.PC)),
+				"pc", mipsevm.HexU64(pc),
+				"insn", mipsevm.HexU32(insn),
 				"ips", float64(step-startStep)/(float64(delta)/float64(time.Second)),
-				"pages", state.Memory.PageCount(),
-				"mem", state.Memory.Usage(),
-				"name", meta.LookupSymbol(state.PC),
+				"pages", pages,
+				"mem", usage,
+				"name", meta.LookupSymbol(pc),
 			)
 		}
 
-		if sleepCheck(state.PC) { // don't loop forever when we get stuck because of an unexpected bad program
+		if sleepCheck(pc) { // don't loop forever when we get stuck because of an unexpected bad program
 			return fmt.Errorf("got stuck in Go sleep at step %d", step)
 		}
 
@@ -320,8 +350,12 @@ func Run(ctx *cli.Context) error {
 			break
 		}
 
+		if profiler != nil {
+			profiler.record(state, pc)
+		}
+
 		if snapshotAt(state) {
-			if err := writeJSON(fmt.Sprintf(snapshotFmt, step), state); err != nil {
+			if err := snapshots.write(fmt.Sprintf(snapshotFmt, step), state); err != nil {
 				return fmt.Errorf("failed to write state snapshot: %w", err)
 			}
 		}
@@ -333,36 +367,36 @@ func Run(ctx *cli.Context) error {
 			}
 			witness, err := stepFn(true)
 			if err != nil {
-				return fmt.Errorf("failed at proof-gen step %d (PC: %08x): %w", step, state.PC, err)
+				return fmt.Errorf("failed at proof-gen step %d (PC: %08x): %w", step, pc, err)
 			}
 			postStateHash, err := state.EncodeWitness().StateHash()
 			if err != nil {
 				return fmt.Errorf("failed to hash poststate witness: %w", err)
 			}
-			proof := &Proof{
+			proof := &mipsevm.Proof{
 				Step:      step,
 				Pre:       preStateHash,
 				Post:      postStateHash,
 				StateData: witness.State,
-				ProofData: witness.MemProof,
+				ProofData: witness.ProofData,
 			}
 			if witness.HasPreimage() {
 				proof.OracleKey = witness.PreimageKey[:]
 				proof.OracleValue = witness.PreimageValue
 				proof.OracleOffset = witness.PreimageOffset
 			}
-			if err := writeJSON(fmt.Sprintf(proofFmt, step), proof); err != nil {
+			if err := writeFile(fmt.Sprintf(proofFmt, step), proof); err != nil {
 				return fmt.Errorf("failed to write proof data: %w", err)
 			}
 		} else {
 			_, err = stepFn(false)
 			if err != nil {
-				return fmt.Errorf("failed at step %d (PC: %08x): %w", step, state.PC, err)
+				return fmt.Errorf("failed at step %d (PC: %08x): %w", step, pc, err)
 			}
 		}
 	}
 
-	if err := writeJSON(ctx.Path(RunOutputFlag.Name), state); err != nil {
+	if err := writeFile(ctx.Path(RunOutputFlag.Name), state); err != nil {
 		return fmt.Errorf("failed to write state output: %w", err)
 	}
 	return nil
@@ -380,9 +414,13 @@ var RunCommand = &cli.Command{
 		RunProofFmtFlag,
 		RunSnapshotAtFlag,
 		RunSnapshotFmtFlag,
+		RunSnapshotDeltaFlag,
 		RunStopAtFlag,
 		RunMetaFlag,
 		RunInfoAtFlag,
 		RunPProfCPU,
+		RunGuestProfileFlag,
+		RunGuestProfileStatsFlag,
+		VMTypeFlag,
 	},
 }
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+// loadFile loads the value from the given path, in the binary format if the path has a binary file extension,
+// see serialize.IsBinaryFile, or as JSON otherwise.
+func loadFile[X any](inputPath string) (*X, error) {
+	if serialize.IsBinaryFile(inputPath) {
+		return serialize.LoadSerializedBinary[X](inputPath)
+	}
+	return loadJSON[X](inputPath)
+}
+
+// writeFile writes the value to the given path, in the binary format if the path has a binary file extension,
+// or as JSON otherwise.
+func writeFile[X any](outputPath string, value X) error {
+	if serialize.IsBinaryFile(outputPath) {
+		s, ok := any(value).(serialize.Serializable)
+		if !ok {
+			return fmt.Errorf("%T does not support the binary format", value)
+		}
+		return serialize.WriteSerializedBinary(outputPath, s, 0755)
+	}
+	return writeJSON(outputPath, value)
+}
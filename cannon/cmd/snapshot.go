@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+)
+
+// snapshotDelta is an incremental snapshot: the full VM state, except for the memory,
+// which only contains the pages that were modified since the base snapshot.
+type snapshotDelta[T any] struct {
+	// Base is the path of the snapshot this delta applies to, relative to the directory of the delta.
+	// It must be the first field: JSON deltas are recognized by their encoding starting with it.
+	Base  string `json:"base"`
+	State *T     `json:"state"`
+}
+
+var snapshotDeltaPrefix = []byte(`{"base":`)
+
+// snapshotDeltaBinaryVersion is the first byte of binary deltas.
+// It differs from the versions of the binary state encodings, to recognize deltas.
+const snapshotDeltaBinaryVersion uint8 = 0xff
+
+// Serialize writes the delta in the binary format: the version byte, the length-prefixed base path,
+// followed by the binary encoding of the state.
+func (d *snapshotDelta[T]) Serialize(out io.Writer) error {
+	state, ok := any(d.State).(serialize.Serializable)
+	if !ok {
+		return fmt.Errorf("%T does not support the binary format", d.State)
+	}
+	w := serialize.NewBinaryWriter(out)
+	if err := w.WriteFixed(snapshotDeltaBinaryVersion); err != nil {
+		return err
+	}
+	if err := w.WriteBytes([]byte(d.Base)); err != nil {
+		return err
+	}
+	return state.Serialize(out)
+}
+
+func (d *snapshotDelta[T]) Deserialize(in io.Reader) error {
+	d.State = new(T)
+	state, ok := any(d.State).(serialize.Serializable)
+	if !ok {
+		return fmt.Errorf("%T does not support the binary format", d.State)
+	}
+	r := serialize.NewBinaryReader(in)
+	var version uint8
+	if err := r.ReadFixed(&version); err != nil {
+		return err
+	}
+	if version != snapshotDeltaBinaryVersion {
+		return fmt.Errorf("not a snapshot delta, version %d", version)
+	}
+	var base []byte
+	if err := r.ReadBytes(&base); err != nil {
+		return err
+	}
+	d.Base = string(base)
+	return state.Deserialize(in)
+}
+
+// isSnapshotDelta checks if the file at the given path is an incremental snapshot, rather than a full state.
+func isSnapshotDelta(path string) (bool, error) {
+	f, err := ioutil.OpenDecompressed(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open file %q: %w", path, err)
+	}
+	defer f.Close()
+	if serialize.IsBinaryFile(path) {
+		version, _ := bufio.NewReader(f).Peek(1)
+		return bytes.Equal(version, []byte{snapshotDeltaBinaryVersion}), nil
+	}
+	prefix, _ := bufio.NewReader(f).Peek(len(snapshotDeltaPrefix))
+	return bytes.Equal(prefix, snapshotDeltaPrefix), nil
+}
+
+// loadSnapshot loads a full state, or an incremental snapshot by applying it to its chain of base snapshots.
+func loadSnapshot[T any](path string) (*T, error) {
+	if path == "" {
+		return loadFile[T](path)
+	}
+	isDelta, err := isSnapshotDelta(path)
+	if err != nil {
+		return nil, err
+	}
+	if !isDelta {
+		return loadFile[T](path)
+	}
+	delta, err := loadFile[snapshotDelta[T]](path)
+	if err != nil {
+		return nil, err
+	}
+	if delta.State == nil {
+		return nil, fmt.Errorf("snapshot delta %q has no state", path)
+	}
+	basePath := delta.Base
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(path), basePath)
+	}
+	base, err := loadSnapshot[T](basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base of snapshot delta %q: %w", path, err)
+	}
+	if err := applySnapshotDelta(any(base), any(delta.State)); err != nil {
+		return nil, fmt.Errorf("failed to apply snapshot delta %q: %w", path, err)
+	}
+	return delta.State, nil
+}
+
+// applySnapshotDelta completes the memory of the delta state with the unmodified pages of the base state.
+func applySnapshotDelta(base any, delta any) error {
+	switch d := delta.(type) {
+	case mipsevm.FPVMState:
+		b, ok := base.(mipsevm.FPVMState)
+		if !ok {
+			return fmt.Errorf("base state type %T does not match delta state type %T", base, delta)
+		}
+		b.GetMemory().ApplyPages(d.GetMemory())
+		return setMemory(d, b.GetMemory())
+	case *mipsevm.MIPS64State:
+		b, ok := base.(*mipsevm.MIPS64State)
+		if !ok {
+			return fmt.Errorf("base state type %T does not match delta state type %T", base, delta)
+		}
+		b.Memory.ApplyPages(d.Memory)
+		d.Memory = b.Memory
+		return nil
+	default:
+		return fmt.Errorf("unsupported state type %T", delta)
+	}
+}
+
+func setMemory(state mipsevm.FPVMState, mem *mipsevm.Memory) error {
+	switch s := state.(type) {
+	case *mipsevm.State:
+		s.Memory = mem
+	case *mipsevm.MTState:
+		s.Memory = mem
+	default:
+		return fmt.Errorf("unsupported state type %T", state)
+	}
+	return nil
+}
+
+// snapshotWriter writes the snapshots of a run, optionally as incremental snapshots.
+type snapshotWriter struct {
+	delta bool
+	// path of the previous snapshot, the base of the next incremental snapshot
+	prev string
+}
+
+// write writes the snapshot of the state to the given path.
+// The first snapshot is always a full snapshot, after which incremental snapshots are written, if enabled.
+func (w *snapshotWriter) write(path string, state mipsevm.VMState) error {
+	var err error
+	if !w.delta || w.prev == "" {
+		err = writeFile(path, state)
+	} else {
+		err = w.writeDelta(path, state)
+	}
+	if err != nil {
+		return err
+	}
+	w.prev = path
+	// track the modifications from this snapshot on
+	switch s := state.(type) {
+	case mipsevm.FPVMState:
+		s.GetMemory().ResetDirty()
+	case *mipsevm.MIPS64State:
+		s.Memory.ResetDirty()
+	}
+	return nil
+}
+
+func (w *snapshotWriter) writeDelta(path string, state mipsevm.VMState) error {
+	base, err := filepath.Rel(filepath.Dir(path), w.prev)
+	if err != nil {
+		base, err = filepath.Abs(w.prev)
+		if err != nil {
+			return fmt.Errorf("failed to determine path of base snapshot: %w", err)
+		}
+	}
+	switch s := state.(type) {
+	case *mipsevm.State:
+		d := *s
+		d.Memory = s.Memory.Subset(s.Memory.DirtyPages())
+		return writeFile(path, &snapshotDelta[mipsevm.State]{Base: base, State: &d})
+	case *mipsevm.MTState:
+		d := *s
+		d.Memory = s.Memory.Subset(s.Memory.DirtyPages())
+		return writeFile(path, &snapshotDelta[mipsevm.MTState]{Base: base, State: &d})
+	case *mipsevm.MIPS64State:
+		d := *s
+		d.Memory = s.Memory.Subset(s.Memory.DirtyPages())
+		return writeFile(path, &snapshotDelta[mipsevm.MIPS64State]{Base: base, State: &d})
+	default:
+		return fmt.Errorf("unsupported state type %T", state)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"debug/elf"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+func TestIncrementalSnapshots(t *testing.T) {
+	for _, ext := range []string{".json", ".json.gz", ".bin", ".bin.gz"} {
+		ext := ext
+		t.Run(ext, func(t *testing.T) {
+			elfProgram, err := elf.Open("../example/bin/hello.elf")
+			require.NoError(t, err)
+			state, err := mipsevm.LoadELF(elfProgram, mipsevm.CreateInitialState)
+			require.NoError(t, err)
+			require.NoError(t, mipsevm.PatchGo(elfProgram, state))
+			require.NoError(t, mipsevm.PatchStack(state))
+			us := mipsevm.NewInstrumentedState(state, nil, io.Discard, io.Discard)
+
+			dir := t.TempDir()
+			snapshots := &snapshotWriter{delta: true}
+			var paths []string
+			for i := 0; i < 4; i++ {
+				path := filepath.Join(dir, fmt.Sprintf("%d%s", state.Step, ext))
+				require.NoError(t, snapshots.write(path, state))
+				paths = append(paths, path)
+				for j := 0; j < 10_000; j++ {
+					_, err := us.Step(false)
+					require.NoError(t, err)
+				}
+			}
+
+			isDelta, err := isSnapshotDelta(paths[0])
+			require.NoError(t, err)
+			require.False(t, isDelta, "first snapshot is a full snapshot")
+
+			last := paths[len(paths)-1]
+			isDelta, err = isSnapshotDelta(last)
+			require.NoError(t, err)
+			require.True(t, isDelta, "later snapshots are incremental")
+
+			loaded, err := loadState(cannonVMType, last)
+			require.NoError(t, err)
+			require.Equal(t, uint64(30_000), loaded.GetStep())
+
+			// continue from the loaded snapshot, to compare it with the live state
+			loadedUs, err := instrument(loaded, nil, io.Discard, io.Discard)
+			require.NoError(t, err)
+			for j := 0; j < 10_000; j++ {
+				_, err := loadedUs.Step(false)
+				require.NoError(t, err)
+			}
+			require.Equal(t, state.EncodeWitness(), loaded.EncodeWitness(), "snapshot must restore the full state")
+
+			changes, err := diffStates(state, loaded)
+			require.NoError(t, err)
+			require.Empty(t, changes)
+		})
+	}
+}
+
+func TestDiffStates(t *testing.T) {
+	a := mipsevm.CreateInitialState(0x1000, 0x2000)
+	a.Memory.SetMemory(0x4000, 1)
+	a.Memory.SetMemory(0x8000, 1)
+	b := mipsevm.CreateInitialState(0x1004, 0x2000)
+	b.Registers[3] = 0x42
+	b.Memory.SetMemory(0x4000, 0xff)
+	b.Memory.SetMemory(0xc000, 1)
+
+	changes, err := diffStates(a, b)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"nextPC: 4100 -> 4104",
+		"pc: 4096 -> 4100",
+		"registers[3]: 0x0 -> 0x42",
+		"page 0x4000: 1 bytes changed",
+		"page 0x8000: removed",
+		"page 0xc000: added",
+	}, changes)
+}
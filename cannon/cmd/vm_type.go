@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+)
+
+type VMType string
+
+const (
+	cannonVMType VMType = "cannon"
+	mtVMType     VMType = "cannon-mt"
+	mips64VMType VMType = "cannon-mips64"
+)
+
+var VMTypeFlag = &cli.StringFlag{
+	Name:     "type",
+	Usage:    fmt.Sprintf("VM type of the state. Options are '%s' (single-threaded, default), '%s' (multi-threaded), '%s' (single-threaded MIPS64)", cannonVMType, mtVMType, mips64VMType),
+	Value:    string(cannonVMType),
+	Required: false,
+}
+
+func vmTypeFromFlag(ctx *cli.Context) (VMType, error) {
+	switch typ := VMType(ctx.String(VMTypeFlag.Name)); typ {
+	case cannonVMType, mtVMType, mips64VMType:
+		return typ, nil
+	default:
+		return "", fmt.Errorf("unknown VM type %q", typ)
+	}
+}
+
+// loadState loads the JSON or binary state of the given VM type, either a full state or an incremental snapshot.
+func loadState(vmType VMType, path string) (mipsevm.VMState, error) {
+	switch vmType {
+	case mtVMType:
+		return loadSnapshot[mipsevm.MTState](path)
+	case mips64VMType:
+		return loadSnapshot[mipsevm.MIPS64State](path)
+	default:
+		return loadSnapshot[mipsevm.State](path)
+	}
+}
+
+// instrument creates the instrumented VM to step through the given state.
+func instrument(state mipsevm.VMState, po mipsevm.PreimageOracle, stdOut, stdErr io.Writer) (mipsevm.FPVM, error) {
+	switch s := state.(type) {
+	case *mipsevm.State:
+		return mipsevm.NewInstrumentedState(s, po, stdOut, stdErr), nil
+	case *mipsevm.MTState:
+		return mipsevm.NewMTInstrumentedState(s, po, stdOut, stdErr), nil
+	case *mipsevm.MIPS64State:
+		return mipsevm.NewMIPS64InstrumentedState(s, po, stdOut, stdErr), nil
+	default:
+		return nil, fmt.Errorf("unsupported state type %T", state)
+	}
+}
+
+// statePC returns the program counter of the given state.
+func statePC(state mipsevm.VMState) uint64 {
+	switch s := state.(type) {
+	case mipsevm.FPVMState:
+		return uint64(s.GetPC())
+	case *mipsevm.MIPS64State:
+		return s.PC
+	default:
+		return 0
+	}
+}
+
+// stepInfo returns the instruction at the program counter, the syscall number register (v0),
+// and the number of allocated memory pages of the given state.
+func stepInfo(state mipsevm.VMState) (insn uint32, v0 uint64, pages int) {
+	switch s := state.(type) {
+	case mipsevm.FPVMState:
+		mem := s.GetMemory()
+		return mem.GetMemory(s.GetPC()), uint64(s.GetRegisters()[2]), mem.PageCount()
+	case *mipsevm.MIPS64State:
+		word := s.Memory.GetMemory(s.PC &^ 7)
+		return uint32(word >> (32 - (s.PC&4)*8)), s.Registers[2], s.Memory.PageCount()
+	default:
+		return 0, 0, 0
+	}
+}
+
+// debugInfo returns the instruction at the program counter, and the memory usage of the given state.
+func debugInfo(state mipsevm.VMState) (insn uint32, pages int, usage string) {
+	switch s := state.(type) {
+	case mipsevm.FPVMState:
+		mem := s.GetMemory()
+		return mem.GetMemory(s.GetPC()), mem.PageCount(), mem.Usage()
+	case *mipsevm.MIPS64State:
+		word := s.Memory.GetMemory(s.PC &^ 7)
+		return uint32(word >> (32 - (s.PC&4)*8)), s.Memory.PageCount(), s.Memory.Usage()
+	default:
+		return 0, 0, ""
+	}
+}
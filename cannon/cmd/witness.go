@@ -4,14 +4,13 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
 	"github.com/urfave/cli/v2"
 )
 
 var (
 	WitnessInputFlag = &cli.PathFlag{
 		Name:      "input",
-		Usage:     "path of input JSON state.",
+		Usage:     "path of input state, binary if the path ends in .bin or .bin.gz, JSON otherwise.",
 		TakesFile: true,
 		Required:  true,
 	}
@@ -25,7 +24,11 @@ var (
 func Witness(ctx *cli.Context) error {
 	input := ctx.Path(WitnessInputFlag.Name)
 	output := ctx.Path(WitnessOutputFlag.Name)
-	state, err := loadJSON[mipsevm.State](input)
+	vmType, err := vmTypeFromFlag(ctx)
+	if err != nil {
+		return err
+	}
+	state, err := loadState(vmType, input)
 	if err != nil {
 		return fmt.Errorf("invalid input state (%v): %w", input, err)
 	}
@@ -45,11 +48,12 @@ func Witness(ctx *cli.Context) error {
 
 var WitnessCommand = &cli.Command{
 	Name:        "witness",
-	Usage:       "Convert a Cannon JSON state into a binary witness",
-	Description: "Convert a Cannon JSON state into a binary witness. The hash of the witness is written to stdout",
+	Usage:       "Convert a Cannon state into a binary witness",
+	Description: "Convert a Cannon JSON or binary state into a binary witness. The hash of the witness is written to stdout",
 	Action:      Witness,
 	Flags: []cli.Flag{
 		WitnessInputFlag,
 		WitnessOutputFlag,
+		VMTypeFlag,
 	},
 }
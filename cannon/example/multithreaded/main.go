@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+func main() {
+	// spin up goroutines, some locked to their own OS thread, to exercise thread creation and futex based wakeups.
+	var wg sync.WaitGroup
+	var counter atomic.Int32
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(locked bool) {
+			defer wg.Done()
+			if locked {
+				runtime.LockOSThread()
+				defer runtime.UnlockOSThread()
+			}
+			for j := 0; j < 10; j++ {
+				counter.Add(1)
+				runtime.Gosched()
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+	fmt.Printf("counter: %d\n", counter.Load())
+
+	// allocate garbage, and run the GC, which uses background worker threads.
+	var data [][]byte
+	for i := 0; i < 100; i++ {
+		data = append(data, make([]byte, 1024))
+	}
+	data = nil
+	runtime.GC()
+	_, _ = os.Stdout.Write([]byte("GC complete!\n"))
+}
@@ -95,7 +95,7 @@ func encodeStepInput(t *testing.T, wit *StepWitness, localContext LocalContext)
 	mipsAbi, err := bindings.MIPSMetaData.GetAbi()
 	require.NoError(t, err)
 
-	input, err := mipsAbi.Pack("step", wit.State, wit.MemProof, localContext)
+	input, err := mipsAbi.Pack("step", wit.State, wit.ProofData, localContext)
 	require.NoError(t, err)
 	return input
 }
@@ -270,8 +270,8 @@ func TestEVMFault(t *testing.T) {
 
 			insnProof := initialState.Memory.MerkleProof(0)
 			stepWitness := &StepWitness{
-				State:    initialState.EncodeWitness(),
-				MemProof: insnProof[:],
+				State:     initialState.EncodeWitness(),
+				ProofData: insnProof[:],
 			}
 			input := encodeStepInput(t, stepWitness, LocalContext{})
 			startingGas := uint64(30_000_000)
@@ -291,7 +291,7 @@ func TestHelloEVM(t *testing.T) {
 	elfProgram, err := elf.Open("../example/bin/hello.elf")
 	require.NoError(t, err, "open ELF file")
 
-	state, err := LoadELF(elfProgram)
+	state, err := LoadELF(elfProgram, CreateInitialState)
 	require.NoError(t, err, "load ELF into state")
 
 	err = PatchGo(elfProgram, state)
@@ -341,7 +341,7 @@ func TestClaimEVM(t *testing.T) {
 	elfProgram, err := elf.Open("../example/bin/claim.elf")
 	require.NoError(t, err, "open ELF file")
 
-	state, err := LoadELF(elfProgram)
+	state, err := LoadELF(elfProgram, CreateInitialState)
 	require.NoError(t, err, "load ELF into state")
 
 	err = PatchGo(elfProgram, state)
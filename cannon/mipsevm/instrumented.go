@@ -9,6 +9,8 @@ type PreimageOracle interface {
 	GetPreimage(k [32]byte) []byte
 }
 
+var _ FPVM = (*InstrumentedState)(nil)
+
 type InstrumentedState struct {
 	state *State
 
@@ -19,14 +21,7 @@ type InstrumentedState struct {
 	memProofEnabled bool
 	memProof        [28 * 32]byte
 
-	preimageOracle PreimageOracle
-
-	// cached pre-image data, including 8 byte length prefix
-	lastPreimage []byte
-	// key for above preimage
-	lastPreimageKey [32]byte
-	// offset we last read from, or max uint32 if nothing is read this step
-	lastPreimageOffset uint32
+	preimageTracker
 }
 
 const (
@@ -40,19 +35,25 @@ const (
 )
 
 const (
-	MipsEBADF  = 0x9
-	MipsEINVAL = 0x16
+	MipsEBADF     = 0x9
+	MipsEAGAIN    = 0xb
+	MipsEINVAL    = 0x16
+	MipsETIMEDOUT = 0x91
 )
 
 func NewInstrumentedState(state *State, po PreimageOracle, stdOut, stdErr io.Writer) *InstrumentedState {
 	return &InstrumentedState{
-		state:          state,
-		stdOut:         stdOut,
-		stdErr:         stdErr,
-		preimageOracle: po,
+		state:           state,
+		stdOut:          stdOut,
+		stdErr:          stdErr,
+		preimageTracker: preimageTracker{preimageOracle: po},
 	}
 }
 
+func (m *InstrumentedState) GetState() VMState {
+	return m.state
+}
+
 func (m *InstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
 	m.memProofEnabled = proof
 	m.lastMemAccess = ^uint32(0)
@@ -61,8 +62,8 @@ func (m *InstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
 	if proof {
 		insnProof := m.state.Memory.MerkleProof(m.state.PC)
 		wit = &StepWitness{
-			State:    m.state.EncodeWitness(),
-			MemProof: insnProof[:],
+			State:     m.state.EncodeWitness(),
+			ProofData: insnProof[:],
 		}
 	}
 	err = m.mipsStep()
@@ -71,7 +72,7 @@ func (m *InstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
 	}
 
 	if proof {
-		wit.MemProof = append(wit.MemProof, m.memProof[:]...)
+		wit.ProofData = append(wit.ProofData, m.memProof[:]...)
 		if m.lastPreimageOffset != ^uint32(0) {
 			wit.PreimageOffset = m.lastPreimageOffset
 			wit.PreimageKey = m.lastPreimageKey
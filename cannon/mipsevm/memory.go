@@ -9,6 +9,8 @@ import (
 	"sort"
 
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
 )
 
 // Note: 2**12 = 4 KiB, the min phys page size in the Go runtime.
@@ -219,7 +221,7 @@ func (m *Memory) GetMemory(addr uint32) uint32 {
 }
 
 func (m *Memory) AllocPage(pageIndex uint32) *CachedPage {
-	p := &CachedPage{Data: new(Page)}
+	p := &CachedPage{Data: new(Page), Dirty: true}
 	m.pages[pageIndex] = p
 	// make nodes to root
 	k := (1 << PageKeySize) | uint64(pageIndex)
@@ -230,6 +232,60 @@ func (m *Memory) AllocPage(pageIndex uint32) *CachedPage {
 	return p
 }
 
+// DirtyPages returns the sorted indices of the pages that were modified since the last ResetDirty.
+// Newly allocated pages are dirty as well.
+func (m *Memory) DirtyPages() []uint32 {
+	var out []uint32
+	for pageIndex, p := range m.pages {
+		if p.Dirty {
+			out = append(out, pageIndex)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// ResetDirty marks all pages as unmodified, to track the pages modified from this point on.
+func (m *Memory) ResetDirty() {
+	for _, p := range m.pages {
+		p.Dirty = false
+	}
+}
+
+// Subset returns a memory with only the given pages, sharing the page data with m.
+// It is used to encode the pages of an incremental snapshot, and should not be modified.
+func (m *Memory) Subset(pageIndices []uint32) *Memory {
+	out := NewMemory()
+	for _, pageIndex := range pageIndices {
+		if p, ok := m.pages[pageIndex]; ok {
+			out.pages[pageIndex] = &CachedPage{Data: p.Data}
+		}
+	}
+	return out
+}
+
+// ApplyPages copies all pages of the given memory into m, replacing any existing pages with the same index.
+// It is used to apply the pages of an incremental snapshot to the memory of its base snapshot.
+func (m *Memory) ApplyPages(delta *Memory) {
+	for pageIndex, dp := range delta.pages {
+		p, ok := m.pageLookup(pageIndex)
+		if !ok {
+			p = m.AllocPage(pageIndex)
+		} else {
+			// invalidate the nodes to the root, now that the page changed
+			k := (uint64(1) << PageKeySize) | uint64(pageIndex)
+			for k > 0 {
+				m.nodes[k] = nil
+				k >>= 1
+			}
+		}
+		*p.Data = *dp.Data
+		p.InvalidateFull()
+	}
+}
+
 type pageEntry struct {
 	Index uint32 `json:"index"`
 	Data  *Page  `json:"data"`
@@ -267,6 +323,59 @@ func (m *Memory) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Serialize writes the memory in the binary format:
+// the page count, followed by the index and data of every page, in order of page index.
+func (m *Memory) Serialize(out io.Writer) error {
+	w := serialize.NewBinaryWriter(out)
+	indices := make([]uint32, 0, len(m.pages))
+	for k := range m.pages {
+		indices = append(indices, k)
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
+	})
+	if err := w.WriteFixed(uint32(len(indices))); err != nil {
+		return err
+	}
+	for _, k := range indices {
+		if err := w.WriteFixed(k); err != nil {
+			return err
+		}
+		if err := w.WriteFixed(m.pages[k].Data[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Deserialize(in io.Reader) error {
+	r := serialize.NewBinaryReader(in)
+	var count uint32
+	if err := r.ReadFixed(&count); err != nil {
+		return err
+	}
+	m.nodes = make(map[uint64]*[32]byte)
+	m.pages = make(map[uint32]*CachedPage)
+	m.lastPageKeys = [2]uint32{^uint32(0), ^uint32(0)}
+	m.lastPage = [2]*CachedPage{nil, nil}
+	for i := uint32(0); i < count; i++ {
+		var index uint32
+		if err := r.ReadFixed(&index); err != nil {
+			return err
+		}
+		if index > PageKeyMask {
+			return fmt.Errorf("invalid page index %d, entry %d", index, i)
+		}
+		if _, ok := m.pages[index]; ok {
+			return fmt.Errorf("cannot load duplicate page, entry %d, page index %d", i, index)
+		}
+		if err := r.ReadFixed(m.AllocPage(index).Data[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *Memory) SetMemoryRange(addr uint32, r io.Reader) error {
 	for {
 		pageIndex := addr >> PageAddrSize
@@ -325,7 +434,11 @@ func (m *Memory) ReadMemoryRange(addr uint32, count uint32) io.Reader {
 }
 
 func (m *Memory) Usage() string {
-	total := uint64(len(m.pages)) * PageSize
+	return memoryUsage(len(m.pages))
+}
+
+func memoryUsage(pageCount int) string {
+	total := uint64(pageCount) * PageSize
 	const unit = 1024
 	if total < unit {
 		return fmt.Sprintf("%d B", total)
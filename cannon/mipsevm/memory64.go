@@ -0,0 +1,419 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+const (
+	PageKeySize64   = 64 - PageAddrSize
+	PageKeyMask64   = (uint64(1) << PageKeySize64) - 1
+	MemProofLeafs64 = 64 - 5 + 1
+	MemProofSize64  = MemProofLeafs64 * 32
+)
+
+// Memory64 is the 64-bit address space of the MIPS64 VM.
+// It uses the same pages as the 32-bit Memory, but with a deeper merkle tree to cover all 64-bit addresses.
+type Memory64 struct {
+	// generalized index -> merkle root or nil if invalidated
+	nodes map[uint64]*[32]byte
+
+	// pageIndex -> cached page
+	pages map[uint64]*CachedPage
+
+	// Note: since we don't de-alloc pages, we don't do ref-counting.
+	// Once a page exists, it doesn't leave memory
+
+	// two caches: we often read instructions from one page, and do memory things with another page.
+	// this prevents map lookups each instruction
+	lastPageKeys [2]uint64
+	lastPage     [2]*CachedPage
+}
+
+func NewMemory64() *Memory64 {
+	return &Memory64{
+		nodes:        make(map[uint64]*[32]byte),
+		pages:        make(map[uint64]*CachedPage),
+		lastPageKeys: [2]uint64{^uint64(0), ^uint64(0)}, // default to invalid keys, to not match any pages
+	}
+}
+
+func (m *Memory64) PageCount() int {
+	return len(m.pages)
+}
+
+func (m *Memory64) ForEachPage(fn func(pageIndex uint64, page *Page) error) error {
+	for pageIndex, cachedPage := range m.pages {
+		if err := fn(pageIndex, cachedPage.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory64) Invalidate(addr uint64) {
+	// addr must be aligned to 8 bytes
+	if addr&0x7 != 0 {
+		panic(fmt.Errorf("unaligned memory access: %x", addr))
+	}
+
+	// find page, and invalidate addr within it
+	if p, ok := m.pageLookup(addr >> PageAddrSize); ok {
+		prevValid := p.Ok[1]
+		p.Invalidate(uint32(addr & PageAddrMask))
+		if !prevValid { // if the page was already invalid before, then nodes to mem-root will also still be.
+			return
+		}
+	} else { // no page? nothing to invalidate
+		return
+	}
+
+	// find the gindex of the first page covering the address
+	gindex := (uint64(1) << PageKeySize64) | (addr >> PageAddrSize)
+
+	for gindex > 0 {
+		m.nodes[gindex] = nil
+		gindex >>= 1
+	}
+}
+
+func (m *Memory64) MerkleizeSubtree(gindex uint64) [32]byte {
+	l := uint64(bits.Len64(gindex))
+	if l > MemProofLeafs64 {
+		panic("gindex too deep")
+	}
+	if l > PageKeySize64 {
+		depthIntoPage := l - 1 - PageKeySize64
+		pageIndex := (gindex >> depthIntoPage) & PageKeyMask64
+		if p, ok := m.pages[pageIndex]; ok {
+			pageGindex := (1 << depthIntoPage) | (gindex & ((1 << depthIntoPage) - 1))
+			return p.MerkleizeSubtree(pageGindex)
+		} else {
+			return zeroHashes[MemProofLeafs64-l] // page does not exist
+		}
+	}
+	n, ok := m.nodes[gindex]
+	if !ok {
+		// if the node doesn't exist, the whole sub-tree is zeroed
+		return zeroHashes[MemProofLeafs64-l]
+	}
+	if n != nil {
+		return *n
+	}
+	left := m.MerkleizeSubtree(gindex << 1)
+	right := m.MerkleizeSubtree((gindex << 1) | 1)
+	r := HashPair(left, right)
+	m.nodes[gindex] = &r
+	return r
+}
+
+func (m *Memory64) MerkleProof(addr uint64) (out [MemProofSize64]byte) {
+	proof := m.traverseBranch(1, addr, 0)
+	// encode the proof
+	for i := 0; i < MemProofLeafs64; i++ {
+		copy(out[i*32:(i+1)*32], proof[i][:])
+	}
+	return out
+}
+
+func (m *Memory64) traverseBranch(parent uint64, addr uint64, depth uint8) (proof [][32]byte) {
+	if depth == 64-5 {
+		proof = make([][32]byte, 0, 64-5+1)
+		proof = append(proof, m.MerkleizeSubtree(parent))
+		return
+	}
+	if depth > 64-5 {
+		panic("traversed too deep")
+	}
+	self := parent << 1
+	sibling := self | 1
+	if addr&(1<<(63-depth)) != 0 {
+		self, sibling = sibling, self
+	}
+	proof = m.traverseBranch(self, addr, depth+1)
+	siblingNode := m.MerkleizeSubtree(sibling)
+	proof = append(proof, siblingNode)
+	return
+}
+
+func (m *Memory64) MerkleRoot() [32]byte {
+	return m.MerkleizeSubtree(1)
+}
+
+func (m *Memory64) pageLookup(pageIndex uint64) (*CachedPage, bool) {
+	// hit caches
+	if pageIndex == m.lastPageKeys[0] {
+		return m.lastPage[0], true
+	}
+	if pageIndex == m.lastPageKeys[1] {
+		return m.lastPage[1], true
+	}
+	p, ok := m.pages[pageIndex]
+
+	// only cache existing pages.
+	if ok {
+		m.lastPageKeys[1] = m.lastPageKeys[0]
+		m.lastPage[1] = m.lastPage[0]
+		m.lastPageKeys[0] = pageIndex
+		m.lastPage[0] = p
+	}
+
+	return p, ok
+}
+
+func (m *Memory64) SetMemory(addr uint64, v uint64) {
+	// addr must be aligned to 8 bytes
+	if addr&0x7 != 0 {
+		panic(fmt.Errorf("unaligned memory access: %x", addr))
+	}
+
+	pageIndex := addr >> PageAddrSize
+	pageAddr := addr & PageAddrMask
+	p, ok := m.pageLookup(pageIndex)
+	if !ok {
+		// allocate the page if we have not already.
+		// Go may mmap relatively large ranges, but we only allocate the pages just in time.
+		p = m.AllocPage(pageIndex)
+	} else {
+		m.Invalidate(addr) // invalidate this branch of memory, now that the value changed
+	}
+	binary.BigEndian.PutUint64(p.Data[pageAddr:pageAddr+8], v)
+}
+
+func (m *Memory64) GetMemory(addr uint64) uint64 {
+	// addr must be aligned to 8 bytes
+	if addr&0x7 != 0 {
+		panic(fmt.Errorf("unaligned memory access: %x", addr))
+	}
+	p, ok := m.pageLookup(addr >> PageAddrSize)
+	if !ok {
+		return 0
+	}
+	pageAddr := addr & PageAddrMask
+	return binary.BigEndian.Uint64(p.Data[pageAddr : pageAddr+8])
+}
+
+func (m *Memory64) AllocPage(pageIndex uint64) *CachedPage {
+	p := &CachedPage{Data: new(Page), Dirty: true}
+	m.pages[pageIndex] = p
+	// make nodes to root
+	k := (uint64(1) << PageKeySize64) | pageIndex
+	for k > 0 {
+		m.nodes[k] = nil
+		k >>= 1
+	}
+	return p
+}
+
+// DirtyPages returns the sorted indices of the pages that were modified since the last ResetDirty.
+// Newly allocated pages are dirty as well.
+func (m *Memory64) DirtyPages() []uint64 {
+	var out []uint64
+	for pageIndex, p := range m.pages {
+		if p.Dirty {
+			out = append(out, pageIndex)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// ResetDirty marks all pages as unmodified, to track the pages modified from this point on.
+func (m *Memory64) ResetDirty() {
+	for _, p := range m.pages {
+		p.Dirty = false
+	}
+}
+
+// Subset returns a memory with only the given pages, sharing the page data with m.
+// It is used to encode the pages of an incremental snapshot, and should not be modified.
+func (m *Memory64) Subset(pageIndices []uint64) *Memory64 {
+	out := NewMemory64()
+	for _, pageIndex := range pageIndices {
+		if p, ok := m.pages[pageIndex]; ok {
+			out.pages[pageIndex] = &CachedPage{Data: p.Data}
+		}
+	}
+	return out
+}
+
+// ApplyPages copies all pages of the given memory into m, replacing any existing pages with the same index.
+// It is used to apply the pages of an incremental snapshot to the memory of its base snapshot.
+func (m *Memory64) ApplyPages(delta *Memory64) {
+	for pageIndex, dp := range delta.pages {
+		p, ok := m.pageLookup(pageIndex)
+		if !ok {
+			p = m.AllocPage(pageIndex)
+		} else {
+			// invalidate the nodes to the root, now that the page changed
+			k := (uint64(1) << PageKeySize64) | pageIndex
+			for k > 0 {
+				m.nodes[k] = nil
+				k >>= 1
+			}
+		}
+		*p.Data = *dp.Data
+		p.InvalidateFull()
+	}
+}
+
+type pageEntry64 struct {
+	Index uint64 `json:"index"`
+	Data  *Page  `json:"data"`
+}
+
+func (m *Memory64) MarshalJSON() ([]byte, error) { // nosemgrep
+	pages := make([]pageEntry64, 0, len(m.pages))
+	for k, p := range m.pages {
+		pages = append(pages, pageEntry64{
+			Index: k,
+			Data:  p.Data,
+		})
+	}
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].Index < pages[j].Index
+	})
+	return json.Marshal(pages)
+}
+
+func (m *Memory64) UnmarshalJSON(data []byte) error {
+	var pages []pageEntry64
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return err
+	}
+	m.nodes = make(map[uint64]*[32]byte)
+	m.pages = make(map[uint64]*CachedPage)
+	m.lastPageKeys = [2]uint64{^uint64(0), ^uint64(0)}
+	m.lastPage = [2]*CachedPage{nil, nil}
+	for i, p := range pages {
+		if p.Index > PageKeyMask64 {
+			return fmt.Errorf("invalid page index %d, entry %d", p.Index, i)
+		}
+		if _, ok := m.pages[p.Index]; ok {
+			return fmt.Errorf("cannot load duplicate page, entry %d, page index %d", i, p.Index)
+		}
+		m.AllocPage(p.Index).Data = p.Data
+	}
+	return nil
+}
+
+// Serialize writes the memory in the binary format, like Memory.Serialize but with 64-bit page indices.
+func (m *Memory64) Serialize(out io.Writer) error {
+	w := serialize.NewBinaryWriter(out)
+	indices := make([]uint64, 0, len(m.pages))
+	for k := range m.pages {
+		indices = append(indices, k)
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
+	})
+	if err := w.WriteFixed(uint64(len(indices))); err != nil {
+		return err
+	}
+	for _, k := range indices {
+		if err := w.WriteFixed(k); err != nil {
+			return err
+		}
+		if err := w.WriteFixed(m.pages[k].Data[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory64) Deserialize(in io.Reader) error {
+	r := serialize.NewBinaryReader(in)
+	var count uint64
+	if err := r.ReadFixed(&count); err != nil {
+		return err
+	}
+	m.nodes = make(map[uint64]*[32]byte)
+	m.pages = make(map[uint64]*CachedPage)
+	m.lastPageKeys = [2]uint64{^uint64(0), ^uint64(0)}
+	m.lastPage = [2]*CachedPage{nil, nil}
+	for i := uint64(0); i < count; i++ {
+		var index uint64
+		if err := r.ReadFixed(&index); err != nil {
+			return err
+		}
+		if index > PageKeyMask64 {
+			return fmt.Errorf("invalid page index %d, entry %d", index, i)
+		}
+		if _, ok := m.pages[index]; ok {
+			return fmt.Errorf("cannot load duplicate page, entry %d, page index %d", i, index)
+		}
+		if err := r.ReadFixed(m.AllocPage(index).Data[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory64) SetMemoryRange(addr uint64, r io.Reader) error {
+	for {
+		pageIndex := addr >> PageAddrSize
+		pageAddr := addr & PageAddrMask
+		p, ok := m.pageLookup(pageIndex)
+		if !ok {
+			p = m.AllocPage(pageIndex)
+		}
+		p.InvalidateFull()
+		n, err := r.Read(p.Data[pageAddr:])
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		addr += uint64(n)
+	}
+}
+
+type memReader64 struct {
+	m     *Memory64
+	addr  uint64
+	count uint64
+}
+
+func (r *memReader64) Read(dest []byte) (n int, err error) {
+	if r.count == 0 {
+		return 0, io.EOF
+	}
+
+	// Keep iterating over memory until we have all our data.
+	// It may wrap around the address range, and may not be aligned
+	endAddr := r.addr + r.count
+
+	pageIndex := r.addr >> PageAddrSize
+	start := r.addr & PageAddrMask
+	end := uint64(PageSize)
+
+	if pageIndex == (endAddr >> PageAddrSize) {
+		end = endAddr & PageAddrMask
+	}
+	p, ok := r.m.pageLookup(pageIndex)
+	if ok {
+		n = copy(dest, p.Data[start:end])
+	} else {
+		n = copy(dest, make([]byte, end-start)) // default to zeroes
+	}
+	r.addr += uint64(n)
+	r.count -= uint64(n)
+	return n, nil
+}
+
+func (m *Memory64) ReadMemoryRange(addr uint64, count uint64) io.Reader {
+	return &memReader64{m: m, addr: addr, count: count}
+}
+
+func (m *Memory64) Usage() string {
+	return memoryUsage(len(m.pages))
+}
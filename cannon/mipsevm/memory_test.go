@@ -187,3 +187,27 @@ func TestMemoryJSON(t *testing.T) {
 	require.NoError(t, json.Unmarshal(dat, &res))
 	require.Equal(t, uint32(123), res.GetMemory(8))
 }
+
+func TestMemoryDirtyPages(t *testing.T) {
+	m := NewMemory()
+	m.SetMemory(0x10000, 1)
+	m.SetMemory(PageSize*3, 2)
+	require.Equal(t, []uint32{3, 0x10000 >> PageAddrSize}, m.DirtyPages(), "new pages are dirty")
+
+	m.ResetDirty()
+	require.Empty(t, m.DirtyPages())
+	require.Equal(t, uint32(1), m.GetMemory(0x10000), "reads don't dirty pages")
+	require.Empty(t, m.DirtyPages())
+
+	m.SetMemory(0x10004, 42)
+	require.Equal(t, []uint32{0x10000 >> PageAddrSize}, m.DirtyPages())
+
+	// apply the dirty pages to a copy of the memory, from before the modification
+	base := NewMemory()
+	base.SetMemory(0x10000, 1)
+	base.SetMemory(PageSize*3, 2)
+	_ = base.MerkleRoot()
+	base.ApplyPages(m.Subset(m.DirtyPages()))
+	require.Equal(t, m.MerkleRoot(), base.MerkleRoot(), "memory must match after applying the dirty pages")
+	require.Equal(t, uint32(42), base.GetMemory(0x10004))
+}
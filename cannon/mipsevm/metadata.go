@@ -8,8 +8,8 @@ import (
 
 type Symbol struct {
 	Name  string `json:"name"`
-	Start uint32 `json:"start"`
-	Size  uint32 `json:"size"`
+	Start uint64 `json:"start"`
+	Size  uint64 `json:"size"`
 }
 
 type Metadata struct {
@@ -27,12 +27,12 @@ func MakeMetadata(elfProgram *elf.File) (*Metadata, error) {
 	})
 	out := &Metadata{Symbols: make([]Symbol, len(syms))}
 	for i, s := range syms {
-		out.Symbols[i] = Symbol{Name: s.Name, Start: uint32(s.Value), Size: uint32(s.Size)}
+		out.Symbols[i] = Symbol{Name: s.Name, Start: s.Value, Size: s.Size}
 	}
 	return out, nil
 }
 
-func (m *Metadata) LookupSymbol(addr uint32) string {
+func (m *Metadata) LookupSymbol(addr uint64) string {
 	if len(m.Symbols) == 0 {
 		return "!unknown"
 	}
@@ -50,17 +50,17 @@ func (m *Metadata) LookupSymbol(addr uint32) string {
 	return out.Name
 }
 
-func (m *Metadata) SymbolMatcher(name string) func(addr uint32) bool {
+func (m *Metadata) SymbolMatcher(name string) func(addr uint64) bool {
 	for _, s := range m.Symbols {
 		if s.Name == name {
 			start := s.Start
 			end := s.Start + s.Size
-			return func(addr uint32) bool {
+			return func(addr uint64) bool {
 				return addr >= start && addr < end
 			}
 		}
 	}
-	return func(addr uint32) bool {
+	return func(addr uint64) bool {
 		return false
 	}
 }
@@ -75,3 +75,14 @@ func (v HexU32) String() string {
 func (v HexU32) MarshalText() ([]byte, error) {
 	return []byte(v.String()), nil
 }
+
+// HexU64 to lazy-format 64-bit integer attributes for logging
+type HexU64 uint64
+
+func (v HexU64) String() string {
+	return fmt.Sprintf("%016x", uint64(v))
+}
+
+func (v HexU64) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
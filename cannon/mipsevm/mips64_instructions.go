@@ -0,0 +1,510 @@
+package mipsevm
+
+import (
+	"math/bits"
+)
+
+// CpuScalars64 are the scalar registers of a MIPS64 CPU, besides the 32 general purpose registers.
+type CpuScalars64 struct {
+	PC     uint64 `json:"pc"`
+	NextPC uint64 `json:"nextPC"`
+	LO     uint64 `json:"lo"`
+	HI     uint64 `json:"hi"`
+}
+
+// MemTracker64 is the MIPS64 equivalent of MemTracker, called with the 8-byte aligned effective address.
+type MemTracker64 func(effAddr uint64)
+
+func getInstructionDetails64(pc uint64, memory *Memory64) (insn, opcode, fun uint32) {
+	// instructions are 4 bytes, memory is accessed in 8 byte words
+	insn = selectWord64(memory.GetMemory(pc&^7), pc)
+	opcode = insn >> 26 // First 6-bits
+	fun = insn & 0x3f   // Last 6-bits
+	return insn, opcode, fun
+}
+
+// execMips64CoreStepLogic executes all MIPS64 instructions, except for syscalls.
+// Like execMipsCoreStepLogic, the syscall instruction is left to the caller.
+func execMips64CoreStepLogic(cpu *CpuScalars64, registers *[32]uint64, memory *Memory64, insn, opcode, fun uint32, memTracker MemTracker64) error {
+	// j-type j/jal
+	if opcode == 2 || opcode == 3 {
+		linkReg := uint32(0)
+		if opcode == 3 {
+			linkReg = 31
+		}
+		// Take top 36 bits of the next PC (its 256 MB region), and concatenate with the 26-bit offset
+		target := (cpu.NextPC & 0xFFFFFFFF_F0000000) | uint64((insn&0x03FFFFFF)<<2)
+		return handleJump64(cpu, registers, linkReg, target)
+	}
+
+	// ldl and ldr are the only loads outside of the upper opcode range
+	isLoadStore := opcode >= 0x20 || opcode == 0x1A || opcode == 0x1B
+
+	// register fetch
+	rs := uint64(0) // source register 1 value
+	rt := uint64(0) // source register 2 / temp value
+	rtReg := (insn >> 16) & 0x1F
+
+	// R-type or I-type (stores rt)
+	rs = registers[(insn>>21)&0x1F]
+	rdReg := rtReg
+	if opcode == 0 || opcode == 0x1c {
+		// R-type (stores rd)
+		rt = registers[rtReg]
+		rdReg = (insn >> 11) & 0x1F
+	} else if !isLoadStore {
+		// rt is SignExtImm
+		// don't sign extend for andi, ori, xori
+		if opcode == 0xC || opcode == 0xD || opcode == 0xe {
+			// ZeroExtImm
+			rt = uint64(insn & 0xFFFF)
+		} else {
+			// SignExtImm
+			rt = SE64(uint64(insn&0xFFFF), 16)
+		}
+	} else if opcode >= 0x28 || opcode == 0x22 || opcode == 0x26 || opcode == 0x1A || opcode == 0x1B {
+		// store rt value with store
+		rt = registers[rtReg]
+
+		// store actual rt with lwl, lwr, ldl and ldr
+		rdReg = rtReg
+	}
+
+	if (opcode >= 4 && opcode < 8) || opcode == 1 {
+		return handleBranch64(cpu, registers, opcode, insn, rtReg, rs)
+	}
+
+	storeAddr := ^uint64(0)
+	// memory fetch (all I-type)
+	// we do the load for stores also
+	mem := uint64(0)
+	if isLoadStore {
+		// M[R[rs]+SignExtImm]
+		rs += SE64(uint64(insn&0xFFFF), 16)
+		addr := rs &^ 7
+		memTracker(addr)
+		mem = memory.GetMemory(addr)
+		if opcode >= 0x28 && opcode != 0x30 && opcode != 0x34 && opcode != 0x37 {
+			// store
+			storeAddr = addr
+			// store opcodes don't write back to a register
+			rdReg = 0
+		}
+	}
+
+	// ALU
+	val := execute64(insn, rs, rt, mem)
+
+	if opcode == 0 && fun >= 8 && fun < 0x20 && (fun < 0x14 || fun > 0x17) {
+		if fun == 8 || fun == 9 { // jr/jalr
+			linkReg := uint32(0)
+			if fun == 9 {
+				linkReg = rdReg
+			}
+			return handleJump64(cpu, registers, linkReg, rs)
+		}
+
+		if fun == 0xa { // movz
+			return handleRd64(cpu, registers, rdReg, rs, rt == 0)
+		}
+		if fun == 0xb { // movn
+			return handleRd64(cpu, registers, rdReg, rs, rt != 0)
+		}
+
+		// lo and hi registers
+		// can write back
+		if fun >= 0x10 {
+			return handleHiLo64(cpu, registers, fun, rs, rt, rdReg)
+		}
+	}
+
+	// stupid sc and scd, write a 1 to rt
+	if (opcode == 0x38 || opcode == 0x3C) && rtReg != 0 {
+		registers[rtReg] = 1
+	}
+
+	// write memory
+	if storeAddr != ^uint64(0) {
+		memTracker(storeAddr)
+		memory.SetMemory(storeAddr, val)
+	}
+
+	// write back the value to destination register
+	return handleRd64(cpu, registers, rdReg, val, true)
+}
+
+func handleBranch64(cpu *CpuScalars64, registers *[32]uint64, opcode uint32, insn uint32, rtReg uint32, rs uint64) error {
+	if cpu.NextPC != cpu.PC+4 {
+		panic("branch in delay slot")
+	}
+
+	shouldBranch := false
+	if opcode == 4 || opcode == 5 { // beq/bne
+		rt := registers[rtReg]
+		shouldBranch = (rs == rt && opcode == 4) || (rs != rt && opcode == 5)
+	} else if opcode == 6 {
+		shouldBranch = int64(rs) <= 0 // blez
+	} else if opcode == 7 {
+		shouldBranch = int64(rs) > 0 // bgtz
+	} else if opcode == 1 {
+		// regimm
+		rtv := (insn >> 16) & 0x1F
+		if rtv == 0 { // bltz
+			shouldBranch = int64(rs) < 0
+		}
+		if rtv == 1 { // bgez
+			shouldBranch = int64(rs) >= 0
+		}
+	}
+
+	prevPC := cpu.PC
+	cpu.PC = cpu.NextPC // execute the delay slot first
+	if shouldBranch {
+		cpu.NextPC = prevPC + 4 + (SE64(uint64(insn&0xFFFF), 16) << 2) // then continue with the instruction the branch jumps to.
+	} else {
+		cpu.NextPC = cpu.NextPC + 4 // branch not taken
+	}
+	return nil
+}
+
+func handleHiLo64(cpu *CpuScalars64, registers *[32]uint64, fun uint32, rs uint64, rt uint64, storeReg uint32) error {
+	val := uint64(0)
+	switch fun {
+	case 0x10: // mfhi
+		val = cpu.HI
+	case 0x11: // mthi
+		cpu.HI = rs
+	case 0x12: // mflo
+		val = cpu.LO
+	case 0x13: // mtlo
+		cpu.LO = rs
+	case 0x18: // mult
+		acc := uint64(int64(int32(rs)) * int64(int32(rt)))
+		cpu.HI = signExtend32(uint32(acc >> 32))
+		cpu.LO = signExtend32(uint32(acc))
+	case 0x19: // multu
+		acc := uint64(uint32(rs)) * uint64(uint32(rt))
+		cpu.HI = signExtend32(uint32(acc >> 32))
+		cpu.LO = signExtend32(uint32(acc))
+	case 0x1a: // div
+		cpu.HI = signExtend32(uint32(int32(rs) % int32(rt)))
+		cpu.LO = signExtend32(uint32(int32(rs) / int32(rt)))
+	case 0x1b: // divu
+		cpu.HI = signExtend32(uint32(rs) % uint32(rt))
+		cpu.LO = signExtend32(uint32(rs) / uint32(rt))
+	case 0x1c: // dmult
+		hi, lo := bits.Mul64(rs, rt)
+		// correct the unsigned high word for the signed operands
+		if int64(rs) < 0 {
+			hi -= rt
+		}
+		if int64(rt) < 0 {
+			hi -= rs
+		}
+		cpu.HI = hi
+		cpu.LO = lo
+	case 0x1d: // dmultu
+		cpu.HI, cpu.LO = bits.Mul64(rs, rt)
+	case 0x1e: // ddiv
+		cpu.HI = uint64(int64(rs) % int64(rt))
+		cpu.LO = uint64(int64(rs) / int64(rt))
+	case 0x1f: // ddivu
+		cpu.HI = rs % rt
+		cpu.LO = rs / rt
+	}
+
+	if storeReg != 0 {
+		registers[storeReg] = val
+	}
+
+	cpu.PC = cpu.NextPC
+	cpu.NextPC = cpu.NextPC + 4
+	return nil
+}
+
+func handleJump64(cpu *CpuScalars64, registers *[32]uint64, linkReg uint32, dest uint64) error {
+	if cpu.NextPC != cpu.PC+4 {
+		panic("jump in delay slot")
+	}
+	prevPC := cpu.PC
+	cpu.PC = cpu.NextPC
+	cpu.NextPC = dest
+	if linkReg != 0 {
+		registers[linkReg] = prevPC + 8 // set the link-register to the instr after the delay slot instruction.
+	}
+	return nil
+}
+
+func handleRd64(cpu *CpuScalars64, registers *[32]uint64, storeReg uint32, val uint64, conditional bool) error {
+	if storeReg >= 32 {
+		panic("invalid register")
+	}
+	if storeReg != 0 && conditional {
+		registers[storeReg] = val
+	}
+	cpu.PC = cpu.NextPC
+	cpu.NextPC = cpu.NextPC + 4
+	return nil
+}
+
+func execute64(insn uint32, rs uint64, rt uint64, mem uint64) uint64 {
+	opcode := insn >> 26 // 6-bits
+
+	if opcode == 0 || (opcode >= 8 && opcode < 0xF) || opcode == 0x18 || opcode == 0x19 {
+		fun := insn & 0x3f // 6-bits
+		// transform ArithLogI to SPECIAL
+		switch opcode {
+		case 8:
+			fun = 0x20 // addi
+		case 9:
+			fun = 0x21 // addiu
+		case 0xA:
+			fun = 0x2A // slti
+		case 0xB:
+			fun = 0x2B // sltiu
+		case 0xC:
+			fun = 0x24 // andi
+		case 0xD:
+			fun = 0x25 // ori
+		case 0xE:
+			fun = 0x26 // xori
+		case 0x18:
+			fun = 0x2C // daddi
+		case 0x19:
+			fun = 0x2D // daddiu
+		}
+
+		shamt := uint64((insn >> 6) & 0x1F)
+		switch fun {
+		case 0x00: // sll
+			return signExtend32(uint32(rt) << shamt)
+		case 0x02: // srl
+			return signExtend32(uint32(rt) >> shamt)
+		case 0x03: // sra
+			return signExtend32(uint32(int32(rt) >> shamt))
+		case 0x04: // sllv
+			return signExtend32(uint32(rt) << (rs & 0x1F))
+		case 0x06: // srlv
+			return signExtend32(uint32(rt) >> (rs & 0x1F))
+		case 0x07: // srav
+			return signExtend32(uint32(int32(rt) >> (rs & 0x1F)))
+		// functs in range [0x8, 0x1f] are handled specially by other functions, except for the doubleword shifts
+		case 0x08: // jr
+			return rs
+		case 0x09: // jalr
+			return rs
+		case 0x0a: // movz
+			return rs
+		case 0x0b: // movn
+			return rs
+		case 0x0c: // syscall
+			return rs
+		// 0x0d - break not supported
+		case 0x0f: // sync
+			return rs
+		case 0x10: // mfhi
+			return rs
+		case 0x11: // mthi
+			return rs
+		case 0x12: // mflo
+			return rs
+		case 0x13: // mtlo
+			return rs
+		case 0x14: // dsllv
+			return rt << (rs & 0x3F)
+		case 0x16: // dsrlv
+			return rt >> (rs & 0x3F)
+		case 0x17: // dsrav
+			return uint64(int64(rt) >> (rs & 0x3F))
+		case 0x18: // mult
+			return rs
+		case 0x19: // multu
+			return rs
+		case 0x1a: // div
+			return rs
+		case 0x1b: // divu
+			return rs
+		case 0x1c: // dmult
+			return rs
+		case 0x1d: // dmultu
+			return rs
+		case 0x1e: // ddiv
+			return rs
+		case 0x1f: // ddivu
+			return rs
+		// The rest includes transformed R-type arith imm instructions
+		case 0x20: // add
+			return signExtend32(uint32(rs) + uint32(rt))
+		case 0x21: // addu
+			return signExtend32(uint32(rs) + uint32(rt))
+		case 0x22: // sub
+			return signExtend32(uint32(rs) - uint32(rt))
+		case 0x23: // subu
+			return signExtend32(uint32(rs) - uint32(rt))
+		case 0x24: // and
+			return rs & rt
+		case 0x25: // or
+			return rs | rt
+		case 0x26: // xor
+			return rs ^ rt
+		case 0x27: // nor
+			return ^(rs | rt)
+		case 0x2a: // slti
+			if int64(rs) < int64(rt) {
+				return 1
+			}
+			return 0
+		case 0x2b: // sltiu
+			if rs < rt {
+				return 1
+			}
+			return 0
+		case 0x2c: // dadd
+			return rs + rt
+		case 0x2d: // daddu
+			return rs + rt
+		case 0x2e: // dsub
+			return rs - rt
+		case 0x2f: // dsubu
+			return rs - rt
+		case 0x38: // dsll
+			return rt << shamt
+		case 0x3a: // dsrl
+			return rt >> shamt
+		case 0x3b: // dsra
+			return uint64(int64(rt) >> shamt)
+		case 0x3c: // dsll32
+			return rt << (shamt + 32)
+		case 0x3e: // dsrl32
+			return rt >> (shamt + 32)
+		case 0x3f: // dsra32
+			return uint64(int64(rt) >> (shamt + 32))
+		default:
+			panic("invalid instruction")
+		}
+	} else {
+		switch opcode {
+		// SPECIAL2
+		case 0x1C:
+			fun := insn & 0x3f // 6-bits
+			switch fun {
+			case 0x2: // mul
+				return signExtend32(uint32(int32(rs) * int32(rt)))
+			case 0x20: // clz
+				return uint64(bits.LeadingZeros32(uint32(rs)))
+			case 0x21: // clo
+				return uint64(bits.LeadingZeros32(^uint32(rs)))
+			case 0x24: // dclz
+				return uint64(bits.LeadingZeros64(rs))
+			case 0x25: // dclo
+				return uint64(bits.LeadingZeros64(^rs))
+			}
+		case 0x0F: // lui
+			return signExtend32(uint32(rt) << 16)
+		case 0x1A: // ldl
+			val := mem << ((rs & 7) * 8)
+			mask := ^uint64(0) << ((rs & 7) * 8)
+			return (rt & ^mask) | val
+		case 0x1B: // ldr
+			val := mem >> (56 - (rs&7)*8)
+			mask := ^uint64(0) >> (56 - (rs&7)*8)
+			return (rt & ^mask) | val
+		case 0x20: // lb
+			return SE64((mem>>(56-(rs&7)*8))&0xFF, 8)
+		case 0x21: // lh
+			return SE64((mem>>(48-(rs&6)*8))&0xFFFF, 16)
+		case 0x22: // lwl
+			w := selectWord64(mem, rs)
+			val := w << ((rs & 3) * 8)
+			mask := uint32(0xFFFFFFFF) << ((rs & 3) * 8)
+			return signExtend32((uint32(rt) & ^mask) | val)
+		case 0x23: // lw
+			return signExtend32(selectWord64(mem, rs))
+		case 0x24: // lbu
+			return (mem >> (56 - (rs&7)*8)) & 0xFF
+		case 0x25: //  lhu
+			return (mem >> (48 - (rs&6)*8)) & 0xFFFF
+		case 0x26: //  lwr
+			w := selectWord64(mem, rs)
+			val := w >> (24 - (rs&3)*8)
+			mask := uint32(0xFFFFFFFF) >> (24 - (rs&3)*8)
+			res := (uint32(rt) & ^mask) | val
+			if rs&3 == 3 { // the full word is loaded, including the sign bit
+				return signExtend32(res)
+			}
+			// the upper word of the register is left untouched
+			return (rt & 0xFFFFFFFF_00000000) | uint64(res)
+		case 0x27: //  lwu
+			return uint64(selectWord64(mem, rs))
+		case 0x28: //  sb
+			val := (rt & 0xFF) << (56 - (rs&7)*8)
+			mask := ^uint64(0) ^ uint64(0xFF<<(56-(rs&7)*8))
+			return (mem & mask) | val
+		case 0x29: //  sh
+			val := (rt & 0xFFFF) << (48 - (rs&6)*8)
+			mask := ^uint64(0) ^ uint64(0xFFFF<<(48-(rs&6)*8))
+			return (mem & mask) | val
+		case 0x2a: //  swl
+			w := selectWord64(mem, rs)
+			val := uint32(rt) >> ((rs & 3) * 8)
+			mask := uint32(0xFFFFFFFF) >> ((rs & 3) * 8)
+			return updateWord64(mem, rs, (w & ^mask)|val)
+		case 0x2b: //  sw
+			return updateWord64(mem, rs, uint32(rt))
+		case 0x2c: //  sdl
+			val := rt >> ((rs & 7) * 8)
+			mask := ^uint64(0) >> ((rs & 7) * 8)
+			return (mem & ^mask) | val
+		case 0x2d: //  sdr
+			val := rt << (56 - (rs&7)*8)
+			mask := ^uint64(0) << (56 - (rs&7)*8)
+			return (mem & ^mask) | val
+		case 0x2e: //  swr
+			w := selectWord64(mem, rs)
+			val := uint32(rt) << (24 - (rs&3)*8)
+			mask := uint32(0xFFFFFFFF) << (24 - (rs&3)*8)
+			return updateWord64(mem, rs, (w & ^mask)|val)
+		case 0x30: //  ll
+			return signExtend32(selectWord64(mem, rs))
+		case 0x34: //  lld
+			return mem
+		case 0x37: //  ld
+			return mem
+		case 0x38: //  sc
+			return updateWord64(mem, rs, uint32(rt))
+		case 0x3c: //  scd
+			return rt
+		case 0x3f: //  sd
+			return rt
+		default:
+			panic("invalid instruction")
+		}
+	}
+	panic("invalid instruction")
+}
+
+// selectWord64 returns the 4-byte word at addr, from the 8-byte memory word that contains it.
+func selectWord64(mem uint64, addr uint64) uint32 {
+	return uint32(mem >> (32 - (addr&4)*8))
+}
+
+// updateWord64 replaces the 4-byte word at addr, in the 8-byte memory word that contains it.
+func updateWord64(mem uint64, addr uint64, w uint32) uint64 {
+	shift := 32 - (addr&4)*8
+	return (mem & ^(uint64(0xFFFFFFFF) << shift)) | (uint64(w) << shift)
+}
+
+func signExtend32(v uint32) uint64 {
+	return uint64(int64(int32(v)))
+}
+
+func SE64(dat uint64, idx uint64) uint64 {
+	isSigned := (dat >> (idx - 1)) != 0
+	signed := ((uint64(1) << (64 - idx)) - 1) << idx
+	mask := (uint64(1) << idx) - 1
+	if isSigned {
+		return dat&mask | signed
+	} else {
+		return dat & mask
+	}
+}
@@ -0,0 +1,61 @@
+package mipsevm
+
+import (
+	"io"
+)
+
+var _ FPVM = (*MIPS64InstrumentedState)(nil)
+
+type MIPS64InstrumentedState struct {
+	state *MIPS64State
+
+	stdOut io.Writer
+	stdErr io.Writer
+
+	lastMemAccess   uint64
+	memProofEnabled bool
+	memProof        [MemProofSize64]byte
+
+	preimageTracker
+}
+
+func NewMIPS64InstrumentedState(state *MIPS64State, po PreimageOracle, stdOut, stdErr io.Writer) *MIPS64InstrumentedState {
+	return &MIPS64InstrumentedState{
+		state:           state,
+		stdOut:          stdOut,
+		stdErr:          stdErr,
+		preimageTracker: preimageTracker{preimageOracle: po},
+	}
+}
+
+func (m *MIPS64InstrumentedState) GetState() VMState {
+	return m.state
+}
+
+func (m *MIPS64InstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
+	m.memProofEnabled = proof
+	m.lastMemAccess = ^uint64(0)
+	m.lastPreimageOffset = ^uint32(0)
+
+	if proof {
+		insnProof := m.state.Memory.MerkleProof(m.state.PC)
+		wit = &StepWitness{
+			State:     m.state.EncodeWitness(),
+			ProofData: insnProof[:],
+		}
+	}
+	err = m.mipsStep()
+	if err != nil {
+		return nil, err
+	}
+
+	if proof {
+		wit.ProofData = append(wit.ProofData, m.memProof[:]...)
+		if m.lastPreimageOffset != ^uint32(0) {
+			wit.PreimageOffset = m.lastPreimageOffset
+			wit.PreimageKey = m.lastPreimageKey
+			wit.PreimageValue = m.lastPreimage
+		}
+	}
+	return
+}
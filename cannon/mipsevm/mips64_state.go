@@ -0,0 +1,139 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+// MIPS64StateWitnessSize is the size of the MIPS64 state witness encoding in bytes.
+const MIPS64StateWitnessSize = 378
+
+// mips64ExitCodeWitnessOffset is the offset of the exit code in the MIPS64 state witness,
+// it is directly followed by the exited flag.
+const mips64ExitCodeWitnessOffset = 32*2 + 8*6
+
+var _ VMState = (*MIPS64State)(nil)
+
+// MIPS64State is the state of the single-threaded MIPS64 VM.
+// It mirrors State, but with 64-bit registers and a 64-bit address space.
+type MIPS64State struct {
+	Memory *Memory64 `json:"memory"`
+
+	PreimageKey    common.Hash `json:"preimageKey"`
+	PreimageOffset uint64      `json:"preimageOffset"` // note that the offset includes the 8-byte length prefix
+
+	PC     uint64 `json:"pc"`
+	NextPC uint64 `json:"nextPC"`
+	LO     uint64 `json:"lo"`
+	HI     uint64 `json:"hi"`
+	Heap   uint64 `json:"heap"` // to handle mmap growth
+
+	ExitCode uint8 `json:"exit"`
+	Exited   bool  `json:"exited"`
+
+	Step uint64 `json:"step"`
+
+	Registers [32]uint64 `json:"registers"`
+
+	// LastHint is optional metadata, and not part of the VM state itself.
+	// See State.LastHint.
+	LastHint hexutil.Bytes `json:"lastHint,omitempty"`
+}
+
+// CreateInitialMIPS64State creates an empty MIPS64 VM state.
+func CreateInitialMIPS64State(pc, heapStart uint64) *MIPS64State {
+	return &MIPS64State{
+		PC:        pc,
+		NextPC:    pc + 4,
+		HI:        0,
+		LO:        0,
+		Heap:      heapStart,
+		Registers: [32]uint64{},
+		Memory:    NewMemory64(),
+		ExitCode:  0,
+		Exited:    false,
+		Step:      0,
+	}
+}
+
+func (s *MIPS64State) VMStatus() uint8 {
+	return vmStatus(s.Exited, s.ExitCode)
+}
+
+func (s *MIPS64State) GetStep() uint64 { return s.Step }
+
+func (s *MIPS64State) GetExited() bool { return s.Exited }
+
+func (s *MIPS64State) GetExitCode() uint8 { return s.ExitCode }
+
+func (s *MIPS64State) GetLastHint() hexutil.Bytes { return s.LastHint }
+
+func (s *MIPS64State) cpu() CpuScalars64 {
+	return CpuScalars64{PC: s.PC, NextPC: s.NextPC, LO: s.LO, HI: s.HI}
+}
+
+func (s *MIPS64State) setCpu(cpu CpuScalars64) {
+	s.PC = cpu.PC
+	s.NextPC = cpu.NextPC
+	s.LO = cpu.LO
+	s.HI = cpu.HI
+}
+
+// Serialize writes the state in the binary format, like State.Serialize.
+func (s *MIPS64State) Serialize(out io.Writer) error {
+	w := serialize.NewBinaryWriter(out)
+	if err := w.WriteFixed(mips64StateBinaryVersion); err != nil {
+		return err
+	}
+	if err := s.Memory.Serialize(out); err != nil {
+		return err
+	}
+	for _, v := range []any{s.PreimageKey, s.PreimageOffset, s.PC, s.NextPC, s.LO, s.HI, s.Heap, s.ExitCode, s.Exited, s.Step, s.Registers} {
+		if err := w.WriteFixed(v); err != nil {
+			return err
+		}
+	}
+	return w.WriteBytes(s.LastHint)
+}
+
+func (s *MIPS64State) Deserialize(in io.Reader) error {
+	r := serialize.NewBinaryReader(in)
+	if err := readBinaryVersion(r, mips64StateBinaryVersion); err != nil {
+		return err
+	}
+	s.Memory = NewMemory64()
+	if err := s.Memory.Deserialize(in); err != nil {
+		return err
+	}
+	for _, v := range []any{&s.PreimageKey, &s.PreimageOffset, &s.PC, &s.NextPC, &s.LO, &s.HI, &s.Heap, &s.ExitCode, &s.Exited, &s.Step, &s.Registers} {
+		if err := r.ReadFixed(v); err != nil {
+			return err
+		}
+	}
+	return r.ReadBytes((*[]byte)(&s.LastHint))
+}
+
+func (s *MIPS64State) EncodeWitness() StateWitness {
+	out := make([]byte, 0, MIPS64StateWitnessSize)
+	memRoot := s.Memory.MerkleRoot()
+	out = append(out, memRoot[:]...)
+	out = append(out, s.PreimageKey[:]...)
+	out = binary.BigEndian.AppendUint64(out, s.PreimageOffset)
+	out = binary.BigEndian.AppendUint64(out, s.PC)
+	out = binary.BigEndian.AppendUint64(out, s.NextPC)
+	out = binary.BigEndian.AppendUint64(out, s.LO)
+	out = binary.BigEndian.AppendUint64(out, s.HI)
+	out = binary.BigEndian.AppendUint64(out, s.Heap)
+	out = append(out, s.ExitCode)
+	out = appendBool(out, s.Exited)
+	out = binary.BigEndian.AppendUint64(out, s.Step)
+	for _, r := range s.Registers {
+		out = binary.BigEndian.AppendUint64(out, r)
+	}
+	return out
+}
@@ -0,0 +1,186 @@
+package mipsevm
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func loadMIPS64ELF(t *testing.T, path string) *MIPS64State {
+	elfProgram, err := elf.Open(path)
+	require.NoError(t, err, "open ELF file")
+
+	state, err := LoadELF64(elfProgram)
+	require.NoError(t, err, "load ELF into state")
+
+	err = PatchGo64(elfProgram, state)
+	require.NoError(t, err, "apply Go runtime patches")
+	require.NoError(t, PatchStack64(state), "add initial stack")
+	return state
+}
+
+func TestMIPS64Hello(t *testing.T) {
+	state := loadMIPS64ELF(t, "../example/bin/hello.64.elf")
+
+	var stdOutBuf, stdErrBuf bytes.Buffer
+	us := NewMIPS64InstrumentedState(state, nil, io.MultiWriter(&stdOutBuf, os.Stdout), io.MultiWriter(&stdErrBuf, os.Stderr))
+
+	for i := 0; i < 400_000; i++ {
+		if us.state.Exited {
+			break
+		}
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+
+	require.True(t, state.Exited, "must complete program")
+	require.Equal(t, uint8(0), state.ExitCode, "exit with 0")
+
+	require.Equal(t, "hello world!\n", stdOutBuf.String(), "stdout says hello")
+	require.Equal(t, "", stdErrBuf.String(), "stderr silent")
+}
+
+func TestMIPS64Claim(t *testing.T) {
+	state := loadMIPS64ELF(t, "../example/bin/claim.64.elf")
+
+	oracle, expectedStdOut, expectedStdErr := claimTestOracle(t)
+
+	var stdOutBuf, stdErrBuf bytes.Buffer
+	us := NewMIPS64InstrumentedState(state, oracle, io.MultiWriter(&stdOutBuf, os.Stdout), io.MultiWriter(&stdErrBuf, os.Stderr))
+
+	for i := 0; i < 2000_000; i++ {
+		if us.state.Exited {
+			break
+		}
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+
+	require.True(t, state.Exited, "must complete program")
+	require.Equal(t, uint8(0), state.ExitCode, "exit with 0")
+
+	require.Equal(t, expectedStdOut, stdOutBuf.String(), "stdout")
+	require.Equal(t, expectedStdErr, stdErrBuf.String(), "stderr")
+}
+
+func TestMIPS64StateHash(t *testing.T) {
+	state := CreateInitialMIPS64State(0x1000, heapStart64)
+	state.Exited = true
+	state.ExitCode = 1
+	witness := state.EncodeWitness()
+	require.Len(t, witness, MIPS64StateWitnessSize)
+
+	hash, err := witness.StateHash()
+	require.NoError(t, err)
+	expected := crypto.Keccak256Hash(witness)
+	expected[0] = VMStatusInvalid
+	require.Equal(t, expected, hash)
+}
+
+func TestMIPS64StepProofs(t *testing.T) {
+	state := loadMIPS64ELF(t, "../example/bin/hello.64.elf")
+	us := NewMIPS64InstrumentedState(state, nil, io.Discard, io.Discard)
+
+	verify := func(proof []byte, addr uint64, root [32]byte) {
+		node := *(*[32]byte)(proof[:32])
+		path := addr >> 5
+		for i := 32; i < len(proof); i += 32 {
+			sib := *(*[32]byte)(proof[i : i+32])
+			if path&1 != 0 {
+				node = HashPair(sib, node)
+			} else {
+				node = HashPair(node, sib)
+			}
+			path >>= 1
+		}
+		require.Equal(t, root, node, "proof of %x must verify", addr)
+	}
+
+	for i := 0; i < 10_000; i++ {
+		pc := state.PC
+		root := state.Memory.MerkleRoot()
+		wit, err := us.Step(true)
+		require.NoError(t, err)
+		require.Len(t, wit.State, MIPS64StateWitnessSize)
+		require.Len(t, wit.ProofData, 2*MemProofSize64)
+		require.Equal(t, root[:], wit.State[:32], "memory root is first in witness")
+		verify(wit.ProofData[:MemProofSize64], pc, root)
+		if us.lastMemAccess != ^uint64(0) {
+			verify(wit.ProofData[MemProofSize64:], us.lastMemAccess, root)
+		}
+	}
+}
+
+func TestMIPS64Instructions(t *testing.T) {
+	encodeR := func(rs, rt, rd, shamt, fun uint32) uint32 {
+		return rs<<21 | rt<<16 | rd<<11 | shamt<<6 | fun
+	}
+	encodeI := func(opcode, rs, rt, imm uint32) uint32 {
+		return opcode<<26 | rs<<21 | rt<<16 | (imm & 0xFFFF)
+	}
+	cases := []struct {
+		name     string
+		insn     uint32
+		rs       uint64
+		rt       uint64
+		mem      uint64
+		expected uint64
+	}{
+		{name: "addu sign-extends", insn: encodeR(1, 2, 3, 0, 0x21), rs: 0x7FFF_FFFF, rt: 1, expected: 0xFFFFFFFF_80000000},
+		{name: "daddu", insn: encodeR(1, 2, 3, 0, 0x2d), rs: 0x7FFF_FFFF, rt: 1, expected: 0x8000_0000},
+		{name: "daddiu", insn: encodeI(0x19, 1, 2, 0xFFFF), rs: 0x1_0000_0000, rt: SE64(0xFFFF, 16), expected: 0xFFFF_FFFF},
+		{name: "sll sign-extends", insn: encodeR(0, 2, 3, 31, 0x00), rt: 1, expected: 0xFFFFFFFF_80000000},
+		{name: "dsll32", insn: encodeR(0, 2, 3, 4, 0x3c), rt: 1, expected: 1 << 36},
+		{name: "dsra", insn: encodeR(0, 2, 3, 4, 0x3b), rt: 0x80000000_00000000, expected: 0xF8000000_00000000},
+		{name: "dsrlv", insn: encodeR(1, 2, 3, 0, 0x16), rs: 63, rt: 0x80000000_00000000, expected: 1},
+		{name: "slt signed", insn: encodeR(1, 2, 3, 0, 0x2a), rs: ^uint64(0), rt: 0, expected: 1},
+		{name: "lui sign-extends", insn: encodeI(0x0F, 0, 2, 0x8000), rt: SE64(0x8000, 16), expected: 0xFFFFFFFF_80000000},
+		{name: "lw upper word", insn: encodeI(0x23, 1, 2, 0), rs: 0x1000, mem: 0x80000000_00000001, expected: 0xFFFFFFFF_80000000},
+		{name: "lw lower word", insn: encodeI(0x23, 1, 2, 0), rs: 0x1004, mem: 0x80000000_00000001, expected: 1},
+		{name: "lwu", insn: encodeI(0x27, 1, 2, 0), rs: 0x1000, mem: 0x80000000_00000001, expected: 0x80000000},
+		{name: "lb", insn: encodeI(0x20, 1, 2, 0), rs: 0x1007, mem: 0x00000000_000000F0, expected: 0xFFFFFFFF_FFFFFFF0},
+		{name: "ld", insn: encodeI(0x37, 1, 2, 0), rs: 0x1000, mem: 0x01020304_05060708, expected: 0x01020304_05060708},
+		{name: "ldl", insn: encodeI(0x1A, 1, 2, 0), rs: 0x1003, rt: 0x11111111_11111111, mem: 0x01020304_05060708, expected: 0x04050607_08111111},
+		{name: "ldr", insn: encodeI(0x1B, 1, 2, 0), rs: 0x1003, rt: 0x11111111_11111111, mem: 0x01020304_05060708, expected: 0x11111111_01020304},
+		{name: "sw lower word", insn: encodeI(0x2b, 1, 2, 0), rs: 0x1004, rt: 0xAABBCCDD, mem: 0x01020304_05060708, expected: 0x01020304_AABBCCDD},
+		{name: "sb", insn: encodeI(0x28, 1, 2, 0), rs: 0x1001, rt: 0xFF, mem: 0x01020304_05060708, expected: 0x01FF0304_05060708},
+		{name: "sdl", insn: encodeI(0x2c, 1, 2, 0), rs: 0x1002, rt: 0xAABBCCDD_EEFF0011, mem: 0x01020304_05060708, expected: 0x0102AABB_CCDDEEFF},
+		{name: "sdr", insn: encodeI(0x2d, 1, 2, 0), rs: 0x1002, rt: 0xAABBCCDD_EEFF0011, mem: 0x01020304_05060708, expected: 0xFF001104_05060708},
+		{name: "dclz", insn: encodeR(1, 2, 3, 0, 0x24) | 0x1C<<26, rs: 1 << 40, expected: 23},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, execute64(c.insn, c.rs, c.rt, c.mem))
+		})
+	}
+
+	t.Run("dmult signed", func(t *testing.T) {
+		var cpu CpuScalars64
+		cpu.NextPC = 4
+		var registers [32]uint64
+		require.NoError(t, handleHiLo64(&cpu, &registers, 0x1c, ^uint64(0), 2, 0))
+		require.Equal(t, ^uint64(0), cpu.HI)
+		require.Equal(t, ^uint64(1), cpu.LO)
+	})
+}
+
+func TestMemory64MerkleRoot(t *testing.T) {
+	m := NewMemory64()
+	require.Equal(t, zeroHashes[64-5], m.MerkleRoot(), "fully zeroed memory should have expected zero hash")
+	m.SetMemory(0x10_00_00_00_00_00_00_00, 0)
+	require.Equal(t, zeroHashes[64-5], m.MerkleRoot(), "zero still")
+	m.SetMemory(0x10_00_00_00_00_00_00_08, 0xaabbccdd_eeff0011)
+	require.NotEqual(t, zeroHashes[64-5], m.MerkleRoot(), "non-zero")
+	require.Equal(t, uint64(0xaabbccdd_eeff0011), m.GetMemory(0x10_00_00_00_00_00_00_08))
+	proof := m.MerkleProof(0x10_00_00_00_00_00_00_08)
+	require.Equal(t, uint64(0xaabbccdd_eeff0011), binary.BigEndian.Uint64(proof[8:16]))
+	m.SetMemory(0x10_00_00_00_00_00_00_08, 0)
+	require.Equal(t, zeroHashes[64-5], m.MerkleRoot(), "zero again")
+}
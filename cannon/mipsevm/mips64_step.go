@@ -0,0 +1,181 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// MIPS64 syscalls use the n64 ABI numbering
+const (
+	sys64Read      = 5000
+	sys64Write     = 5001
+	sys64Mmap      = 5009
+	sys64Brk       = 5012
+	sys64Clone     = 5055
+	sys64Fcntl     = 5070
+	sys64ExitGroup = 5205
+)
+
+// sys64ErrorSignal is the MIPS64 equivalent of sysErrorSignal.
+const sys64ErrorSignal = ^uint64(0)
+
+func (m *MIPS64InstrumentedState) trackMemAccess(effAddr uint64) {
+	if m.memProofEnabled && m.lastMemAccess != effAddr {
+		if m.lastMemAccess != ^uint64(0) {
+			panic(fmt.Errorf("unexpected different mem access at %016x, already have access at %016x buffered", effAddr, m.lastMemAccess))
+		}
+		m.lastMemAccess = effAddr
+		m.memProof = m.state.Memory.MerkleProof(effAddr)
+	}
+}
+
+func (m *MIPS64InstrumentedState) handleSyscall() error {
+	syscallNum := m.state.Registers[2] // v0
+	a0 := m.state.Registers[4]
+	a1 := m.state.Registers[5]
+	a2 := m.state.Registers[6]
+
+	v0 := uint64(0)
+	v1 := uint64(0)
+
+	switch syscallNum {
+	case sys64Mmap:
+		sz := a1
+		if sz&PageAddrMask != 0 { // adjust size to align with page size
+			sz += PageSize - (sz & PageAddrMask)
+		}
+		if a0 == 0 {
+			v0 = m.state.Heap
+			m.state.Heap += sz
+		} else {
+			v0 = a0
+		}
+	case sys64Brk:
+		v0 = 0x40000000
+	case sys64Clone: // clone (not supported)
+		v0 = 1
+	case sys64ExitGroup:
+		m.state.Exited = true
+		m.state.ExitCode = uint8(a0)
+		return nil
+	case sys64Read:
+		v0, v1 = m.handleSysRead(a0, a1, a2)
+	case sys64Write:
+		v0, v1 = m.handleSysWrite(a0, a1, a2)
+	case sys64Fcntl:
+		r0, r1 := handleSysFcntl(uint32(a0), uint32(a1))
+		v0, v1 = signExtend32(r0), uint64(r1) // sign-extension preserves the error signal
+	}
+
+	m.state.Registers[2] = v0
+	m.state.Registers[7] = v1
+
+	m.state.PC = m.state.NextPC
+	m.state.NextPC = m.state.NextPC + 4
+	return nil
+}
+
+func (m *MIPS64InstrumentedState) handleSysRead(a0, a1, a2 uint64) (v0, v1 uint64) {
+	// args: a0 = fd, a1 = addr, a2 = count
+	// returns: v0 = read, v1 = err code
+	switch a0 {
+	case fdStdin:
+		// leave v0 and v1 zero: read nothing, no error
+	case fdPreimageRead: // pre-image oracle
+		effAddr := a1 &^ 7
+		m.trackMemAccess(effAddr)
+		mem := m.state.Memory.GetMemory(effAddr)
+		dat, datLen := m.readPreimage(m.state.PreimageKey, uint32(m.state.PreimageOffset))
+		alignment := a1 & 7
+		space := 8 - alignment
+		if space < uint64(datLen) {
+			datLen = uint32(space)
+		}
+		if a2 < uint64(datLen) {
+			datLen = uint32(a2)
+		}
+		var outMem [8]byte
+		binary.BigEndian.PutUint64(outMem[:], mem)
+		copy(outMem[alignment:], dat[:datLen])
+		m.state.Memory.SetMemory(effAddr, binary.BigEndian.Uint64(outMem[:]))
+		m.state.PreimageOffset += uint64(datLen)
+		v0 = uint64(datLen)
+	case fdHintRead: // hint response
+		// don't actually read into memory, just say we read it all, we ignore the result anyway
+		v0 = a2
+	default:
+		v0 = sys64ErrorSignal
+		v1 = MipsEBADF
+	}
+	return v0, v1
+}
+
+func (m *MIPS64InstrumentedState) handleSysWrite(a0, a1, a2 uint64) (v0, v1 uint64) {
+	// args: a0 = fd, a1 = addr, a2 = count
+	// returns: v0 = written, v1 = err code
+	switch a0 {
+	case fdStdout:
+		_, _ = io.Copy(m.stdOut, m.state.Memory.ReadMemoryRange(a1, a2))
+		v0 = a2
+	case fdStderr:
+		_, _ = io.Copy(m.stdErr, m.state.Memory.ReadMemoryRange(a1, a2))
+		v0 = a2
+	case fdHintWrite:
+		hintData, _ := io.ReadAll(m.state.Memory.ReadMemoryRange(a1, a2))
+		lastHint := append(m.state.LastHint, hintData...)
+		for len(lastHint) >= 4 { // process while there is enough data to check if there are any hints
+			hintLen := binary.BigEndian.Uint32(lastHint[:4])
+			if hintLen >= uint32(len(lastHint[4:])) {
+				hint := lastHint[4 : 4+hintLen] // without the length prefix
+				lastHint = lastHint[4+hintLen:]
+				m.preimageOracle.Hint(hint)
+			} else {
+				break // stop processing hints if there is incomplete data buffered
+			}
+		}
+		m.state.LastHint = lastHint
+		v0 = a2
+	case fdPreimageWrite:
+		effAddr := a1 &^ 7
+		m.trackMemAccess(effAddr)
+		mem := m.state.Memory.GetMemory(effAddr)
+		key := m.state.PreimageKey
+		alignment := a1 & 7
+		space := 8 - alignment
+		if space < a2 {
+			a2 = space
+		}
+		copy(key[:], key[a2:])
+		var tmp [8]byte
+		binary.BigEndian.PutUint64(tmp[:], mem)
+		copy(key[32-a2:], tmp[alignment:])
+		m.state.PreimageKey = key
+		m.state.PreimageOffset = 0
+		v0 = a2
+	default:
+		v0 = sys64ErrorSignal
+		v1 = MipsEBADF
+	}
+	return v0, v1
+}
+
+func (m *MIPS64InstrumentedState) mipsStep() error {
+	if m.state.Exited {
+		return nil
+	}
+	m.state.Step += 1
+	// instruction fetch
+	insn, opcode, fun := getInstructionDetails64(m.state.PC, m.state.Memory)
+
+	// syscall (can read and write)
+	if opcode == 0 && fun == 0xC {
+		return m.handleSyscall()
+	}
+
+	// Exec the rest of the step logic
+	cpu := m.state.cpu()
+	err := execMips64CoreStepLogic(&cpu, &m.state.Registers, m.state.Memory, insn, opcode, fun, m.trackMemAccess)
+	m.state.setCpu(cpu)
+	return err
+}
@@ -0,0 +1,219 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	sysMmap      = 4090
+	sysBrk       = 4045
+	sysClone     = 4120
+	sysExitGroup = 4246
+	sysRead      = 4003
+	sysWrite     = 4004
+	sysFcntl     = 4055
+
+	// syscalls only supported by the multi-threaded VM
+	sysExit         = 4001
+	sysSchedYield   = 4162
+	sysNanosleep    = 4166
+	sysGetTID       = 4222
+	sysFutex        = 4238
+	sysClockGetTime = 4263
+)
+
+// sysErrorSignal is the v0 value to return when a syscall fails, the error code is returned in v1.
+const sysErrorSignal = ^uint32(0)
+
+// preimageTracker reads pre-images from the oracle, and remembers what was read,
+// so it can be included in the step witness.
+type preimageTracker struct {
+	preimageOracle PreimageOracle
+
+	// cached pre-image data, including 8 byte length prefix
+	lastPreimage []byte
+	// key for above preimage
+	lastPreimageKey [32]byte
+	// offset we last read from, or max uint32 if nothing is read this step
+	lastPreimageOffset uint32
+}
+
+func (p *preimageTracker) readPreimage(key [32]byte, offset uint32) (dat [32]byte, datLen uint32) {
+	preimage := p.lastPreimage
+	if key != p.lastPreimageKey {
+		p.lastPreimageKey = key
+		data := p.preimageOracle.GetPreimage(key)
+		// add the length prefix
+		preimage = make([]byte, 0, 8+len(data))
+		preimage = binary.BigEndian.AppendUint64(preimage, uint64(len(data)))
+		preimage = append(preimage, data...)
+		p.lastPreimage = preimage
+	}
+	p.lastPreimageOffset = offset
+	datLen = uint32(copy(dat[:], preimage[offset:]))
+	return
+}
+
+func getSyscallArgs(registers *[32]uint32) (syscallNum, a0, a1, a2, a3 uint32) {
+	syscallNum = registers[2] // v0
+
+	a0 = registers[4]
+	a1 = registers[5]
+	a2 = registers[6]
+	a3 = registers[7]
+
+	return syscallNum, a0, a1, a2, a3
+}
+
+func handleSysMmap(a0, a1, heap uint32) (v0, v1, newHeap uint32) {
+	v1 = uint32(0)
+	newHeap = heap
+
+	sz := a1
+	if sz&PageAddrMask != 0 { // adjust size to align with page size
+		sz += PageSize - (sz & PageAddrMask)
+	}
+	if a0 == 0 {
+		v0 = heap
+		//fmt.Printf("mmap heap 0x%x size 0x%x\n", v0, sz)
+		newHeap += sz
+	} else {
+		v0 = a0
+		//fmt.Printf("mmap hint 0x%x size 0x%x\n", v0, sz)
+	}
+
+	return v0, v1, newHeap
+}
+
+func handleSysRead(a0, a1, a2 uint32, preimageKey [32]byte, preimageOffset uint32, pt *preimageTracker, memory *Memory, memTracker MemTracker) (v0, v1, newPreimageOffset uint32) {
+	// args: a0 = fd, a1 = addr, a2 = count
+	// returns: v0 = read, v1 = err code
+	v0 = uint32(0)
+	v1 = uint32(0)
+	newPreimageOffset = preimageOffset
+
+	switch a0 {
+	case fdStdin:
+		// leave v0 and v1 zero: read nothing, no error
+	case fdPreimageRead: // pre-image oracle
+		effAddr := a1 & 0xFFffFFfc
+		memTracker(effAddr)
+		mem := memory.GetMemory(effAddr)
+		dat, datLen := pt.readPreimage(preimageKey, preimageOffset)
+		//fmt.Printf("reading pre-image data: addr: %08x, offset: %d, datLen: %d, data: %x, key: %s  count: %d\n", a1, preimageOffset, datLen, dat[:datLen], preimageKey, a2)
+		alignment := a1 & 3
+		space := 4 - alignment
+		if space < datLen {
+			datLen = space
+		}
+		if a2 < datLen {
+			datLen = a2
+		}
+		var outMem [4]byte
+		binary.BigEndian.PutUint32(outMem[:], mem)
+		copy(outMem[alignment:], dat[:datLen])
+		memory.SetMemory(effAddr, binary.BigEndian.Uint32(outMem[:]))
+		newPreimageOffset += datLen
+		v0 = datLen
+		//fmt.Printf("read %d pre-image bytes, new offset: %d, eff addr: %08x mem: %08x\n", datLen, newPreimageOffset, effAddr, outMem)
+	case fdHintRead: // hint response
+		// don't actually read into memory, just say we read it all, we ignore the result anyway
+		v0 = a2
+	default:
+		v0 = sysErrorSignal
+		v1 = MipsEBADF
+	}
+
+	return v0, v1, newPreimageOffset
+}
+
+func handleSysWrite(a0, a1, a2 uint32, lastHint hexutil.Bytes, preimageKey [32]byte, preimageOffset uint32, oracle PreimageOracle, memory *Memory, memTracker MemTracker, stdOut, stdErr io.Writer) (v0, v1 uint32, newLastHint hexutil.Bytes, newPreimageKey common.Hash, newPreimageOffset uint32) {
+	// args: a0 = fd, a1 = addr, a2 = count
+	// returns: v0 = written, v1 = err code
+	v1 = uint32(0)
+	newLastHint = lastHint
+	newPreimageKey = preimageKey
+	newPreimageOffset = preimageOffset
+
+	switch a0 {
+	case fdStdout:
+		_, _ = io.Copy(stdOut, memory.ReadMemoryRange(a1, a2))
+		v0 = a2
+	case fdStderr:
+		_, _ = io.Copy(stdErr, memory.ReadMemoryRange(a1, a2))
+		v0 = a2
+	case fdHintWrite:
+		hintData, _ := io.ReadAll(memory.ReadMemoryRange(a1, a2))
+		lastHint = append(lastHint, hintData...)
+		for len(lastHint) >= 4 { // process while there is enough data to check if there are any hints
+			hintLen := binary.BigEndian.Uint32(lastHint[:4])
+			if hintLen >= uint32(len(lastHint[4:])) {
+				hint := lastHint[4 : 4+hintLen] // without the length prefix
+				lastHint = lastHint[4+hintLen:]
+				oracle.Hint(hint)
+			} else {
+				break // stop processing hints if there is incomplete data buffered
+			}
+		}
+		newLastHint = lastHint
+		v0 = a2
+	case fdPreimageWrite:
+		effAddr := a1 & 0xFFffFFfc
+		memTracker(effAddr)
+		mem := memory.GetMemory(effAddr)
+		key := preimageKey
+		alignment := a1 & 3
+		space := 4 - alignment
+		if space < a2 {
+			a2 = space
+		}
+		copy(key[:], key[a2:])
+		var tmp [4]byte
+		binary.BigEndian.PutUint32(tmp[:], mem)
+		copy(key[32-a2:], tmp[alignment:])
+		newPreimageKey = key
+		newPreimageOffset = 0
+		//fmt.Printf("updating pre-image key: %s\n", newPreimageKey)
+		v0 = a2
+	default:
+		v0 = sysErrorSignal
+		v1 = MipsEBADF
+	}
+
+	return v0, v1, newLastHint, newPreimageKey, newPreimageOffset
+}
+
+func handleSysFcntl(a0, a1 uint32) (v0, v1 uint32) {
+	// args: a0 = fd, a1 = cmd
+	v1 = uint32(0)
+
+	if a1 == 3 { // F_GETFL: get file descriptor flags
+		switch a0 {
+		case fdStdin, fdPreimageRead, fdHintRead:
+			v0 = 0 // O_RDONLY
+		case fdStdout, fdStderr, fdPreimageWrite, fdHintWrite:
+			v0 = 1 // O_WRONLY
+		default:
+			v0 = sysErrorSignal
+			v1 = MipsEBADF
+		}
+	} else {
+		v0 = sysErrorSignal
+		v1 = MipsEINVAL // cmd not recognized by this kernel
+	}
+
+	return v0, v1
+}
+
+// handleSyscallUpdates writes the syscall results, and moves the PC past the syscall instruction.
+func handleSyscallUpdates(cpu *CpuScalars, registers *[32]uint32, v0, v1 uint32) {
+	registers[2] = v0
+	registers[7] = v1
+
+	cpu.PC = cpu.NextPC
+	cpu.NextPC = cpu.NextPC + 4
+}
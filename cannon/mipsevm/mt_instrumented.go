@@ -0,0 +1,94 @@
+package mipsevm
+
+import (
+	"fmt"
+	"io"
+)
+
+var _ FPVM = (*MTInstrumentedState)(nil)
+
+// MTInstrumentedState is the instrumented multi-threaded VM.
+type MTInstrumentedState struct {
+	state *MTState
+
+	stdOut io.Writer
+	stdErr io.Writer
+
+	// a step may access up to two distinct memory locations (besides the instruction itself)
+	lastMemAccess   uint32
+	lastMemAccess2  uint32
+	memProofEnabled bool
+	memProof        [28 * 32]byte
+	memProof2       [28 * 32]byte
+
+	preimageTracker
+}
+
+func NewMTInstrumentedState(state *MTState, po PreimageOracle, stdOut, stdErr io.Writer) *MTInstrumentedState {
+	return &MTInstrumentedState{
+		state:           state,
+		stdOut:          stdOut,
+		stdErr:          stdErr,
+		preimageTracker: preimageTracker{preimageOracle: po},
+	}
+}
+
+func (m *MTInstrumentedState) GetState() VMState {
+	return m.state
+}
+
+// Step executes a single step of the multi-threaded VM.
+// The proof data of the witness consists of the active thread witness,
+// the root of the remainder of the active thread stack, the instruction memory proof,
+// and two memory proofs for any other memory accesses.
+func (m *MTInstrumentedState) Step(proof bool) (wit *StepWitness, err error) {
+	m.memProofEnabled = proof
+	m.lastMemAccess = ^uint32(0)
+	m.lastMemAccess2 = ^uint32(0)
+	m.lastPreimageOffset = ^uint32(0)
+
+	if proof {
+		m.memProof = [28 * 32]byte{}
+		m.memProof2 = [28 * 32]byte{}
+		proofData := m.state.EncodeThreadProof()
+		insnProof := m.state.Memory.MerkleProof(m.state.GetPC())
+		proofData = append(proofData, insnProof[:]...)
+		wit = &StepWitness{
+			State:     m.state.EncodeWitness(),
+			ProofData: proofData,
+		}
+	}
+	err = m.mipsStep()
+	if err != nil {
+		return nil, err
+	}
+
+	if proof {
+		wit.ProofData = append(wit.ProofData, m.memProof[:]...)
+		wit.ProofData = append(wit.ProofData, m.memProof2[:]...)
+		if m.lastPreimageOffset != ^uint32(0) {
+			wit.PreimageOffset = m.lastPreimageOffset
+			wit.PreimageKey = m.lastPreimageKey
+			wit.PreimageValue = m.lastPreimage
+		}
+	}
+	return
+}
+
+func (m *MTInstrumentedState) trackMemAccess(effAddr uint32) {
+	if !m.memProofEnabled || m.lastMemAccess == effAddr || m.lastMemAccess2 == effAddr {
+		return
+	}
+	if m.lastMemAccess == ^uint32(0) {
+		m.lastMemAccess = effAddr
+		m.memProof = m.state.Memory.MerkleProof(effAddr)
+		return
+	}
+	if m.lastMemAccess2 == ^uint32(0) {
+		m.lastMemAccess2 = effAddr
+		m.memProof2 = m.state.Memory.MerkleProof(effAddr)
+		return
+	}
+	panic(fmt.Errorf("unexpected different mem access at %08x, already have access at %08x and %08x buffered",
+		effAddr, m.lastMemAccess, m.lastMemAccess2))
+}
@@ -0,0 +1,241 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+// MTStateWitnessSize is the size of the multi-threaded state witness encoding in bytes.
+const MTStateWitnessSize = 172
+
+// mtExitCodeWitnessOffset is the offset of the exit code in the multi-threaded state witness,
+// directly followed by the exited flag.
+const mtExitCodeWitnessOffset = 32 + 32 + 4 + 4 + 1 + 4 + 4
+
+var _ FPVMState = (*MTState)(nil)
+
+// MTState is the state of the multi-threaded VM.
+//
+// Threads are kept in two stacks. The active thread is the top of the stack that is currently being traversed,
+// and is moved to the top of the other stack when it is preempted.
+// When the current stack runs empty, the traversal direction flips.
+// This round-robin schedule only has to commit to the two stack roots and the traversal direction.
+type MTState struct {
+	Memory *Memory `json:"memory"`
+
+	PreimageKey    common.Hash `json:"preimageKey"`
+	PreimageOffset uint32      `json:"preimageOffset"` // note that the offset includes the 8-byte length prefix
+
+	Heap uint32 `json:"heap"` // to handle mmap growth
+
+	// LLReservationActive is set by a load-linked instruction, and cleared by any write to LLAddress,
+	// so a store-conditional can detect whether another thread interfered.
+	LLReservationActive bool   `json:"llReservationActive"`
+	LLAddress           uint32 `json:"llAddress"`
+	LLOwnerThread       uint32 `json:"llOwnerThread"`
+
+	ExitCode uint8 `json:"exit"`
+	Exited   bool  `json:"exited"`
+
+	Step                        uint64 `json:"step"`
+	StepsSinceLastContextSwitch uint64 `json:"stepsSinceLastContextSwitch"`
+
+	// Wakeup is the futex address threads are being woken up for, or FutexEmptyAddr if no wakeup is in progress.
+	Wakeup uint32 `json:"wakeup"`
+
+	TraverseRight    bool           `json:"traverseRight"`
+	LeftThreadStack  []*ThreadState `json:"leftThreadStack"`
+	RightThreadStack []*ThreadState `json:"rightThreadStack"`
+	NextThreadID     uint32         `json:"nextThreadId"`
+
+	// LastHint is optional metadata, and not part of the VM state itself.
+	// See State.LastHint.
+	LastHint hexutil.Bytes `json:"lastHint,omitempty"`
+}
+
+// NewMTState creates a multi-threaded state with a single thread, starting at the given pc.
+func NewMTState(pc uint32, heap uint32) *MTState {
+	initThread := &ThreadState{
+		ThreadID:  0,
+		FutexAddr: FutexEmptyAddr,
+		Cpu: CpuScalars{
+			PC:     pc,
+			NextPC: pc + 4,
+		},
+	}
+	return &MTState{
+		Memory:           NewMemory(),
+		Heap:             heap,
+		Wakeup:           FutexEmptyAddr,
+		TraverseRight:    false,
+		LeftThreadStack:  []*ThreadState{initThread},
+		RightThreadStack: []*ThreadState{},
+		NextThreadID:     initThread.ThreadID + 1,
+	}
+}
+
+// ConvertToMTState converts a single-threaded state into a multi-threaded state, with the same single thread.
+func ConvertToMTState(st *State) *MTState {
+	mt := NewMTState(st.PC, st.Heap)
+	mt.Memory = st.Memory
+	mt.PreimageKey = st.PreimageKey
+	mt.PreimageOffset = st.PreimageOffset
+	mt.ExitCode = st.ExitCode
+	mt.Exited = st.Exited
+	mt.Step = st.Step
+	mt.LastHint = st.LastHint
+	thread := mt.getCurrentThread()
+	thread.Cpu = st.cpu()
+	thread.Registers = st.Registers
+	return mt
+}
+
+func (s *MTState) getActiveThreadStack() []*ThreadState {
+	if s.TraverseRight {
+		return s.RightThreadStack
+	}
+	return s.LeftThreadStack
+}
+
+func (s *MTState) getCurrentThread() *ThreadState {
+	activeStack := s.getActiveThreadStack()
+	if len(activeStack) == 0 {
+		panic("active thread stack is empty")
+	}
+	return activeStack[len(activeStack)-1]
+}
+
+// ThreadCount returns the number of threads, including exited threads that were not cleaned up yet.
+func (s *MTState) ThreadCount() int {
+	return len(s.LeftThreadStack) + len(s.RightThreadStack)
+}
+
+func (s *MTState) VMStatus() uint8 {
+	return vmStatus(s.Exited, s.ExitCode)
+}
+
+func (s *MTState) GetMemory() *Memory { return s.Memory }
+
+func (s *MTState) GetPC() uint32 { return s.getCurrentThread().Cpu.PC }
+
+func (s *MTState) GetRegisters() *[32]uint32 { return &s.getCurrentThread().Registers }
+
+func (s *MTState) GetStep() uint64 { return s.Step }
+
+func (s *MTState) GetExited() bool { return s.Exited }
+
+func (s *MTState) GetExitCode() uint8 { return s.ExitCode }
+
+func (s *MTState) GetLastHint() hexutil.Bytes { return s.LastHint }
+
+func (s *MTState) EncodeWitness() StateWitness {
+	out := make([]byte, 0, MTStateWitnessSize)
+	memRoot := s.Memory.MerkleRoot()
+	out = append(out, memRoot[:]...)
+	out = append(out, s.PreimageKey[:]...)
+	out = binary.BigEndian.AppendUint32(out, s.PreimageOffset)
+	out = binary.BigEndian.AppendUint32(out, s.Heap)
+	out = appendBool(out, s.LLReservationActive)
+	out = binary.BigEndian.AppendUint32(out, s.LLAddress)
+	out = binary.BigEndian.AppendUint32(out, s.LLOwnerThread)
+	out = append(out, s.ExitCode)
+	out = appendBool(out, s.Exited)
+	out = binary.BigEndian.AppendUint64(out, s.Step)
+	out = binary.BigEndian.AppendUint64(out, s.StepsSinceLastContextSwitch)
+	out = binary.BigEndian.AppendUint32(out, s.Wakeup)
+	out = appendBool(out, s.TraverseRight)
+	leftRoot := threadStackRoot(s.LeftThreadStack)
+	out = append(out, leftRoot[:]...)
+	rightRoot := threadStackRoot(s.RightThreadStack)
+	out = append(out, rightRoot[:]...)
+	out = binary.BigEndian.AppendUint32(out, s.NextThreadID)
+	return out
+}
+
+// Serialize writes the state in the binary format: the version byte, the memory,
+// the fields of the state in witness order with the full thread stacks instead of their roots,
+// and the length-prefixed last hint.
+func (s *MTState) Serialize(out io.Writer) error {
+	w := serialize.NewBinaryWriter(out)
+	if err := w.WriteFixed(mtStateBinaryVersion); err != nil {
+		return err
+	}
+	if err := s.Memory.Serialize(out); err != nil {
+		return err
+	}
+	for _, v := range []any{s.PreimageKey, s.PreimageOffset, s.Heap, s.LLReservationActive, s.LLAddress, s.LLOwnerThread,
+		s.ExitCode, s.Exited, s.Step, s.StepsSinceLastContextSwitch, s.Wakeup, s.TraverseRight} {
+		if err := w.WriteFixed(v); err != nil {
+			return err
+		}
+	}
+	for _, stack := range [][]*ThreadState{s.LeftThreadStack, s.RightThreadStack} {
+		if err := w.WriteFixed(uint32(len(stack))); err != nil {
+			return err
+		}
+		for _, t := range stack {
+			if err := w.WriteFixed(t); err != nil {
+				return err
+			}
+		}
+	}
+	if err := w.WriteFixed(s.NextThreadID); err != nil {
+		return err
+	}
+	return w.WriteBytes(s.LastHint)
+}
+
+func (s *MTState) Deserialize(in io.Reader) error {
+	r := serialize.NewBinaryReader(in)
+	if err := readBinaryVersion(r, mtStateBinaryVersion); err != nil {
+		return err
+	}
+	s.Memory = NewMemory()
+	if err := s.Memory.Deserialize(in); err != nil {
+		return err
+	}
+	for _, v := range []any{&s.PreimageKey, &s.PreimageOffset, &s.Heap, &s.LLReservationActive, &s.LLAddress, &s.LLOwnerThread,
+		&s.ExitCode, &s.Exited, &s.Step, &s.StepsSinceLastContextSwitch, &s.Wakeup, &s.TraverseRight} {
+		if err := r.ReadFixed(v); err != nil {
+			return err
+		}
+	}
+	for _, stack := range []*[]*ThreadState{&s.LeftThreadStack, &s.RightThreadStack} {
+		var count uint32
+		if err := r.ReadFixed(&count); err != nil {
+			return err
+		}
+		*stack = make([]*ThreadState, 0, count)
+		for i := uint32(0); i < count; i++ {
+			t := new(ThreadState)
+			if err := r.ReadFixed(t); err != nil {
+				return err
+			}
+			*stack = append(*stack, t)
+		}
+	}
+	if err := r.ReadFixed(&s.NextThreadID); err != nil {
+		return err
+	}
+	return r.ReadBytes((*[]byte)(&s.LastHint))
+}
+
+// EncodeThreadProof encodes the witness of the active thread,
+// followed by the root of the remainder of the active thread stack.
+func (s *MTState) EncodeThreadProof() []byte {
+	activeStack := s.getActiveThreadStack()
+	if len(activeStack) == 0 {
+		panic("active thread stack is empty")
+	}
+	activeThread := activeStack[len(activeStack)-1]
+	innerRoot := threadStackRoot(activeStack[:len(activeStack)-1])
+	out := make([]byte, 0, ThreadWitnessSize+32)
+	out = append(out, activeThread.EncodeWitness()...)
+	out = append(out, innerRoot[:]...)
+	return out
+}
@@ -0,0 +1,113 @@
+package mipsevm
+
+import (
+	"bytes"
+	"debug/elf"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMTStateHash(t *testing.T) {
+	cases := []struct {
+		exited   bool
+		exitCode uint8
+	}{
+		{exited: false, exitCode: 0},
+		{exited: false, exitCode: 1},
+		{exited: true, exitCode: 0},
+		{exited: true, exitCode: 1},
+		{exited: true, exitCode: 2},
+	}
+
+	for _, c := range cases {
+		state := NewMTState(0, heapStart)
+		state.Exited = c.exited
+		state.ExitCode = c.exitCode
+
+		witness := state.EncodeWitness()
+		require.Len(t, witness, MTStateWitnessSize, "incorrect witness size")
+		require.Equal(t, c.exitCode, witness[mtExitCodeWitnessOffset])
+		require.Equal(t, c.exited, witness[mtExitCodeWitnessOffset+1] == 1)
+
+		stateHash, err := witness.StateHash()
+		require.NoError(t, err)
+		expectedStateHash := crypto.Keccak256Hash(witness)
+		expectedStateHash[0] = vmStatus(c.exited, c.exitCode)
+		require.Equal(t, expectedStateHash, stateHash, "incorrect state hash")
+	}
+}
+
+func TestMTStateWitnessThreadRoots(t *testing.T) {
+	state := NewMTState(0x1000, heapStart)
+	thread := state.getCurrentThread()
+
+	witness := state.EncodeWitness()
+	leftRoot := []byte(witness[104:136])
+	rightRoot := []byte(witness[136:168])
+	require.Equal(t, ComputeThreadRoot(EmptyThreadsRoot, thread).Bytes(), leftRoot)
+	require.Equal(t, EmptyThreadsRoot.Bytes(), rightRoot)
+
+	second := &ThreadState{ThreadID: 1, FutexAddr: FutexEmptyAddr}
+	state.LeftThreadStack = append(state.LeftThreadStack, second)
+	witness = state.EncodeWitness()
+	expected := ComputeThreadRoot(ComputeThreadRoot(EmptyThreadsRoot, thread), second)
+	require.Equal(t, expected.Bytes(), []byte(witness[104:136]))
+
+	// the thread proof holds the active thread, and the root of the threads below it
+	threadProof := state.EncodeThreadProof()
+	require.Len(t, threadProof, ThreadWitnessSize+32)
+	require.Equal(t, second.EncodeWitness(), threadProof[:ThreadWitnessSize])
+	require.Equal(t, ComputeThreadRoot(EmptyThreadsRoot, thread).Bytes(), threadProof[ThreadWitnessSize:])
+}
+
+func TestConvertToMTState(t *testing.T) {
+	st := &State{PC: 0x100, NextPC: 0x104, LO: 1, HI: 2, Heap: 0x3000, Step: 42, Memory: NewMemory()}
+	st.Registers[29] = 0x7000
+	st.Memory.SetMemory(0x100, 0xaabbccdd)
+
+	mt := ConvertToMTState(st)
+	require.Equal(t, st.Memory.MerkleRoot(), mt.Memory.MerkleRoot())
+	require.Equal(t, uint32(0x100), mt.GetPC())
+	require.Equal(t, st.Registers, *mt.GetRegisters())
+	require.Equal(t, CpuScalars{PC: 0x100, NextPC: 0x104, LO: 1, HI: 2}, mt.getCurrentThread().Cpu)
+	require.Equal(t, st.Heap, mt.Heap)
+	require.Equal(t, st.Step, mt.GetStep())
+	require.Equal(t, 1, mt.ThreadCount())
+}
+
+func TestMTMultithreaded(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/multithreaded.elf")
+	require.NoError(t, err, "open ELF file")
+
+	state, err := LoadELF(elfProgram, NewMTState)
+	require.NoError(t, err, "load ELF into state")
+
+	err = PatchGo(elfProgram, state)
+	require.NoError(t, err, "apply Go runtime patches")
+	require.NoError(t, PatchStack(state), "add initial stack")
+
+	var stdOutBuf, stdErrBuf bytes.Buffer
+	us := NewMTInstrumentedState(state, nil, io.MultiWriter(&stdOutBuf, os.Stdout), io.MultiWriter(&stdErrBuf, os.Stderr))
+
+	maxThreads := 0
+	for i := 0; i < 20_000_000; i++ {
+		if state.Exited {
+			break
+		}
+		_, err := us.Step(false)
+		require.NoError(t, err)
+		if c := state.ThreadCount(); c > maxThreads {
+			maxThreads = c
+		}
+	}
+	t.Logf("steps: %d, max threads: %d", state.Step, maxThreads)
+
+	require.True(t, state.Exited, "must complete program")
+	require.Equal(t, uint8(0), state.ExitCode, "exit with 0")
+	require.Equal(t, "counter: 40\nGC complete!\n", stdOutBuf.String())
+	require.Equal(t, "", stdErrBuf.String(), "stderr silent")
+}
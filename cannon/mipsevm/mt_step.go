@@ -0,0 +1,352 @@
+package mipsevm
+
+const (
+	// SchedQuantum is the number of steps a thread may run for before it is preempted.
+	SchedQuantum = 100_000
+	// FutexTimeoutSteps is the number of steps after which a futex wait with a timeout expires.
+	FutexTimeoutSteps = 10_000
+	// HZ is the number of steps per second of the monotonic clock, as observed by the program.
+	HZ = 10_000_000
+)
+
+const (
+	futexWaitPrivate = 128
+	futexWakePrivate = 129
+	futexPrivateFlag = 128
+
+	clockRealtime  = 0
+	clockMonotonic = 1
+
+	// validCloneFlags are the clone flags used by the Go runtime to create threads:
+	// CLONE_VM | CLONE_FS | CLONE_FILES | CLONE_SIGHAND | CLONE_SYSVSEM | CLONE_THREAD
+	validCloneFlags = 0x00050f00
+
+	mipsOpStoreMinimum = 0x28
+	mipsOpLoadLinked   = 0x30
+	mipsOpStoreCond    = 0x38
+)
+
+func (m *MTInstrumentedState) mipsStep() error {
+	if m.state.Exited {
+		return nil
+	}
+	m.state.Step += 1
+	thread := m.state.getCurrentThread()
+
+	// While a wakeup is in progress, no thread executes,
+	// the threads are traversed until one is found that waits on the wakeup address.
+	if m.state.Wakeup != FutexEmptyAddr {
+		if thread.FutexAddr == m.state.Wakeup {
+			// completed wake traversal, resume execution on the woken up thread
+			m.state.Wakeup = FutexEmptyAddr
+			return nil
+		}
+		traversingRight := m.state.TraverseRight
+		changedDirections := m.preemptThread(thread)
+		if traversingRight && changedDirections {
+			// we went all the way around, stop traversal
+			m.state.Wakeup = FutexEmptyAddr
+		}
+		return nil
+	}
+
+	if thread.Exited {
+		m.popThread()
+		return nil
+	}
+
+	// check if the thread is waiting on a futex
+	if thread.FutexAddr != FutexEmptyAddr {
+		if m.state.Step > thread.FutexTimeoutStep {
+			m.onWaitComplete(thread, true)
+			return nil
+		}
+		m.trackMemAccess(thread.FutexAddr)
+		mem := m.state.Memory.GetMemory(thread.FutexAddr)
+		if thread.FutexVal == mem {
+			// still got the expected value, continue sleeping, try the next thread
+			m.preemptThread(thread)
+		} else {
+			// wake the thread up, the value at its address changed.
+			// Userspace can put the thread back to sleep if the wakeup was spurious.
+			m.onWaitComplete(thread, false)
+		}
+		return nil
+	}
+
+	if m.state.StepsSinceLastContextSwitch >= SchedQuantum {
+		// force a context switch, this thread has been active for too long
+		m.preemptThread(thread)
+		return nil
+	}
+	m.state.StepsSinceLastContextSwitch += 1
+
+	// instruction fetch
+	insn, opcode, fun := getInstructionDetails(thread.Cpu.PC, m.state.Memory)
+
+	// syscall (can read and write)
+	if opcode == 0 && fun == 0xC {
+		return m.handleSyscall(thread)
+	}
+
+	// load-linked and store-conditional are tracked with a reservation,
+	// since the thread may be preempted in between.
+	if opcode == mipsOpLoadLinked || opcode == mipsOpStoreCond {
+		return m.handleRMWOps(thread, insn, opcode)
+	}
+
+	if opcode >= mipsOpStoreMinimum && m.state.LLReservationActive {
+		base := thread.Registers[(insn>>21)&0x1F]
+		m.handleMemoryUpdate((base + SE(insn&0xFFFF, 16)) & 0xFFFFFFFC)
+	}
+
+	// Exec the rest of the step logic
+	return execMipsCoreStepLogic(&thread.Cpu, &thread.Registers, m.state.Memory, insn, opcode, fun, m.trackMemAccess)
+}
+
+func (m *MTInstrumentedState) handleSyscall(thread *ThreadState) error {
+	syscallNum, a0, a1, a2, a3 := getSyscallArgs(&thread.Registers)
+
+	v0 := uint32(0)
+	v1 := uint32(0)
+
+	switch syscallNum {
+	case sysMmap:
+		var newHeap uint32
+		v0, v1, newHeap = handleSysMmap(a0, a1, m.state.Heap)
+		m.state.Heap = newHeap
+	case sysBrk:
+		v0 = 0x40000000
+	case sysClone:
+		// args: a0 = flag bitmask, a1 = stack pointer
+		if a0 != validCloneFlags {
+			m.state.Exited = true
+			m.state.ExitCode = VMStatusPanic
+			return nil
+		}
+		newThread := &ThreadState{
+			ThreadID:  m.state.NextThreadID,
+			FutexAddr: FutexEmptyAddr,
+			Cpu: CpuScalars{
+				PC:     thread.Cpu.NextPC,
+				NextPC: thread.Cpu.NextPC + 4,
+				LO:     thread.Cpu.LO,
+				HI:     thread.Cpu.HI,
+			},
+			Registers: thread.Registers,
+		}
+		newThread.Registers[29] = a1
+		// the child perceives a 0 return value, and no error
+		newThread.Registers[2] = 0
+		newThread.Registers[7] = 0
+		m.state.NextThreadID++
+
+		// complete the syscall of the parent, before switching to the new thread
+		handleSyscallUpdates(&thread.Cpu, &thread.Registers, newThread.ThreadID, 0)
+		m.pushThread(newThread)
+		return nil
+	case sysExitGroup:
+		m.state.Exited = true
+		m.state.ExitCode = uint8(a0)
+		return nil
+	case sysExit:
+		thread.Exited = true
+		thread.ExitCode = uint8(a0)
+		if m.lastThreadRemaining() {
+			m.state.Exited = true
+			m.state.ExitCode = uint8(a0)
+		}
+		return nil
+	case sysRead:
+		var newPreimageOffset uint32
+		v0, v1, newPreimageOffset = handleSysRead(a0, a1, a2, m.state.PreimageKey, m.state.PreimageOffset, &m.preimageTracker, m.state.Memory, m.trackMemAccess)
+		m.state.PreimageOffset = newPreimageOffset
+		if a0 == fdPreimageRead {
+			m.handleMemoryUpdate(a1 & 0xFFffFFfc)
+		}
+	case sysWrite:
+		var newLastHint []byte
+		var newPreimageKey [32]byte
+		var newPreimageOffset uint32
+		v0, v1, newLastHint, newPreimageKey, newPreimageOffset = handleSysWrite(a0, a1, a2, m.state.LastHint, m.state.PreimageKey, m.state.PreimageOffset, m.preimageOracle, m.state.Memory, m.trackMemAccess, m.stdOut, m.stdErr)
+		m.state.LastHint = newLastHint
+		m.state.PreimageKey = newPreimageKey
+		m.state.PreimageOffset = newPreimageOffset
+	case sysFcntl:
+		v0, v1 = handleSysFcntl(a0, a1)
+	case sysFutex:
+		// args: a0 = addr, a1 = op, a2 = val, a3 = timeout
+		effAddr := a0 & 0xFFffFFfc
+		switch a1 | futexPrivateFlag {
+		case futexWaitPrivate:
+			m.trackMemAccess(effAddr)
+			mem := m.state.Memory.GetMemory(effAddr)
+			if mem != a2 {
+				v0 = sysErrorSignal
+				v1 = MipsEAGAIN
+			} else {
+				thread.FutexAddr = effAddr
+				thread.FutexVal = a2
+				if a3 == 0 {
+					thread.FutexTimeoutStep = FutexNoTimeout
+				} else {
+					thread.FutexTimeoutStep = m.state.Step + FutexTimeoutSteps
+				}
+				// The syscall is completed by onWaitComplete, once the thread wakes up or times out.
+				return nil
+			}
+		case futexWakePrivate:
+			// Start a traversal of the threads, starting with the left stack,
+			// until a thread is found that waits on the address.
+			m.state.Wakeup = effAddr
+			// There are no guarantees about how many threads are woken up, the woken up thread has to check in userspace.
+			handleSyscallUpdates(&thread.Cpu, &thread.Registers, 0, 0)
+			m.preemptThread(thread)
+			m.state.TraverseRight = len(m.state.LeftThreadStack) == 0
+			return nil
+		default:
+			v0 = sysErrorSignal
+			v1 = MipsEINVAL
+		}
+	case sysSchedYield, sysNanosleep:
+		// yield to the next thread, sleeping does not advance time other than through steps.
+		handleSyscallUpdates(&thread.Cpu, &thread.Registers, 0, 0)
+		m.preemptThread(thread)
+		return nil
+	case sysGetTID:
+		v0 = thread.ThreadID
+	case sysClockGetTime:
+		// args: a0 = clock id, a1 = timespec address
+		switch a0 {
+		case clockRealtime, clockMonotonic:
+			// The realtime clock is fixed to the unix epoch,
+			// the monotonic clock is derived from the step count, for deterministic scheduling of timers.
+			var secs, nsecs uint32
+			if a0 == clockMonotonic {
+				secs = uint32(m.state.Step / HZ)
+				nsecs = uint32((m.state.Step % HZ) * (1_000_000_000 / HZ))
+			}
+			effAddr := a1 & 0xFFffFFfc
+			m.trackMemAccess(effAddr)
+			m.state.Memory.SetMemory(effAddr, secs)
+			m.handleMemoryUpdate(effAddr)
+			m.trackMemAccess(effAddr + 4)
+			m.state.Memory.SetMemory(effAddr+4, nsecs)
+			m.handleMemoryUpdate(effAddr + 4)
+		default:
+			v0 = sysErrorSignal
+			v1 = MipsEINVAL
+		}
+	}
+
+	handleSyscallUpdates(&thread.Cpu, &thread.Registers, v0, v1)
+	return nil
+}
+
+func (m *MTInstrumentedState) handleRMWOps(thread *ThreadState, insn, opcode uint32) error {
+	base := thread.Registers[(insn>>21)&0x1F]
+	rtReg := (insn >> 16) & 0x1F
+	effAddr := (base + SE(insn&0xFFFF, 16)) & 0xFFFFFFFC
+	m.trackMemAccess(effAddr)
+	mem := m.state.Memory.GetMemory(effAddr)
+
+	var retVal uint32
+	if opcode == mipsOpLoadLinked {
+		retVal = mem
+		m.state.LLReservationActive = true
+		m.state.LLAddress = effAddr
+		m.state.LLOwnerThread = thread.ThreadID
+	} else if m.state.LLReservationActive && m.state.LLAddress == effAddr && m.state.LLOwnerThread == thread.ThreadID {
+		// store-conditional only succeeds if the reservation of this thread is still intact
+		m.clearLLReservation()
+		m.state.Memory.SetMemory(effAddr, thread.Registers[rtReg])
+		retVal = 1
+	}
+
+	return handleRd(&thread.Cpu, &thread.Registers, rtReg, retVal, true)
+}
+
+// handleMemoryUpdate clears the load-linked reservation if the given address is written to.
+func (m *MTInstrumentedState) handleMemoryUpdate(effAddr uint32) {
+	if m.state.LLReservationActive && m.state.LLAddress == effAddr {
+		m.clearLLReservation()
+	}
+}
+
+func (m *MTInstrumentedState) clearLLReservation() {
+	m.state.LLReservationActive = false
+	m.state.LLAddress = 0
+	m.state.LLOwnerThread = 0
+}
+
+func (m *MTInstrumentedState) onWaitComplete(thread *ThreadState, isTimedOut bool) {
+	thread.FutexAddr = FutexEmptyAddr
+	thread.FutexVal = 0
+	thread.FutexTimeoutStep = 0
+
+	// complete the futex wait syscall
+	v0 := uint32(0)
+	v1 := uint32(0)
+	if isTimedOut {
+		v0 = sysErrorSignal
+		v1 = MipsETIMEDOUT
+	}
+	handleSyscallUpdates(&thread.Cpu, &thread.Registers, v0, v1)
+}
+
+// preemptThread moves the active thread to the top of the other thread stack.
+// It returns true if the active stack ran empty, and the traversal direction changed.
+func (m *MTInstrumentedState) preemptThread(thread *ThreadState) bool {
+	if m.state.TraverseRight {
+		m.state.RightThreadStack = m.state.RightThreadStack[:len(m.state.RightThreadStack)-1]
+		m.state.LeftThreadStack = append(m.state.LeftThreadStack, thread)
+	} else {
+		m.state.LeftThreadStack = m.state.LeftThreadStack[:len(m.state.LeftThreadStack)-1]
+		m.state.RightThreadStack = append(m.state.RightThreadStack, thread)
+	}
+
+	changedDirections := false
+	if len(m.state.getActiveThreadStack()) == 0 {
+		m.state.TraverseRight = !m.state.TraverseRight
+		changedDirections = true
+	}
+
+	m.state.StepsSinceLastContextSwitch = 0
+	return changedDirections
+}
+
+// pushThread makes the given thread the active thread.
+func (m *MTInstrumentedState) pushThread(thread *ThreadState) {
+	if m.state.TraverseRight {
+		m.state.RightThreadStack = append(m.state.RightThreadStack, thread)
+	} else {
+		m.state.LeftThreadStack = append(m.state.LeftThreadStack, thread)
+	}
+	m.state.StepsSinceLastContextSwitch = 0
+}
+
+// popThread removes the active thread.
+func (m *MTInstrumentedState) popThread() {
+	if m.state.TraverseRight {
+		m.state.RightThreadStack = m.state.RightThreadStack[:len(m.state.RightThreadStack)-1]
+	} else {
+		m.state.LeftThreadStack = m.state.LeftThreadStack[:len(m.state.LeftThreadStack)-1]
+	}
+
+	if len(m.state.getActiveThreadStack()) == 0 {
+		m.state.TraverseRight = !m.state.TraverseRight
+	}
+	m.state.StepsSinceLastContextSwitch = 0
+}
+
+func (m *MTInstrumentedState) lastThreadRemaining() bool {
+	alive := 0
+	for _, stack := range [][]*ThreadState{m.state.LeftThreadStack, m.state.RightThreadStack} {
+		for _, t := range stack {
+			if !t.Exited {
+				alive++
+			}
+		}
+	}
+	return alive == 0
+}
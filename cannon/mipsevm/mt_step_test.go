@@ -0,0 +1,270 @@
+package mipsevm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newMTSyscallState creates a multi-threaded state, with the active thread about to execute a syscall.
+func newMTSyscallState(syscallNum uint32) *MTState {
+	state := NewMTState(0x1000, heapStart)
+	state.Memory.SetMemory(0x1000, syscallInsn)
+	state.GetRegisters()[2] = syscallNum
+	return state
+}
+
+func mtStep(t *testing.T, us *MTInstrumentedState) {
+	_, err := us.Step(true)
+	require.NoError(t, err)
+}
+
+func TestMTSyscallClone(t *testing.T) {
+	state := newMTSyscallState(sysClone)
+	state.GetRegisters()[4] = validCloneFlags
+	state.GetRegisters()[5] = 0x7000 // stack pointer of the new thread
+	us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+	mtStep(t, us)
+
+	require.Equal(t, 2, state.ThreadCount())
+	require.Equal(t, uint32(2), state.NextThreadID)
+	child := state.getCurrentThread()
+	require.Equal(t, uint32(1), child.ThreadID, "new thread is active")
+	require.Equal(t, uint32(0x1004), child.Cpu.PC)
+	require.Equal(t, uint32(0x1008), child.Cpu.NextPC)
+	require.Equal(t, uint32(0x7000), child.Registers[29])
+	require.Equal(t, uint32(0), child.Registers[2], "child sees 0 return value")
+
+	parent := state.LeftThreadStack[0]
+	require.Equal(t, uint32(0), parent.ThreadID)
+	require.Equal(t, uint32(0x1004), parent.Cpu.PC)
+	require.Equal(t, uint32(1), parent.Registers[2], "parent sees child thread ID")
+	require.Equal(t, uint32(0), parent.Registers[7])
+}
+
+func TestMTSyscallCloneUnsupportedFlags(t *testing.T) {
+	state := newMTSyscallState(sysClone)
+	state.GetRegisters()[4] = 0x11 // SIGCHLD, i.e. fork
+	us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+	mtStep(t, us)
+
+	require.True(t, state.Exited)
+	require.Equal(t, uint8(VMStatusPanic), state.ExitCode)
+}
+
+func TestMTSyscallYield(t *testing.T) {
+	for _, syscallNum := range []uint32{sysSchedYield, sysNanosleep} {
+		state := newMTSyscallState(syscallNum)
+		other := &ThreadState{ThreadID: 1, FutexAddr: FutexEmptyAddr}
+		state.LeftThreadStack = []*ThreadState{other, state.getCurrentThread()}
+		state.NextThreadID = 2
+		state.StepsSinceLastContextSwitch = 10
+		us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+		mtStep(t, us)
+
+		require.Equal(t, other, state.getCurrentThread(), "must switch to other thread")
+		require.Len(t, state.RightThreadStack, 1)
+		yielded := state.RightThreadStack[0]
+		require.Equal(t, uint32(0x1004), yielded.Cpu.PC, "syscall must complete")
+		require.Equal(t, uint32(0), yielded.Registers[2])
+		require.Equal(t, uint64(0), state.StepsSinceLastContextSwitch)
+	}
+}
+
+func TestMTFutexWaitWake(t *testing.T) {
+	const futexAddr = 0x2000
+	state := newMTSyscallState(sysFutex)
+	state.Memory.SetMemory(futexAddr, 42)
+	waiter := state.getCurrentThread()
+	waiter.Registers[4] = futexAddr
+	waiter.Registers[5] = futexWaitPrivate
+	waiter.Registers[6] = 42
+
+	// the waker stores to the futex address, and then wakes up the waiter
+	waker := &ThreadState{ThreadID: 1, FutexAddr: FutexEmptyAddr, Cpu: CpuScalars{PC: 0x3000, NextPC: 0x3004}}
+	waker.Registers[2] = sysFutex
+	waker.Registers[4] = futexAddr
+	waker.Registers[5] = futexWakePrivate
+	waker.Registers[8] = futexAddr
+	state.Memory.SetMemory(0x3000, 0xad000000) // sw $zero, 0($t0)
+	state.Memory.SetMemory(0x3004, syscallInsn)
+	state.LeftThreadStack = []*ThreadState{waker, waiter}
+	state.NextThreadID = 2
+	us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+
+	mtStep(t, us) // waiter goes to sleep
+	require.Equal(t, uint32(futexAddr), waiter.FutexAddr)
+	require.Equal(t, uint32(42), waiter.FutexVal)
+	require.Equal(t, FutexNoTimeout, waiter.FutexTimeoutStep)
+	require.Equal(t, uint32(0x1000), waiter.Cpu.PC, "syscall is not completed yet")
+
+	mtStep(t, us) // waiter is still asleep, and is preempted
+	require.Equal(t, waker, state.getCurrentThread())
+
+	mtStep(t, us) // waker stores to the futex address
+	mtStep(t, us) // waker wakes up the waiter
+	require.Equal(t, uint32(futexAddr), state.Wakeup)
+	require.Equal(t, uint32(0x3008), waker.Cpu.PC)
+
+	// traverse threads until the waiter is found
+	for i := 0; i < 4 && state.Wakeup != FutexEmptyAddr; i++ {
+		mtStep(t, us)
+	}
+	require.Equal(t, FutexEmptyAddr, state.Wakeup)
+	require.Equal(t, waiter, state.getCurrentThread())
+
+	mtStep(t, us) // waiter observes the changed value and completes the syscall
+	require.Equal(t, FutexEmptyAddr, waiter.FutexAddr)
+	require.Equal(t, uint32(0x1004), waiter.Cpu.PC)
+	require.Equal(t, uint32(0), waiter.Registers[2])
+	require.Equal(t, uint32(0), waiter.Registers[7])
+}
+
+func TestMTFutexWaitMismatch(t *testing.T) {
+	state := newMTSyscallState(sysFutex)
+	state.Memory.SetMemory(0x2000, 1)
+	state.GetRegisters()[4] = 0x2000
+	state.GetRegisters()[5] = futexWaitPrivate
+	state.GetRegisters()[6] = 2
+	us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+	mtStep(t, us)
+
+	thread := state.getCurrentThread()
+	require.Equal(t, FutexEmptyAddr, thread.FutexAddr)
+	require.Equal(t, uint32(0x1004), thread.Cpu.PC)
+	require.Equal(t, sysErrorSignal, thread.Registers[2])
+	require.Equal(t, uint32(MipsEAGAIN), thread.Registers[7])
+}
+
+func TestMTFutexTimeout(t *testing.T) {
+	state := newMTSyscallState(sysFutex)
+	state.GetRegisters()[4] = 0x2000
+	state.GetRegisters()[5] = futexWaitPrivate
+	state.GetRegisters()[6] = 0
+	state.GetRegisters()[7] = 0x2100 // timeout
+	us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+	mtStep(t, us)
+
+	thread := state.getCurrentThread()
+	require.Equal(t, uint64(1+FutexTimeoutSteps), thread.FutexTimeoutStep)
+	for state.Step < thread.FutexTimeoutStep {
+		mtStep(t, us)
+		require.Equal(t, uint32(0x1000), thread.Cpu.PC, "still asleep")
+	}
+	mtStep(t, us)
+	require.Equal(t, FutexEmptyAddr, thread.FutexAddr)
+	require.Equal(t, uint32(0x1004), thread.Cpu.PC)
+	require.Equal(t, sysErrorSignal, thread.Registers[2])
+	require.Equal(t, uint32(MipsETIMEDOUT), thread.Registers[7])
+}
+
+func TestMTSyscallExit(t *testing.T) {
+	state := newMTSyscallState(sysExit)
+	state.GetRegisters()[4] = 3
+	other := &ThreadState{ThreadID: 1, FutexAddr: FutexEmptyAddr, Cpu: CpuScalars{PC: 0x1000, NextPC: 0x1004}}
+	other.Registers[2] = sysExit
+	other.Registers[4] = 4
+	state.LeftThreadStack = []*ThreadState{other, state.getCurrentThread()}
+	state.NextThreadID = 2
+	us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+
+	mtStep(t, us) // first thread exits
+	require.False(t, state.Exited)
+	require.True(t, state.LeftThreadStack[1].Exited)
+
+	mtStep(t, us) // exited thread is removed
+	require.Equal(t, 1, state.ThreadCount())
+	require.Equal(t, other, state.getCurrentThread())
+
+	mtStep(t, us) // last thread exits, and with it the program
+	require.True(t, state.Exited)
+	require.Equal(t, uint8(4), state.ExitCode)
+}
+
+func TestMTPreemptAfterQuantum(t *testing.T) {
+	state := NewMTState(0x1000, heapStart)
+	state.Memory.SetMemory(0x1000, 0x1000ffff) // beq $zero, $zero, -1: loop forever
+	other := &ThreadState{ThreadID: 1, FutexAddr: FutexEmptyAddr}
+	state.LeftThreadStack = []*ThreadState{other, state.getCurrentThread()}
+	state.NextThreadID = 2
+	state.StepsSinceLastContextSwitch = SchedQuantum - 1
+	us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+
+	mtStep(t, us)
+	require.Equal(t, uint64(SchedQuantum), state.StepsSinceLastContextSwitch)
+	require.Equal(t, uint32(0), state.getCurrentThread().ThreadID)
+	mtStep(t, us)
+	require.Equal(t, uint64(0), state.StepsSinceLastContextSwitch)
+	require.Equal(t, other, state.getCurrentThread())
+}
+
+func TestMTLoadLinkedStoreConditional(t *testing.T) {
+	const addr = 0x2000
+	const llInsn = 0xc1090000 // ll $t1, 0($t0)
+	const scInsn = 0xe10a0000 // sc $t2, 0($t0)
+	const swInsn = 0xad0b0000 // sw $t3, 0($t0)
+
+	newState := func() *MTState {
+		state := NewMTState(0x1000, heapStart)
+		state.Memory.SetMemory(addr, 5)
+		state.Memory.SetMemory(0x1000, llInsn)
+		state.Memory.SetMemory(0x1004, scInsn)
+		state.GetRegisters()[8] = addr
+		state.GetRegisters()[10] = 6
+		return state
+	}
+
+	t.Run("success", func(t *testing.T) {
+		state := newState()
+		us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+		mtStep(t, us)
+		require.Equal(t, uint32(5), state.GetRegisters()[9])
+		require.True(t, state.LLReservationActive)
+		mtStep(t, us)
+		require.Equal(t, uint32(1), state.GetRegisters()[10], "sc must succeed")
+		require.Equal(t, uint32(6), state.Memory.GetMemory(addr))
+		require.False(t, state.LLReservationActive)
+	})
+
+	t.Run("interfering store", func(t *testing.T) {
+		state := newState()
+		other := &ThreadState{ThreadID: 1, FutexAddr: FutexEmptyAddr, Cpu: CpuScalars{PC: 0x3000, NextPC: 0x3004}}
+		other.Registers[8] = addr
+		other.Registers[11] = 7
+		state.Memory.SetMemory(0x3000, swInsn)
+		first := state.getCurrentThread()
+		state.LeftThreadStack = []*ThreadState{other, first}
+		state.NextThreadID = 2
+		us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+
+		mtStep(t, us) // ll
+		require.True(t, state.LLReservationActive)
+		state.StepsSinceLastContextSwitch = SchedQuantum
+		mtStep(t, us) // preempted, other thread is next
+		require.Equal(t, other, state.getCurrentThread())
+		mtStep(t, us) // store by other thread
+		require.False(t, state.LLReservationActive)
+		for state.getCurrentThread() != first {
+			state.StepsSinceLastContextSwitch = SchedQuantum
+			mtStep(t, us) // preempt until the first thread is scheduled again
+		}
+		mtStep(t, us) // sc
+		require.Equal(t, uint32(0), first.Registers[10], "sc must fail")
+		require.Equal(t, uint32(7), state.Memory.GetMemory(addr))
+	})
+}
+
+func TestMTSyscallClockGettime(t *testing.T) {
+	state := newMTSyscallState(sysClockGetTime)
+	state.Step = 3*HZ + 5
+	state.GetRegisters()[4] = clockMonotonic
+	state.GetRegisters()[5] = 0x2000
+	us := NewMTInstrumentedState(state, nil, os.Stdout, os.Stderr)
+	wit, err := us.Step(true)
+	require.NoError(t, err)
+
+	require.Equal(t, uint32(3), state.Memory.GetMemory(0x2000))
+	require.Equal(t, uint32(6*(1_000_000_000/HZ)), state.Memory.GetMemory(0x2004))
+	require.Len(t, wit.ProofData, ThreadWitnessSize+32+3*28*32, "thread proof, instruction proof and two memory proofs")
+}
@@ -0,0 +1,78 @@
+package mipsevm
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ThreadWitnessSize is the size of the thread witness encoding in bytes.
+const ThreadWitnessSize = 166
+
+// EmptyThreadsRoot is the root of an empty thread stack.
+var EmptyThreadsRoot = crypto.Keccak256Hash(make([]byte, 64))
+
+const (
+	// FutexEmptyAddr is the futex address of a thread that is not waiting on a futex.
+	FutexEmptyAddr = ^uint32(0)
+	// FutexNoTimeout is the futex timeout step of a thread that is waiting without a timeout.
+	FutexNoTimeout = ^uint64(0)
+)
+
+// ThreadState is the state of a single thread of the multi-threaded VM.
+type ThreadState struct {
+	ThreadID uint32 `json:"threadId"`
+	ExitCode uint8  `json:"exit"`
+	Exited   bool   `json:"exited"`
+
+	// FutexAddr is the address the thread is waiting on, or FutexEmptyAddr if it is not waiting.
+	FutexAddr uint32 `json:"futexAddr"`
+	// FutexVal is the value the thread expects at FutexAddr while it is asleep.
+	FutexVal uint32 `json:"futexVal"`
+	// FutexTimeoutStep is the step after which the wait times out.
+	FutexTimeoutStep uint64 `json:"futexTimeoutStep"`
+
+	Cpu       CpuScalars `json:"cpu"`
+	Registers [32]uint32 `json:"registers"`
+}
+
+func (t *ThreadState) EncodeWitness() []byte {
+	out := make([]byte, 0, ThreadWitnessSize)
+	out = binary.BigEndian.AppendUint32(out, t.ThreadID)
+	out = append(out, t.ExitCode)
+	out = appendBool(out, t.Exited)
+	out = binary.BigEndian.AppendUint32(out, t.FutexAddr)
+	out = binary.BigEndian.AppendUint32(out, t.FutexVal)
+	out = binary.BigEndian.AppendUint64(out, t.FutexTimeoutStep)
+	out = binary.BigEndian.AppendUint32(out, t.Cpu.PC)
+	out = binary.BigEndian.AppendUint32(out, t.Cpu.NextPC)
+	out = binary.BigEndian.AppendUint32(out, t.Cpu.LO)
+	out = binary.BigEndian.AppendUint32(out, t.Cpu.HI)
+	for _, r := range t.Registers {
+		out = binary.BigEndian.AppendUint32(out, r)
+	}
+	return out
+}
+
+// ComputeThreadRoot computes the root of a thread stack after pushing the given thread on top of it.
+func ComputeThreadRoot(prevStackRoot common.Hash, threadToPush *ThreadState) common.Hash {
+	hashedThread := crypto.Keccak256Hash(threadToPush.EncodeWitness())
+	return crypto.Keccak256Hash(prevStackRoot[:], hashedThread[:])
+}
+
+// threadStackRoot commits to a stack of threads, the last thread being the top of the stack.
+func threadStackRoot(stack []*ThreadState) common.Hash {
+	root := EmptyThreadsRoot
+	for _, t := range stack {
+		root = ComputeThreadRoot(root, t)
+	}
+	return root
+}
+
+func appendBool(out []byte, v bool) []byte {
+	if v {
+		return append(out, 1)
+	}
+	return append(out, 0)
+}
@@ -68,12 +68,15 @@ type CachedPage struct {
 	Cache [PageSize / 32][32]byte
 	// true if the intermediate node is valid
 	Ok [PageSize / 32]bool
+	// true if the page data was modified since the last time the dirty flags of the memory were reset
+	Dirty bool
 }
 
 func (p *CachedPage) Invalidate(pageAddr uint32) {
 	if pageAddr >= PageSize {
 		panic("invalid page addr")
 	}
+	p.Dirty = true
 	k := (1 << PageAddrSize) | pageAddr
 	// first cache layer caches nodes that has two 32 byte leaf nodes.
 	k >>= 5 + 1
@@ -84,6 +87,7 @@ func (p *CachedPage) Invalidate(pageAddr uint32) {
 }
 
 func (p *CachedPage) InvalidateFull() {
+	p.Dirty = true
 	p.Ok = [PageSize / 32]bool{} // reset everything to false
 }
 
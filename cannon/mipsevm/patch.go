@@ -8,19 +8,31 @@ import (
 	"io"
 )
 
-func LoadELF(f *elf.File) (*State, error) {
-	s := &State{
-		PC:        uint32(f.Entry),
-		NextPC:    uint32(f.Entry + 4),
+// heapStart is the start of the heap, at which mmap allocates memory.
+const heapStart = 0x20000000
+
+// CreateInitialFPVMState creates an empty VM state, with the program starting at the given pc.
+type CreateInitialFPVMState[T FPVMState] func(pc, heapStart uint32) T
+
+// CreateInitialState creates an empty single-threaded VM state.
+func CreateInitialState(pc, heapStart uint32) *State {
+	return &State{
+		PC:        pc,
+		NextPC:    pc + 4,
 		HI:        0,
 		LO:        0,
-		Heap:      0x20000000,
+		Heap:      heapStart,
 		Registers: [32]uint32{},
 		Memory:    NewMemory(),
 		ExitCode:  0,
 		Exited:    false,
 		Step:      0,
 	}
+}
+
+func LoadELF[T FPVMState](f *elf.File, initState CreateInitialFPVMState[T]) (T, error) {
+	var empty T
+	s := initState(uint32(f.Entry), heapStart)
 
 	for i, prog := range f.Progs {
 		if prog.Type == 0x70000003 { // MIPS_ABIFLAGS
@@ -33,33 +45,36 @@ func LoadELF(f *elf.File) (*State, error) {
 				if prog.Filesz < prog.Memsz {
 					r = io.MultiReader(r, bytes.NewReader(make([]byte, prog.Memsz-prog.Filesz)))
 				} else {
-					return nil, fmt.Errorf("invalid PT_LOAD program segment %d, file size (%d) > mem size (%d)", i, prog.Filesz, prog.Memsz)
+					return empty, fmt.Errorf("invalid PT_LOAD program segment %d, file size (%d) > mem size (%d)", i, prog.Filesz, prog.Memsz)
 				}
 			} else {
-				return nil, fmt.Errorf("program segment %d has different file size (%d) than mem size (%d): filling for non PT_LOAD segments is not supported", i, prog.Filesz, prog.Memsz)
+				return empty, fmt.Errorf("program segment %d has different file size (%d) than mem size (%d): filling for non PT_LOAD segments is not supported", i, prog.Filesz, prog.Memsz)
 			}
 		}
 
 		if prog.Vaddr+prog.Memsz >= uint64(1<<32) {
-			return nil, fmt.Errorf("program %d out of 32-bit mem range: %x - %x (size: %x)", i, prog.Vaddr, prog.Vaddr+prog.Memsz, prog.Memsz)
+			return empty, fmt.Errorf("program %d out of 32-bit mem range: %x - %x (size: %x)", i, prog.Vaddr, prog.Vaddr+prog.Memsz, prog.Memsz)
 		}
-		if err := s.Memory.SetMemoryRange(uint32(prog.Vaddr), r); err != nil {
-			return nil, fmt.Errorf("failed to read program segment %d: %w", i, err)
+		if err := s.GetMemory().SetMemoryRange(uint32(prog.Vaddr), r); err != nil {
+			return empty, fmt.Errorf("failed to read program segment %d: %w", i, err)
 		}
 	}
 
 	return s, nil
 }
 
-func PatchGo(f *elf.File, st *State) error {
+// PatchGo patches the Go runtime to run in the VM.
+// The single-threaded VM cannot run anything concurrently, so the GC and other background work is patched out as well.
+func PatchGo(f *elf.File, st FPVMState) error {
 	symbols, err := f.Symbols()
 	if err != nil {
 		return fmt.Errorf("failed to read symbols data, cannot patch program: %w", err)
 	}
+	_, multiThreaded := st.(*MTState)
 
 	for _, s := range symbols {
-		// Disable Golang GC by patching the functions that enable the GC to a no-op function.
 		switch s.Name {
+		// Disable Golang GC by patching the functions that enable the GC to a no-op function.
 		case "runtime.gcenable",
 			"runtime.init.5",            // patch out: init() { go forcegchelper() }
 			"runtime.main.func1",        // patch out: main.func() { newm(sysmon, ....) }
@@ -72,22 +87,23 @@ func PatchGo(f *elf.File, st *State) error {
 			"github.com/prometheus/common/model.init",
 			"github.com/prometheus/client_model/go.init",
 			"github.com/prometheus/client_model/go.init.0",
-			"github.com/prometheus/client_model/go.init.1",
-			// skip flag pkg init, we need to debug arg-processing more to see why this fails
-			"flag.init",
+			"github.com/prometheus/client_model/go.init.1":
+			if multiThreaded {
+				// the multi-threaded VM runs the GC and other background threads
+				continue
+			}
+			if err := patchRet(st, s); err != nil {
+				return err
+			}
+		// skip flag pkg init, we need to debug arg-processing more to see why this fails
+		case "flag.init",
 			// We need to patch this out, we don't pass float64nan because we don't support floats
 			"runtime.check":
-			// MIPS32 patch: ret (pseudo instruction)
-			// 03e00008 = jr $ra = ret (pseudo instruction)
-			// 00000000 = nop (executes with delay-slot, but does nothing)
-			if err := st.Memory.SetMemoryRange(uint32(s.Value), bytes.NewReader([]byte{
-				0x03, 0xe0, 0x00, 0x08,
-				0, 0, 0, 0,
-			})); err != nil {
-				return fmt.Errorf("failed to patch Go runtime.gcenable: %w", err)
+			if err := patchRet(st, s); err != nil {
+				return err
 			}
 		case "runtime.MemProfileRate":
-			if err := st.Memory.SetMemoryRange(uint32(s.Value), bytes.NewReader(make([]byte, 4))); err != nil { // disable mem profiling, to avoid a lot of unnecessary floating point ops
+			if err := st.GetMemory().SetMemoryRange(uint32(s.Value), bytes.NewReader(make([]byte, 4))); err != nil { // disable mem profiling, to avoid a lot of unnecessary floating point ops
 				return err
 			}
 		}
@@ -95,19 +111,33 @@ func PatchGo(f *elf.File, st *State) error {
 	return nil
 }
 
-func PatchStack(st *State) error {
+// patchRet patches the function of the given symbol to return immediately.
+func patchRet(st FPVMState, s elf.Symbol) error {
+	// MIPS32 patch: ret (pseudo instruction)
+	// 03e00008 = jr $ra = ret (pseudo instruction)
+	// 00000000 = nop (executes with delay-slot, but does nothing)
+	if err := st.GetMemory().SetMemoryRange(uint32(s.Value), bytes.NewReader([]byte{
+		0x03, 0xe0, 0x00, 0x08,
+		0, 0, 0, 0,
+	})); err != nil {
+		return fmt.Errorf("failed to patch Go function %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+func PatchStack(st FPVMState) error {
 	// setup stack pointer
 	sp := uint32(0x7f_ff_d0_00)
 	// allocate 1 page for the initial stack data, and 16KB = 4 pages for the stack to grow
-	if err := st.Memory.SetMemoryRange(sp-4*PageSize, bytes.NewReader(make([]byte, 5*PageSize))); err != nil {
+	if err := st.GetMemory().SetMemoryRange(sp-4*PageSize, bytes.NewReader(make([]byte, 5*PageSize))); err != nil {
 		return fmt.Errorf("failed to allocate page for stack content")
 	}
-	st.Registers[29] = sp
+	st.GetRegisters()[29] = sp
 
 	storeMem := func(addr uint32, v uint32) {
 		var dat [4]byte
 		binary.BigEndian.PutUint32(dat[:], v)
-		_ = st.Memory.SetMemoryRange(addr, bytes.NewReader(dat[:]))
+		_ = st.GetMemory().SetMemoryRange(addr, bytes.NewReader(dat[:]))
 	}
 
 	// init argc, argv, aux on stack
@@ -120,7 +150,7 @@ func PatchStack(st *State) error {
 	storeMem(sp+4*7, sp+4*9) // auxv[3] = address of 16 bytes containing random value
 	storeMem(sp+4*8, 0)      // auxv[term] = 0
 
-	_ = st.Memory.SetMemoryRange(sp+4*9, bytes.NewReader([]byte("4;byfairdiceroll"))) // 16 bytes of "randomness"
+	_ = st.GetMemory().SetMemoryRange(sp+4*9, bytes.NewReader([]byte("4;byfairdiceroll"))) // 16 bytes of "randomness"
 
 	return nil
 }
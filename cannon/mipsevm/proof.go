@@ -0,0 +1,64 @@
+package mipsevm
+
+import (
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+// proofBinaryVersion is the version of the binary serialization format of Proof.
+const proofBinaryVersion uint8 = 1
+
+// Proof is the data of a single step, as written by cannon run to the proof files,
+// to replicate the step onchain.
+type Proof struct {
+	Step uint64 `json:"step"`
+
+	Pre  common.Hash `json:"pre"`
+	Post common.Hash `json:"post"`
+
+	StateData hexutil.Bytes `json:"state-data"`
+	ProofData hexutil.Bytes `json:"proof-data"`
+
+	OracleKey    hexutil.Bytes `json:"oracle-key,omitempty"`
+	OracleValue  hexutil.Bytes `json:"oracle-value,omitempty"`
+	OracleOffset uint32        `json:"oracle-offset,omitempty"`
+}
+
+var _ serialize.Serializable = (*Proof)(nil)
+
+func (p *Proof) Serialize(out io.Writer) error {
+	w := serialize.NewBinaryWriter(out)
+	for _, v := range []any{proofBinaryVersion, p.Step, p.Pre, p.Post} {
+		if err := w.WriteFixed(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range [][]byte{p.StateData, p.ProofData, p.OracleKey, p.OracleValue} {
+		if err := w.WriteBytes(v); err != nil {
+			return err
+		}
+	}
+	return w.WriteFixed(p.OracleOffset)
+}
+
+func (p *Proof) Deserialize(in io.Reader) error {
+	r := serialize.NewBinaryReader(in)
+	if err := readBinaryVersion(r, proofBinaryVersion); err != nil {
+		return err
+	}
+	for _, v := range []any{&p.Step, &p.Pre, &p.Post} {
+		if err := r.ReadFixed(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range []*hexutil.Bytes{&p.StateData, &p.ProofData, &p.OracleKey, &p.OracleValue} {
+		if err := r.ReadBytes((*[]byte)(v)); err != nil {
+			return err
+		}
+	}
+	return r.ReadFixed(&p.OracleOffset)
+}
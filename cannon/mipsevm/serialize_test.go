@@ -0,0 +1,112 @@
+package mipsevm
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// requireBinaryRoundTrip serializes the state, and checks it deserializes into the same state.
+func requireBinaryRoundTrip(t *testing.T, state VMState, decoded VMState) {
+	var buf bytes.Buffer
+	require.NoError(t, state.Serialize(&buf))
+	require.NoError(t, decoded.Deserialize(&buf))
+	require.Zero(t, buf.Len(), "all data must be consumed")
+
+	require.Equal(t, state.EncodeWitness(), decoded.EncodeWitness())
+	expected, err := json.Marshal(state)
+	require.NoError(t, err)
+	actual, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(actual))
+}
+
+func TestStateSerialize(t *testing.T) {
+	elfProgram, err := elf.Open("../example/bin/hello.elf")
+	require.NoError(t, err, "open ELF file")
+	state, err := LoadELF(elfProgram, CreateInitialState)
+	require.NoError(t, err, "load ELF into state")
+	require.NoError(t, PatchGo(elfProgram, state), "apply Go runtime patches")
+	require.NoError(t, PatchStack(state), "add initial stack")
+
+	us := NewInstrumentedState(state, nil, io.Discard, io.Discard)
+	for i := 0; i < 10_000; i++ {
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+	state.PreimageKey = common.Hash{0xaa}
+	state.PreimageOffset = 8
+	state.LastHint = []byte{0, 0, 0, 3, 1, 2}
+	requireBinaryRoundTrip(t, state, new(State))
+}
+
+func TestMTStateSerialize(t *testing.T) {
+	state := NewMTState(0x1000, 0x2000)
+	state.Memory.SetMemory(0x1000, 0x12345678)
+	state.LLReservationActive = true
+	state.LLAddress = 0x4000
+	state.LLOwnerThread = 1
+	state.StepsSinceLastContextSwitch = 7
+	state.RightThreadStack = append(state.RightThreadStack, &ThreadState{
+		ThreadID:         1,
+		FutexAddr:        0x4000,
+		FutexVal:         3,
+		FutexTimeoutStep: 100,
+		Cpu:              CpuScalars{PC: 0x2000, NextPC: 0x2004, LO: 1, HI: 2},
+		Registers:        [32]uint32{29: 0x7000},
+	})
+	state.NextThreadID = 2
+	requireBinaryRoundTrip(t, state, new(MTState))
+}
+
+func TestMIPS64StateSerialize(t *testing.T) {
+	state := loadMIPS64ELF(t, "../example/bin/hello.64.elf")
+	us := NewMIPS64InstrumentedState(state, nil, io.Discard, io.Discard)
+	for i := 0; i < 10_000; i++ {
+		_, err := us.Step(false)
+		require.NoError(t, err)
+	}
+	state.LastHint = []byte{0, 0, 0, 3, 1, 2}
+	requireBinaryRoundTrip(t, state, new(MIPS64State))
+}
+
+func TestStateDeserializeOtherVMType(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewMTState(0x1000, 0x2000).Serialize(&buf))
+	require.ErrorContains(t, new(State).Deserialize(&buf), "unsupported binary format version")
+}
+
+func TestNewStateForBinaryVersion(t *testing.T) {
+	for _, state := range []VMState{&State{Memory: NewMemory()}, NewMTState(0x1000, 0x2000), CreateInitialMIPS64State(0x1000, 0x2000)} {
+		var buf bytes.Buffer
+		require.NoError(t, state.Serialize(&buf))
+		decoded, err := NewStateForBinaryVersion(buf.Bytes()[0])
+		require.NoError(t, err)
+		require.IsType(t, state, decoded)
+	}
+	_, err := NewStateForBinaryVersion(0)
+	require.ErrorContains(t, err, "unsupported binary format version")
+}
+
+func TestProofSerialize(t *testing.T) {
+	proof := &Proof{
+		Step:         42,
+		Pre:          common.Hash{0x01},
+		Post:         common.Hash{0x02},
+		StateData:    []byte{1, 2, 3},
+		ProofData:    []byte{4, 5, 6},
+		OracleKey:    common.Hash{0x03}.Bytes(),
+		OracleValue:  []byte{7, 8},
+		OracleOffset: 4,
+	}
+	var buf bytes.Buffer
+	require.NoError(t, proof.Serialize(&buf))
+	var decoded Proof
+	require.NoError(t, decoded.Deserialize(&buf))
+	require.Equal(t, proof, &decoded)
+}
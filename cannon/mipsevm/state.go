@@ -3,15 +3,21 @@ package mipsevm
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
 )
 
 // StateWitnessSize is the size of the state witness encoding in bytes.
 var StateWitnessSize = 226
 
+var _ FPVMState = (*State)(nil)
+
+// State is the state of the single-threaded VM.
 type State struct {
 	Memory *Memory `json:"memory"`
 
@@ -46,6 +52,31 @@ func (s *State) VMStatus() uint8 {
 	return vmStatus(s.Exited, s.ExitCode)
 }
 
+func (s *State) GetMemory() *Memory { return s.Memory }
+
+func (s *State) GetPC() uint32 { return s.PC }
+
+func (s *State) GetRegisters() *[32]uint32 { return &s.Registers }
+
+func (s *State) GetStep() uint64 { return s.Step }
+
+func (s *State) GetExited() bool { return s.Exited }
+
+func (s *State) GetExitCode() uint8 { return s.ExitCode }
+
+func (s *State) GetLastHint() hexutil.Bytes { return s.LastHint }
+
+func (s *State) cpu() CpuScalars {
+	return CpuScalars{PC: s.PC, NextPC: s.NextPC, LO: s.LO, HI: s.HI}
+}
+
+func (s *State) setCpu(cpu CpuScalars) {
+	s.PC = cpu.PC
+	s.NextPC = cpu.NextPC
+	s.LO = cpu.LO
+	s.HI = cpu.HI
+}
+
 func (s *State) EncodeWitness() StateWitness {
 	out := make([]byte, 0)
 	memRoot := s.Memory.MerkleRoot()
@@ -70,6 +101,41 @@ func (s *State) EncodeWitness() StateWitness {
 	return out
 }
 
+// Serialize writes the state in the binary format: the version byte, the memory,
+// the fields of the state in witness order, and the length-prefixed last hint.
+func (s *State) Serialize(out io.Writer) error {
+	w := serialize.NewBinaryWriter(out)
+	if err := w.WriteFixed(stateBinaryVersion); err != nil {
+		return err
+	}
+	if err := s.Memory.Serialize(out); err != nil {
+		return err
+	}
+	for _, v := range []any{s.PreimageKey, s.PreimageOffset, s.PC, s.NextPC, s.LO, s.HI, s.Heap, s.ExitCode, s.Exited, s.Step, s.Registers} {
+		if err := w.WriteFixed(v); err != nil {
+			return err
+		}
+	}
+	return w.WriteBytes(s.LastHint)
+}
+
+func (s *State) Deserialize(in io.Reader) error {
+	r := serialize.NewBinaryReader(in)
+	if err := readBinaryVersion(r, stateBinaryVersion); err != nil {
+		return err
+	}
+	s.Memory = NewMemory()
+	if err := s.Memory.Deserialize(in); err != nil {
+		return err
+	}
+	for _, v := range []any{&s.PreimageKey, &s.PreimageOffset, &s.PC, &s.NextPC, &s.LO, &s.HI, &s.Heap, &s.ExitCode, &s.Exited, &s.Step, &s.Registers} {
+		if err := r.ReadFixed(v); err != nil {
+			return err
+		}
+	}
+	return r.ReadBytes((*[]byte)(&s.LastHint))
+}
+
 type StateWitness []byte
 
 const (
@@ -79,13 +145,22 @@ const (
 	VMStatusUnfinished = 3
 )
 
+// StateHash computes the state hash of a single-threaded, multi-threaded or MIPS64 state witness,
+// these are distinguished by their length.
 func (sw StateWitness) StateHash() (common.Hash, error) {
-	if len(sw) != 226 {
-		return common.Hash{}, fmt.Errorf("Invalid witness length. Got %d, expected at least 88", len(sw))
+	var offset int
+	switch len(sw) {
+	case StateWitnessSize:
+		offset = 32*2 + 4*6
+	case MTStateWitnessSize:
+		offset = mtExitCodeWitnessOffset
+	case MIPS64StateWitnessSize:
+		offset = mips64ExitCodeWitnessOffset
+	default:
+		return common.Hash{}, fmt.Errorf("invalid witness length. Got %d, expected %d, %d or %d", len(sw), StateWitnessSize, MTStateWitnessSize, MIPS64StateWitnessSize)
 	}
 
 	hash := crypto.Keccak256Hash(sw)
-	offset := 32*2 + 4*6
 	exitCode := sw[offset]
 	exited := sw[offset+1]
 	status := vmStatus(exited == 1, exitCode)
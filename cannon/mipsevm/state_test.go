@@ -43,7 +43,7 @@ func TestState(t *testing.T) {
 			fn := path.Join("open_mips_tests/test/bin", f.Name())
 			//elfProgram, err := elf.Open()
 			//require.NoError(t, err, "must load test ELF binary")
-			//state, err := LoadELF(elfProgram)
+			//state, err := LoadELF(elfProgram, CreateInitialState)
 			//require.NoError(t, err, "must load ELF into state")
 			programMem, err := os.ReadFile(fn)
 			require.NoError(t, err)
@@ -133,7 +133,7 @@ func TestHello(t *testing.T) {
 	elfProgram, err := elf.Open("../example/bin/hello.elf")
 	require.NoError(t, err, "open ELF file")
 
-	state, err := LoadELF(elfProgram)
+	state, err := LoadELF(elfProgram, CreateInitialState)
 	require.NoError(t, err, "load ELF into state")
 
 	err = PatchGo(elfProgram, state)
@@ -231,7 +231,7 @@ func TestClaim(t *testing.T) {
 	elfProgram, err := elf.Open("../example/bin/claim.elf")
 	require.NoError(t, err, "open ELF file")
 
-	state, err := LoadELF(elfProgram)
+	state, err := LoadELF(elfProgram, CreateInitialState)
 	require.NoError(t, err, "load ELF into state")
 
 	err = PatchGo(elfProgram, state)
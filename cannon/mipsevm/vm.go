@@ -0,0 +1,85 @@
+package mipsevm
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+)
+
+// Versions of the binary serialization format of the VM states, the first byte of their encoding.
+// Each state type has its own versions, so a state is never decoded as the state of another VM type.
+const (
+	stateBinaryVersion       uint8 = 1
+	mtStateBinaryVersion     uint8 = 2
+	mips64StateBinaryVersion uint8 = 3
+)
+
+// VMState is the architecture-independent part of the state of a fault proof VM.
+type VMState interface {
+	GetStep() uint64
+
+	GetExited() bool
+
+	GetExitCode() uint8
+
+	// GetLastHint returns optional metadata which is not part of the VM state itself.
+	// It is used to remember the last pre-image hint,
+	// so a VM can start from any state without fetching prior pre-images,
+	// and instead just repeat the last hint on setup,
+	// to make sure pre-image requests can be served.
+	GetLastHint() hexutil.Bytes
+
+	EncodeWitness() StateWitness
+
+	// Serializable encodes the full state, including the memory, in the binary format.
+	serialize.Serializable
+}
+
+// FPVMState is the state of a 32-bit fault proof VM: either the single-threaded State, or the multi-threaded MTState.
+type FPVMState interface {
+	VMState
+
+	GetMemory() *Memory
+
+	// GetPC returns the program counter of the active thread
+	GetPC() uint32
+
+	// GetRegisters returns the general purpose registers of the active thread
+	GetRegisters() *[32]uint32
+}
+
+// FPVM is an instrumented fault proof VM, that steps through its state and optionally produces witness data.
+type FPVM interface {
+	GetState() VMState
+
+	// Step executes a single instruction, and returns the witness data if proof is true
+	Step(proof bool) (*StepWitness, error)
+}
+
+// NewStateForBinaryVersion returns an empty state of the VM type that is encoded with the given binary format version,
+// to deserialize a state of unknown VM type into.
+func NewStateForBinaryVersion(version uint8) (VMState, error) {
+	switch version {
+	case stateBinaryVersion:
+		return &State{}, nil
+	case mtStateBinaryVersion:
+		return &MTState{}, nil
+	case mips64StateBinaryVersion:
+		return &MIPS64State{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported binary format version %d", version)
+	}
+}
+
+func readBinaryVersion(r *serialize.BinaryReader, expected uint8) error {
+	var version uint8
+	if err := r.ReadFixed(&version); err != nil {
+		return err
+	}
+	if version != expected {
+		return fmt.Errorf("unsupported binary format version %d, expected %d", version, expected)
+	}
+	return nil
+}
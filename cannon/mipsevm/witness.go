@@ -8,7 +8,9 @@ type StepWitness struct {
 	// encoded state witness
 	State []byte
 
-	MemProof []byte
+	// ProofData contains the memory proofs of the step,
+	// and for the multi-threaded VM, the witness of the active thread.
+	ProofData []byte
 
 	PreimageKey    [32]byte // zeroed when no pre-image is accessed
 	PreimageValue  []byte   // including the 8-byte length prefix
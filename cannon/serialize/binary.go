@@ -0,0 +1,63 @@
+package serialize
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// BinaryReader reads the fields of the binary serialization format, in big-endian order.
+type BinaryReader struct {
+	in io.Reader
+}
+
+func NewBinaryReader(in io.Reader) *BinaryReader {
+	return &BinaryReader{in: in}
+}
+
+// ReadFixed reads a fixed-size value into the target pointer or byte slice.
+// Fixed-size values are integers, bools, and arrays, slices and structs of those.
+func (r *BinaryReader) ReadFixed(target any) error {
+	return binary.Read(r.in, binary.BigEndian, target)
+}
+
+// ReadBytes reads a uint32 length-prefixed byte slice.
+// An empty slice is read as a non-nil empty slice, nil and empty slices are not distinguished.
+func (r *BinaryReader) ReadBytes(target *[]byte) error {
+	var size uint32
+	if err := r.ReadFixed(&size); err != nil {
+		return err
+	}
+	out := make([]byte, size)
+	if _, err := io.ReadFull(r.in, out); err != nil {
+		return err
+	}
+	*target = out
+	return nil
+}
+
+// BinaryWriter writes the fields of the binary serialization format, in big-endian order.
+type BinaryWriter struct {
+	out io.Writer
+}
+
+func NewBinaryWriter(out io.Writer) *BinaryWriter {
+	return &BinaryWriter{out: out}
+}
+
+// WriteFixed writes a fixed-size value, see BinaryReader.ReadFixed.
+func (w *BinaryWriter) WriteFixed(v any) error {
+	return binary.Write(w.out, binary.BigEndian, v)
+}
+
+// WriteBytes writes a uint32 length-prefixed byte slice.
+func (w *BinaryWriter) WriteBytes(v []byte) error {
+	if uint64(len(v)) > uint64(^uint32(0)) {
+		return fmt.Errorf("byte slice of length %d is too large to serialize", len(v))
+	}
+	if err := w.WriteFixed(uint32(len(v))); err != nil {
+		return err
+	}
+	_, err := w.out.Write(v)
+	return err
+}
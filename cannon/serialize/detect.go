@@ -0,0 +1,9 @@
+package serialize
+
+import "strings"
+
+// IsBinaryFile returns true if the path has a binary file extension, either .bin or .bin.gz.
+// Any other path is considered to be a JSON file.
+func IsBinaryFile(path string) bool {
+	return strings.HasSuffix(path, ".bin") || strings.HasSuffix(path, ".bin.gz")
+}
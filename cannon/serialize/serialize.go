@@ -0,0 +1,71 @@
+package serialize
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+)
+
+// Serializable is implemented by types that support the binary serialization format.
+// The encoding is streamed, so large states do not have to be buffered in full.
+// Implementations are expected to start their encoding with a version byte,
+// and to reject encodings of any version they do not support.
+type Serializable interface {
+	// Serialize writes the binary encoding of the value to out.
+	Serialize(out io.Writer) error
+	// Deserialize reads the binary encoding from in, and replaces the value with it.
+	Deserialize(in io.Reader) error
+}
+
+// LoadSerializedBinary reads the value of type X from the binary file at the given path.
+// The file is decompressed if it is gzipped. *X must implement Serializable.
+func LoadSerializedBinary[X any](inputPath string) (*X, error) {
+	if inputPath == "" {
+		return nil, errors.New("no path specified")
+	}
+	var x X
+	serializable, ok := any(&x).(Serializable)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a Serializable", &x)
+	}
+	f, err := ioutil.OpenDecompressed(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %q: %w", inputPath, err)
+	}
+	defer f.Close()
+	if err := serializable.Deserialize(bufio.NewReader(f)); err != nil {
+		return nil, fmt.Errorf("failed to decode file %q: %w", inputPath, err)
+	}
+	return &x, nil
+}
+
+// WriteSerializedBinary atomically writes the binary encoding of the value to the given path.
+// The output is gzipped if the path ends in .gz. Nothing is written if the path is empty.
+func WriteSerializedBinary(outputPath string, value Serializable, perm os.FileMode) error {
+	if outputPath == "" {
+		return nil
+	}
+	f, err := ioutil.NewAtomicWriterCompressed(outputPath, perm)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	// Ensure we close the stream even if failures occur.
+	defer f.Close()
+	out := bufio.NewWriter(f)
+	if err := value.Serialize(out); err != nil {
+		return fmt.Errorf("failed to encode to binary: %w", err)
+	}
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("failed to flush output: %w", err)
+	}
+	// Closing the file causes it to be renamed to the final destination
+	// so make sure we handle any errors it returns
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to finish write: %w", err)
+	}
+	return nil
+}
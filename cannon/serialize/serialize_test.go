@@ -0,0 +1,122 @@
+package serialize
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+)
+
+const serializeTestVersion = uint8(7)
+
+type serializeTestData struct {
+	A uint64
+	B bool
+	C []byte
+	D [4]uint32
+}
+
+func (s *serializeTestData) Serialize(out io.Writer) error {
+	w := NewBinaryWriter(out)
+	if err := w.WriteFixed(serializeTestVersion); err != nil {
+		return err
+	}
+	if err := w.WriteFixed(s.A); err != nil {
+		return err
+	}
+	if err := w.WriteFixed(s.B); err != nil {
+		return err
+	}
+	if err := w.WriteBytes(s.C); err != nil {
+		return err
+	}
+	return w.WriteFixed(s.D)
+}
+
+func (s *serializeTestData) Deserialize(in io.Reader) error {
+	r := NewBinaryReader(in)
+	var version uint8
+	if err := r.ReadFixed(&version); err != nil {
+		return err
+	}
+	if version != serializeTestVersion {
+		return fmt.Errorf("unsupported version %d", version)
+	}
+	if err := r.ReadFixed(&s.A); err != nil {
+		return err
+	}
+	if err := r.ReadFixed(&s.B); err != nil {
+		return err
+	}
+	if err := r.ReadBytes(&s.C); err != nil {
+		return err
+	}
+	return r.ReadFixed(&s.D)
+}
+
+func TestRoundTripBinary(t *testing.T) {
+	for _, name := range []string{"test.bin", "test.bin.gz"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, name)
+			data := &serializeTestData{A: 42, B: true, C: []byte{1, 2, 3}, D: [4]uint32{5, 6, 7, 8}}
+			require.NoError(t, WriteSerializedBinary(file, data, 0o644))
+			require.Equal(t, filepath.Ext(name) == ".gz", ioutil.IsGzip(file))
+
+			result, err := LoadSerializedBinary[serializeTestData](file)
+			require.NoError(t, err)
+			require.EqualValues(t, data, result)
+		})
+	}
+}
+
+func TestEmptyBytes(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.bin")
+	require.NoError(t, WriteSerializedBinary(file, &serializeTestData{}, 0o644))
+	result, err := LoadSerializedBinary[serializeTestData](file)
+	require.NoError(t, err)
+	require.NotNil(t, result.C)
+	require.Empty(t, result.C)
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.bin")
+	require.NoError(t, os.WriteFile(file, []byte{serializeTestVersion + 1}, 0o644))
+	_, err := LoadSerializedBinary[serializeTestData](file)
+	require.ErrorContains(t, err, "unsupported version")
+}
+
+func TestLoadTruncated(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.bin")
+	require.NoError(t, os.WriteFile(file, []byte{serializeTestVersion, 0, 0}, 0o644))
+	_, err := LoadSerializedBinary[serializeTestData](file)
+	require.True(t, errors.Is(err, io.ErrUnexpectedEOF), "unexpected error: %v", err)
+}
+
+func TestWriteNoPath(t *testing.T) {
+	require.NoError(t, WriteSerializedBinary("", &serializeTestData{}, 0o644))
+}
+
+func TestIsBinaryFile(t *testing.T) {
+	tests := map[string]bool{
+		"state.bin":         true,
+		"state.bin.gz":      true,
+		"dir/100.bin.gz":    true,
+		"state.json":        false,
+		"state.json.gz":     false,
+		"state.binary":      false,
+		"state.bin.json.gz": false,
+		"":                  false,
+		"-":                 false,
+	}
+	for path, expected := range tests {
+		require.Equalf(t, expected, IsBinaryFile(path), "path %q", path)
+	}
+}
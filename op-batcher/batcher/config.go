@@ -10,6 +10,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
 	"github.com/ethereum-optimism/optimism/op-batcher/flags"
+	plasma "github.com/ethereum-optimism/optimism/op-plasma"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
@@ -67,6 +68,7 @@ type CLIConfig struct {
 	PprofConfig      oppprof.CLIConfig
 	CompressorConfig compressor.CLIConfig
 	RPC              oprpc.CLIConfig
+	PlasmaConfig     plasma.CLIConfig
 }
 
 func (c *CLIConfig) Check() error {
@@ -94,9 +96,16 @@ func (c *CLIConfig) Check() error {
 	switch c.DataAvailabilityType {
 	case flags.CalldataType:
 	case flags.BlobsType:
+	case flags.PlasmaType:
+		if !c.PlasmaConfig.Enabled() {
+			return errors.New("plasma data availability type requires a DA server")
+		}
 	default:
 		return fmt.Errorf("unknown data availability type: %v", c.DataAvailabilityType)
 	}
+	if err := c.PlasmaConfig.Check(); err != nil {
+		return err
+	}
 	if err := c.MetricsConfig.Check(); err != nil {
 		return err
 	}
@@ -135,5 +144,6 @@ func NewConfig(ctx *cli.Context) *CLIConfig {
 		PprofConfig:            oppprof.ReadCLIConfig(ctx),
 		CompressorConfig:       compressor.ReadCLIConfig(ctx),
 		RPC:                    oprpc.ReadCLIConfig(ctx),
+		PlasmaConfig:           plasma.ReadCLIConfig(ctx),
 	}
 }
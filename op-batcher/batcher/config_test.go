@@ -87,6 +87,19 @@ func TestBatcherConfig(t *testing.T) {
 			override:  func(c *batcher.CLIConfig) { c.DataAvailabilityType = "foo" },
 			errString: "unknown data availability type: foo",
 		},
+		{
+			name:      "plasma without DA server",
+			override:  func(c *batcher.CLIConfig) { c.DataAvailabilityType = flags.PlasmaType },
+			errString: "plasma data availability type requires a DA server",
+		},
+		{
+			name: "invalid DA server url",
+			override: func(c *batcher.CLIConfig) {
+				c.DataAvailabilityType = flags.PlasmaType
+				c.PlasmaConfig.DAServerURL = "not a url"
+			},
+			errString: "invalid DA server url",
+		},
 	}
 
 	for _, test := range tests {
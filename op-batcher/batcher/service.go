@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
 	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	plasma "github.com/ethereum-optimism/optimism/op-plasma"
 	"github.com/ethereum-optimism/optimism/op-service/cliapp"
 	"github.com/ethereum-optimism/optimism/op-service/dial"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -38,6 +39,9 @@ type BatcherConfig struct {
 
 	// UseBlobs is true if the batcher should use blobs instead of calldata for posting blobs
 	UseBlobs bool
+
+	// UsePlasma is true if the batcher should store the batch data on a DA server, and only post commitments to L1
+	UsePlasma bool
 }
 
 // BatcherService represents a full batch-submitter instance and its resources,
@@ -53,6 +57,9 @@ type BatcherService struct {
 
 	RollupConfig *rollup.Config
 
+	// PlasmaDA is the client of the DA server storing the batch data in plasma mode
+	PlasmaDA *plasma.DAClient
+
 	// Channel builder parameters
 	ChannelConfig ChannelConfig
 
@@ -192,11 +199,23 @@ func (bs *BatcherService) initChannelConfig(cfg *CLIConfig) error {
 	case flags.CalldataType:
 		bs.ChannelConfig.MaxFrameSize = cfg.MaxL1TxSize
 		bs.UseBlobs = false
+	case flags.PlasmaType:
+		if !bs.RollupConfig.UsePlasma() {
+			return errors.New("cannot use plasma data availability type, plasma mode is not enabled in the rollup config")
+		}
+		bs.ChannelConfig.MaxFrameSize = cfg.MaxL1TxSize
+		bs.UseBlobs = false
+		bs.UsePlasma = true
+		bs.PlasmaDA = cfg.PlasmaConfig.NewDAClient()
 	default:
 		return fmt.Errorf("unknown data availability type: %v", cfg.DataAvailabilityType)
 	}
 	bs.ChannelConfig.MaxFrameSize-- // subtract 1 byte for version
 
+	if bs.ChannelConfig.CompressorConfig.CompressionAlgo.IsBrotli() && !bs.RollupConfig.IsFjord(uint64(time.Now().Unix())) {
+		return fmt.Errorf("cannot use %v compression before Fjord is active", bs.ChannelConfig.CompressorConfig.CompressionAlgo)
+	}
+
 	if err := bs.ChannelConfig.Check(); err != nil {
 		return fmt.Errorf("invalid channel configuration: %w", err)
 	}
@@ -258,6 +277,7 @@ func (bs *BatcherService) initDriver() {
 		L1Client:         bs.L1Client,
 		EndpointProvider: bs.EndpointProvider,
 		ChannelConfig:    bs.ChannelConfig,
+		PlasmaDA:         bs.PlasmaDA,
 	})
 }
 
@@ -3,7 +3,9 @@ package compressor
 import (
 	"strings"
 
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
+	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	"github.com/urfave/cli/v2"
 )
 
@@ -12,6 +14,7 @@ const (
 	TargetNumFramesFlagName     = "target-num-frames"
 	ApproxComprRatioFlagName    = "approx-compr-ratio"
 	KindFlagName                = "compressor"
+	CompressionAlgoFlagName     = "compression-algo"
 )
 
 func CLIFlags(envPrefix string) []cli.Flag {
@@ -40,6 +43,16 @@ func CLIFlags(envPrefix string) []cli.Flag {
 			EnvVars: opservice.PrefixEnvVar(envPrefix, "COMPRESSOR"),
 			Value:   ShadowKind,
 		},
+		&cli.GenericFlag{
+			Name: CompressionAlgoFlagName,
+			Usage: "The compression algorithm to use for channels. Brotli compression requires Fjord to be active. Valid options: " +
+				openum.EnumString(derive.CompressionAlgos),
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "COMPRESSION_ALGO"),
+			Value: func() *derive.CompressionAlgo {
+				algo := derive.Zlib
+				return &algo
+			}(),
+		},
 	}
 }
 
@@ -57,6 +70,8 @@ type CLIConfig struct {
 	ApproxComprRatio float64
 	// Type of compressor to use. Must be one of KindKeys.
 	Kind string
+	// CompressionAlgo to compress channels with.
+	CompressionAlgo derive.CompressionAlgo
 }
 
 func (c *CLIConfig) Config() Config {
@@ -65,6 +80,7 @@ func (c *CLIConfig) Config() Config {
 		TargetNumFrames:  c.TargetNumFrames,
 		ApproxComprRatio: c.ApproxComprRatio,
 		Kind:             c.Kind,
+		CompressionAlgo:  c.CompressionAlgo,
 	}
 }
 
@@ -74,5 +90,6 @@ func ReadCLIConfig(ctx *cli.Context) CLIConfig {
 		TargetL1TxSizeBytes: ctx.Uint64(TargetL1TxSizeBytesFlagName),
 		TargetNumFrames:     ctx.Int(TargetNumFramesFlagName),
 		ApproxComprRatio:    ctx.Float64(ApproxComprRatioFlagName),
+		CompressionAlgo:     derive.CompressionAlgo(ctx.String(CompressionAlgoFlagName)),
 	}
 }
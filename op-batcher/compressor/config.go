@@ -19,6 +19,9 @@ type Config struct {
 	// Kind of compressor to use. Must be one of KindKeys. If unset, NewCompressor
 	// will default to RatioKind.
 	Kind string
+	// CompressionAlgo to compress channels with. If unset, zlib is used.
+	// Brotli compressed channels are only valid once Fjord is active.
+	CompressionAlgo derive.CompressionAlgo
 }
 
 func (c Config) NewCompressor() (derive.Compressor, error) {
@@ -28,3 +31,10 @@ func (c Config) NewCompressor() (derive.Compressor, error) {
 	// default to RatioCompressor
 	return Kinds[RatioKind](c)
 }
+
+func (c Config) compressionAlgo() derive.CompressionAlgo {
+	if c.CompressionAlgo == "" {
+		return derive.Zlib
+	}
+	return c.CompressionAlgo
+}
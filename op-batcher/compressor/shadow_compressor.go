@@ -1,9 +1,6 @@
 package compressor
 
 import (
-	"bytes"
-	"compress/zlib"
-
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 )
 
@@ -16,20 +13,23 @@ const (
 	// might be possible, but it would be highly unlikely, and the system still works if our
 	// estimate is wrong -- we just end up writing one more tx for the overflow.
 	safeCompressionOverhead = 51
+
+	// closeOverheadZlib is the 4 byte digest a zlib stream writes on close, after a flush.
+	closeOverheadZlib = 4
+	// closeOverheadBrotli is the final empty meta-block a Brotli stream writes on close, after a flush.
+	closeOverheadBrotli = 1
 )
 
 type ShadowCompressor struct {
 	config Config
 
-	buf      bytes.Buffer
-	compress *zlib.Writer
-
-	shadowBuf      bytes.Buffer
-	shadowCompress *zlib.Writer
+	compressor       derive.ChannelCompressor
+	shadowCompressor derive.ChannelCompressor
 
 	fullErr error
 
-	bound uint64 // best known upperbound on the size of the compressed output
+	bound         uint64 // best known upperbound on the size of the compressed output
+	closeOverhead uint64 // bytes the compression algorithm writes on close, after a flush
 }
 
 // NewShadowCompressor creates a new derive.Compressor implementation that contains two
@@ -45,15 +45,19 @@ func NewShadowCompressor(config Config) (derive.Compressor, error) {
 	}
 
 	var err error
-	c.compress, err = zlib.NewWriterLevel(&c.buf, zlib.BestCompression)
+	c.compressor, err = derive.NewChannelCompressor(config.compressionAlgo())
 	if err != nil {
 		return nil, err
 	}
-	c.shadowCompress, err = zlib.NewWriterLevel(&c.shadowBuf, zlib.BestCompression)
+	c.shadowCompressor, err = derive.NewChannelCompressor(config.compressionAlgo())
 	if err != nil {
 		return nil, err
 	}
 
+	c.closeOverhead = closeOverheadZlib
+	if config.compressionAlgo().IsBrotli() {
+		c.closeOverhead = closeOverheadBrotli
+	}
 	c.bound = safeCompressionOverhead
 	return c, nil
 }
@@ -62,7 +66,7 @@ func (t *ShadowCompressor) Write(p []byte) (int, error) {
 	if t.fullErr != nil {
 		return 0, t.fullErr
 	}
-	_, err := t.shadowCompress.Write(p)
+	_, err := t.shadowCompressor.Write(p)
 	if err != nil {
 		return 0, err
 	}
@@ -72,11 +76,11 @@ func (t *ShadowCompressor) Write(p []byte) (int, error) {
 		// Do not flush the buffer unless there's some chance we will be over the size limit.
 		// This reduces CPU but more importantly it makes the shadow compression ratio more
 		// closely reflect the ultimate compression ratio.
-		err = t.shadowCompress.Flush()
+		err = t.shadowCompressor.Flush()
 		if err != nil {
 			return 0, err
 		}
-		newBound = uint64(t.shadowBuf.Len()) + 4 // + 4 is to account for the digest written on close()
+		newBound = uint64(t.shadowCompressor.Len()) + t.closeOverhead
 		if newBound > cap {
 			t.fullErr = derive.CompressorFullErr
 			if t.Len() > 0 {
@@ -87,32 +91,30 @@ func (t *ShadowCompressor) Write(p []byte) (int, error) {
 		}
 	}
 	t.bound = newBound
-	return t.compress.Write(p)
+	return t.compressor.Write(p)
 }
 
 func (t *ShadowCompressor) Close() error {
-	return t.compress.Close()
+	return t.compressor.Close()
 }
 
 func (t *ShadowCompressor) Read(p []byte) (int, error) {
-	return t.buf.Read(p)
+	return t.compressor.Read(p)
 }
 
 func (t *ShadowCompressor) Reset() {
-	t.buf.Reset()
-	t.compress.Reset(&t.buf)
-	t.shadowBuf.Reset()
-	t.shadowCompress.Reset(&t.shadowBuf)
+	t.compressor.Reset()
+	t.shadowCompressor.Reset()
 	t.fullErr = nil
 	t.bound = safeCompressionOverhead
 }
 
 func (t *ShadowCompressor) Len() int {
-	return t.buf.Len()
+	return t.compressor.Len()
 }
 
 func (t *ShadowCompressor) Flush() error {
-	return t.compress.Flush()
+	return t.compressor.Flush()
 }
 
 func (t *ShadowCompressor) FullErr() error {
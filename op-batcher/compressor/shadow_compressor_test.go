@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/andybalholm/brotli"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/stretchr/testify/require"
 )
@@ -115,6 +116,45 @@ func TestShadowCompressor(t *testing.T) {
 	}
 }
 
+// TestShadowCompressorLargeFirstBlock checks that a single block over the target is accepted by
+// an empty compressor with every compression algorithm, and that the next block is rejected.
+func TestShadowCompressorLargeFirstBlock(t *testing.T) {
+	for _, algo := range derive.CompressionAlgos {
+		algo := algo
+		t.Run(algo.String(), func(t *testing.T) {
+			sc, err := NewShadowCompressor(Config{
+				TargetFrameSize: 1,
+				TargetNumFrames: 1,
+				CompressionAlgo: algo,
+			})
+			require.NoError(t, err)
+			require.Zero(t, sc.Len())
+
+			block := bytes.Repeat([]byte{0}, 1024)
+			_, err = sc.Write(block)
+			require.NoError(t, err)
+			require.ErrorIs(t, sc.FullErr(), derive.CompressorFullErr)
+			_, err = sc.Write([]byte{0})
+			require.ErrorIs(t, err, derive.CompressorFullErr)
+
+			require.NoError(t, sc.Close())
+			buf, err := io.ReadAll(sc)
+			require.NoError(t, err)
+			var r io.Reader
+			if algo.IsBrotli() {
+				require.Equal(t, derive.ChannelVersionBrotli, buf[0])
+				r = brotli.NewReader(bytes.NewReader(buf[1:]))
+			} else {
+				r, err = zlib.NewReader(bytes.NewReader(buf))
+				require.NoError(t, err)
+			}
+			uncompressed, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, block, uncompressed)
+		})
+	}
+}
+
 // TestBoundInaccruateForLargeRandomData documents where our bounding heuristic starts to fail
 // (writing at least 128k of random data)
 func TestBoundInaccurateForLargeRandomData(t *testing.T) {
@@ -139,3 +179,39 @@ func TestBoundInaccurateForLargeRandomData(t *testing.T) {
 	require.NoError(t, err)
 	require.LessOrEqual(t, uint64(sc.Len()), sc.(*ShadowCompressor).bound)
 }
+
+func TestCompressionAlgos(t *testing.T) {
+	data := bytes.Repeat([]byte("block data "), 100)
+	for _, kind := range []string{RatioKind, ShadowKind} {
+		for _, algo := range derive.CompressionAlgos {
+			kind, algo := kind, algo
+			t.Run(kind+"-"+algo.String(), func(t *testing.T) {
+				c, err := Config{
+					TargetFrameSize:  1 << 17,
+					TargetNumFrames:  1,
+					ApproxComprRatio: 0.4,
+					Kind:             kind,
+					CompressionAlgo:  algo,
+				}.NewCompressor()
+				require.NoError(t, err)
+				_, err = c.Write(data)
+				require.NoError(t, err)
+				require.NoError(t, c.Close())
+				buf, err := io.ReadAll(c)
+				require.NoError(t, err)
+
+				var r io.Reader
+				if algo.IsBrotli() {
+					require.Equal(t, derive.ChannelVersionBrotli, buf[0])
+					r = brotli.NewReader(bytes.NewReader(buf[1:]))
+				} else {
+					r, err = zlib.NewReader(bytes.NewReader(buf))
+					require.NoError(t, err)
+				}
+				uncompressed, err := io.ReadAll(r)
+				require.NoError(t, err)
+				require.Equal(t, data, uncompressed)
+			})
+		}
+	}
+}
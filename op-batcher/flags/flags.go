@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
+	plasma "github.com/ethereum-optimism/optimism/op-plasma"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
@@ -25,6 +26,8 @@ const (
 	// data availability types
 	CalldataType = "calldata"
 	BlobsType    = "blobs"
+	// PlasmaType stores the batch data on a DA server, and submits commitments to it as calldata.
+	PlasmaType = "plasma"
 )
 
 var (
@@ -90,7 +93,7 @@ var (
 	}
 	DataAvailabilityTypeFlag = &cli.StringFlag{
 		Name:    "data-availability-type",
-		Usage:   "The data availability type to use for submitting batches to the L1, e.g. blobs or calldata.",
+		Usage:   "The data availability type to use for submitting batches to the L1, e.g. blobs, calldata or plasma.",
 		Value:   CalldataType,
 		EnvVars: prefixEnvVars("DATA_AVAILABILITY_TYPE"),
 	}
@@ -123,6 +126,7 @@ func init() {
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, txmgr.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, compressor.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, plasma.CLIFlags(EnvVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+var (
+	GameTypeFlag = &cli.UintFlag{
+		Name:    "game-type",
+		Usage:   "Game type to create (0 = cannon, 1 = permissioned, 2 = asterisc, 255 = alphabet).",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "GAME_TYPE"),
+		Value:   0,
+	}
+	OutputRootFlag = &cli.StringFlag{
+		Name:    "output-root",
+		Usage:   "The output root for the fault dispute game.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "OUTPUT_ROOT"),
+	}
+	L2BlockNumFlag = &cli.Uint64Flag{
+		Name:    "l2-block-num",
+		Usage:   "The L2 block number for the fault dispute game.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "L2_BLOCK_NUM"),
+	}
+)
+
+func CreateGame(ctx *cli.Context) error {
+	outputRoot, err := opservice.ParseHash(ctx.String(OutputRootFlag.Name))
+	if err != nil {
+		return fmt.Errorf("invalid %v: %w", OutputRootFlag.Name, err)
+	}
+	if !ctx.IsSet(L2BlockNumFlag.Name) {
+		return fmt.Errorf("missing %v", L2BlockNumFlag.Name)
+	}
+	gameType := ctx.Uint(GameTypeFlag.Name)
+	if gameType > 255 {
+		return fmt.Errorf("invalid %v: %v", GameTypeFlag.Name, gameType)
+	}
+	l2BlockNum := ctx.Uint64(L2BlockNumFlag.Name)
+
+	factory, txMgr, err := NewContractWithTxMgr(ctx, flags.FactoryAddressFlag.Name, contracts.NewDisputeGameFactoryContract)
+	if err != nil {
+		return err
+	}
+	defer txMgr.Close()
+
+	gameAddr, err := createGame(ctx.Context, factory, txMgr, uint8(gameType), outputRoot, l2BlockNum)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Fetched Game Address: %s\n", gameAddr.Hex())
+	return nil
+}
+
+func createGame(ctx context.Context, factory *contracts.DisputeGameFactoryContract, txMgr txmgr.TxManager, gameType uint8, outputRoot common.Hash, l2BlockNum uint64) (common.Address, error) {
+	tx, err := factory.CreateTx(ctx, gameType, outputRoot, l2BlockNum)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to create tx: %w", err)
+	}
+	rct, err := txMgr.Send(ctx, tx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to send tx: %w", err)
+	}
+	if rct.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, fmt.Errorf("game creation transaction (%v) reverted", rct.TxHash.Hex())
+	}
+	gameAddr, err := factory.GetGameFromParameters(ctx, gameType, outputRoot, l2BlockNum)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to load game address: %w", err)
+	}
+	return gameAddr, nil
+}
+
+func createGameFlags() []cli.Flag {
+	return txFlags(flags.FactoryAddressFlag, OutputRootFlag, L2BlockNumFlag, GameTypeFlag)
+}
+
+var CreateGameCommand = &cli.Command{
+	Name:        "create-game",
+	Usage:       "Creates a dispute game via the factory",
+	Description: "Creates a dispute game via the factory for the specified output root and L2 block number",
+	Action:      CreateGame,
+	Flags:       createGameFlags(),
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func ListClaims(ctx *cli.Context) error {
+	game, err := NewContract(ctx, GameAddressFlag.Name, contracts.NewFaultDisputeGameContract)
+	if err != nil {
+		return err
+	}
+	return listClaims(ctx.Context, game)
+}
+
+func listClaims(ctx context.Context, game *contracts.FaultDisputeGameContract) error {
+	maxDepth, err := game.GetMaxGameDepth(ctx)
+	if err != nil {
+		return err
+	}
+	splitDepth, err := game.GetSplitDepth(ctx)
+	if err != nil {
+		return err
+	}
+	prestateBlock, poststateBlock, err := game.GetBlockRange(ctx)
+	if err != nil {
+		return err
+	}
+	duration, err := game.GetGameDuration(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := game.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+	claims, err := game.GetAllClaims(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Status: %v  Blocks: %v to %v  Max Depth: %v  Split Depth: %v  Clock Limit: %v\n",
+		status, prestateBlock, poststateBlock, maxDepth, splitDepth, time.Duration(duration/2)*time.Second)
+
+	// The duration each claim has used on its team's chess clock
+	clockUsed := make([]uint64, len(claims))
+	for i := 1; i < len(claims); i++ {
+		parent := claims[i].ParentContractIndex
+		clockUsed[i] = claims[i].Clock - claims[parent].Clock
+		if parent != 0 {
+			clockUsed[i] += clockUsed[claims[parent].ParentContractIndex]
+		}
+	}
+
+	lineFormat := "%3v %-7v %6v %5v %-14v %-66v %-42v %-42v %-19v %v\n"
+	fmt.Printf(lineFormat, "Idx", "Move", "Parent", "Depth", "Trace", "Value", "Claimant", "Countered By", "Time (UTC)", "Clock Used")
+	for i, claim := range claims {
+		pos := claim.Position
+		move := "Root"
+		parent := ""
+		if i > 0 {
+			parentClaim := claims[claim.ParentContractIndex]
+			move = "Defend"
+			if parentClaim.Position.Attack() == pos {
+				move = "Attack"
+			}
+			parent = fmt.Sprintf("%v", claim.ParentContractIndex)
+		}
+		var trace string
+		if pos.Depth() <= splitDepth {
+			// Output root claims commit to the output root at a block in the disputed range
+			traceIdx := pos.TraceIndex(splitDepth)
+			block := new(big.Int).Add(new(big.Int).SetUint64(prestateBlock), traceIdx)
+			block.Add(block, big.NewInt(1))
+			if block.Cmp(new(big.Int).SetUint64(poststateBlock)) > 0 {
+				block.SetUint64(poststateBlock)
+			}
+			trace = fmt.Sprintf("block %v", block)
+		} else {
+			// Execution trace claims commit to the VM state after a step
+			relativePos, err := pos.RelativeToAncestorAtDepth(splitDepth + 1)
+			if err != nil {
+				return fmt.Errorf("invalid position for claim %v: %w", i, err)
+			}
+			trace = fmt.Sprintf("step %v", relativePos.TraceIndex(maxDepth-splitDepth-1))
+		}
+		countered := "-"
+		if claim.CounteredBy != (common.Address{}) {
+			countered = claim.CounteredBy.Hex()
+		}
+		timestamp := time.Unix(int64(claim.Clock), 0).UTC().Format(time.DateTime)
+		fmt.Printf(lineFormat, i, move, parent, pos.Depth(), trace, claim.Value.Hex(), claim.Claimant.Hex(), countered,
+			timestamp, time.Duration(clockUsed[i])*time.Second)
+	}
+	return nil
+}
+
+func listClaimsFlags() []cli.Flag {
+	return readFlags(GameAddressFlag)
+}
+
+var ListClaimsCommand = &cli.Command{
+	Name:        "list-claims",
+	Usage:       "List the claims in a dispute game",
+	Description: "Prints the claim tree of a dispute game, including the parent, position, trace index and clock of each claim",
+	Action:      ListClaims,
+	Flags:       listClaimsFlags(),
+}
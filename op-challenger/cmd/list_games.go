@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+)
+
+func ListGames(ctx *cli.Context) error {
+	l1Client, err := dialL1(ctx)
+	if err != nil {
+		return err
+	}
+	defer l1Client.Close()
+	factory, err := newContract(ctx, l1Client, flags.FactoryAddressFlag.Name, contracts.NewDisputeGameFactoryContract)
+	if err != nil {
+		return err
+	}
+	head, err := l1Client.HeaderByNumber(ctx.Context, nil)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve current head block: %w", err)
+	}
+	caller := batching.NewMultiCaller(l1Client.Client(), batching.DefaultBatchSize)
+	return listGames(ctx.Context, factory, caller, head.Hash())
+}
+
+func listGames(ctx context.Context, factory *contracts.DisputeGameFactoryContract, caller *batching.MultiCaller, blockHash common.Hash) error {
+	count, err := factory.GetGameCount(ctx, blockHash)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-5v %-42v %-4v %-19v %-10v %-6v %v\n", "Idx", "Game", "Type", "Created (UTC)", "L2 Block", "Claims", "Status")
+	for i := uint64(0); i < count; i++ {
+		game, err := factory.GetGame(ctx, i, blockHash)
+		if err != nil {
+			return err
+		}
+		created := time.Unix(int64(game.Timestamp), 0).UTC().Format(time.DateTime)
+		gameContract, err := contracts.NewFaultDisputeGameContract(game.Proxy, caller)
+		if err != nil {
+			return fmt.Errorf("failed to create contract bindings for game %v: %w", game.Proxy, err)
+		}
+		_, l2BlockNum, err := gameContract.GetBlockRange(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load block range for game %v: %w", game.Proxy, err)
+		}
+		claimCount, err := gameContract.GetClaimCount(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load claim count for game %v: %w", game.Proxy, err)
+		}
+		status, err := gameContract.GetStatus(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load status for game %v: %w", game.Proxy, err)
+		}
+		fmt.Printf("%-5v %-42v %-4v %-19v %-10v %-6v %v\n", i, game.Proxy, game.GameType, created, l2BlockNum, claimCount, status)
+	}
+	return nil
+}
+
+func listGamesFlags() []cli.Flag {
+	return readFlags(flags.FactoryAddressFlag)
+}
+
+var ListGamesCommand = &cli.Command{
+	Name:        "list-games",
+	Usage:       "List the games created by a dispute game factory",
+	Description: "Lists the games created by a dispute game factory along with their status",
+	Action:      ListGames,
+	Flags:       listGamesFlags(),
+}
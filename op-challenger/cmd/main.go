@@ -58,6 +58,14 @@ func run(ctx context.Context, args []string, action ConfiguredLifecycle) error {
 		}
 		return action(ctx.Context, logger, cfg)
 	})
+	app.Commands = []*cli.Command{
+		ListGamesCommand,
+		ListClaimsCommand,
+		CreateGameCommand,
+		MoveCommand,
+		ResolveCommand,
+		ResolveClaimCommand,
+	}
 	return app.RunContext(ctx, args)
 }
 
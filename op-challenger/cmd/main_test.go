@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -28,6 +29,11 @@ var (
 	datadir                 = "./test_data"
 	cannonL2                = "http://example.com:9545"
 	rollupRpc               = "http://example.com:8555"
+	asteriscNetwork         = "op-mainnet"
+	asteriscBin             = "./bin/asterisc"
+	asteriscServer          = "./bin/op-program"
+	asteriscPreState        = "./pre.json"
+	asteriscL2              = "http://example.com:9545"
 )
 
 func TestLogLevel(t *testing.T) {
@@ -349,6 +355,25 @@ func TestGameWindow(t *testing.T) {
 	})
 }
 
+func TestPreimageCacheSize(t *testing.T) {
+	t.Run("DefaultDisabled", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeCannon))
+		require.Zero(t, cfg.PreimageCacheSize)
+		require.Equal(t, "", cfg.PreimageCacheDir())
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeCannon, "--preimage-cache-size=2048"))
+		require.Equal(t, uint64(2048*1024*1024), cfg.PreimageCacheSize)
+		require.Equal(t, filepath.Join(cfg.Datadir, "preimage-cache"), cfg.PreimageCacheDir())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid value \"abc\" for flag -preimage-cache-size",
+			addRequiredArgs(config.TraceTypeCannon, "--preimage-cache-size=abc"))
+	})
+}
+
 func TestRequireEitherCannonNetworkOrRollupAndGenesis(t *testing.T) {
 	verifyArgsInvalid(
 		t,
@@ -410,6 +435,82 @@ func TestCannonL2Genesis(t *testing.T) {
 	})
 }
 
+func TestPermissionedTraceType(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypePermissioned))
+		require.Equal(t, []config.TraceType{config.TraceTypePermissioned}, cfg.TraceTypes)
+		require.Equal(t, cannonBin, cfg.CannonBin)
+	})
+
+	t.Run("RequiresCannonArgs", func(t *testing.T) {
+		verifyArgsInvalid(t, "flag cannon-bin is required", addRequiredArgsExcept(config.TraceTypePermissioned, "--cannon-bin"))
+		verifyArgsInvalid(t, "flag cannon-l2 is required", addRequiredArgsExcept(config.TraceTypePermissioned, "--cannon-l2"))
+	})
+
+	t.Run("RequiresRollupRpc", func(t *testing.T) {
+		verifyArgsInvalid(t, "flag rollup-rpc is required", addRequiredArgsExcept(config.TraceTypePermissioned, "--rollup-rpc"))
+	})
+}
+
+func TestAsteriscRequiredArgs(t *testing.T) {
+	for _, name := range []string{"asterisc-bin", "asterisc-server", "asterisc-prestate", "asterisc-l2"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Run("NotRequiredForCannonTrace", func(t *testing.T) {
+				configForArgs(t, addRequiredArgsExcept(config.TraceTypeCannon, "--"+name))
+			})
+
+			t.Run("Required", func(t *testing.T) {
+				verifyArgsInvalid(t, "flag "+name+" is required", addRequiredArgsExcept(config.TraceTypeAsterisc, "--"+name))
+			})
+		})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAsterisc))
+		require.Equal(t, asteriscBin, cfg.AsteriscBin)
+		require.Equal(t, asteriscServer, cfg.AsteriscServer)
+		require.Equal(t, asteriscPreState, cfg.AsteriscAbsolutePreState)
+		require.Equal(t, asteriscL2, cfg.AsteriscL2)
+		require.Equal(t, asteriscNetwork, cfg.AsteriscNetwork)
+		require.Equal(t, config.DefaultAsteriscSnapshotFreq, cfg.AsteriscSnapshotFreq)
+		require.Equal(t, config.DefaultAsteriscInfoFreq, cfg.AsteriscInfoFreq)
+	})
+
+	t.Run("RollupRpc", func(t *testing.T) {
+		verifyArgsInvalid(t, "flag rollup-rpc is required", addRequiredArgsExcept(config.TraceTypeAsterisc, "--rollup-rpc"))
+	})
+}
+
+func TestAsteriscNetworkOrRollupAndGenesis(t *testing.T) {
+	t.Run("RequireEither", func(t *testing.T) {
+		verifyArgsInvalid(
+			t,
+			"flag asterisc-network or asterisc-rollup-config and asterisc-l2-genesis is required",
+			addRequiredArgsExcept(config.TraceTypeAsterisc, "--asterisc-network", "--asterisc-rollup-config=rollup.json"))
+	})
+
+	t.Run("MustNotSpecifyBoth", func(t *testing.T) {
+		verifyArgsInvalid(
+			t,
+			"flag asterisc-network can not be used with asterisc-rollup-config and asterisc-l2-genesis",
+			addRequiredArgs(config.TraceTypeAsterisc, "--asterisc-rollup-config=rollup.json"))
+	})
+
+	t.Run("RollupAndGenesis", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgsExcept(config.TraceTypeAsterisc, "--asterisc-network",
+			"--asterisc-rollup-config=rollup.json", "--asterisc-l2-genesis=genesis.json"))
+		require.Equal(t, "rollup.json", cfg.AsteriscRollupConfigPath)
+		require.Equal(t, "genesis.json", cfg.AsteriscL2GenesisPath)
+	})
+}
+
+func TestAsteriscSnapshotAndInfoFreq(t *testing.T) {
+	cfg := configForArgs(t, addRequiredArgs(config.TraceTypeAsterisc, "--asterisc-snapshot-freq=1234", "--asterisc-info-freq=5678"))
+	require.Equal(t, uint(1234), cfg.AsteriscSnapshotFreq)
+	require.Equal(t, uint(5678), cfg.AsteriscInfoFreq)
+}
+
 func verifyArgsInvalid(t *testing.T, messageContains string, cliArgs []string) {
 	_, _, err := dryRunWithArgs(cliArgs)
 	require.ErrorContains(t, err, messageContains)
@@ -457,8 +558,10 @@ func requiredArgs(traceType config.TraceType) map[string]string {
 		"--datadir":              datadir,
 	}
 	switch traceType {
-	case config.TraceTypeCannon:
+	case config.TraceTypeCannon, config.TraceTypePermissioned:
 		addRequiredCannonArgs(args)
+	case config.TraceTypeAsterisc:
+		addRequiredAsteriscArgs(args)
 	case config.TraceTypeAlphabet:
 		addRequiredOutputArgs(args)
 	}
@@ -474,6 +577,15 @@ func addRequiredCannonArgs(args map[string]string) {
 	addRequiredOutputArgs(args)
 }
 
+func addRequiredAsteriscArgs(args map[string]string) {
+	args["--asterisc-network"] = asteriscNetwork
+	args["--asterisc-bin"] = asteriscBin
+	args["--asterisc-server"] = asteriscServer
+	args["--asterisc-prestate"] = asteriscPreState
+	args["--asterisc-l2"] = asteriscL2
+	addRequiredOutputArgs(args)
+}
+
 func addRequiredOutputArgs(args map[string]string) {
 	args["--rollup-rpc"] = rollupRpc
 }
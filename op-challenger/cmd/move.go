@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+var (
+	AttackFlag = &cli.BoolFlag{
+		Name:    "attack",
+		Usage:   "An attack move. If true, the defend flag must not be set.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "ATTACK"),
+	}
+	DefendFlag = &cli.BoolFlag{
+		Name:    "defend",
+		Usage:   "A defending move. If true, the attack flag must not be set.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "DEFEND"),
+	}
+	ParentIndexFlag = &cli.Uint64Flag{
+		Name:    "parent-index",
+		Usage:   "The index of the claim to move on.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "PARENT_INDEX"),
+	}
+	ClaimFlag = &cli.StringFlag{
+		Name:    "claim",
+		Usage:   "The claim hash.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "CLAIM"),
+	}
+)
+
+func Move(ctx *cli.Context) error {
+	attack := ctx.Bool(AttackFlag.Name)
+	defend := ctx.Bool(DefendFlag.Name)
+	if attack == defend {
+		return fmt.Errorf("exactly one of %v and %v must be set", AttackFlag.Name, DefendFlag.Name)
+	}
+	if !ctx.IsSet(ParentIndexFlag.Name) {
+		return fmt.Errorf("missing %v", ParentIndexFlag.Name)
+	}
+	parentIndex := ctx.Uint64(ParentIndexFlag.Name)
+	claim, err := opservice.ParseHash(ctx.String(ClaimFlag.Name))
+	if err != nil {
+		return fmt.Errorf("invalid %v: %w", ClaimFlag.Name, err)
+	}
+
+	game, txMgr, err := NewContractWithTxMgr(ctx, GameAddressFlag.Name, contracts.NewFaultDisputeGameContract)
+	if err != nil {
+		return err
+	}
+	defer txMgr.Close()
+
+	return move(ctx.Context, game, txMgr, parentIndex, claim, attack)
+}
+
+func move(ctx context.Context, game *contracts.FaultDisputeGameContract, txMgr txmgr.TxManager, parentIndex uint64, claim common.Hash, attack bool) error {
+	parent, err := game.GetClaim(ctx, parentIndex)
+	if err != nil {
+		return fmt.Errorf("failed to load parent claim %v: %w", parentIndex, err)
+	}
+	var tx txmgr.TxCandidate
+	if attack {
+		tx, err = game.AttackTx(parentIndex, claim)
+		if err != nil {
+			return fmt.Errorf("failed to create attack tx: %w", err)
+		}
+		tx.Value, err = game.GetRequiredBond(ctx, parent.Position.Attack())
+	} else {
+		tx, err = game.DefendTx(parentIndex, claim)
+		if err != nil {
+			return fmt.Errorf("failed to create defense tx: %w", err)
+		}
+		tx.Value, err = game.GetRequiredBond(ctx, parent.Position.Defend())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load required bond: %w", err)
+	}
+	return sendTx(ctx, txMgr, tx)
+}
+
+// sendTx sends the transaction and waits for it to be included, returning an error if it reverted.
+func sendTx(ctx context.Context, txMgr txmgr.TxManager, tx txmgr.TxCandidate) error {
+	rct, err := txMgr.Send(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to send tx: %w", err)
+	}
+	if rct.Status != ethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction (%v) reverted", rct.TxHash.Hex())
+	}
+	fmt.Printf("Transaction (%v) included in block %v\n", rct.TxHash.Hex(), rct.BlockNumber)
+	return nil
+}
+
+func moveFlags() []cli.Flag {
+	return txFlags(GameAddressFlag, AttackFlag, DefendFlag, ParentIndexFlag, ClaimFlag)
+}
+
+var MoveCommand = &cli.Command{
+	Name:        "move",
+	Usage:       "Creates and sends a move transaction to the dispute game",
+	Description: "Creates and sends an attack or defend move transaction to the dispute game, posting the required bond",
+	Action:      Move,
+	Flags:       moveFlags(),
+}
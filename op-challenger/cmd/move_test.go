@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveRequiresExactlyOneOfAttackOrDefend(t *testing.T) {
+	t.Run("Neither", func(t *testing.T) {
+		verifyArgsInvalid(t, "exactly one of attack and defend must be set",
+			[]string{"move", "--parent-index", "0", "--claim", "0x1234567890123456789012345678901234567890123456789012345678901234"})
+	})
+
+	t.Run("Both", func(t *testing.T) {
+		verifyArgsInvalid(t, "exactly one of attack and defend must be set",
+			[]string{"move", "--attack", "--defend", "--parent-index", "0", "--claim", "0x1234567890123456789012345678901234567890123456789012345678901234"})
+	})
+}
+
+func TestMoveRequiresParentIndex(t *testing.T) {
+	verifyArgsInvalid(t, "missing parent-index",
+		[]string{"move", "--attack", "--claim", "0x1234567890123456789012345678901234567890123456789012345678901234"})
+}
+
+func TestMoveRequiresValidClaim(t *testing.T) {
+	verifyArgsInvalid(t, "invalid claim",
+		[]string{"move", "--attack", "--parent-index", "0", "--claim", "0x1234"})
+}
+
+func TestCreateGameRequiresValidOutputRoot(t *testing.T) {
+	verifyArgsInvalid(t, "invalid output-root", []string{"create-game", "--l2-block-num", "5", "--output-root", "foo"})
+}
+
+func TestCreateGameRequiresL2BlockNum(t *testing.T) {
+	verifyArgsInvalid(t, "missing l2-block-num",
+		[]string{"create-game", "--output-root", "0x1234567890123456789012345678901234567890123456789012345678901234"})
+}
+
+func TestSubcommandsDoNotRequireServiceArgs(t *testing.T) {
+	// Subcommands have their own flags and do not need the flags required to run the challenger service.
+	_, _, err := dryRunWithArgs([]string{"list-games", "--help"})
+	require.NoError(t, err)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+var (
+	ClaimIndexFlag = &cli.Uint64Flag{
+		Name:    "claim",
+		Usage:   "Index of the claim to resolve.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "CLAIM_INDEX"),
+	}
+)
+
+func Resolve(ctx *cli.Context) error {
+	game, txMgr, err := NewContractWithTxMgr(ctx, GameAddressFlag.Name, contracts.NewFaultDisputeGameContract)
+	if err != nil {
+		return err
+	}
+	defer txMgr.Close()
+	return resolve(ctx.Context, game, txMgr)
+}
+
+func resolve(ctx context.Context, game *contracts.FaultDisputeGameContract, txMgr txmgr.TxManager) error {
+	result, err := game.CallResolve(ctx)
+	if err != nil {
+		return fmt.Errorf("game cannot be resolved: %w", err)
+	}
+	tx, err := game.ResolveTx()
+	if err != nil {
+		return fmt.Errorf("failed to create resolve tx: %w", err)
+	}
+	if err := sendTx(ctx, txMgr, tx); err != nil {
+		return err
+	}
+	fmt.Printf("Game resolved: %v\n", result)
+	return nil
+}
+
+func ResolveClaim(ctx *cli.Context) error {
+	if !ctx.IsSet(ClaimIndexFlag.Name) {
+		return fmt.Errorf("missing %v", ClaimIndexFlag.Name)
+	}
+	claimIdx := ctx.Uint64(ClaimIndexFlag.Name)
+	game, txMgr, err := NewContractWithTxMgr(ctx, GameAddressFlag.Name, contracts.NewFaultDisputeGameContract)
+	if err != nil {
+		return err
+	}
+	defer txMgr.Close()
+	if err := game.CallResolveClaim(ctx.Context, claimIdx); err != nil {
+		return fmt.Errorf("claim %v cannot be resolved: %w", claimIdx, err)
+	}
+	tx, err := game.ResolveClaimTx(claimIdx)
+	if err != nil {
+		return fmt.Errorf("failed to create resolve claim tx: %w", err)
+	}
+	return sendTx(ctx.Context, txMgr, tx)
+}
+
+var ResolveCommand = &cli.Command{
+	Name:        "resolve",
+	Usage:       "Resolves the specified dispute game if possible",
+	Description: "Resolves the specified dispute game if possible. All claims must already have been resolved",
+	Action:      Resolve,
+	Flags:       txFlags(GameAddressFlag),
+}
+
+var ResolveClaimCommand = &cli.Command{
+	Name:        "resolve-claim",
+	Usage:       "Resolves the specified claim if possible",
+	Description: "Resolves the specified claim if possible. Its clock must have expired and all of its counter claims resolved",
+	Action:      ResolveClaim,
+	Flags:       txFlags(GameAddressFlag, ClaimIndexFlag),
+}
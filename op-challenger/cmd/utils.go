@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/flags"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+	"github.com/ethereum-optimism/optimism/op-service/dial"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	txmetrics "github.com/ethereum-optimism/optimism/op-service/txmgr/metrics"
+)
+
+var (
+	GameAddressFlag = &cli.StringFlag{
+		Name:    "game-address",
+		Usage:   "Address of the fault dispute game contract.",
+		EnvVars: opservice.PrefixEnvVar(flags.EnvVarPrefix, "GAME_ADDRESS"),
+	}
+)
+
+type ContractCreator[T any] func(common.Address, *batching.MultiCaller) (T, error)
+
+// NewContractWithTxMgr creates a contract binding for the address in the specified flag,
+// along with a transaction manager to send transactions to it.
+func NewContractWithTxMgr[T any](ctx *cli.Context, flagName string, creator ContractCreator[T]) (T, txmgr.TxManager, error) {
+	var contract T
+	l1Client, txMgr, err := newClientsFromCLI(ctx)
+	if err != nil {
+		return contract, nil, err
+	}
+	contract, err = newContract(ctx, l1Client, flagName, creator)
+	if err != nil {
+		txMgr.Close()
+		return contract, nil, err
+	}
+	return contract, txMgr, nil
+}
+
+// NewContract creates a read-only contract binding for the address in the specified flag.
+func NewContract[T any](ctx *cli.Context, flagName string, creator ContractCreator[T]) (T, error) {
+	var contract T
+	l1Client, err := dialL1(ctx)
+	if err != nil {
+		return contract, err
+	}
+	return newContract(ctx, l1Client, flagName, creator)
+}
+
+func newContract[T any](ctx *cli.Context, l1Client *ethclient.Client, flagName string, creator ContractCreator[T]) (T, error) {
+	var contract T
+	addr, err := opservice.ParseAddress(ctx.String(flagName))
+	if err != nil {
+		return contract, fmt.Errorf("invalid %v: %w", flagName, err)
+	}
+	caller := batching.NewMultiCaller(l1Client.Client(), batching.DefaultBatchSize)
+	contract, err = creator(addr, caller)
+	if err != nil {
+		return contract, fmt.Errorf("failed to create contract bindings: %w", err)
+	}
+	return contract, nil
+}
+
+func dialL1(ctx *cli.Context) (*ethclient.Client, error) {
+	logger := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	rpcUrl := ctx.String(flags.L1EthRpcFlag.Name)
+	if rpcUrl == "" {
+		return nil, fmt.Errorf("missing %v", flags.L1EthRpcFlag.Name)
+	}
+	return dial.DialEthClientWithTimeout(ctx.Context, dial.DefaultDialTimeout, logger, rpcUrl)
+}
+
+func newClientsFromCLI(ctx *cli.Context) (*ethclient.Client, txmgr.TxManager, error) {
+	logger := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
+	l1Client, err := dialL1(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	txMgrConfig := txmgr.ReadCLIConfig(ctx)
+	txMgr, err := txmgr.NewSimpleTxManager("challenger", logger, &txmetrics.NoopTxMetrics{}, txMgrConfig)
+	if err != nil {
+		l1Client.Close()
+		return nil, nil, fmt.Errorf("failed to create the transaction manager: %w", err)
+	}
+	return l1Client, txMgr, nil
+}
+
+// txFlags returns the flags required by commands that send transactions.
+func txFlags(cmdFlags ...cli.Flag) []cli.Flag {
+	cmdFlags = append(cmdFlags, flags.L1EthRpcFlag)
+	cmdFlags = append(cmdFlags, txmgr.CLIFlagsWithDefaults(flags.EnvVarPrefix, txmgr.DefaultChallengerFlagValues)...)
+	cmdFlags = append(cmdFlags, oplog.CLIFlags(flags.EnvVarPrefix)...)
+	return cliapp.ProtectFlags(cmdFlags)
+}
+
+// readFlags returns the flags required by commands that only read from L1.
+func readFlags(cmdFlags ...cli.Flag) []cli.Flag {
+	cmdFlags = append(cmdFlags, flags.L1EthRpcFlag)
+	cmdFlags = append(cmdFlags, oplog.CLIFlags(flags.EnvVarPrefix)...)
+	return cliapp.ProtectFlags(cmdFlags)
+}
@@ -20,17 +20,30 @@ var (
 	validDatadir               = "/tmp/data"
 	validCannonL2              = "http://localhost:9545"
 	validRollupRpc             = "http://localhost:8555"
+
+	validAsteriscBin             = "./bin/asterisc"
+	validAsteriscOpProgramBin    = "./bin/op-program"
+	validAsteriscNetwork         = "mainnet"
+	validAsteriscAbsolutPreState = "pre.json"
+	validAsteriscL2              = "http://localhost:9545"
 )
 
 func validConfig(traceType TraceType) Config {
 	cfg := NewConfig(validGameFactoryAddress, validL1EthRpc, validDatadir, traceType)
-	if traceType == TraceTypeCannon {
+	if traceType == TraceTypeCannon || traceType == TraceTypePermissioned {
 		cfg.CannonBin = validCannonBin
 		cfg.CannonServer = validCannonOpProgramBin
 		cfg.CannonAbsolutePreState = validCannonAbsolutPreState
 		cfg.CannonL2 = validCannonL2
 		cfg.CannonNetwork = validCannonNetwork
 	}
+	if traceType == TraceTypeAsterisc {
+		cfg.AsteriscBin = validAsteriscBin
+		cfg.AsteriscServer = validAsteriscOpProgramBin
+		cfg.AsteriscAbsolutePreState = validAsteriscAbsolutPreState
+		cfg.AsteriscL2 = validAsteriscL2
+		cfg.AsteriscNetwork = validAsteriscNetwork
+	}
 	cfg.RollupRpc = validRollupRpc
 	return cfg
 }
@@ -109,6 +122,20 @@ func TestMaxConcurrency(t *testing.T) {
 	})
 }
 
+func TestPreimageCacheDataFormat(t *testing.T) {
+	cfg := validConfig(TraceTypeCannon)
+	cfg.PreimageCacheSize = 1_000_000
+	t.Setenv("OP_PROGRAM_DATA_FORMAT", "directory")
+	require.NoError(t, cfg.Check())
+
+	t.Setenv("OP_PROGRAM_DATA_FORMAT", "pebble")
+	require.ErrorIs(t, cfg.Check(), ErrPreimageCacheDataFormat)
+
+	// Pre-images stored per game may use any format
+	cfg.PreimageCacheSize = 0
+	require.NoError(t, cfg.Check())
+}
+
 func TestHttpPollInterval(t *testing.T) {
 	t.Run("Default", func(t *testing.T) {
 		config := validConfig(TraceTypeAlphabet)
@@ -188,6 +215,55 @@ func TestNetworkMustBeValid(t *testing.T) {
 	require.ErrorIs(t, cfg.Check(), ErrCannonNetworkUnknown)
 }
 
+func TestAsteriscRequiredArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(cfg *Config)
+		expected error
+	}{
+		{"Bin", func(cfg *Config) { cfg.AsteriscBin = "" }, ErrMissingAsteriscBin},
+		{"Server", func(cfg *Config) { cfg.AsteriscServer = "" }, ErrMissingAsteriscServer},
+		{"AbsolutePreState", func(cfg *Config) { cfg.AsteriscAbsolutePreState = "" }, ErrMissingAsteriscAbsolutePreState},
+		{"L2", func(cfg *Config) { cfg.AsteriscL2 = "" }, ErrMissingAsteriscL2},
+		{"SnapshotFreq", func(cfg *Config) { cfg.AsteriscSnapshotFreq = 0 }, ErrMissingAsteriscSnapshotFreq},
+		{"InfoFreq", func(cfg *Config) { cfg.AsteriscInfoFreq = 0 }, ErrMissingAsteriscInfoFreq},
+		{"RollupRpc", func(cfg *Config) { cfg.RollupRpc = "" }, ErrMissingRollupRpc},
+		{"NetworkOrRollupConfig", func(cfg *Config) {
+			cfg.AsteriscNetwork = ""
+			cfg.AsteriscL2GenesisPath = "genesis.json"
+		}, ErrMissingAsteriscRollupConfig},
+		{"NetworkOrL2Genesis", func(cfg *Config) {
+			cfg.AsteriscNetwork = ""
+			cfg.AsteriscRollupConfigPath = "foo.json"
+		}, ErrMissingAsteriscL2Genesis},
+		{"NetworkAndRollupConfig", func(cfg *Config) { cfg.AsteriscRollupConfigPath = "foo.json" }, ErrAsteriscNetworkAndRollupConfig},
+		{"NetworkAndL2Genesis", func(cfg *Config) { cfg.AsteriscL2GenesisPath = "foo.json" }, ErrAsteriscNetworkAndL2Genesis},
+		{"UnknownNetwork", func(cfg *Config) { cfg.AsteriscNetwork = "unknown" }, ErrAsteriscNetworkUnknown},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cfg := validConfig(TraceTypeAsterisc)
+			test.modify(&cfg)
+			require.ErrorIs(t, cfg.Check(), test.expected)
+		})
+	}
+}
+
+func TestPermissionedRequiresCannonArgs(t *testing.T) {
+	cfg := validConfig(TraceTypePermissioned)
+	cfg.CannonBin = ""
+	require.ErrorIs(t, cfg.Check(), ErrMissingCannonBin)
+
+	cfg = validConfig(TraceTypePermissioned)
+	cfg.CannonL2 = ""
+	require.ErrorIs(t, cfg.Check(), ErrMissingCannonL2)
+
+	cfg = validConfig(TraceTypePermissioned)
+	cfg.RollupRpc = ""
+	require.ErrorIs(t, cfg.Check(), ErrMissingRollupRpc)
+}
+
 func TestRequireConfigForMultipleTraceTypes(t *testing.T) {
 	cfg := validConfig(TraceTypeCannon)
 	cfg.TraceTypes = []TraceType{TraceTypeCannon, TraceTypeAlphabet}
@@ -20,11 +20,11 @@ import (
 )
 
 const (
-	envVarPrefix = "OP_CHALLENGER"
+	EnvVarPrefix = "OP_CHALLENGER"
 )
 
 func prefixEnvVars(name string) []string {
-	return opservice.PrefixEnvVar(envVarPrefix, name)
+	return opservice.PrefixEnvVar(EnvVarPrefix, name)
 }
 
 var (
@@ -77,53 +77,109 @@ var (
 	CannonNetworkFlag = &cli.StringFlag{
 		Name: "cannon-network",
 		Usage: fmt.Sprintf(
-			"Predefined network selection. Available networks: %s (cannon trace type only)",
+			"Predefined network selection. Available networks: %s (cannon and permissioned trace types only)",
 			strings.Join(chaincfg.AvailableNetworks(), ", "),
 		),
 		EnvVars: prefixEnvVars("CANNON_NETWORK"),
 	}
 	CannonRollupConfigFlag = &cli.StringFlag{
 		Name:    "cannon-rollup-config",
-		Usage:   "Rollup chain parameters (cannon trace type only)",
+		Usage:   "Rollup chain parameters (cannon and permissioned trace types only)",
 		EnvVars: prefixEnvVars("CANNON_ROLLUP_CONFIG"),
 	}
 	CannonL2GenesisFlag = &cli.StringFlag{
 		Name:    "cannon-l2-genesis",
-		Usage:   "Path to the op-geth genesis file (cannon trace type only)",
+		Usage:   "Path to the op-geth genesis file (cannon and permissioned trace types only)",
 		EnvVars: prefixEnvVars("CANNON_L2_GENESIS"),
 	}
 	CannonBinFlag = &cli.StringFlag{
 		Name:    "cannon-bin",
-		Usage:   "Path to cannon executable to use when generating trace data (cannon trace type only)",
+		Usage:   "Path to cannon executable to use when generating trace data (cannon and permissioned trace types only)",
 		EnvVars: prefixEnvVars("CANNON_BIN"),
 	}
 	CannonServerFlag = &cli.StringFlag{
 		Name:    "cannon-server",
-		Usage:   "Path to executable to use as pre-image oracle server when generating trace data (cannon trace type only)",
+		Usage:   "Path to executable to use as pre-image oracle server when generating trace data (cannon and permissioned trace types only)",
 		EnvVars: prefixEnvVars("CANNON_SERVER"),
 	}
 	CannonPreStateFlag = &cli.StringFlag{
 		Name:    "cannon-prestate",
-		Usage:   "Path to absolute prestate to use when generating trace data (cannon trace type only)",
+		Usage:   "Path to absolute prestate to use when generating trace data (cannon and permissioned trace types only)",
 		EnvVars: prefixEnvVars("CANNON_PRESTATE"),
 	}
 	CannonL2Flag = &cli.StringFlag{
 		Name:    "cannon-l2",
-		Usage:   "L2 Address of L2 JSON-RPC endpoint to use (eth and debug namespace required)  (cannon trace type only)",
+		Usage:   "L2 Address of L2 JSON-RPC endpoint to use (eth and debug namespace required)  (cannon and permissioned trace types only)",
 		EnvVars: prefixEnvVars("CANNON_L2"),
 	}
 	CannonSnapshotFreqFlag = &cli.UintFlag{
 		Name:    "cannon-snapshot-freq",
-		Usage:   "Frequency of cannon snapshots to generate in VM steps (cannon trace type only)",
+		Usage:   "Frequency of cannon snapshots to generate in VM steps (cannon and permissioned trace types only)",
 		EnvVars: prefixEnvVars("CANNON_SNAPSHOT_FREQ"),
 		Value:   config.DefaultCannonSnapshotFreq,
 	}
 	CannonInfoFreqFlag = &cli.UintFlag{
 		Name:    "cannon-info-freq",
-		Usage:   "Frequency of cannon info log messages to generate in VM steps (cannon trace type only)",
+		Usage:   "Frequency of cannon info log messages to generate in VM steps (cannon and permissioned trace types only)",
 		EnvVars: prefixEnvVars("CANNON_INFO_FREQ"),
 		Value:   config.DefaultCannonInfoFreq,
 	}
+	AsteriscNetworkFlag = &cli.StringFlag{
+		Name: "asterisc-network",
+		Usage: fmt.Sprintf(
+			"Predefined network selection. Available networks: %s (asterisc trace type only)",
+			strings.Join(chaincfg.AvailableNetworks(), ", "),
+		),
+		EnvVars: prefixEnvVars("ASTERISC_NETWORK"),
+	}
+	AsteriscRollupConfigFlag = &cli.StringFlag{
+		Name:    "asterisc-rollup-config",
+		Usage:   "Rollup chain parameters (asterisc trace type only)",
+		EnvVars: prefixEnvVars("ASTERISC_ROLLUP_CONFIG"),
+	}
+	AsteriscL2GenesisFlag = &cli.StringFlag{
+		Name:    "asterisc-l2-genesis",
+		Usage:   "Path to the op-geth genesis file (asterisc trace type only)",
+		EnvVars: prefixEnvVars("ASTERISC_L2_GENESIS"),
+	}
+	AsteriscBinFlag = &cli.StringFlag{
+		Name:    "asterisc-bin",
+		Usage:   "Path to asterisc executable to use when generating trace data (asterisc trace type only)",
+		EnvVars: prefixEnvVars("ASTERISC_BIN"),
+	}
+	AsteriscServerFlag = &cli.StringFlag{
+		Name:    "asterisc-server",
+		Usage:   "Path to executable to use as pre-image oracle server when generating trace data (asterisc trace type only)",
+		EnvVars: prefixEnvVars("ASTERISC_SERVER"),
+	}
+	AsteriscPreStateFlag = &cli.StringFlag{
+		Name:    "asterisc-prestate",
+		Usage:   "Path to absolute prestate to use when generating trace data (asterisc trace type only)",
+		EnvVars: prefixEnvVars("ASTERISC_PRESTATE"),
+	}
+	AsteriscL2Flag = &cli.StringFlag{
+		Name:    "asterisc-l2",
+		Usage:   "L2 Address of L2 JSON-RPC endpoint to use (eth and debug namespace required)  (asterisc trace type only)",
+		EnvVars: prefixEnvVars("ASTERISC_L2"),
+	}
+	AsteriscSnapshotFreqFlag = &cli.UintFlag{
+		Name:    "asterisc-snapshot-freq",
+		Usage:   "Frequency of asterisc snapshots to generate in VM steps (asterisc trace type only)",
+		EnvVars: prefixEnvVars("ASTERISC_SNAPSHOT_FREQ"),
+		Value:   config.DefaultAsteriscSnapshotFreq,
+	}
+	AsteriscInfoFreqFlag = &cli.UintFlag{
+		Name:    "asterisc-info-freq",
+		Usage:   "Frequency of asterisc info log messages to generate in VM steps (asterisc trace type only)",
+		EnvVars: prefixEnvVars("ASTERISC_INFO_FREQ"),
+		Value:   config.DefaultAsteriscInfoFreq,
+	}
+	PreimageCacheSizeFlag = &cli.Uint64Flag{
+		Name: "preimage-cache-size",
+		Usage: "Maximum size in MiB of the pre-image cache shared by the cannon and asterisc executions of all games. " +
+			"If 0, pre-images are stored separately for each game.",
+		EnvVars: prefixEnvVars("PREIMAGE_CACHE_SIZE"),
+	}
 	GameWindowFlag = &cli.DurationFlag{
 		Name:    "game-window",
 		Usage:   "The time window which the challenger will look for games to progress.",
@@ -155,14 +211,24 @@ var optionalFlags = []cli.Flag{
 	CannonL2Flag,
 	CannonSnapshotFreqFlag,
 	CannonInfoFreqFlag,
+	AsteriscNetworkFlag,
+	AsteriscRollupConfigFlag,
+	AsteriscL2GenesisFlag,
+	AsteriscBinFlag,
+	AsteriscServerFlag,
+	AsteriscPreStateFlag,
+	AsteriscL2Flag,
+	AsteriscSnapshotFreqFlag,
+	AsteriscInfoFreqFlag,
 	GameWindowFlag,
+	PreimageCacheSizeFlag,
 }
 
 func init() {
-	optionalFlags = append(optionalFlags, oplog.CLIFlags(envVarPrefix)...)
-	optionalFlags = append(optionalFlags, txmgr.CLIFlagsWithDefaults(envVarPrefix, txmgr.DefaultChallengerFlagValues)...)
-	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(envVarPrefix)...)
-	optionalFlags = append(optionalFlags, oppprof.CLIFlags(envVarPrefix)...)
+	optionalFlags = append(optionalFlags, oplog.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, txmgr.CLIFlagsWithDefaults(EnvVarPrefix, txmgr.DefaultChallengerFlagValues)...)
+	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, oppprof.CLIFlags(EnvVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -196,6 +262,32 @@ func CheckCannonFlags(ctx *cli.Context) error {
 	return nil
 }
 
+func CheckAsteriscFlags(ctx *cli.Context) error {
+	if !ctx.IsSet(AsteriscNetworkFlag.Name) &&
+		!(ctx.IsSet(AsteriscRollupConfigFlag.Name) && ctx.IsSet(AsteriscL2GenesisFlag.Name)) {
+		return fmt.Errorf("flag %v or %v and %v is required",
+			AsteriscNetworkFlag.Name, AsteriscRollupConfigFlag.Name, AsteriscL2GenesisFlag.Name)
+	}
+	if ctx.IsSet(AsteriscNetworkFlag.Name) &&
+		(ctx.IsSet(AsteriscRollupConfigFlag.Name) || ctx.IsSet(AsteriscL2GenesisFlag.Name)) {
+		return fmt.Errorf("flag %v can not be used with %v and %v",
+			AsteriscNetworkFlag.Name, AsteriscRollupConfigFlag.Name, AsteriscL2GenesisFlag.Name)
+	}
+	if !ctx.IsSet(AsteriscBinFlag.Name) {
+		return fmt.Errorf("flag %s is required", AsteriscBinFlag.Name)
+	}
+	if !ctx.IsSet(AsteriscServerFlag.Name) {
+		return fmt.Errorf("flag %s is required", AsteriscServerFlag.Name)
+	}
+	if !ctx.IsSet(AsteriscPreStateFlag.Name) {
+		return fmt.Errorf("flag %s is required", AsteriscPreStateFlag.Name)
+	}
+	if !ctx.IsSet(AsteriscL2Flag.Name) {
+		return fmt.Errorf("flag %s is required", AsteriscL2Flag.Name)
+	}
+	return nil
+}
+
 func CheckRequired(ctx *cli.Context, traceTypes []config.TraceType) error {
 	for _, f := range requiredFlags {
 		if !ctx.IsSet(f.Names()[0]) {
@@ -204,13 +296,20 @@ func CheckRequired(ctx *cli.Context, traceTypes []config.TraceType) error {
 	}
 	for _, traceType := range traceTypes {
 		switch traceType {
-		case config.TraceTypeCannon:
+		case config.TraceTypeCannon, config.TraceTypePermissioned:
 			if err := CheckCannonFlags(ctx); err != nil {
 				return err
 			}
 			if !ctx.IsSet(RollupRpcFlag.Name) {
 				return fmt.Errorf("flag %s is required", RollupRpcFlag.Name)
 			}
+		case config.TraceTypeAsterisc:
+			if err := CheckAsteriscFlags(ctx); err != nil {
+				return err
+			}
+			if !ctx.IsSet(RollupRpcFlag.Name) {
+				return fmt.Errorf("flag %s is required", RollupRpcFlag.Name)
+			}
 		case config.TraceTypeAlphabet:
 			if !ctx.IsSet(RollupRpcFlag.Name) {
 				return fmt.Errorf("flag %s is required", RollupRpcFlag.Name)
@@ -270,26 +369,36 @@ func NewConfigFromCLI(ctx *cli.Context) (*config.Config, error) {
 	}
 	return &config.Config{
 		// Required Flags
-		L1EthRpc:               ctx.String(L1EthRpcFlag.Name),
-		TraceTypes:             traceTypes,
-		GameFactoryAddress:     gameFactoryAddress,
-		GameAllowlist:          allowedGames,
-		GameWindow:             ctx.Duration(GameWindowFlag.Name),
-		MaxConcurrency:         maxConcurrency,
-		PollInterval:           ctx.Duration(HTTPPollInterval.Name),
-		RollupRpc:              ctx.String(RollupRpcFlag.Name),
-		CannonNetwork:          ctx.String(CannonNetworkFlag.Name),
-		CannonRollupConfigPath: ctx.String(CannonRollupConfigFlag.Name),
-		CannonL2GenesisPath:    ctx.String(CannonL2GenesisFlag.Name),
-		CannonBin:              ctx.String(CannonBinFlag.Name),
-		CannonServer:           ctx.String(CannonServerFlag.Name),
-		CannonAbsolutePreState: ctx.String(CannonPreStateFlag.Name),
-		Datadir:                ctx.String(DatadirFlag.Name),
-		CannonL2:               ctx.String(CannonL2Flag.Name),
-		CannonSnapshotFreq:     ctx.Uint(CannonSnapshotFreqFlag.Name),
-		CannonInfoFreq:         ctx.Uint(CannonInfoFreqFlag.Name),
-		TxMgrConfig:            txMgrConfig,
-		MetricsConfig:          metricsConfig,
-		PprofConfig:            pprofConfig,
+		L1EthRpc:                 ctx.String(L1EthRpcFlag.Name),
+		TraceTypes:               traceTypes,
+		GameFactoryAddress:       gameFactoryAddress,
+		GameAllowlist:            allowedGames,
+		GameWindow:               ctx.Duration(GameWindowFlag.Name),
+		MaxConcurrency:           maxConcurrency,
+		PollInterval:             ctx.Duration(HTTPPollInterval.Name),
+		PreimageCacheSize:        ctx.Uint64(PreimageCacheSizeFlag.Name) * 1024 * 1024,
+		RollupRpc:                ctx.String(RollupRpcFlag.Name),
+		CannonNetwork:            ctx.String(CannonNetworkFlag.Name),
+		CannonRollupConfigPath:   ctx.String(CannonRollupConfigFlag.Name),
+		CannonL2GenesisPath:      ctx.String(CannonL2GenesisFlag.Name),
+		CannonBin:                ctx.String(CannonBinFlag.Name),
+		CannonServer:             ctx.String(CannonServerFlag.Name),
+		CannonAbsolutePreState:   ctx.String(CannonPreStateFlag.Name),
+		Datadir:                  ctx.String(DatadirFlag.Name),
+		CannonL2:                 ctx.String(CannonL2Flag.Name),
+		CannonSnapshotFreq:       ctx.Uint(CannonSnapshotFreqFlag.Name),
+		CannonInfoFreq:           ctx.Uint(CannonInfoFreqFlag.Name),
+		AsteriscNetwork:          ctx.String(AsteriscNetworkFlag.Name),
+		AsteriscRollupConfigPath: ctx.String(AsteriscRollupConfigFlag.Name),
+		AsteriscL2GenesisPath:    ctx.String(AsteriscL2GenesisFlag.Name),
+		AsteriscBin:              ctx.String(AsteriscBinFlag.Name),
+		AsteriscServer:           ctx.String(AsteriscServerFlag.Name),
+		AsteriscAbsolutePreState: ctx.String(AsteriscPreStateFlag.Name),
+		AsteriscL2:               ctx.String(AsteriscL2Flag.Name),
+		AsteriscSnapshotFreq:     ctx.Uint(AsteriscSnapshotFreqFlag.Name),
+		AsteriscInfoFreq:         ctx.Uint(AsteriscInfoFreqFlag.Name),
+		TxMgrConfig:              txMgrConfig,
+		MetricsConfig:            metricsConfig,
+		PprofConfig:              pprofConfig,
 	}, nil
 }
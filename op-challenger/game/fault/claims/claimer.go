@@ -0,0 +1,150 @@
+package claims
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// resolvedBond is the bond value the FaultDisputeGame contract stores for claims once their bond has been
+// distributed as credit.
+var resolvedBond = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+type BondClaimMetrics interface {
+	RecordBondClaimed(amount *big.Int)
+	RecordBondClaimFailed()
+	RecordOutstandingBonds(locked *big.Int, claimable *big.Int)
+}
+
+type TxSender interface {
+	From() common.Address
+	Send(ctx context.Context, candidate txmgr.TxCandidate) (*ethtypes.Receipt, error)
+}
+
+type BondContract interface {
+	GetStatus(ctx context.Context) (gameTypes.GameStatus, error)
+	GetAllClaims(ctx context.Context) ([]types.Claim, error)
+	GetCredit(ctx context.Context, recipient common.Address) (*big.Int, error)
+	ClaimCreditTx(recipient common.Address) (txmgr.TxCandidate, error)
+}
+
+type BondContractCreator func(game gameTypes.GameMetadata) (BondContract, error)
+
+// gameBonds is the state of the bonds posted by the challenger in a single game.
+type gameBonds struct {
+	// locked is the total of the bonds posted by the challenger that have not yet been distributed.
+	locked *big.Int
+	// claimable is the credit held by the game for the challenger that has not been claimed.
+	claimable *big.Int
+	// settled is true once the game is resolved and there is nothing left to claim from it.
+	settled bool
+}
+
+// Claimer tracks the bonds posted by the challenger and claims the resulting credit once games resolve.
+type Claimer struct {
+	logger          log.Logger
+	metrics         BondClaimMetrics
+	contractCreator BondContractCreator
+	txSender        TxSender
+
+	// settled records the games that have nothing left to claim, so they don't need to be loaded again.
+	settled map[common.Address]bool
+}
+
+func NewBondClaimer(logger log.Logger, metrics BondClaimMetrics, contractCreator BondContractCreator, txSender TxSender) *Claimer {
+	return &Claimer{
+		logger:          logger,
+		metrics:         metrics,
+		contractCreator: contractCreator,
+		txSender:        txSender,
+		settled:         make(map[common.Address]bool),
+	}
+}
+
+// ClaimBonds checks each of the games for bonds posted by the challenger and claims any credit available from
+// resolved games. The outstanding bond totals are recorded after all games are checked.
+func (c *Claimer) ClaimBonds(ctx context.Context, games []gameTypes.GameMetadata) error {
+	locked := new(big.Int)
+	claimable := new(big.Int)
+	settled := make(map[common.Address]bool)
+	var errs error
+	for _, game := range games {
+		if c.settled[game.Proxy] {
+			settled[game.Proxy] = true
+			continue
+		}
+		bonds, err := c.claimBonds(ctx, game)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to claim bonds for game %v: %w", game.Proxy, err))
+			continue
+		}
+		locked.Add(locked, bonds.locked)
+		claimable.Add(claimable, bonds.claimable)
+		if bonds.settled {
+			settled[game.Proxy] = true
+		}
+	}
+	// Only keep track of games that are still being reported, so the set doesn't grow forever.
+	c.settled = settled
+	c.metrics.RecordOutstandingBonds(locked, claimable)
+	return errs
+}
+
+func (c *Claimer) claimBonds(ctx context.Context, game gameTypes.GameMetadata) (gameBonds, error) {
+	contract, err := c.contractCreator(game)
+	if err != nil {
+		return gameBonds{}, fmt.Errorf("failed to create bond contract: %w", err)
+	}
+	status, err := contract.GetStatus(ctx)
+	if err != nil {
+		return gameBonds{}, err
+	}
+	claims, err := contract.GetAllClaims(ctx)
+	if err != nil {
+		return gameBonds{}, err
+	}
+	recipient := c.txSender.From()
+	locked := new(big.Int)
+	for _, claim := range claims {
+		if claim.Claimant == recipient && claim.Bond.Cmp(resolvedBond) != 0 {
+			locked.Add(locked, claim.Bond)
+		}
+	}
+	credit, err := contract.GetCredit(ctx, recipient)
+	if err != nil {
+		return gameBonds{}, err
+	}
+	if status == gameTypes.GameStatusInProgress {
+		// Credit is only claimed once the game is resolved.
+		return gameBonds{locked: locked, claimable: credit}, nil
+	}
+	if credit.Sign() == 0 {
+		return gameBonds{locked: locked, claimable: credit, settled: locked.Sign() == 0}, nil
+	}
+
+	c.logger.Info("Claiming credit", "game", game.Proxy, "recipient", recipient, "credit", credit)
+	candidate, err := contract.ClaimCreditTx(recipient)
+	if err != nil {
+		return gameBonds{}, fmt.Errorf("failed to create claim credit tx: %w", err)
+	}
+	receipt, err := c.txSender.Send(ctx, candidate)
+	if err != nil {
+		c.metrics.RecordBondClaimFailed()
+		return gameBonds{}, fmt.Errorf("failed to send claim credit tx: %w", err)
+	}
+	if receipt.Status == ethtypes.ReceiptStatusFailed {
+		c.metrics.RecordBondClaimFailed()
+		return gameBonds{}, fmt.Errorf("claim credit tx %v reverted", receipt.TxHash)
+	}
+	c.logger.Info("Claimed credit", "game", game.Proxy, "recipient", recipient, "credit", credit, "tx_hash", receipt.TxHash)
+	c.metrics.RecordBondClaimed(credit)
+	return gameBonds{locked: locked, claimable: new(big.Int), settled: locked.Sign() == 0}, nil
+}
@@ -0,0 +1,211 @@
+package claims
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	gameTypes "github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ourAddr   = common.Address{0xaa}
+	otherAddr = common.Address{0xbb}
+	gameAddr  = common.Address{0x01}
+	gameAddr2 = common.Address{0x02}
+	mockErr   = errors.New("mock error")
+)
+
+func TestClaimBonds(t *testing.T) {
+	t.Run("ClaimsCreditFromResolvedGame", func(t *testing.T) {
+		claimer, contracts, txSender, m := setupClaimerTest(t)
+		contracts[gameAddr] = &stubBondContract{status: gameTypes.GameStatusChallengerWon, credit: big.NewInt(500)}
+		require.NoError(t, claimer.ClaimBonds(context.Background(), []gameTypes.GameMetadata{{Proxy: gameAddr}}))
+		require.Len(t, txSender.sent, 1)
+		require.Equal(t, ourAddr, contracts[gameAddr].claimedFor)
+		require.Equal(t, big.NewInt(500), m.claimed)
+		require.Equal(t, 1, m.claims)
+		require.Equal(t, big.NewInt(0), m.claimable)
+		require.True(t, claimer.settled[gameAddr])
+	})
+
+	t.Run("DoesNotClaimFromInProgressGame", func(t *testing.T) {
+		claimer, contracts, txSender, m := setupClaimerTest(t)
+		contracts[gameAddr] = &stubBondContract{
+			status: gameTypes.GameStatusInProgress,
+			credit: big.NewInt(100),
+			claims: []types.Claim{
+				newClaim(ourAddr, big.NewInt(30)),
+				newClaim(otherAddr, big.NewInt(50)),
+				newClaim(ourAddr, big.NewInt(20)),
+				newClaim(ourAddr, resolvedBond),
+			},
+		}
+		require.NoError(t, claimer.ClaimBonds(context.Background(), []gameTypes.GameMetadata{{Proxy: gameAddr}}))
+		require.Empty(t, txSender.sent)
+		require.Equal(t, big.NewInt(50), m.locked)
+		require.Equal(t, big.NewInt(100), m.claimable)
+		require.False(t, claimer.settled[gameAddr])
+	})
+
+	t.Run("SettledGamesAreNotReloaded", func(t *testing.T) {
+		claimer, contracts, txSender, _ := setupClaimerTest(t)
+		contracts[gameAddr] = &stubBondContract{status: gameTypes.GameStatusDefenderWon, credit: big.NewInt(0)}
+		games := []gameTypes.GameMetadata{{Proxy: gameAddr}}
+		require.NoError(t, claimer.ClaimBonds(context.Background(), games))
+		require.NoError(t, claimer.ClaimBonds(context.Background(), games))
+		require.Empty(t, txSender.sent)
+		require.Equal(t, 1, contracts[gameAddr].loads)
+	})
+
+	t.Run("ForgetsSettledGamesNoLongerReported", func(t *testing.T) {
+		claimer, contracts, _, _ := setupClaimerTest(t)
+		contracts[gameAddr] = &stubBondContract{status: gameTypes.GameStatusDefenderWon, credit: big.NewInt(0)}
+		contracts[gameAddr2] = &stubBondContract{status: gameTypes.GameStatusDefenderWon, credit: big.NewInt(0)}
+		require.NoError(t, claimer.ClaimBonds(context.Background(), []gameTypes.GameMetadata{{Proxy: gameAddr}, {Proxy: gameAddr2}}))
+		require.NoError(t, claimer.ClaimBonds(context.Background(), []gameTypes.GameMetadata{{Proxy: gameAddr2}}))
+		require.False(t, claimer.settled[gameAddr])
+		require.True(t, claimer.settled[gameAddr2])
+	})
+
+	t.Run("ResolvedGameWithLockedBondsIsNotSettled", func(t *testing.T) {
+		claimer, contracts, _, m := setupClaimerTest(t)
+		contracts[gameAddr] = &stubBondContract{
+			status: gameTypes.GameStatusDefenderWon,
+			credit: big.NewInt(0),
+			claims: []types.Claim{newClaim(ourAddr, big.NewInt(10))},
+		}
+		require.NoError(t, claimer.ClaimBonds(context.Background(), []gameTypes.GameMetadata{{Proxy: gameAddr}}))
+		require.False(t, claimer.settled[gameAddr])
+		require.Equal(t, big.NewInt(10), m.locked)
+	})
+
+	t.Run("SendFails", func(t *testing.T) {
+		claimer, contracts, txSender, m := setupClaimerTest(t)
+		txSender.sendErr = mockErr
+		contracts[gameAddr] = &stubBondContract{status: gameTypes.GameStatusChallengerWon, credit: big.NewInt(500)}
+		require.ErrorIs(t, claimer.ClaimBonds(context.Background(), []gameTypes.GameMetadata{{Proxy: gameAddr}}), mockErr)
+		require.Equal(t, 1, m.failures)
+		require.Zero(t, m.claims)
+		require.False(t, claimer.settled[gameAddr])
+	})
+
+	t.Run("TxReverted", func(t *testing.T) {
+		claimer, contracts, txSender, m := setupClaimerTest(t)
+		txSender.status = ethtypes.ReceiptStatusFailed
+		contracts[gameAddr] = &stubBondContract{status: gameTypes.GameStatusChallengerWon, credit: big.NewInt(500)}
+		require.ErrorContains(t, claimer.ClaimBonds(context.Background(), []gameTypes.GameMetadata{{Proxy: gameAddr}}), "reverted")
+		require.Equal(t, 1, m.failures)
+		require.Zero(t, m.claims)
+	})
+
+	t.Run("ContinuesAfterGameError", func(t *testing.T) {
+		claimer, contracts, txSender, m := setupClaimerTest(t)
+		contracts[gameAddr] = &stubBondContract{statusErr: mockErr}
+		contracts[gameAddr2] = &stubBondContract{status: gameTypes.GameStatusChallengerWon, credit: big.NewInt(500)}
+		err := claimer.ClaimBonds(context.Background(), []gameTypes.GameMetadata{{Proxy: gameAddr}, {Proxy: gameAddr2}})
+		require.ErrorIs(t, err, mockErr)
+		require.Len(t, txSender.sent, 1)
+		require.Equal(t, big.NewInt(500), m.claimed)
+	})
+}
+
+func setupClaimerTest(t *testing.T) (*Claimer, map[common.Address]*stubBondContract, *stubTxSender, *stubMetrics) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	m := &stubMetrics{}
+	txSender := &stubTxSender{status: ethtypes.ReceiptStatusSuccessful}
+	contracts := make(map[common.Address]*stubBondContract)
+	creator := func(game gameTypes.GameMetadata) (BondContract, error) {
+		contract, ok := contracts[game.Proxy]
+		if !ok {
+			return nil, errors.New("unknown game")
+		}
+		return contract, nil
+	}
+	return NewBondClaimer(logger, m, creator, txSender), contracts, txSender, m
+}
+
+func newClaim(claimant common.Address, bond *big.Int) types.Claim {
+	return types.Claim{
+		ClaimData: types.ClaimData{Bond: bond},
+		Claimant:  claimant,
+	}
+}
+
+type stubBondContract struct {
+	status     gameTypes.GameStatus
+	statusErr  error
+	claims     []types.Claim
+	credit     *big.Int
+	loads      int
+	claimedFor common.Address
+}
+
+func (s *stubBondContract) GetStatus(_ context.Context) (gameTypes.GameStatus, error) {
+	s.loads++
+	return s.status, s.statusErr
+}
+
+func (s *stubBondContract) GetAllClaims(_ context.Context) ([]types.Claim, error) {
+	return s.claims, nil
+}
+
+func (s *stubBondContract) GetCredit(_ context.Context, recipient common.Address) (*big.Int, error) {
+	if recipient != ourAddr {
+		return big.NewInt(0), nil
+	}
+	return s.credit, nil
+}
+
+func (s *stubBondContract) ClaimCreditTx(recipient common.Address) (txmgr.TxCandidate, error) {
+	s.claimedFor = recipient
+	return txmgr.TxCandidate{To: &gameAddr}, nil
+}
+
+type stubTxSender struct {
+	sent    []txmgr.TxCandidate
+	sendErr error
+	status  uint64
+}
+
+func (s *stubTxSender) From() common.Address {
+	return ourAddr
+}
+
+func (s *stubTxSender) Send(_ context.Context, candidate txmgr.TxCandidate) (*ethtypes.Receipt, error) {
+	if s.sendErr != nil {
+		return nil, s.sendErr
+	}
+	s.sent = append(s.sent, candidate)
+	return &ethtypes.Receipt{Status: s.status}, nil
+}
+
+type stubMetrics struct {
+	claims    int
+	claimed   *big.Int
+	failures  int
+	locked    *big.Int
+	claimable *big.Int
+}
+
+func (s *stubMetrics) RecordBondClaimed(amount *big.Int) {
+	s.claims++
+	s.claimed = amount
+}
+
+func (s *stubMetrics) RecordBondClaimFailed() {
+	s.failures++
+}
+
+func (s *stubMetrics) RecordOutstandingBonds(locked *big.Int, claimable *big.Int) {
+	s.locked = locked
+	s.claimable = claimable
+}
@@ -0,0 +1,72 @@
+package claims
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type BondClaimer interface {
+	ClaimBonds(ctx context.Context, games []types.GameMetadata) error
+}
+
+// BondClaimScheduler runs the bond claimer in the background so that waiting for claim transactions
+// doesn't delay progressing games.
+type BondClaimScheduler struct {
+	logger  log.Logger
+	claimer BondClaimer
+	queue   chan []types.GameMetadata
+	wg      sync.WaitGroup
+	cancel  func()
+}
+
+func NewBondClaimScheduler(logger log.Logger, claimer BondClaimer) *BondClaimScheduler {
+	return &BondClaimScheduler{
+		logger:  logger,
+		claimer: claimer,
+		// A size of 1 means updates are skipped while the previous claims are still being processed.
+		queue: make(chan []types.GameMetadata, 1),
+	}
+}
+
+func (s *BondClaimScheduler) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.wg.Add(1)
+	go s.loop(ctx)
+}
+
+func (s *BondClaimScheduler) Close() error {
+	if s.cancel == nil {
+		return nil // never started
+	}
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *BondClaimScheduler) Schedule(games []types.GameMetadata) error {
+	select {
+	case s.queue <- games:
+		return nil
+	default:
+		return scheduler.ErrBusy
+	}
+}
+
+func (s *BondClaimScheduler) loop(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case games := <-s.queue:
+			if err := s.claimer.ClaimBonds(ctx, games); err != nil {
+				s.logger.Error("Failed to claim bonds", "err", err)
+			}
+		}
+	}
+}
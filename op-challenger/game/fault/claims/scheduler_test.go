@@ -0,0 +1,61 @@
+package claims
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBondClaimScheduler(t *testing.T) {
+	t.Run("ClaimsScheduledGames", func(t *testing.T) {
+		claimer := &stubClaimer{}
+		s := NewBondClaimScheduler(testlog.Logger(t, log.LvlInfo), claimer)
+		s.Start(context.Background())
+		defer s.Close()
+
+		games := []types.GameMetadata{{Proxy: common.Address{0xaa}}}
+		require.NoError(t, s.Schedule(games))
+		require.Eventually(t, func() bool {
+			return len(claimer.Claimed()) == 1
+		}, 10*time.Second, 10*time.Millisecond)
+		require.Equal(t, games, claimer.Claimed()[0])
+	})
+
+	t.Run("BusyWhenUpdateAlreadyQueued", func(t *testing.T) {
+		s := NewBondClaimScheduler(testlog.Logger(t, log.LvlInfo), &stubClaimer{})
+		// Not started, so the first update remains queued
+		require.NoError(t, s.Schedule(nil))
+		require.ErrorIs(t, s.Schedule(nil), scheduler.ErrBusy)
+	})
+
+	t.Run("CloseWithoutStart", func(t *testing.T) {
+		s := NewBondClaimScheduler(testlog.Logger(t, log.LvlInfo), &stubClaimer{})
+		require.NoError(t, s.Close())
+	})
+}
+
+type stubClaimer struct {
+	sync.Mutex
+	claimed [][]types.GameMetadata
+}
+
+func (s *stubClaimer) Claimed() [][]types.GameMetadata {
+	s.Lock()
+	defer s.Unlock()
+	return s.claimed
+}
+
+func (s *stubClaimer) ClaimBonds(_ context.Context, games []types.GameMetadata) error {
+	s.Lock()
+	defer s.Unlock()
+	s.claimed = append(s.claimed, games)
+	return nil
+}
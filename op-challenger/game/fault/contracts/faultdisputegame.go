@@ -33,6 +33,8 @@ var (
 	methodSplitDepth         = "splitDepth"
 	methodL2BlockNumber      = "l2BlockNumber"
 	methodRequiredBond       = "getRequiredBond"
+	methodCredit             = "credit"
+	methodClaimCredit        = "claimCredit"
 )
 
 type FaultDisputeGameContract struct {
@@ -100,6 +102,20 @@ func (c *FaultDisputeGameContract) GetRequiredBond(ctx context.Context, position
 	return bond.GetBigInt(0), nil
 }
 
+// GetCredit returns the credit held by the game for the recipient, which can be withdrawn with claimCredit.
+func (c *FaultDisputeGameContract) GetCredit(ctx context.Context, recipient common.Address) (*big.Int, error) {
+	credit, err := c.multiCaller.SingleCall(ctx, batching.BlockLatest, c.contract.Call(methodCredit, recipient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credit: %w", err)
+	}
+	return credit.GetBigInt(0), nil
+}
+
+func (c *FaultDisputeGameContract) ClaimCreditTx(recipient common.Address) (txmgr.TxCandidate, error) {
+	call := c.contract.Call(methodClaimCredit, recipient)
+	return call.ToTxCandidate()
+}
+
 func (f *FaultDisputeGameContract) UpdateOracleTx(ctx context.Context, claimIdx uint64, data *types.PreimageOracleData) (txmgr.TxCandidate, error) {
 	if data.IsLocal {
 		return f.addLocalDataTx(claimIdx, data)
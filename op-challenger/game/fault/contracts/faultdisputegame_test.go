@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
 	batchingTest "github.com/ethereum-optimism/optimism/op-service/sources/batching/test"
 	"github.com/ethereum/go-ethereum/common"
@@ -287,6 +288,25 @@ func TestGetGenesisOutputRoot(t *testing.T) {
 	require.Equal(t, expectedOutputRoot, genesisOutputRoot)
 }
 
+func TestGetCredit(t *testing.T) {
+	stubRpc, game := setupFaultDisputeGameTest(t)
+	addr := common.Address{0x01}
+	expectedCredit := big.NewInt(4284)
+	stubRpc.SetResponse(fdgAddr, methodCredit, batching.BlockLatest, []interface{}{addr}, []interface{}{expectedCredit})
+	actualCredit, err := game.GetCredit(context.Background(), addr)
+	require.NoError(t, err)
+	require.Equal(t, expectedCredit, actualCredit)
+}
+
+func TestClaimCreditTx(t *testing.T) {
+	stubRpc, game := setupFaultDisputeGameTest(t)
+	addr := common.Address{0xaa}
+	stubRpc.SetResponse(fdgAddr, methodClaimCredit, batching.BlockLatest, []interface{}{addr}, nil)
+	tx, err := game.ClaimCreditTx(addr)
+	require.NoError(t, err)
+	stubRpc.VerifyTxCandidate(tx)
+}
+
 func TestFaultDisputeGame_UpdateOracleTx(t *testing.T) {
 	t.Run("Local", func(t *testing.T) {
 		stubRpc, game := setupFaultDisputeGameTest(t)
@@ -311,7 +331,7 @@ func TestFaultDisputeGame_UpdateOracleTx(t *testing.T) {
 		stubRpc, game := setupFaultDisputeGameTest(t)
 		data := &faultTypes.PreimageOracleData{
 			IsLocal:      false,
-			OracleKey:    common.Hash{0xbc}.Bytes(),
+			OracleKey:    common.Hash{byte(preimage.Keccak256KeyType), 0xbc}.Bytes(),
 			OracleData:   []byte{1, 2, 3, 4, 5, 6, 7, 9, 10, 11, 12, 13, 14, 15},
 			OracleOffset: 16,
 		}
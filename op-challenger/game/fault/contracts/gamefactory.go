@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -15,6 +16,9 @@ const (
 	methodGameCount   = "gameCount"
 	methodGameAtIndex = "gameAtIndex"
 	methodGameImpls   = "gameImpls"
+	methodInitBonds   = "initBonds"
+	methodCreateGame  = "create"
+	methodGames       = "games"
 )
 
 type DisputeGameFactoryContract struct {
@@ -57,6 +61,44 @@ func (f *DisputeGameFactoryContract) GetGameImpl(ctx context.Context, gameType u
 	return result.GetAddress(0), nil
 }
 
+func (f *DisputeGameFactoryContract) GetInitBond(ctx context.Context, gameType uint8) (*big.Int, error) {
+	result, err := f.multiCaller.SingleCall(ctx, batching.BlockLatest, f.contract.Call(methodInitBonds, gameType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load init bond for game type %v: %w", gameType, err)
+	}
+	return result.GetBigInt(0), nil
+}
+
+// GetGameFromParameters returns the address of the game created with the specified game type, output root and
+// L2 block number. The zero address is returned if no such game exists.
+func (f *DisputeGameFactoryContract) GetGameFromParameters(ctx context.Context, gameType uint8, outputRoot common.Hash, l2BlockNum uint64) (common.Address, error) {
+	result, err := f.multiCaller.SingleCall(ctx, batching.BlockLatest,
+		f.contract.Call(methodGames, gameType, outputRoot, gameExtraData(l2BlockNum)))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to load game: %w", err)
+	}
+	return result.GetAddress(0), nil
+}
+
+// CreateTx returns a transaction to create a new game, including the required init bond.
+func (f *DisputeGameFactoryContract) CreateTx(ctx context.Context, gameType uint8, outputRoot common.Hash, l2BlockNum uint64) (txmgr.TxCandidate, error) {
+	bond, err := f.GetInitBond(ctx, gameType)
+	if err != nil {
+		return txmgr.TxCandidate{}, err
+	}
+	candidate, err := f.contract.Call(methodCreateGame, gameType, outputRoot, gameExtraData(l2BlockNum)).ToTxCandidate()
+	if err != nil {
+		return txmgr.TxCandidate{}, err
+	}
+	candidate.Value = bond
+	return candidate, nil
+}
+
+// gameExtraData returns the extra data of fault dispute games, the abi encoded L2 block number.
+func gameExtraData(l2BlockNum uint64) []byte {
+	return common.BigToHash(new(big.Int).SetUint64(l2BlockNum)).Bytes()
+}
+
 func (f *DisputeGameFactoryContract) decodeGame(result *batching.CallResult) types.GameMetadata {
 	gameType := result.GetUint8(0)
 	timestamp := result.GetUint64(1)
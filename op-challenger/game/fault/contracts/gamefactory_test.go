@@ -93,6 +93,52 @@ func TestGetGameImpl(t *testing.T) {
 	require.Equal(t, gameImplAddr, actual)
 }
 
+func TestGetInitBond(t *testing.T) {
+	stubRpc, factory := setupDisputeGameFactoryTest(t)
+	gameType := uint8(1)
+	bond := big.NewInt(59395)
+	stubRpc.SetResponse(factoryAddr, methodInitBonds, batching.BlockLatest, []interface{}{gameType}, []interface{}{bond})
+	actual, err := factory.GetInitBond(context.Background(), gameType)
+	require.NoError(t, err)
+	require.Equal(t, bond, actual)
+}
+
+func TestGetGameFromParameters(t *testing.T) {
+	stubRpc, factory := setupDisputeGameFactoryTest(t)
+	gameType := uint8(1)
+	outputRoot := common.Hash{0x01}
+	l2BlockNum := uint64(0x3344)
+	gameAddr := common.Address{0xaa}
+	stubRpc.SetResponse(
+		factoryAddr,
+		methodGames,
+		batching.BlockLatest,
+		[]interface{}{gameType, outputRoot, common.BigToHash(big.NewInt(int64(l2BlockNum))).Bytes()},
+		[]interface{}{gameAddr, uint64(1234)})
+	actual, err := factory.GetGameFromParameters(context.Background(), gameType, outputRoot, l2BlockNum)
+	require.NoError(t, err)
+	require.Equal(t, gameAddr, actual)
+}
+
+func TestCreateTx(t *testing.T) {
+	stubRpc, factory := setupDisputeGameFactoryTest(t)
+	gameType := uint8(0)
+	outputRoot := common.Hash{0x01}
+	l2BlockNum := uint64(6000)
+	bond := big.NewInt(10000)
+	stubRpc.SetResponse(factoryAddr, methodInitBonds, batching.BlockLatest, []interface{}{gameType}, []interface{}{bond})
+	stubRpc.SetResponse(
+		factoryAddr,
+		methodCreateGame,
+		batching.BlockLatest,
+		[]interface{}{gameType, outputRoot, common.BigToHash(big.NewInt(int64(l2BlockNum))).Bytes()},
+		nil)
+	tx, err := factory.CreateTx(context.Background(), gameType, outputRoot, l2BlockNum)
+	require.NoError(t, err)
+	stubRpc.VerifyTxCandidate(tx)
+	require.Equal(t, bond, tx.Value)
+}
+
 func expectGetGame(stubRpc *batchingTest.AbiBasedRpc, idx int, blockHash common.Hash, game types.GameMetadata) {
 	stubRpc.SetResponse(
 		factoryAddr,
@@ -1,11 +1,13 @@
 package contracts
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
@@ -15,6 +17,8 @@ const (
 	methodLoadKeccak256PreimagePart = "loadKeccak256PreimagePart"
 )
 
+var ErrUnsupportedKeyType = errors.New("unsupported pre-image key type")
+
 // PreimageOracleContract is a binding that works with contracts implementing the IPreimageOracle interface
 type PreimageOracleContract struct {
 	addr        common.Address
@@ -39,7 +43,12 @@ func (c *PreimageOracleContract) Addr() common.Address {
 	return c.addr
 }
 
+// AddGlobalDataTx creates a transaction to load the global pre-image data into the oracle.
+// Only keccak256 pre-images can be loaded, the contract has no loaders for the other global key types.
 func (c *PreimageOracleContract) AddGlobalDataTx(data *types.PreimageOracleData) (txmgr.TxCandidate, error) {
+	if len(data.OracleKey) == 0 || preimage.KeyType(data.OracleKey[0]) != preimage.Keccak256KeyType {
+		return txmgr.TxCandidate{}, fmt.Errorf("%w: key %x", ErrUnsupportedKeyType, data.OracleKey)
+	}
 	call := c.contract.Call(methodLoadKeccak256PreimagePart, new(big.Int).SetUint64(uint64(data.OracleOffset)), data.GetPreimageWithoutSize())
 	return call.ToTxCandidate()
 }
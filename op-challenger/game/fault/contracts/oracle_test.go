@@ -6,6 +6,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
 	batchingTest "github.com/ethereum-optimism/optimism/op-service/sources/batching/test"
 	"github.com/ethereum/go-ethereum/common"
@@ -21,7 +22,7 @@ func TestPreimageOracleContract_LoadKeccak256(t *testing.T) {
 	require.NoError(t, err)
 
 	data := &types.PreimageOracleData{
-		OracleKey:    common.Hash{0xcc}.Bytes(),
+		OracleKey:    common.Hash{byte(preimage.Keccak256KeyType), 0xcc}.Bytes(),
 		OracleData:   make([]byte, 20),
 		OracleOffset: 545,
 	}
@@ -34,3 +35,18 @@ func TestPreimageOracleContract_LoadKeccak256(t *testing.T) {
 	require.NoError(t, err)
 	stubRpc.VerifyTxCandidate(tx)
 }
+
+func TestPreimageOracleContract_UnsupportedKeyType(t *testing.T) {
+	oracleAbi, err := bindings.PreimageOracleMetaData.GetAbi()
+	require.NoError(t, err)
+
+	stubRpc := batchingTest.NewAbiBasedRpc(t, oracleAddr, oracleAbi)
+	oracleContract, err := NewPreimageOracleContract(oracleAddr, batching.NewMultiCaller(stubRpc, batching.DefaultBatchSize))
+	require.NoError(t, err)
+
+	for _, keyType := range []preimage.KeyType{preimage.Sha256KeyType, preimage.BlobKeyType, preimage.PrecompileKeyType} {
+		data := types.NewPreimageOracleData(common.Hash{byte(keyType), 0xcc}.Bytes(), make([]byte, 20), 545)
+		_, err := oracleContract.AddGlobalDataTx(data)
+		require.ErrorIs(t, err, ErrUnsupportedKeyType)
+	}
+}
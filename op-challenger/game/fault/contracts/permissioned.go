@@ -0,0 +1,85 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	methodProposer   = "proposer"
+	methodChallenger = "challenger"
+)
+
+// permissionedRolesAbi is the ABI of the role getters the permissioned dispute game adds to the fault dispute game.
+const permissionedRolesAbi = `[
+	{"type":"function","name":"proposer","inputs":[],"outputs":[{"name":"proposer_","type":"address","internalType":"address"}],"stateMutability":"view"},
+	{"type":"function","name":"challenger","inputs":[],"outputs":[{"name":"challenger_","type":"address","internalType":"address"}],"stateMutability":"view"}
+]`
+
+// PermissionedRoles are the addresses allowed to make moves in a permissioned dispute game.
+type PermissionedRoles struct {
+	Proposer   common.Address
+	Challenger common.Address
+}
+
+// HasRole returns true if addr is allowed to make moves in the game.
+func (r PermissionedRoles) HasRole(addr common.Address) bool {
+	return addr == r.Proposer || addr == r.Challenger
+}
+
+// PermissionedDisputeGameContract is a binding for the permissioned dispute game, a fault dispute game
+// where only the proposer and challenger may make moves.
+type PermissionedDisputeGameContract struct {
+	*FaultDisputeGameContract
+}
+
+func NewPermissionedDisputeGameContract(addr common.Address, caller *batching.MultiCaller) (*PermissionedDisputeGameContract, error) {
+	contractAbi, err := loadPermissionedDisputeGameAbi()
+	if err != nil {
+		return nil, err
+	}
+	return &PermissionedDisputeGameContract{
+		FaultDisputeGameContract: &FaultDisputeGameContract{
+			multiCaller: caller,
+			contract:    batching.NewBoundContract(contractAbi, addr),
+		},
+	}, nil
+}
+
+// GetRoles returns the addresses allowed to make moves in the game.
+func (c *PermissionedDisputeGameContract) GetRoles(ctx context.Context) (PermissionedRoles, error) {
+	results, err := c.multiCaller.Call(ctx, batching.BlockLatest,
+		c.contract.Call(methodProposer),
+		c.contract.Call(methodChallenger))
+	if err != nil {
+		return PermissionedRoles{}, fmt.Errorf("failed to retrieve game roles: %w", err)
+	}
+	if len(results) != 2 {
+		return PermissionedRoles{}, fmt.Errorf("expected 2 results but got %v", len(results))
+	}
+	return PermissionedRoles{
+		Proposer:   results[0].GetAddress(0),
+		Challenger: results[1].GetAddress(0),
+	}, nil
+}
+
+func loadPermissionedDisputeGameAbi() (*abi.ABI, error) {
+	contractAbi, err := bindings.FaultDisputeGameMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fault dispute game ABI: %w", err)
+	}
+	rolesAbi, err := abi.JSON(strings.NewReader(permissionedRolesAbi))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissioned roles ABI: %w", err)
+	}
+	for name, method := range rolesAbi.Methods {
+		contractAbi.Methods[name] = method
+	}
+	return contractAbi, nil
+}
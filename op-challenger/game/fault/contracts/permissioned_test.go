@@ -0,0 +1,42 @@
+package contracts
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	batchingTest "github.com/ethereum-optimism/optimism/op-service/sources/batching/test"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionedDisputeGame_GetRoles(t *testing.T) {
+	proposer := common.Address{0xaa}
+	challenger := common.Address{0xbb}
+	contractAbi, err := loadPermissionedDisputeGameAbi()
+	require.NoError(t, err)
+	stubRpc := batchingTest.NewAbiBasedRpc(t, fdgAddr, contractAbi)
+	game, err := NewPermissionedDisputeGameContract(fdgAddr, batching.NewMultiCaller(stubRpc, batching.DefaultBatchSize))
+	require.NoError(t, err)
+	stubRpc.SetResponse(fdgAddr, methodProposer, batching.BlockLatest, nil, []interface{}{proposer})
+	stubRpc.SetResponse(fdgAddr, methodChallenger, batching.BlockLatest, nil, []interface{}{challenger})
+
+	roles, err := game.GetRoles(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PermissionedRoles{Proposer: proposer, Challenger: challenger}, roles)
+
+	// The fault dispute game methods are still available
+	stubRpc.SetResponse(fdgAddr, methodClaimCount, batching.BlockLatest, nil, []interface{}{big.NewInt(3)})
+	count, err := game.GetClaimCount(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count)
+}
+
+func TestPermissionedRoles_HasRole(t *testing.T) {
+	roles := PermissionedRoles{Proposer: common.Address{0xaa}, Challenger: common.Address{0xbb}}
+	require.True(t, roles.HasRole(common.Address{0xaa}))
+	require.True(t, roles.HasRole(common.Address{0xbb}))
+	require.False(t, roles.HasRole(common.Address{0xcc}))
+	require.False(t, roles.HasRole(common.Address{}))
+}
@@ -3,9 +3,10 @@ package contracts
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -13,6 +14,13 @@ const (
 	methodOracle = "oracle"
 )
 
+// bigStepperAbi is the ABI of the IBigStepper interface, which all fault proof VM contracts implement,
+// so the binding is not specific to the MIPS contract.
+const bigStepperAbi = `[
+	{"type":"function","name":"oracle","inputs":[],"outputs":[{"name":"oracle_","type":"address","internalType":"contract IPreimageOracle"}],"stateMutability":"view"},
+	{"type":"function","name":"step","inputs":[{"name":"_stateData","type":"bytes","internalType":"bytes"},{"name":"_proof","type":"bytes","internalType":"bytes"},{"name":"_localContext","type":"bytes32","internalType":"bytes32"}],"outputs":[{"name":"postState_","type":"bytes32","internalType":"bytes32"}],"stateMutability":"nonpayable"}
+]`
+
 // VMContract is a binding that works with contracts implementing the IBigStepper interface
 type VMContract struct {
 	multiCaller *batching.MultiCaller
@@ -20,14 +28,14 @@ type VMContract struct {
 }
 
 func NewVMContract(addr common.Address, caller *batching.MultiCaller) (*VMContract, error) {
-	mipsAbi, err := bindings.MIPSMetaData.GetAbi()
+	vmAbi, err := abi.JSON(strings.NewReader(bigStepperAbi))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load VM ABI: %w", err)
 	}
 
 	return &VMContract{
 		multiCaller: caller,
-		contract:    batching.NewBoundContract(mipsAbi, addr),
+		contract:    batching.NewBoundContract(&vmAbi, addr),
 	}, nil
 }
 
@@ -6,8 +6,10 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
 	batchingTest "github.com/ethereum-optimism/optimism/op-service/sources/batching/test"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,6 +26,7 @@ func TestVMContract_Oracle(t *testing.T) {
 	oracleContract, err := vmContract.Oracle(context.Background())
 	require.NoError(t, err)
 	tx, err := oracleContract.AddGlobalDataTx(&types.PreimageOracleData{
+		OracleKey:  common.Hash{byte(preimage.Keccak256KeyType)}.Bytes(),
 		OracleData: make([]byte, 20),
 	})
 	require.NoError(t, err)
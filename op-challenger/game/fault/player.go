@@ -94,6 +94,30 @@ func NewGamePlayer(
 	}, nil
 }
 
+// NewObserverGamePlayer creates a GamePlayer that tracks the status of the game, but never acts in it.
+// It is used for games the challenger is not permitted to make moves in.
+func NewObserverGamePlayer(
+	ctx context.Context,
+	logger log.Logger,
+	addr common.Address,
+	loader GameInfo,
+) (*GamePlayer, error) {
+	logger = logger.New("game", addr)
+	status, err := loader.GetStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch game status: %w", err)
+	}
+	return &GamePlayer{
+		logger: logger,
+		loader: loader,
+		status: status,
+		// Act function does nothing because the challenger is not permitted to act in the game
+		act: func(ctx context.Context) error {
+			return nil
+		},
+	}, nil
+}
+
 func (g *GamePlayer) ValidatePrestate(ctx context.Context) error {
 	for _, validator := range g.prestateValidators {
 		if err := validator.Validate(ctx); err != nil {
@@ -89,6 +89,22 @@ func TestDoNotActOnCompleteGame(t *testing.T) {
 	}
 }
 
+func TestObserverGamePlayer(t *testing.T) {
+	gameState := &stubGameState{claimCount: 1, status: types.GameStatusInProgress}
+	game, err := NewObserverGamePlayer(context.Background(), testlog.Logger(t, log.LvlDebug), common.Address{0xaa}, gameState)
+	require.NoError(t, err)
+	require.Equal(t, types.GameStatusInProgress, game.Status())
+
+	fetched := game.ProgressGame(context.Background())
+	require.Equal(t, types.GameStatusInProgress, fetched)
+
+	// Tracks the status of the game as it is resolved by others
+	gameState.status = types.GameStatusDefenderWon
+	fetched = game.ProgressGame(context.Background())
+	require.Equal(t, types.GameStatusDefenderWon, fetched)
+	require.Zero(t, gameState.callCount, "should never act")
+}
+
 func TestValidatePrestate(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -6,21 +6,25 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
-	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/cannon"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/outputs"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
 	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/sources/batching"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 )
 
 var (
-	cannonGameType   = uint8(0)
-	alphabetGameType = uint8(255)
+	cannonGameType       = uint8(0)
+	permissionedGameType = uint8(1)
+	asteriscGameType     = uint8(2)
+	alphabetGameType     = uint8(255)
 )
 
 type CloseFunc func()
@@ -40,23 +44,53 @@ func RegisterGameTypes(
 	gameFactory *contracts.DisputeGameFactoryContract,
 	caller *batching.MultiCaller,
 ) (CloseFunc, error) {
-	var closer CloseFunc
-	var l2Client *ethclient.Client
-	if cfg.TraceTypeEnabled(config.TraceTypeCannon) {
-		l2, err := ethclient.DialContext(ctx, cfg.CannonL2)
+	var l2Clients []*ethclient.Client
+	closer := func() {
+		for _, l2Client := range l2Clients {
+			l2Client.Close()
+		}
+	}
+	dialL2 := func(url string) (*ethclient.Client, error) {
+		l2Client, err := ethclient.DialContext(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("dial l2 client %v: %w", url, err)
+		}
+		l2Clients = append(l2Clients, l2Client)
+		return l2Client, nil
+	}
+	if cfg.TraceTypeEnabled(config.TraceTypeCannon) || cfg.TraceTypeEnabled(config.TraceTypePermissioned) {
+		l2Client, err := dialL2(cfg.CannonL2)
 		if err != nil {
-			return nil, fmt.Errorf("dial l2 client %v: %w", cfg.CannonL2, err)
+			closer()
+			return nil, err
+		}
+		if cfg.TraceTypeEnabled(config.TraceTypeCannon) {
+			if err := registerCannon(registry, ctx, logger, m, cfg, rollupClient, txMgr, gameFactory, caller, l2Client); err != nil {
+				closer()
+				return nil, fmt.Errorf("failed to register cannon game type: %w", err)
+			}
+		}
+		if cfg.TraceTypeEnabled(config.TraceTypePermissioned) {
+			if err := registerPermissioned(registry, ctx, logger, m, cfg, rollupClient, txMgr, gameFactory, caller, l2Client); err != nil {
+				closer()
+				return nil, fmt.Errorf("failed to register permissioned game type: %w", err)
+			}
 		}
-		l2Client = l2
-		closer = l2Client.Close
 	}
-	if cfg.TraceTypeEnabled(config.TraceTypeCannon) {
-		if err := registerCannon(registry, ctx, logger, m, cfg, rollupClient, txMgr, gameFactory, caller, l2Client); err != nil {
-			return nil, fmt.Errorf("failed to register cannon game type: %w", err)
+	if cfg.TraceTypeEnabled(config.TraceTypeAsterisc) {
+		l2Client, err := dialL2(cfg.AsteriscL2)
+		if err != nil {
+			closer()
+			return nil, err
+		}
+		if err := registerAsterisc(registry, ctx, logger, m, cfg, rollupClient, txMgr, gameFactory, caller, l2Client); err != nil {
+			closer()
+			return nil, fmt.Errorf("failed to register asterisc game type: %w", err)
 		}
 	}
 	if cfg.TraceTypeEnabled(config.TraceTypeAlphabet) {
 		if err := registerAlphabet(registry, ctx, logger, m, rollupClient, txMgr, gameFactory, caller); err != nil {
+			closer()
 			return nil, fmt.Errorf("failed to register alphabet game type: %w", err)
 		}
 	}
@@ -122,6 +156,66 @@ func createOracle(ctx context.Context, gameFactory *contracts.DisputeGameFactory
 	return oracle, nil
 }
 
+// outputVmGameContract is the contract of an output root game that uses a fault proof VM for the execution trace.
+type outputVmGameContract interface {
+	GameContract
+	utils.L1HeadSource
+	GetBlockRange(ctx context.Context) (prestateBlock uint64, poststateBlock uint64, retErr error)
+	GetSplitDepth(ctx context.Context) (faultTypes.Depth, error)
+	GetAbsolutePrestateHash(ctx context.Context) (common.Hash, error)
+	GetGenesisOutputRoot(ctx context.Context) (common.Hash, error)
+}
+
+// outputVmContractCreator loads the contract of the game at addr.
+// It also reports whether sender is permitted to make moves in the game. Games it is not permitted in are only observed.
+type outputVmContractCreator func(ctx context.Context, addr common.Address, sender common.Address) (outputVmGameContract, bool, error)
+
+// outputVmTraceAccessorCreator creates the trace accessor for an output root game,
+// with the execution trace below the split depth provided by a fault proof VM.
+type outputVmTraceAccessorCreator func(
+	logger log.Logger,
+	m metrics.Metricer,
+	cfg *config.Config,
+	l2Client utils.L2HeaderSource,
+	contract utils.L1HeadSource,
+	prestateProvider faultTypes.PrestateProvider,
+	rollupClient outputs.OutputRollupClient,
+	dir string,
+	splitDepth faultTypes.Depth,
+	prestateBlock uint64,
+	poststateBlock uint64,
+) (*trace.Accessor, error)
+
+func newFaultDisputeGameContract(caller *batching.MultiCaller) outputVmContractCreator {
+	return func(_ context.Context, addr common.Address, _ common.Address) (outputVmGameContract, bool, error) {
+		contract, err := contracts.NewFaultDisputeGameContract(addr, caller)
+		if err != nil {
+			return nil, false, err
+		}
+		return contract, true, nil
+	}
+}
+
+// newPermissionedGameContract loads permissioned games, which only allow their proposer and challenger to make moves.
+func newPermissionedGameContract(logger log.Logger, caller *batching.MultiCaller) outputVmContractCreator {
+	return func(ctx context.Context, addr common.Address, sender common.Address) (outputVmGameContract, bool, error) {
+		contract, err := contracts.NewPermissionedDisputeGameContract(addr, caller)
+		if err != nil {
+			return nil, false, err
+		}
+		roles, err := contract.GetRoles(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		if !roles.HasRole(sender) {
+			logger.Info("Not permitted to act in permissioned game, skipping", "game", addr,
+				"proposer", roles.Proposer, "challenger", roles.Challenger, "from", sender)
+			return contract, false, nil
+		}
+		return contract, true, nil
+	}
+}
+
 func registerCannon(
 	registry Registry,
 	ctx context.Context,
@@ -132,13 +226,71 @@ func registerCannon(
 	txMgr txmgr.TxManager,
 	gameFactory *contracts.DisputeGameFactoryContract,
 	caller *batching.MultiCaller,
-	l2Client cannon.L2HeaderSource,
+	l2Client utils.L2HeaderSource,
+) error {
+	return registerOutputVm(cannonGameType, newFaultDisputeGameContract(caller), outputs.NewOutputCannonTraceAccessor,
+		registry, ctx, logger, m, cfg, rollupClient, txMgr, gameFactory, caller, l2Client)
+}
+
+// registerPermissioned registers the permissioned game type, which uses cannon traces,
+// but only allows the proposer and challenger of each game to make moves.
+// Games in which the challenger's address has neither role are only observed.
+func registerPermissioned(
+	registry Registry,
+	ctx context.Context,
+	logger log.Logger,
+	m metrics.Metricer,
+	cfg *config.Config,
+	rollupClient outputs.OutputRollupClient,
+	txMgr txmgr.TxManager,
+	gameFactory *contracts.DisputeGameFactoryContract,
+	caller *batching.MultiCaller,
+	l2Client utils.L2HeaderSource,
+) error {
+	return registerOutputVm(permissionedGameType, newPermissionedGameContract(logger, caller), outputs.NewOutputCannonTraceAccessor,
+		registry, ctx, logger, m, cfg, rollupClient, txMgr, gameFactory, caller, l2Client)
+}
+
+func registerAsterisc(
+	registry Registry,
+	ctx context.Context,
+	logger log.Logger,
+	m metrics.Metricer,
+	cfg *config.Config,
+	rollupClient outputs.OutputRollupClient,
+	txMgr txmgr.TxManager,
+	gameFactory *contracts.DisputeGameFactoryContract,
+	caller *batching.MultiCaller,
+	l2Client utils.L2HeaderSource,
+) error {
+	return registerOutputVm(asteriscGameType, newFaultDisputeGameContract(caller), outputs.NewOutputAsteriscTraceAccessor,
+		registry, ctx, logger, m, cfg, rollupClient, txMgr, gameFactory, caller, l2Client)
+}
+
+// registerOutputVm registers an output root game type that uses a fault proof VM for the execution trace.
+func registerOutputVm(
+	gameType uint8,
+	newContract outputVmContractCreator,
+	newTraceAccessor outputVmTraceAccessorCreator,
+	registry Registry,
+	ctx context.Context,
+	logger log.Logger,
+	m metrics.Metricer,
+	cfg *config.Config,
+	rollupClient outputs.OutputRollupClient,
+	txMgr txmgr.TxManager,
+	gameFactory *contracts.DisputeGameFactoryContract,
+	caller *batching.MultiCaller,
+	l2Client utils.L2HeaderSource,
 ) error {
 	playerCreator := func(game types.GameMetadata, dir string) (scheduler.GamePlayer, error) {
-		contract, err := contracts.NewFaultDisputeGameContract(game.Proxy, caller)
+		contract, permitted, err := newContract(ctx, game.Proxy, txMgr.From())
 		if err != nil {
 			return nil, err
 		}
+		if !permitted {
+			return NewObserverGamePlayer(ctx, logger, game.Proxy, contract)
+		}
 		prestateBlock, poststateBlock, err := contract.GetBlockRange(ctx)
 		if err != nil {
 			return nil, err
@@ -149,7 +301,7 @@ func registerCannon(
 			if err != nil {
 				return nil, fmt.Errorf("failed to load split depth: %w", err)
 			}
-			accessor, err := outputs.NewOutputCannonTraceAccessor(logger, m, cfg, l2Client, contract, prestateProvider, rollupClient, dir, splitDepth, prestateBlock, poststateBlock)
+			accessor, err := newTraceAccessor(logger, m, cfg, l2Client, contract, prestateProvider, rollupClient, dir, splitDepth, prestateBlock, poststateBlock)
 			if err != nil {
 				return nil, err
 			}
@@ -163,6 +315,6 @@ func registerCannon(
 	if err != nil {
 		return err
 	}
-	registry.RegisterGameType(cannonGameType, playerCreator, oracle)
+	registry.RegisterGameType(gameType, playerCreator, oracle)
 	return nil
 }
@@ -0,0 +1,31 @@
+package asterisc
+
+import (
+	"github.com/ethereum-optimism/optimism/op-challenger/config"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// NewVmConfig returns the configuration to run asterisc with the generic VM executor.
+func NewVmConfig(cfg *config.Config) vm.Config {
+	return vm.Config{
+		VmType:           config.TraceTypeAsterisc.String(),
+		VmBin:            cfg.AsteriscBin,
+		Server:           cfg.AsteriscServer,
+		L1:               cfg.L1EthRpc,
+		L2:               cfg.AsteriscL2,
+		Network:          cfg.AsteriscNetwork,
+		RollupConfigPath: cfg.AsteriscRollupConfigPath,
+		L2GenesisPath:    cfg.AsteriscL2GenesisPath,
+		SnapshotFreq:     cfg.AsteriscSnapshotFreq,
+		InfoFreq:         cfg.AsteriscInfoFreq,
+		PreimageCacheDir: cfg.PreimageCacheDir(),
+	}
+}
+
+func NewTraceProvider(logger log.Logger, m vm.Metricer, cfg *config.Config, localContext common.Hash, localInputs utils.LocalGameInputs, dir string, gameDepth types.Depth) *vm.TraceProvider {
+	return vm.NewTraceProvider(logger, m, NewVmConfig(cfg), NewStateConverter(), cfg.AsteriscAbsolutePreState, localContext, localInputs, dir, gameDepth)
+}
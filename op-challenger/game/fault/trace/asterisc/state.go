@@ -0,0 +1,87 @@
+package asterisc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	ErrMissingWitness   = errors.New("state is missing the witness")
+	ErrMissingStateHash = errors.New("state is missing the state hash")
+)
+
+// VMState is the part of the asterisc state that the challenger needs.
+// The VM includes the witness, and its state hash, when writing its states as JSON,
+// so the challenger does not need to know the RISC-V state layout.
+type VMState struct {
+	PC        uint64        `json:"pc"`
+	Exited    bool          `json:"exited"`
+	Step      uint64        `json:"step"`
+	Witness   hexutil.Bytes `json:"witness"`
+	StateHash common.Hash   `json:"stateHash"`
+}
+
+func (state *VMState) validate() error {
+	if len(state.Witness) == 0 {
+		return ErrMissingWitness
+	}
+	if state.StateHash == (common.Hash{}) {
+		return ErrMissingStateHash
+	}
+	return nil
+}
+
+func parseState(path string) (*VMState, error) {
+	file, err := ioutil.OpenDecompressed(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open state file (%v): %w", path, err)
+	}
+	defer file.Close()
+	var state VMState
+	if err := json.NewDecoder(file).Decode(&state); err != nil {
+		return nil, fmt.Errorf("invalid asterisc state (%v): %w", path, err)
+	}
+	if err := state.validate(); err != nil {
+		return nil, fmt.Errorf("invalid asterisc state (%v): %w", path, err)
+	}
+	return &state, nil
+}
+
+// StateConverter reads asterisc states and proofs for the generic VM trace provider.
+// Asterisc writes its proofs as JSON.
+type StateConverter struct{}
+
+var _ vm.StateConverter = (*StateConverter)(nil)
+
+func NewStateConverter() *StateConverter {
+	return &StateConverter{}
+}
+
+func (c *StateConverter) ConvertStateToProof(statePath string) (*vm.ProofData, uint64, bool, error) {
+	state, err := parseState(statePath)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return &vm.ProofData{
+		ClaimValue:   state.StateHash,
+		StateData:    state.Witness,
+		ProofData:    []byte{},
+		OracleKey:    nil,
+		OracleValue:  nil,
+		OracleOffset: 0,
+	}, state.Step, state.Exited, nil
+}
+
+func (c *StateConverter) ReadProof(proofPath string) (*vm.ProofData, error) {
+	return vm.ReadJSONProof(proofPath)
+}
+
+func (c *StateConverter) WriteProof(proofPath string, _ uint64, proof *vm.ProofData) error {
+	return vm.WriteJSONProof(proofPath, proof)
+}
@@ -0,0 +1,54 @@
+package asterisc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertStateToProof(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		path := writeState(t, `{"pc":4,"exited":true,"step":1234,"witness":"0x0102","stateHash":"0x0300000000000000000000000000000000000000000000000000000000000000","extra":"ignored"}`)
+		proof, step, exited, err := NewStateConverter().ConvertStateToProof(path)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1234), step)
+		require.True(t, exited)
+		require.Equal(t, common.Hash{0x03}, proof.ClaimValue)
+		require.EqualValues(t, []byte{0x01, 0x02}, proof.StateData)
+		require.NotNil(t, proof.ProofData)
+		require.Empty(t, proof.ProofData)
+		require.Empty(t, proof.OracleKey)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		_, _, _, err := NewStateConverter().ConvertStateToProof(filepath.Join(t.TempDir(), "missing.json"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		path := writeState(t, `{"step":`)
+		_, _, _, err := NewStateConverter().ConvertStateToProof(path)
+		require.ErrorContains(t, err, "invalid asterisc state")
+	})
+
+	t.Run("MissingWitness", func(t *testing.T) {
+		path := writeState(t, `{"step":1,"stateHash":"0x0300000000000000000000000000000000000000000000000000000000000000"}`)
+		_, _, _, err := NewStateConverter().ConvertStateToProof(path)
+		require.ErrorIs(t, err, ErrMissingWitness)
+	})
+
+	t.Run("MissingStateHash", func(t *testing.T) {
+		path := writeState(t, `{"step":1,"witness":"0x0102"}`)
+		_, _, _, err := NewStateConverter().ConvertStateToProof(path)
+		require.ErrorIs(t, err, ErrMissingStateHash)
+	})
+}
+
+func writeState(t *testing.T, state string) string {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte(state), 0o644))
+	return path
+}
@@ -1,23 +1,129 @@
 package cannon
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
 	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
-func parseState(path string) (*mipsevm.State, error) {
+// parseState loads the cannon state at path, in the binary format if the path has a binary file extension,
+// or as JSON otherwise.
+// The VM type of a binary state is detected from its version byte. JSON states are either multi-threaded states,
+// detected by their thread stacks, or single-threaded 32-bit states: MIPS64 states must use the binary format.
+func parseState(path string) (mipsevm.VMState, error) {
 	file, err := ioutil.OpenDecompressed(path)
 	if err != nil {
 		return nil, fmt.Errorf("cannot open state file (%v): %w", path, err)
 	}
 	defer file.Close()
-	var state mipsevm.State
-	err = json.NewDecoder(file).Decode(&state)
+	if serialize.IsBinaryFile(path) {
+		return parseBinaryState(path, bufio.NewReader(file))
+	}
+	var data json.RawMessage
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid mipsevm state (%v): %w", path, err)
+	}
+	var threads struct {
+		LeftThreadStack  json.RawMessage `json:"leftThreadStack"`
+		RightThreadStack json.RawMessage `json:"rightThreadStack"`
+	}
+	if err := json.Unmarshal(data, &threads); err != nil {
+		return nil, fmt.Errorf("invalid mipsevm state (%v): %w", path, err)
+	}
+	var state mipsevm.VMState = &mipsevm.State{}
+	if threads.LeftThreadStack != nil || threads.RightThreadStack != nil {
+		state = &mipsevm.MTState{}
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid mipsevm state (%v): %w", path, err)
+	}
+	return state, nil
+}
+
+func parseBinaryState(path string, in *bufio.Reader) (mipsevm.VMState, error) {
+	version, err := in.Peek(1)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read state version (%v): %w", path, err)
+	}
+	state, err := mipsevm.NewStateForBinaryVersion(version[0])
 	if err != nil {
 		return nil, fmt.Errorf("invalid mipsevm state (%v): %w", path, err)
 	}
-	return &state, nil
+	if err := state.Deserialize(in); err != nil {
+		return nil, fmt.Errorf("invalid mipsevm state (%v): %w", path, err)
+	}
+	return state, nil
+}
+
+// StateConverter reads cannon states and proofs for the generic VM trace provider.
+// Cannon writes its states and proofs in its binary format.
+type StateConverter struct{}
+
+var _ vm.StateConverter = (*StateConverter)(nil)
+
+func NewStateConverter() *StateConverter {
+	return &StateConverter{}
+}
+
+func (c *StateConverter) ConvertStateToProof(statePath string) (*vm.ProofData, uint64, bool, error) {
+	state, err := parseState(statePath)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	witness := state.EncodeWitness()
+	witnessHash, err := witness.StateHash()
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("cannot hash witness: %w", err)
+	}
+	return &vm.ProofData{
+		ClaimValue:   witnessHash,
+		StateData:    hexutil.Bytes(witness),
+		ProofData:    []byte{},
+		OracleKey:    nil,
+		OracleValue:  nil,
+		OracleOffset: 0,
+	}, state.GetStep(), state.GetExited(), nil
+}
+
+// ReadProof reads the proof at proofPath in the binary format,
+// or the proof at the same step in the JSON format written by earlier versions.
+func (c *StateConverter) ReadProof(proofPath string) (*vm.ProofData, error) {
+	proof, err := serialize.LoadSerializedBinary[mipsevm.Proof](proofPath)
+	if err == nil {
+		return &vm.ProofData{
+			ClaimValue:   proof.Post,
+			StateData:    proof.StateData,
+			ProofData:    proof.ProofData,
+			OracleKey:    proof.OracleKey,
+			OracleValue:  proof.OracleValue,
+			OracleOffset: proof.OracleOffset,
+		}, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read proof (%v): %w", proofPath, err)
+	}
+	return vm.ReadJSONProof(strings.TrimSuffix(proofPath, fileExt) + vm.DefaultFileExt)
+}
+
+func (c *StateConverter) WriteProof(proofPath string, step uint64, proof *vm.ProofData) error {
+	// The proof is of a no-op step, so the pre-state is the same as the post-state.
+	cannonProof := &mipsevm.Proof{
+		Step:         step,
+		Pre:          proof.ClaimValue,
+		Post:         proof.ClaimValue,
+		StateData:    proof.StateData,
+		ProofData:    proof.ProofData,
+		OracleKey:    proof.OracleKey,
+		OracleValue:  proof.OracleValue,
+		OracleOffset: proof.OracleOffset,
+	}
+	return serialize.WriteSerializedBinary(proofPath, cannonProof, 0o644)
 }
@@ -9,12 +9,19 @@ import (
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/cannon/mipsevm"
+	"github.com/ethereum-optimism/optimism/cannon/serialize"
 	"github.com/stretchr/testify/require"
 )
 
 //go:embed test_data/state.json
 var testState []byte
 
+//go:embed test_data/mt_state.json
+var testMTState []byte
+
+//go:embed test_data/mips64_state.bin
+var testMIPS64State []byte
+
 func TestLoadState(t *testing.T) {
 	t.Run("Uncompressed", func(t *testing.T) {
 		dir := t.TempDir()
@@ -47,4 +54,67 @@ func TestLoadState(t *testing.T) {
 		require.NoError(t, json.Unmarshal(testState, &expected))
 		require.Equal(t, &expected, state)
 	})
+
+	t.Run("Binary", func(t *testing.T) {
+		var expected mipsevm.State
+		require.NoError(t, json.Unmarshal(testState, &expected))
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "state.bin.gz")
+		require.NoError(t, serialize.WriteSerializedBinary(path, &expected, 0644))
+
+		state, err := parseState(path)
+		require.NoError(t, err)
+		require.Equal(t, expected.EncodeWitness(), state.EncodeWitness())
+		require.Equal(t, expected.Step, state.GetStep())
+	})
+
+	t.Run("MultiThreadedJSON", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "state.json")
+		require.NoError(t, os.WriteFile(path, testMTState, 0644))
+
+		state, err := parseState(path)
+		require.NoError(t, err)
+
+		var expected mipsevm.MTState
+		require.NoError(t, json.Unmarshal(testMTState, &expected))
+		require.Equal(t, &expected, state)
+	})
+
+	t.Run("MultiThreadedBinary", func(t *testing.T) {
+		var expected mipsevm.MTState
+		require.NoError(t, json.Unmarshal(testMTState, &expected))
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "state.bin.gz")
+		require.NoError(t, serialize.WriteSerializedBinary(path, &expected, 0644))
+
+		state, err := parseState(path)
+		require.NoError(t, err)
+		require.IsType(t, &mipsevm.MTState{}, state)
+		require.Equal(t, expected.EncodeWitness(), state.EncodeWitness())
+		require.Equal(t, expected.Step, state.GetStep())
+	})
+
+	t.Run("MIPS64Binary", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "state.bin")
+		require.NoError(t, os.WriteFile(path, testMIPS64State, 0644))
+
+		state, err := parseState(path)
+		require.NoError(t, err)
+		require.IsType(t, &mipsevm.MIPS64State{}, state)
+		require.Equal(t, uint64(30), state.GetStep())
+		require.Equal(t, uint64(8), state.(*mipsevm.MIPS64State).PC)
+	})
+
+	t.Run("UnknownBinaryVersion", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "state.bin")
+		require.NoError(t, os.WriteFile(path, []byte{0xff, 0x00}, 0644))
+
+		_, err := parseState(path)
+		require.ErrorContains(t, err, "unsupported binary format version 255")
+	})
 }
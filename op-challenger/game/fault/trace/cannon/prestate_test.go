@@ -58,6 +58,35 @@ func TestAbsolutePreStateCommitment(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, expected, actual)
 	})
+
+	t.Run("MultiThreadedAbsolutePreState", func(t *testing.T) {
+		setupPreState(t, dataDir, "mt_state.json")
+		provider := newCannonPrestateProvider(dataDir, prestate)
+		actual, err := provider.AbsolutePreStateCommitment(context.Background())
+		require.NoError(t, err)
+		state := mipsevm.NewMTState(4, 0x1000)
+		state.PreimageKey = common.HexToHash("dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd")
+		state.Step = 20
+		expected, err := state.EncodeWitness().StateHash()
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("MIPS64AbsolutePreState", func(t *testing.T) {
+		dir := t.TempDir()
+		data, err := testData.ReadFile(filepath.Join("test_data", "mips64_state.bin"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "prestate.bin"), data, 0o644))
+		provider := newCannonPrestateProvider(dir, "prestate.bin")
+		actual, err := provider.AbsolutePreStateCommitment(context.Background())
+		require.NoError(t, err)
+		state := mipsevm.CreateInitialMIPS64State(8, 0x2000)
+		state.PreimageKey = common.HexToHash("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+		state.Step = 30
+		expected, err := state.EncodeWitness().StateHash()
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
 }
 
 func setupPreState(t *testing.T, dataDir string, filename string) {
@@ -0,0 +1,211 @@
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	SnapsDir     = "snapshots"
+	PreimagesDir = "preimages"
+	ProofsDir    = "proofs"
+
+	// DefaultFileExt is the extension of the states, snapshots and proofs written by VMs that only support JSON.
+	DefaultFileExt = ".json.gz"
+)
+
+// snapshotNameRegexp matches snapshots in the binary format, and the JSON snapshots written by earlier versions.
+var snapshotNameRegexp = regexp.MustCompile(`^[0-9]+\.(bin|json)\.gz$`)
+
+type snapshotSelect func(logger log.Logger, dir string, absolutePreState string, i uint64) (string, error)
+type cmdExecutor func(ctx context.Context, l log.Logger, binary string, args ...string) error
+
+type Metricer interface {
+	RecordVmExecutionTime(vmType string, t float64)
+}
+
+// Config describes how to run a fault proof VM with a cannon compatible `run` command,
+// and the op-program server that provides the pre-images to the program running in the VM.
+type Config struct {
+	VmType           string // Name of the VM, used in logs and metrics
+	VmBin            string // Path to the VM executable to run when generating trace data
+	Server           string // Path to the executable that provides the pre-image oracle server
+	L1               string // L1 RPC Url
+	L2               string // L2 RPC Url
+	Network          string
+	RollupConfigPath string
+	L2GenesisPath    string
+	SnapshotFreq     uint   // Frequency of snapshots to create when executing (in VM instructions)
+	InfoFreq         uint   // Frequency of progress log messages (in VM instructions)
+	FileExt          string // Extension of the states, snapshots and proofs written by the VM, DefaultFileExt if empty
+	PreimageCacheDir string // Pre-image directory shared by all games, if empty pre-images are stored separately for each game
+}
+
+func (c Config) fileExt() string {
+	if c.FileExt == "" {
+		return DefaultFileExt
+	}
+	return c.FileExt
+}
+
+// FinalStatePath returns the path of the last state written by the VM when generating traces in dir.
+func (c Config) FinalStatePath(dir string) string {
+	return filepath.Join(dir, "final"+c.fileExt())
+}
+
+type Executor struct {
+	logger           log.Logger
+	metrics          Metricer
+	cfg              Config
+	inputs           utils.LocalGameInputs
+	absolutePreState string
+	selectSnapshot   snapshotSelect
+	cmdExecutor      cmdExecutor
+}
+
+func NewExecutor(logger log.Logger, m Metricer, cfg Config, prestate string, inputs utils.LocalGameInputs) *Executor {
+	return &Executor{
+		logger:           logger,
+		metrics:          m,
+		cfg:              cfg,
+		inputs:           inputs,
+		absolutePreState: prestate,
+		selectSnapshot:   findStartingSnapshot,
+		cmdExecutor:      runCmd,
+	}
+}
+
+// GenerateProof executes the VM to generate a proof at the specified trace index in dir.
+func (e *Executor) GenerateProof(ctx context.Context, dir string, i uint64) error {
+	snapshotDir := filepath.Join(dir, SnapsDir)
+	start, err := e.selectSnapshot(e.logger, snapshotDir, e.absolutePreState, i)
+	if err != nil {
+		return fmt.Errorf("find starting snapshot: %w", err)
+	}
+	proofDir := filepath.Join(dir, ProofsDir)
+	dataDir := filepath.Join(dir, PreimagesDir)
+	if e.cfg.PreimageCacheDir != "" {
+		dataDir = e.cfg.PreimageCacheDir
+	}
+	lastGeneratedState := e.cfg.FinalStatePath(dir)
+	args := []string{
+		"run",
+		"--input", start,
+		"--output", lastGeneratedState,
+		"--meta", "",
+		"--info-at", "%" + strconv.FormatUint(uint64(e.cfg.InfoFreq), 10),
+		"--proof-at", "=" + strconv.FormatUint(i, 10),
+		"--proof-fmt", filepath.Join(proofDir, "%d"+e.cfg.fileExt()),
+		"--snapshot-at", "%" + strconv.FormatUint(uint64(e.cfg.SnapshotFreq), 10),
+		"--snapshot-fmt", filepath.Join(snapshotDir, "%d"+e.cfg.fileExt()),
+	}
+	if i < math.MaxUint64 {
+		args = append(args, "--stop-at", "="+strconv.FormatUint(i+1, 10))
+	}
+	args = append(args,
+		"--",
+		e.cfg.Server, "--server",
+		"--l1", e.cfg.L1,
+		"--l2", e.cfg.L2,
+		"--datadir", dataDir,
+		"--l1.head", e.inputs.L1Head.Hex(),
+		"--l2.head", e.inputs.L2Head.Hex(),
+		"--l2.outputroot", e.inputs.L2OutputRoot.Hex(),
+		"--l2.claim", e.inputs.L2Claim.Hex(),
+		"--l2.blocknumber", e.inputs.L2BlockNumber.Text(10),
+	)
+	if e.cfg.Network != "" {
+		args = append(args, "--network", e.cfg.Network)
+	}
+	if e.cfg.RollupConfigPath != "" {
+		args = append(args, "--rollup.config", e.cfg.RollupConfigPath)
+	}
+	if e.cfg.L2GenesisPath != "" {
+		args = append(args, "--l2.genesis", e.cfg.L2GenesisPath)
+	}
+	if e.cfg.PreimageCacheDir != "" {
+		// The shared cache is pruned by deleting pre-image files, so must use the directory format.
+		args = append(args, "--data.format", "directory")
+	}
+
+	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
+		return fmt.Errorf("could not create snapshot directory %v: %w", snapshotDir, err)
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("could not create preimage cache directory %v: %w", dataDir, err)
+	}
+	if err := os.MkdirAll(proofDir, 0755); err != nil {
+		return fmt.Errorf("could not create proofs directory %v: %w", proofDir, err)
+	}
+	e.logger.Info("Generating trace", "vm", e.cfg.VmType, "proof", i, "cmd", e.cfg.VmBin, "args", strings.Join(args, ", "))
+	execStart := time.Now()
+	err = e.cmdExecutor(ctx, e.logger.New("proof", i), e.cfg.VmBin, args...)
+	e.metrics.RecordVmExecutionTime(e.cfg.VmType, time.Since(execStart).Seconds())
+	return err
+}
+
+func runCmd(ctx context.Context, l log.Logger, binary string, args ...string) error {
+	cmd := exec.CommandContext(ctx, binary, args...)
+	stdOut := oplog.NewWriter(l, log.LvlInfo)
+	defer stdOut.Close()
+	// Keep stdErr at info level because VMs use stderr for progress messages
+	stdErr := oplog.NewWriter(l, log.LvlInfo)
+	defer stdErr.Close()
+	cmd.Stdout = stdOut
+	cmd.Stderr = stdErr
+	return cmd.Run()
+}
+
+// findStartingSnapshot finds the closest snapshot before the specified traceIndex in snapDir.
+// If no suitable snapshot can be found it returns absolutePreState.
+func findStartingSnapshot(logger log.Logger, snapDir string, absolutePreState string, traceIndex uint64) (string, error) {
+	// Find the closest snapshot to start from
+	entries, err := os.ReadDir(snapDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return absolutePreState, nil
+		}
+		return "", fmt.Errorf("list snapshots in %v: %w", snapDir, err)
+	}
+	bestSnap := uint64(0)
+	bestName := ""
+	for _, entry := range entries {
+		if entry.IsDir() {
+			logger.Warn("Unexpected directory in snapshots dir", "parent", snapDir, "child", entry.Name())
+			continue
+		}
+		name := entry.Name()
+		if !snapshotNameRegexp.MatchString(name) {
+			logger.Warn("Unexpected file in snapshots dir", "parent", snapDir, "child", entry.Name())
+			continue
+		}
+		index, err := strconv.ParseUint(name[0:strings.Index(name, ".")], 10, 64)
+		if err != nil {
+			logger.Error("Unable to parse trace index of snapshot file", "parent", snapDir, "child", entry.Name())
+			continue
+		}
+		if index > bestSnap && index < traceIndex {
+			bestSnap = index
+			bestName = name
+		}
+	}
+	if bestSnap == 0 {
+		return absolutePreState, nil
+	}
+	startFrom := filepath.Join(snapDir, bestName)
+
+	return startFrom, nil
+}
@@ -1,4 +1,4 @@
-package cannon
+package vm
 
 import (
 	"context"
@@ -10,7 +10,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/ethereum-optimism/optimism/op-challenger/config"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum/common"
@@ -18,30 +18,32 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-const execTestCannonPrestate = "/foo/pre.json"
+const execTestPrestate = "/foo/pre.json"
 
 func TestGenerateProof(t *testing.T) {
 	input := "starting.json"
 	tempDir := t.TempDir()
 	dir := filepath.Join(tempDir, "gameDir")
-	cfg := config.NewConfig(common.Address{0xbb}, "http://localhost:8888", tempDir, config.TraceTypeCannon)
-	cfg.CannonAbsolutePreState = "pre.json"
-	cfg.CannonBin = "./bin/cannon"
-	cfg.CannonServer = "./bin/op-program"
-	cfg.CannonL2 = "http://localhost:9999"
-	cfg.CannonSnapshotFreq = 500
-	cfg.CannonInfoFreq = 900
-
-	inputs := LocalGameInputs{
+	cfg := Config{
+		VmType:       "test",
+		L1:           "http://localhost:8888",
+		L2:           "http://localhost:9999",
+		VmBin:        "./bin/testvm",
+		Server:       "./bin/op-program",
+		SnapshotFreq: 500,
+		InfoFreq:     900,
+	}
+
+	inputs := utils.LocalGameInputs{
 		L1Head:        common.Hash{0x11},
 		L2Head:        common.Hash{0x22},
 		L2OutputRoot:  common.Hash{0x33},
 		L2Claim:       common.Hash{0x44},
 		L2BlockNumber: big.NewInt(3333),
 	}
-	captureExec := func(t *testing.T, cfg config.Config, proofAt uint64) (string, string, map[string]string) {
-		m := &cannonDurationMetrics{}
-		executor := NewExecutor(testlog.Logger(t, log.LvlInfo), m, &cfg, inputs)
+	captureExec := func(t *testing.T, cfg Config, proofAt uint64) (string, string, map[string]string) {
+		m := &vmDurationMetrics{}
+		executor := NewExecutor(testlog.Logger(t, log.LvlInfo), m, cfg, "pre.json", inputs)
 		executor.selectSnapshot = func(logger log.Logger, dir string, absolutePreState string, i uint64) (string, error) {
 			return input, nil
 		}
@@ -53,7 +55,7 @@ func TestGenerateProof(t *testing.T) {
 			subcommand = a[0]
 			for i := 1; i < len(a); {
 				if a[i] == "--" {
-					// Skip over the divider between cannon and server program
+					// Skip over the divider between the VM and server program
 					i += 1
 					continue
 				}
@@ -64,24 +66,25 @@ func TestGenerateProof(t *testing.T) {
 		}
 		err := executor.GenerateProof(context.Background(), dir, proofAt)
 		require.NoError(t, err)
-		require.Equal(t, 1, m.executionTimeRecordCount, "Should record cannon execution time")
+		require.Equal(t, 1, m.executionTimeRecordCount, "Should record vm execution time")
+		require.Equal(t, cfg.VmType, m.vmType)
 		return binary, subcommand, args
 	}
 
 	t.Run("Network", func(t *testing.T) {
-		cfg.CannonNetwork = "mainnet"
-		cfg.CannonRollupConfigPath = ""
-		cfg.CannonL2GenesisPath = ""
+		cfg.Network = "mainnet"
+		cfg.RollupConfigPath = ""
+		cfg.L2GenesisPath = ""
 		binary, subcommand, args := captureExec(t, cfg, 150_000_000)
-		require.DirExists(t, filepath.Join(dir, preimagesDir))
-		require.DirExists(t, filepath.Join(dir, proofsDir))
-		require.DirExists(t, filepath.Join(dir, snapsDir))
-		require.Equal(t, cfg.CannonBin, binary)
+		require.DirExists(t, filepath.Join(dir, PreimagesDir))
+		require.DirExists(t, filepath.Join(dir, ProofsDir))
+		require.DirExists(t, filepath.Join(dir, SnapsDir))
+		require.Equal(t, cfg.VmBin, binary)
 		require.Equal(t, "run", subcommand)
 		require.Equal(t, input, args["--input"])
 		require.Contains(t, args, "--meta")
 		require.Equal(t, "", args["--meta"])
-		require.Equal(t, filepath.Join(dir, finalState), args["--output"])
+		require.Equal(t, filepath.Join(dir, "final.json.gz"), args["--output"])
 		require.Equal(t, "=150000000", args["--proof-at"])
 		require.Equal(t, "=150000001", args["--stop-at"])
 		require.Equal(t, "%500", args["--snapshot-at"])
@@ -89,15 +92,16 @@ func TestGenerateProof(t *testing.T) {
 		// Slight quirk of how we pair off args
 		// The server binary winds up as the key and the first arg --server as the value which has no value
 		// Then everything else pairs off correctly again
-		require.Equal(t, "--server", args[cfg.CannonServer])
-		require.Equal(t, cfg.L1EthRpc, args["--l1"])
-		require.Equal(t, cfg.CannonL2, args["--l2"])
-		require.Equal(t, filepath.Join(dir, preimagesDir), args["--datadir"])
-		require.Equal(t, filepath.Join(dir, proofsDir, "%d.json.gz"), args["--proof-fmt"])
-		require.Equal(t, filepath.Join(dir, snapsDir, "%d.json.gz"), args["--snapshot-fmt"])
-		require.Equal(t, cfg.CannonNetwork, args["--network"])
+		require.Equal(t, "--server", args[cfg.Server])
+		require.Equal(t, cfg.L1, args["--l1"])
+		require.Equal(t, cfg.L2, args["--l2"])
+		require.Equal(t, filepath.Join(dir, PreimagesDir), args["--datadir"])
+		require.Equal(t, filepath.Join(dir, ProofsDir, "%d.json.gz"), args["--proof-fmt"])
+		require.Equal(t, filepath.Join(dir, SnapsDir, "%d.json.gz"), args["--snapshot-fmt"])
+		require.Equal(t, cfg.Network, args["--network"])
 		require.NotContains(t, args, "--rollup.config")
 		require.NotContains(t, args, "--l2.genesis")
+		require.NotContains(t, args, "--data.format")
 
 		// Local game inputs
 		require.Equal(t, inputs.L1Head.Hex(), args["--l1.head"])
@@ -108,22 +112,39 @@ func TestGenerateProof(t *testing.T) {
 	})
 
 	t.Run("RollupAndGenesis", func(t *testing.T) {
-		cfg.CannonNetwork = ""
-		cfg.CannonRollupConfigPath = "rollup.json"
-		cfg.CannonL2GenesisPath = "genesis.json"
+		cfg.Network = ""
+		cfg.RollupConfigPath = "rollup.json"
+		cfg.L2GenesisPath = "genesis.json"
 		_, _, args := captureExec(t, cfg, 150_000_000)
 		require.NotContains(t, args, "--network")
-		require.Equal(t, cfg.CannonRollupConfigPath, args["--rollup.config"])
-		require.Equal(t, cfg.CannonL2GenesisPath, args["--l2.genesis"])
+		require.Equal(t, cfg.RollupConfigPath, args["--rollup.config"])
+		require.Equal(t, cfg.L2GenesisPath, args["--l2.genesis"])
+	})
+
+	t.Run("PreimageCache", func(t *testing.T) {
+		cfg := cfg
+		cfg.PreimageCacheDir = filepath.Join(dir, "preimage-cache")
+		_, _, args := captureExec(t, cfg, 150_000_000)
+		require.Equal(t, cfg.PreimageCacheDir, args["--datadir"])
+		require.Equal(t, "directory", args["--data.format"])
+	})
+
+	t.Run("FileExt", func(t *testing.T) {
+		cfg := cfg
+		cfg.FileExt = ".bin.gz"
+		_, _, args := captureExec(t, cfg, 150_000_000)
+		require.Equal(t, filepath.Join(dir, "final.bin.gz"), args["--output"])
+		require.Equal(t, filepath.Join(dir, ProofsDir, "%d.bin.gz"), args["--proof-fmt"])
+		require.Equal(t, filepath.Join(dir, SnapsDir, "%d.bin.gz"), args["--snapshot-fmt"])
 	})
 
 	t.Run("NoStopAtWhenProofIsMaxUInt", func(t *testing.T) {
-		cfg.CannonNetwork = "mainnet"
-		cfg.CannonRollupConfigPath = "rollup.json"
-		cfg.CannonL2GenesisPath = "genesis.json"
+		cfg.Network = "mainnet"
+		cfg.RollupConfigPath = "rollup.json"
+		cfg.L2GenesisPath = "genesis.json"
 		_, _, args := captureExec(t, cfg, math.MaxUint64)
 		// stop-at would need to be one more than the proof step which would overflow back to 0
-		// so expect that it will be omitted. We'll ultimately want cannon to execute until the program exits.
+		// so expect that it will be omitted. We'll ultimately want the VM to execute until the program exits.
 		require.NotContains(t, args, "--stop-at")
 	})
 }
@@ -155,70 +176,88 @@ func TestFindStartingSnapshot(t *testing.T) {
 
 	t.Run("UsePrestateWhenSnapshotsDirDoesNotExist", func(t *testing.T) {
 		dir := t.TempDir()
-		snapshot, err := findStartingSnapshot(logger, filepath.Join(dir, "doesNotExist"), execTestCannonPrestate, 1200)
+		snapshot, err := findStartingSnapshot(logger, filepath.Join(dir, "doesNotExist"), execTestPrestate, 1200)
 		require.NoError(t, err)
-		require.Equal(t, execTestCannonPrestate, snapshot)
+		require.Equal(t, execTestPrestate, snapshot)
 	})
 
 	t.Run("UsePrestateWhenSnapshotsDirEmpty", func(t *testing.T) {
 		dir := withSnapshots(t)
-		snapshot, err := findStartingSnapshot(logger, dir, execTestCannonPrestate, 1200)
+		snapshot, err := findStartingSnapshot(logger, dir, execTestPrestate, 1200)
 		require.NoError(t, err)
-		require.Equal(t, execTestCannonPrestate, snapshot)
+		require.Equal(t, execTestPrestate, snapshot)
 	})
 
 	t.Run("UsePrestateWhenNoSnapshotBeforeTraceIndex", func(t *testing.T) {
 		dir := withSnapshots(t, "100.json", "200.json")
-		snapshot, err := findStartingSnapshot(logger, dir, execTestCannonPrestate, 99)
+		snapshot, err := findStartingSnapshot(logger, dir, execTestPrestate, 99)
 		require.NoError(t, err)
-		require.Equal(t, execTestCannonPrestate, snapshot)
+		require.Equal(t, execTestPrestate, snapshot)
 
-		snapshot, err = findStartingSnapshot(logger, dir, execTestCannonPrestate, 100)
+		snapshot, err = findStartingSnapshot(logger, dir, execTestPrestate, 100)
 		require.NoError(t, err)
-		require.Equal(t, execTestCannonPrestate, snapshot)
+		require.Equal(t, execTestPrestate, snapshot)
 	})
 
 	t.Run("UseClosestAvailableSnapshot", func(t *testing.T) {
 		dir := withSnapshots(t, "100.json.gz", "123.json.gz", "250.json.gz")
 
-		snapshot, err := findStartingSnapshot(logger, dir, execTestCannonPrestate, 101)
+		snapshot, err := findStartingSnapshot(logger, dir, execTestPrestate, 101)
 		require.NoError(t, err)
 		require.Equal(t, filepath.Join(dir, "100.json.gz"), snapshot)
 
-		snapshot, err = findStartingSnapshot(logger, dir, execTestCannonPrestate, 123)
+		snapshot, err = findStartingSnapshot(logger, dir, execTestPrestate, 123)
 		require.NoError(t, err)
 		require.Equal(t, filepath.Join(dir, "100.json.gz"), snapshot)
 
-		snapshot, err = findStartingSnapshot(logger, dir, execTestCannonPrestate, 124)
+		snapshot, err = findStartingSnapshot(logger, dir, execTestPrestate, 124)
 		require.NoError(t, err)
 		require.Equal(t, filepath.Join(dir, "123.json.gz"), snapshot)
 
-		snapshot, err = findStartingSnapshot(logger, dir, execTestCannonPrestate, 256)
+		snapshot, err = findStartingSnapshot(logger, dir, execTestPrestate, 256)
 		require.NoError(t, err)
 		require.Equal(t, filepath.Join(dir, "250.json.gz"), snapshot)
 	})
 
+	t.Run("UseBinaryAndJSONSnapshots", func(t *testing.T) {
+		dir := withSnapshots(t, "100.json.gz", "123.bin.gz", "250.bin.gz")
+
+		snapshot, err := findStartingSnapshot(logger, dir, execTestPrestate, 101)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "100.json.gz"), snapshot)
+
+		snapshot, err = findStartingSnapshot(logger, dir, execTestPrestate, 124)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "123.bin.gz"), snapshot)
+
+		snapshot, err = findStartingSnapshot(logger, dir, execTestPrestate, 256)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "250.bin.gz"), snapshot)
+	})
+
 	t.Run("IgnoreDirectories", func(t *testing.T) {
 		dir := withSnapshots(t, "100.json.gz")
 		require.NoError(t, os.Mkdir(filepath.Join(dir, "120.json.gz"), 0o777))
-		snapshot, err := findStartingSnapshot(logger, dir, execTestCannonPrestate, 150)
+		snapshot, err := findStartingSnapshot(logger, dir, execTestPrestate, 150)
 		require.NoError(t, err)
 		require.Equal(t, filepath.Join(dir, "100.json.gz"), snapshot)
 	})
 
 	t.Run("IgnoreUnexpectedFiles", func(t *testing.T) {
-		dir := withSnapshots(t, ".file", "100.json.gz", "foo", "bar.json.gz")
-		snapshot, err := findStartingSnapshot(logger, dir, execTestCannonPrestate, 150)
+		dir := withSnapshots(t, ".file", "100.json.gz", "foo", "bar.json.gz", "120.bin")
+		snapshot, err := findStartingSnapshot(logger, dir, execTestPrestate, 150)
 		require.NoError(t, err)
 		require.Equal(t, filepath.Join(dir, "100.json.gz"), snapshot)
 	})
 }
 
-type cannonDurationMetrics struct {
+type vmDurationMetrics struct {
 	metrics.NoopMetricsImpl
 	executionTimeRecordCount int
+	vmType                   string
 }
 
-func (c *cannonDurationMetrics) RecordCannonExecutionTime(_ float64) {
+func (c *vmDurationMetrics) RecordVmExecutionTime(vmType string, _ float64) {
 	c.executionTimeRecordCount++
+	c.vmType = vmType
 }
@@ -0,0 +1,154 @@
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// preimageFileRegexp matches the pre-image files written by the op-program server to its data directory.
+// Temporary files created while writing a pre-image are not matched.
+var preimageFileRegexp = regexp.MustCompile(`^0x[0-9a-f]{64}\.txt$`)
+
+const (
+	// preimageCachePruneInterval is the time between checks of the pre-image cache size.
+	preimageCachePruneInterval = time.Minute
+
+	// preimageCacheTargetRatio is the fraction of the maximum size the cache is reduced to when it is pruned,
+	// so that pruning isn't required again as soon as another pre-image is added.
+	preimageCacheTargetRatio = 0.9
+)
+
+type PreimageCacheMetricer interface {
+	RecordPreimageCacheSize(bytes uint64, entries int)
+	RecordPreimageCacheEvictions(count int)
+}
+
+// PreimageCache manages a pre-image directory shared by the op-program servers of all games.
+// The op-program server reads pre-images from and writes fetched pre-images to the directory, so pre-images fetched
+// for one game are reused by other games on nearby outputs instead of being fetched again.
+// The cache is bounded by periodically evicting the least recently written pre-images once the total size exceeds
+// the maximum. Evicted pre-images are fetched again by the op-program server if they are required.
+// The op-program server must use the directory data format, as other formats aren't pruned.
+type PreimageCache struct {
+	logger  log.Logger
+	m       PreimageCacheMetricer
+	dir     string
+	maxSize uint64
+
+	wg     sync.WaitGroup
+	cancel func()
+}
+
+func NewPreimageCache(logger log.Logger, m PreimageCacheMetricer, dir string, maxSize uint64) *PreimageCache {
+	return &PreimageCache{
+		logger:  logger,
+		m:       m,
+		dir:     dir,
+		maxSize: maxSize,
+	}
+}
+
+// Dir returns the directory the pre-images are stored in.
+func (c *PreimageCache) Dir() string {
+	return c.dir
+}
+
+func (c *PreimageCache) Start(ctx context.Context) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("could not create preimage cache directory %v: %w", c.dir, err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.wg.Add(1)
+	go c.loop(ctx)
+	return nil
+}
+
+func (c *PreimageCache) Close() error {
+	if c.cancel == nil {
+		return nil // never started
+	}
+	c.cancel()
+	c.wg.Wait()
+	return nil
+}
+
+func (c *PreimageCache) loop(ctx context.Context) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(preimageCachePruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.Prune(); err != nil {
+			c.logger.Error("Failed to prune preimage cache", "dir", c.dir, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type cachedPreimage struct {
+	name    string
+	size    uint64
+	modTime time.Time
+}
+
+// Prune evicts the least recently written pre-images until the cache is within its target size,
+// if the total size of the cache exceeds the maximum size.
+func (c *PreimageCache) Prune() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list preimage cache: %w", err)
+	}
+	var preimages []cachedPreimage
+	var total uint64
+	for _, entry := range entries {
+		if entry.IsDir() || !preimageFileRegexp.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// Removed since the directory was listed
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to read preimage %v: %w", entry.Name(), err)
+		}
+		preimages = append(preimages, cachedPreimage{name: entry.Name(), size: uint64(info.Size()), modTime: info.ModTime()})
+		total += uint64(info.Size())
+	}
+
+	evicted := 0
+	if total > c.maxSize {
+		target := uint64(float64(c.maxSize) * preimageCacheTargetRatio)
+		sort.Slice(preimages, func(i, j int) bool {
+			return preimages[i].modTime.Before(preimages[j].modTime)
+		})
+		for _, preimage := range preimages {
+			if total <= target {
+				break
+			}
+			if err := os.Remove(filepath.Join(c.dir, preimage.name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				c.logger.Warn("Failed to evict preimage", "name", preimage.name, "err", err)
+				continue
+			}
+			total -= preimage.size
+			evicted++
+		}
+		c.logger.Info("Pruned preimage cache", "evicted", evicted, "size", total, "maxSize", c.maxSize)
+	}
+	c.m.RecordPreimageCacheEvictions(evicted)
+	c.m.RecordPreimageCacheSize(total, len(preimages)-evicted)
+	return nil
+}
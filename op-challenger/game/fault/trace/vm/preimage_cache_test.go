@@ -0,0 +1,132 @@
+package vm
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreimageCache(t *testing.T) {
+	// writePreimages writes count pre-images of 100 bytes each, with increasing modification times
+	writePreimages := func(t *testing.T, dir string, count int) []string {
+		start := time.Now().Add(-time.Hour)
+		var names []string
+		for i := 0; i < count; i++ {
+			name := common.Hash{byte(i + 1)}.String() + ".txt"
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, make([]byte, 100), 0644))
+			modTime := start.Add(time.Duration(i) * time.Minute)
+			require.NoError(t, os.Chtimes(path, modTime, modTime))
+			names = append(names, name)
+		}
+		return names
+	}
+
+	t.Run("UnderMaxSize", func(t *testing.T) {
+		dir := t.TempDir()
+		names := writePreimages(t, dir, 5)
+		m := &preimageCacheMetrics{}
+		cache := NewPreimageCache(testlog.Logger(t, log.LvlInfo), m, dir, 500)
+		require.NoError(t, cache.Prune())
+		for _, name := range names {
+			require.FileExists(t, filepath.Join(dir, name))
+		}
+		require.Equal(t, uint64(500), m.size)
+		require.Equal(t, 5, m.entries)
+		require.Zero(t, m.evictions)
+	})
+
+	t.Run("EvictOldest", func(t *testing.T) {
+		dir := t.TempDir()
+		names := writePreimages(t, dir, 10)
+		m := &preimageCacheMetrics{}
+		cache := NewPreimageCache(testlog.Logger(t, log.LvlInfo), m, dir, 500)
+		require.NoError(t, cache.Prune())
+		// Pruned to 90% of the max size
+		for _, name := range names[:6] {
+			require.NoFileExists(t, filepath.Join(dir, name))
+		}
+		for _, name := range names[6:] {
+			require.FileExists(t, filepath.Join(dir, name))
+		}
+		require.Equal(t, uint64(400), m.size)
+		require.Equal(t, 4, m.entries)
+		require.Equal(t, 6, m.evictions)
+	})
+
+	t.Run("IgnoreOtherFiles", func(t *testing.T) {
+		dir := t.TempDir()
+		writePreimages(t, dir, 2)
+		tempFile := filepath.Join(dir, common.Hash{0xaa}.String()+".txt.1234")
+		require.NoError(t, os.WriteFile(tempFile, make([]byte, 1000), 0644))
+		otherFile := filepath.Join(dir, "README")
+		require.NoError(t, os.WriteFile(otherFile, make([]byte, 1000), 0644))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, common.Hash{0xbb}.String()+".txt"), 0755))
+
+		m := &preimageCacheMetrics{}
+		cache := NewPreimageCache(testlog.Logger(t, log.LvlInfo), m, dir, 500)
+		require.NoError(t, cache.Prune())
+		require.FileExists(t, tempFile)
+		require.FileExists(t, otherFile)
+		require.Equal(t, uint64(200), m.size)
+		require.Equal(t, 2, m.entries)
+	})
+
+	t.Run("StartCreatesDir", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "cache")
+		m := &preimageCacheMetrics{}
+		cache := NewPreimageCache(testlog.Logger(t, log.LvlInfo), m, dir, 500)
+		require.NoError(t, cache.Start(context.Background()))
+		defer cache.Close()
+		require.DirExists(t, dir)
+	})
+}
+
+func TestGenerateProofWithPreimageCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gameDir")
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	cfg := Config{
+		VmType:           "test",
+		VmBin:            "./bin/testvm",
+		Server:           "./bin/op-program",
+		PreimageCacheDir: cacheDir,
+	}
+	executor := NewExecutor(testlog.Logger(t, log.LvlInfo), &vmDurationMetrics{}, cfg, "pre.json", utils.LocalGameInputs{L2BlockNumber: big.NewInt(1)})
+	var datadir string
+	executor.cmdExecutor = func(ctx context.Context, l log.Logger, b string, a ...string) error {
+		for i, arg := range a {
+			if arg == "--datadir" {
+				datadir = a[i+1]
+			}
+		}
+		return nil
+	}
+	require.NoError(t, executor.GenerateProof(context.Background(), dir, 100))
+	require.Equal(t, cacheDir, datadir)
+	require.DirExists(t, cacheDir)
+	require.NoDirExists(t, filepath.Join(dir, PreimagesDir))
+}
+
+type preimageCacheMetrics struct {
+	size      uint64
+	entries   int
+	evictions int
+}
+
+func (m *preimageCacheMetrics) RecordPreimageCacheSize(bytes uint64, entries int) {
+	m.size = bytes
+	m.entries = entries
+}
+
+func (m *preimageCacheMetrics) RecordPreimageCacheEvictions(count int) {
+	m.evictions += count
+}
@@ -0,0 +1,234 @@
+package vm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/utils"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const diskStateCache = "state.json.gz"
+
+// ProofData is the proof of a single VM step, as written by the VM to the proofs directory.
+type ProofData struct {
+	ClaimValue   common.Hash   `json:"post"`
+	StateData    hexutil.Bytes `json:"state-data"`
+	ProofData    hexutil.Bytes `json:"proof-data"`
+	OracleKey    hexutil.Bytes `json:"oracle-key,omitempty"`
+	OracleValue  hexutil.Bytes `json:"oracle-value,omitempty"`
+	OracleOffset uint32        `json:"oracle-offset,omitempty"`
+}
+
+// StateConverter reads the states and proofs written by a VM.
+// It provides the state encoding, state hash function and proof format of the VM.
+type StateConverter interface {
+	// ConvertStateToProof reads the state at the given path, and returns the proof of a no-op step from it,
+	// along with the step of the state and whether the VM has exited.
+	ConvertStateToProof(statePath string) (*ProofData, uint64, bool, error)
+
+	// ReadProof reads the proof at the given path.
+	// The returned error wraps os.ErrNotExist if the proof does not exist.
+	ReadProof(proofPath string) (*ProofData, error)
+
+	// WriteProof writes the proof of the no-op step at step to the given path, in the format read by ReadProof.
+	WriteProof(proofPath string, step uint64, proof *ProofData) error
+}
+
+type ProofGenerator interface {
+	// GenerateProof executes the VM to generate a proof at the specified trace index in dataDir.
+	GenerateProof(ctx context.Context, dataDir string, proofAt uint64) error
+}
+
+// TraceProvider is a trace provider for a fault proof VM that is run via its `run` command.
+// The format of the states and proofs written by the VM is handled by its StateConverter.
+type TraceProvider struct {
+	logger         log.Logger
+	dir            string
+	prestate       string
+	cfg            Config
+	generator      ProofGenerator
+	stateConverter StateConverter
+	gameDepth      types.Depth
+	localContext   common.Hash
+
+	// lastStep stores the last step in the actual trace if known. 0 indicates unknown.
+	// Cached as an optimisation to avoid repeatedly attempting to execute beyond the end of the trace.
+	lastStep uint64
+}
+
+func NewTraceProvider(logger log.Logger, m Metricer, cfg Config, stateConverter StateConverter, prestate string, localContext common.Hash, localInputs utils.LocalGameInputs, dir string, gameDepth types.Depth) *TraceProvider {
+	return &TraceProvider{
+		logger:         logger,
+		dir:            dir,
+		prestate:       prestate,
+		cfg:            cfg,
+		generator:      NewExecutor(logger, m, cfg, prestate, localInputs),
+		stateConverter: stateConverter,
+		gameDepth:      gameDepth,
+		localContext:   localContext,
+	}
+}
+
+func (p *TraceProvider) Get(ctx context.Context, pos types.Position) (common.Hash, error) {
+	traceIndex := pos.TraceIndex(p.gameDepth)
+	if !traceIndex.IsUint64() {
+		return common.Hash{}, errors.New("trace index out of bounds")
+	}
+	proof, err := p.loadProof(ctx, traceIndex.Uint64())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	value := proof.ClaimValue
+
+	if value == (common.Hash{}) {
+		return common.Hash{}, errors.New("proof missing post hash")
+	}
+	return value, nil
+}
+
+func (p *TraceProvider) GetStepData(ctx context.Context, pos types.Position) ([]byte, []byte, *types.PreimageOracleData, error) {
+	traceIndex := pos.TraceIndex(p.gameDepth)
+	if !traceIndex.IsUint64() {
+		return nil, nil, nil, errors.New("trace index out of bounds")
+	}
+	proof, err := p.loadProof(ctx, traceIndex.Uint64())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	value := ([]byte)(proof.StateData)
+	if len(value) == 0 {
+		return nil, nil, nil, errors.New("proof missing state data")
+	}
+	data := ([]byte)(proof.ProofData)
+	if data == nil {
+		return nil, nil, nil, errors.New("proof missing proof data")
+	}
+	var oracleData *types.PreimageOracleData
+	if len(proof.OracleKey) > 0 {
+		oracleData = types.NewPreimageOracleData(proof.OracleKey, proof.OracleValue, proof.OracleOffset)
+	}
+	return value, data, oracleData, nil
+}
+
+func (p *TraceProvider) AbsolutePreStateCommitment(_ context.Context) (common.Hash, error) {
+	proof, _, _, err := p.stateConverter.ConvertStateToProof(p.prestate)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("cannot load absolute pre-state: %w", err)
+	}
+	return proof.ClaimValue, nil
+}
+
+// loadProof will attempt to load or generate the proof data at the specified index
+// If the requested index is beyond the end of the actual trace it is extended with no-op instructions.
+func (p *TraceProvider) loadProof(ctx context.Context, i uint64) (*ProofData, error) {
+	// Attempt to read the last step from disk cache
+	if p.lastStep == 0 {
+		step, err := readLastStep(p.dir)
+		if err != nil {
+			p.logger.Warn("Failed to read last step from disk cache", "err", err)
+		} else {
+			p.lastStep = step
+		}
+	}
+	// If the last step is tracked, set i to the last step to generate or load the final proof
+	if p.lastStep != 0 && i > p.lastStep {
+		i = p.lastStep
+	}
+	path := filepath.Join(p.dir, ProofsDir, fmt.Sprintf("%d%s", i, p.cfg.fileExt()))
+	proof, err := p.stateConverter.ReadProof(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := p.generator.GenerateProof(ctx, p.dir, i); err != nil {
+			return nil, fmt.Errorf("generate %v trace with proof at %v: %w", p.cfg.VmType, i, err)
+		}
+		// Try reading the proof again now and it should exist.
+		proof, err = p.stateConverter.ReadProof(path)
+		if errors.Is(err, os.ErrNotExist) {
+			// Expected proof wasn't generated, check if we reached the end of execution
+			proof, step, exited, err := p.stateConverter.ConvertStateToProof(p.cfg.FinalStatePath(p.dir))
+			if err != nil {
+				return nil, fmt.Errorf("cannot read final state: %w", err)
+			}
+			if exited && step <= i {
+				p.logger.Warn("Requested proof was after the program exited", "proof", i, "last", step)
+				// The final instruction has already been applied to this state, so the last step we can execute
+				// is one before its Step value.
+				p.lastStep = step - 1
+				// Extend the trace out to the full length using a no-op instruction that doesn't change any state
+				// No execution is done, so no proof-data or oracle values are required.
+				if err := p.writeLastStep(proof); err != nil {
+					p.logger.Warn("Failed to write last step to disk cache", "step", p.lastStep)
+				}
+				return proof, nil
+			} else {
+				return nil, fmt.Errorf("expected proof not generated but final state was not exited, requested step %v, final state at step %v", i, step)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// ReadJSONProof reads a proof written as JSON, for VMs that only support JSON.
+// The returned error wraps os.ErrNotExist if the proof does not exist.
+func ReadJSONProof(path string) (*ProofData, error) {
+	file, err := ioutil.OpenDecompressed(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open proof file (%v): %w", path, err)
+	}
+	defer file.Close()
+	var proof ProofData
+	err = json.NewDecoder(file).Decode(&proof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proof (%v): %w", path, err)
+	}
+	return &proof, nil
+}
+
+// WriteJSONProof writes a proof as JSON, in the format read by ReadJSONProof.
+func WriteJSONProof(path string, proof *ProofData) error {
+	return ioutil.WriteCompressedJson(path, proof)
+}
+
+type diskStateCacheObj struct {
+	Step uint64 `json:"step"`
+}
+
+// readLastStep reads the tracked last step from disk.
+func readLastStep(dir string) (uint64, error) {
+	state := diskStateCacheObj{}
+	file, err := ioutil.OpenDecompressed(filepath.Join(dir, diskStateCache))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	err = json.NewDecoder(file).Decode(&state)
+	if err != nil {
+		return 0, err
+	}
+	return state.Step, nil
+}
+
+// writeLastStep writes the last step and proof to disk as a persistent cache.
+func (p *TraceProvider) writeLastStep(proof *ProofData) error {
+	state := diskStateCacheObj{Step: p.lastStep}
+	lastStepFile := filepath.Join(p.dir, diskStateCache)
+	if err := ioutil.WriteCompressedJson(lastStepFile, state); err != nil {
+		return fmt.Errorf("failed to write last step to %v: %w", lastStepFile, err)
+	}
+	proofFile := filepath.Join(p.dir, ProofsDir, fmt.Sprintf("%d%s", p.lastStep, p.cfg.fileExt()))
+	if err := p.stateConverter.WriteProof(proofFile, p.lastStep, proof); err != nil {
+		return fmt.Errorf("failed to write proof: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-service/ioutil"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func positionFromTraceIndex(provider *TraceProvider, idx *big.Int) types.Position {
+	return types.NewPosition(provider.gameDepth, idx)
+}
+
+func TestGet(t *testing.T) {
+	t.Run("ExistingProof", func(t *testing.T) {
+		provider, generator, _ := setupProvider(t)
+		writeProof(t, provider.dir, 0, &ProofData{ClaimValue: common.Hash{0xaa}, StateData: []byte{0xbb}, ProofData: []byte{0xcc}})
+		value, err := provider.Get(context.Background(), positionFromTraceIndex(provider, common.Big0))
+		require.NoError(t, err)
+		require.Equal(t, common.Hash{0xaa}, value)
+		require.Empty(t, generator.generated)
+	})
+
+	t.Run("ErrorsTraceIndexOutOfBounds", func(t *testing.T) {
+		provider, generator, _ := setupProvider(t)
+		largePosition := positionFromTraceIndex(provider, new(big.Int).Mul(new(big.Int).SetUint64(math.MaxUint64), big.NewInt(2)))
+		_, err := provider.Get(context.Background(), largePosition)
+		require.ErrorContains(t, err, "trace index out of bounds")
+		require.Empty(t, generator.generated)
+	})
+
+	t.Run("GeneratesProof", func(t *testing.T) {
+		provider, generator, _ := setupProvider(t)
+		generator.proof = &ProofData{ClaimValue: common.Hash{0xdd}, StateData: []byte{0xee}, ProofData: []byte{0xff}}
+		value, err := provider.Get(context.Background(), positionFromTraceIndex(provider, big.NewInt(42)))
+		require.NoError(t, err)
+		require.Equal(t, common.Hash{0xdd}, value)
+		require.Equal(t, []int{42}, generator.generated)
+	})
+
+	t.Run("ProofAfterEndOfTrace", func(t *testing.T) {
+		provider, generator, converter := setupProvider(t)
+		generator.finalStep = 10
+		converter.step = 10
+		converter.exited = true
+		value, err := provider.Get(context.Background(), positionFromTraceIndex(provider, big.NewInt(7000)))
+		require.NoError(t, err)
+		require.Contains(t, generator.generated, 7000, "should have tried to generate the proof")
+		require.Equal(t, converter.proof.ClaimValue, value)
+		require.Equal(t, filepath.Join(provider.dir, "final.json.gz"), converter.path)
+	})
+
+	t.Run("ErrorWhenFinalStateNotExited", func(t *testing.T) {
+		provider, generator, converter := setupProvider(t)
+		generator.finalStep = 10
+		converter.step = 10
+		_, err := provider.Get(context.Background(), positionFromTraceIndex(provider, big.NewInt(7000)))
+		require.ErrorContains(t, err, "final state was not exited")
+	})
+
+	t.Run("ErrorWhenFinalStateInvalid", func(t *testing.T) {
+		provider, generator, converter := setupProvider(t)
+		generator.finalStep = 10
+		converter.err = errors.New("boom")
+		_, err := provider.Get(context.Background(), positionFromTraceIndex(provider, big.NewInt(7000)))
+		require.ErrorIs(t, err, converter.err)
+	})
+
+	t.Run("MissingPostHash", func(t *testing.T) {
+		provider, generator, _ := setupProvider(t)
+		writeProof(t, provider.dir, 1, &ProofData{StateData: []byte{0xbb}, ProofData: []byte{0xcc}})
+		_, err := provider.Get(context.Background(), positionFromTraceIndex(provider, big.NewInt(1)))
+		require.ErrorContains(t, err, "missing post hash")
+		require.Empty(t, generator.generated)
+	})
+}
+
+func TestGetStepData(t *testing.T) {
+	t.Run("ExistingProof", func(t *testing.T) {
+		provider, generator, _ := setupProvider(t)
+		writeProof(t, provider.dir, 0, &ProofData{
+			ClaimValue:   common.Hash{0xaa},
+			StateData:    []byte{0xbb},
+			ProofData:    []byte{0xcc},
+			OracleKey:    common.Hash{0xdd}.Bytes(),
+			OracleValue:  []byte{0xee},
+			OracleOffset: 4,
+		})
+		value, proof, data, err := provider.GetStepData(context.Background(), positionFromTraceIndex(provider, common.Big0))
+		require.NoError(t, err)
+		require.Equal(t, []byte{0xbb}, value)
+		require.Equal(t, []byte{0xcc}, proof)
+		require.Equal(t, types.NewPreimageOracleData(common.Hash{0xdd}.Bytes(), []byte{0xee}, 4), data)
+		require.Empty(t, generator.generated)
+	})
+
+	t.Run("MissingStateData", func(t *testing.T) {
+		provider, _, _ := setupProvider(t)
+		writeProof(t, provider.dir, 1, &ProofData{ClaimValue: common.Hash{0xaa}, ProofData: []byte{0xcc}})
+		_, _, _, err := provider.GetStepData(context.Background(), positionFromTraceIndex(provider, big.NewInt(1)))
+		require.ErrorContains(t, err, "missing state data")
+	})
+
+	t.Run("ProofAfterEndOfTrace", func(t *testing.T) {
+		provider, generator, converter := setupProvider(t)
+		generator.finalStep = 10
+		converter.step = 10
+		converter.exited = true
+		value, proof, data, err := provider.GetStepData(context.Background(), positionFromTraceIndex(provider, big.NewInt(7000)))
+		require.NoError(t, err)
+		require.Contains(t, generator.generated, 7000, "should have tried to generate the proof")
+		require.EqualValues(t, converter.proof.StateData, value)
+		require.Empty(t, proof)
+		require.Nil(t, data)
+	})
+
+	t.Run("ReadLastStepFromDisk", func(t *testing.T) {
+		provider, initGenerator, converter := setupProvider(t)
+		initGenerator.finalStep = 10
+		converter.step = 10
+		converter.exited = true
+		_, _, _, err := provider.GetStepData(context.Background(), positionFromTraceIndex(provider, big.NewInt(7000)))
+		require.NoError(t, err)
+		require.Contains(t, initGenerator.generated, 7000, "should have tried to generate the proof")
+
+		// A new provider for the same directory reads the last step from disk, and does not execute the VM again
+		generator := &stubGenerator{}
+		provider = &TraceProvider{
+			logger:         provider.logger,
+			dir:            provider.dir,
+			generator:      generator,
+			stateConverter: converter,
+			gameDepth:      provider.gameDepth,
+		}
+		value, _, _, err := provider.GetStepData(context.Background(), positionFromTraceIndex(provider, big.NewInt(7000)))
+		require.NoError(t, err)
+		require.Empty(t, generator.generated)
+		require.EqualValues(t, converter.proof.StateData, value)
+	})
+}
+
+func TestAbsolutePreStateCommitment(t *testing.T) {
+	provider, _, converter := setupProvider(t)
+	provider.prestate = "pre.json"
+	actual, err := provider.AbsolutePreStateCommitment(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, converter.proof.ClaimValue, actual)
+	require.Equal(t, "pre.json", converter.path)
+
+	converter.err = errors.New("boom")
+	_, err = provider.AbsolutePreStateCommitment(context.Background())
+	require.ErrorIs(t, err, converter.err)
+}
+
+func setupProvider(t *testing.T) (*TraceProvider, *stubGenerator, *stubStateConverter) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ProofsDir), 0o777))
+	generator := &stubGenerator{}
+	converter := &stubStateConverter{
+		proof: &ProofData{
+			ClaimValue: common.Hash{0x01, 0xaa},
+			StateData:  []byte{0x01, 0x02},
+			ProofData:  []byte{},
+		},
+	}
+	return &TraceProvider{
+		logger:         testlog.Logger(t, log.LvlInfo),
+		dir:            dir,
+		cfg:            Config{VmType: "test"},
+		generator:      generator,
+		stateConverter: converter,
+		gameDepth:      63,
+	}, generator, converter
+}
+
+func writeProof(t *testing.T, dir string, i uint64, proof *ProofData) {
+	require.NoError(t, ioutil.WriteCompressedJson(filepath.Join(dir, ProofsDir, fmt.Sprintf("%d.json.gz", i)), proof))
+}
+
+type stubGenerator struct {
+	generated []int // Using int makes assertions easier
+	finalStep uint64
+	proof     *ProofData
+}
+
+func (e *stubGenerator) GenerateProof(ctx context.Context, dir string, i uint64) error {
+	e.generated = append(e.generated, int(i))
+	if e.finalStep > 0 && e.finalStep <= i {
+		// Requesting a trace index past the end of the trace, only the final state is written.
+		return nil
+	}
+	if e.proof != nil {
+		return ioutil.WriteCompressedJson(filepath.Join(dir, ProofsDir, fmt.Sprintf("%d.json.gz", i)), e.proof)
+	}
+	return nil
+}
+
+type stubStateConverter struct {
+	path   string
+	proof  *ProofData
+	step   uint64
+	exited bool
+	err    error
+}
+
+func (c *stubStateConverter) ConvertStateToProof(statePath string) (*ProofData, uint64, bool, error) {
+	c.path = statePath
+	if c.err != nil {
+		return nil, 0, false, c.err
+	}
+	return c.proof, c.step, c.exited, nil
+}
+
+func (c *stubStateConverter) ReadProof(proofPath string) (*ProofData, error) {
+	return ReadJSONProof(proofPath)
+}
+
+func (c *stubStateConverter) WriteProof(proofPath string, _ uint64, proof *ProofData) error {
+	return WriteJSONProof(proofPath, proof)
+}
@@ -30,11 +30,16 @@ type gameScheduler interface {
 	Schedule([]types.GameMetadata, uint64) error
 }
 
+type bondClaimer interface {
+	Schedule([]types.GameMetadata) error
+}
+
 type gameMonitor struct {
 	logger           log.Logger
 	clock            clock.Clock
 	source           gameSource
 	scheduler        gameScheduler
+	claimer          bondClaimer
 	gameWindow       time.Duration
 	fetchBlockNumber blockNumberFetcher
 	allowedGames     []common.Address
@@ -60,6 +65,7 @@ func newGameMonitor(
 	cl clock.Clock,
 	source gameSource,
 	scheduler gameScheduler,
+	claimer bondClaimer,
 	gameWindow time.Duration,
 	fetchBlockNumber blockNumberFetcher,
 	allowedGames []common.Address,
@@ -69,6 +75,7 @@ func newGameMonitor(
 		logger:           logger,
 		clock:            cl,
 		scheduler:        scheduler,
+		claimer:          claimer,
 		source:           source,
 		gameWindow:       gameWindow,
 		fetchBlockNumber: fetchBlockNumber,
@@ -119,6 +126,11 @@ func (m *gameMonitor) progressGames(ctx context.Context, blockHash common.Hash,
 	} else if err != nil {
 		return fmt.Errorf("failed to schedule games: %w", err)
 	}
+	if err := m.claimer.Schedule(gamesToPlay); errors.Is(err, scheduler.ErrBusy) {
+		m.logger.Debug("Bond claimer still busy with previous update")
+	} else if err != nil {
+		return fmt.Errorf("failed to schedule bond claims: %w", err)
+	}
 	return nil
 }
 
@@ -2,12 +2,14 @@ package game
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum"
@@ -154,6 +156,25 @@ func TestMonitorCreateAndProgressGameAgents(t *testing.T) {
 	require.Equal(t, []common.Address{addr1, addr2}, sched.Scheduled()[0])
 }
 
+func TestMonitorSchedulesBondClaims(t *testing.T) {
+	monitor, source, _, _ := setupMonitorTest(t, []common.Address{})
+	claimer := &stubClaimer{}
+	monitor.claimer = claimer
+
+	addr1 := common.Address{0xaa}
+	addr2 := common.Address{0xbb}
+	source.games = []types.GameMetadata{newFDG(addr1, 9999), newFDG(addr2, 9999)}
+
+	require.NoError(t, monitor.progressGames(context.Background(), common.Hash{0x01}, 0))
+	require.Equal(t, [][]common.Address{{addr1, addr2}}, claimer.scheduled)
+
+	claimer.err = scheduler.ErrBusy
+	require.NoError(t, monitor.progressGames(context.Background(), common.Hash{0x01}, 1))
+
+	claimer.err = errors.New("boom")
+	require.ErrorIs(t, monitor.progressGames(context.Background(), common.Hash{0x01}, 2), claimer.err)
+}
+
 func TestMonitorOnlyScheduleSpecifiedGame(t *testing.T) {
 	addr1 := common.Address{0xaa}
 	addr2 := common.Address{0xbb}
@@ -191,6 +212,7 @@ func setupMonitorTest(
 		clock.SystemClock,
 		source,
 		sched,
+		&stubClaimer{},
 		time.Duration(0),
 		fetchBlockNum,
 		allowedGames,
@@ -261,6 +283,23 @@ func (s *stubGameSource) FetchAllGamesAtBlock(
 	return s.games, nil
 }
 
+type stubClaimer struct {
+	scheduled [][]common.Address
+	err       error
+}
+
+func (s *stubClaimer) Schedule(games []types.GameMetadata) error {
+	if s.err != nil {
+		return s.err
+	}
+	var addrs []common.Address
+	for _, game := range games {
+		addrs = append(addrs, game.Proxy)
+	}
+	s.scheduled = append(s.scheduled, addrs)
+	return nil
+}
+
 type stubScheduler struct {
 	sync.Mutex
 	scheduled [][]common.Address
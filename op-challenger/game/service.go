@@ -13,10 +13,13 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-challenger/config"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/claims"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/contracts"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/fault/trace/vm"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/loader"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/registry"
 	"github.com/ethereum-optimism/optimism/op-challenger/game/scheduler"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
 	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
 	"github.com/ethereum-optimism/optimism/op-challenger/version"
 	"github.com/ethereum-optimism/optimism/op-service/client"
@@ -34,6 +37,9 @@ type Service struct {
 	metrics metrics.Metricer
 	monitor *gameMonitor
 	sched   *scheduler.Scheduler
+	claimer *claims.BondClaimScheduler
+
+	preimageCache *vm.PreimageCache
 
 	faultGamesCloser fault.CloseFunc
 
@@ -102,6 +108,8 @@ func (s *Service) initFromConfig(ctx context.Context, cfg *config.Config) error
 	if err := s.initScheduler(cfg); err != nil {
 		return fmt.Errorf("failed to init scheduler: %w", err)
 	}
+	s.initBondClaims()
+	s.initPreimageCache(cfg)
 
 	s.initMonitor(cfg)
 
@@ -218,14 +226,37 @@ func (s *Service) initScheduler(cfg *config.Config) error {
 	return nil
 }
 
+func (s *Service) initPreimageCache(cfg *config.Config) {
+	if dir := cfg.PreimageCacheDir(); dir != "" {
+		s.preimageCache = vm.NewPreimageCache(s.logger, s.metrics, dir, cfg.PreimageCacheSize)
+	}
+}
+
+func (s *Service) initBondClaims() {
+	caller := batching.NewMultiCaller(s.l1Client.Client(), batching.DefaultBatchSize)
+	contractCreator := func(game types.GameMetadata) (claims.BondContract, error) {
+		return contracts.NewFaultDisputeGameContract(game.Proxy, caller)
+	}
+	claimer := claims.NewBondClaimer(s.logger, s.metrics, contractCreator, s.txMgr)
+	s.claimer = claims.NewBondClaimScheduler(s.logger, claimer)
+}
+
 func (s *Service) initMonitor(cfg *config.Config) {
 	cl := clock.SystemClock
-	s.monitor = newGameMonitor(s.logger, cl, s.loader, s.sched, cfg.GameWindow, s.l1Client.BlockNumber, cfg.GameAllowlist, s.pollClient)
+	s.monitor = newGameMonitor(s.logger, cl, s.loader, s.sched, s.claimer, cfg.GameWindow, s.l1Client.BlockNumber, cfg.GameAllowlist, s.pollClient)
 }
 
 func (s *Service) Start(ctx context.Context) error {
+	if s.preimageCache != nil {
+		s.logger.Info("starting preimage cache", "dir", s.preimageCache.Dir())
+		if err := s.preimageCache.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start preimage cache: %w", err)
+		}
+	}
 	s.logger.Info("starting scheduler")
 	s.sched.Start(ctx)
+	s.logger.Info("starting bond claimer")
+	s.claimer.Start(ctx)
 	s.logger.Info("starting monitoring")
 	s.monitor.StartMonitoring()
 	s.logger.Info("challenger game service start completed")
@@ -248,6 +279,16 @@ func (s *Service) Stop(ctx context.Context) error {
 	if s.monitor != nil {
 		s.monitor.StopMonitoring()
 	}
+	if s.claimer != nil {
+		if err := s.claimer.Close(); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to close bond claimer: %w", err))
+		}
+	}
+	if s.preimageCache != nil {
+		if err := s.preimageCache.Close(); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to close preimage cache: %w", err))
+		}
+	}
 	if s.faultGamesCloser != nil {
 		s.faultGamesCloser()
 	}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"io"
+	"math/big"
 
 	"github.com/ethereum-optimism/optimism/op-service/sources/caching"
 	"github.com/ethereum/go-ethereum/common"
@@ -32,13 +33,20 @@ type Metricer interface {
 
 	RecordGameStep()
 	RecordGameMove()
-	RecordCannonExecutionTime(t float64)
+	RecordVmExecutionTime(vmType string, t float64)
+
+	RecordPreimageCacheSize(bytes uint64, entries int)
+	RecordPreimageCacheEvictions(count int)
 
 	RecordGamesStatus(inProgress, defenderWon, challengerWon int)
 
 	RecordGameUpdateScheduled()
 	RecordGameUpdateCompleted()
 
+	RecordBondClaimed(amount *big.Int)
+	RecordBondClaimFailed()
+	RecordOutstandingBonds(locked *big.Int, claimable *big.Int)
+
 	IncActiveExecutors()
 	DecActiveExecutors()
 	IncIdleExecutors()
@@ -67,10 +75,19 @@ type Metrics struct {
 	moves prometheus.Counter
 	steps prometheus.Counter
 
-	cannonExecutionTime prometheus.Histogram
+	vmExecutionTime prometheus.HistogramVec
+
+	preimageCacheSize      prometheus.Gauge
+	preimageCacheEntries   prometheus.Gauge
+	preimageCacheEvictions prometheus.Counter
 
 	trackedGames  prometheus.GaugeVec
 	inflightGames prometheus.Gauge
+
+	bondClaims        prometheus.Counter
+	bondsClaimed      prometheus.Counter
+	bondClaimFailures prometheus.Counter
+	outstandingBonds  prometheus.GaugeVec
 }
 
 func (m *Metrics) Registry() *prometheus.Registry {
@@ -121,13 +138,28 @@ func NewMetrics() *Metrics {
 			Name:      "steps",
 			Help:      "Number of game steps made by the challenge agent",
 		}),
-		cannonExecutionTime: factory.NewHistogram(prometheus.HistogramOpts{
+		vmExecutionTime: *factory.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: Namespace,
 			Name:      "cannon_execution_time",
-			Help:      "Time (in seconds) to execute cannon",
+			Help:      "Time (in seconds) to execute the fault proof VM, labelled by VM type",
 			Buckets: append(
 				[]float64{1.0, 10.0},
 				prometheus.ExponentialBuckets(30.0, 2.0, 14)...),
+		}, []string{"vm"}),
+		preimageCacheSize: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "preimage_cache_size",
+			Help:      "Total size (in bytes) of the pre-images in the shared pre-image cache",
+		}),
+		preimageCacheEntries: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "preimage_cache_entries",
+			Help:      "Number of pre-images in the shared pre-image cache",
+		}),
+		preimageCacheEvictions: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "preimage_cache_evictions",
+			Help:      "Number of pre-images evicted from the shared pre-image cache",
 		}),
 		trackedGames: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: Namespace,
@@ -146,6 +178,28 @@ func NewMetrics() *Metrics {
 			Name:      "inflight_games",
 			Help:      "Number of games being tracked by the challenger",
 		}),
+		bondClaims: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "bond_claims",
+			Help:      "Number of credit claim transactions sent by the challenger",
+		}),
+		bondsClaimed: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "bonds_claimed",
+			Help:      "Total bonds (in ether) claimed by the challenger",
+		}),
+		bondClaimFailures: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "bond_claim_failures",
+			Help:      "Number of failed attempts to claim credit",
+		}),
+		outstandingBonds: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "bonds_outstanding",
+			Help:      "Bonds (in ether) posted by the challenger that have not yet been claimed",
+		}, []string{
+			"state",
+		}),
 	}
 }
 
@@ -185,8 +239,17 @@ func (m *Metrics) RecordGameStep() {
 	m.steps.Add(1)
 }
 
-func (m *Metrics) RecordCannonExecutionTime(t float64) {
-	m.cannonExecutionTime.Observe(t)
+func (m *Metrics) RecordVmExecutionTime(vmType string, t float64) {
+	m.vmExecutionTime.WithLabelValues(vmType).Observe(t)
+}
+
+func (m *Metrics) RecordPreimageCacheSize(bytes uint64, entries int) {
+	m.preimageCacheSize.Set(float64(bytes))
+	m.preimageCacheEntries.Set(float64(entries))
+}
+
+func (m *Metrics) RecordPreimageCacheEvictions(count int) {
+	m.preimageCacheEvictions.Add(float64(count))
 }
 
 func (m *Metrics) IncActiveExecutors() {
@@ -222,3 +285,17 @@ func (m *Metrics) RecordGameUpdateScheduled() {
 func (m *Metrics) RecordGameUpdateCompleted() {
 	m.inflightGames.Sub(1)
 }
+
+func (m *Metrics) RecordBondClaimed(amount *big.Int) {
+	m.bondClaims.Inc()
+	m.bondsClaimed.Add(opmetrics.WeiToEther(amount))
+}
+
+func (m *Metrics) RecordBondClaimFailed() {
+	m.bondClaimFailures.Inc()
+}
+
+func (m *Metrics) RecordOutstandingBonds(locked *big.Int, claimable *big.Int) {
+	m.outstandingBonds.WithLabelValues("locked").Set(opmetrics.WeiToEther(locked))
+	m.outstandingBonds.WithLabelValues("claimable").Set(opmetrics.WeiToEther(claimable))
+}
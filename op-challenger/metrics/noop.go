@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"io"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -28,13 +29,20 @@ func (*NoopMetricsImpl) RecordGameStep() {}
 
 func (*NoopMetricsImpl) RecordActedL1Block(_ uint64) {}
 
-func (*NoopMetricsImpl) RecordCannonExecutionTime(t float64) {}
+func (*NoopMetricsImpl) RecordVmExecutionTime(_ string, _ float64) {}
+
+func (*NoopMetricsImpl) RecordPreimageCacheSize(_ uint64, _ int) {}
+func (*NoopMetricsImpl) RecordPreimageCacheEvictions(_ int)      {}
 
 func (*NoopMetricsImpl) RecordGamesStatus(inProgress, defenderWon, challengerWon int) {}
 
 func (*NoopMetricsImpl) RecordGameUpdateScheduled() {}
 func (*NoopMetricsImpl) RecordGameUpdateCompleted() {}
 
+func (*NoopMetricsImpl) RecordBondClaimed(_ *big.Int)                  {}
+func (*NoopMetricsImpl) RecordBondClaimFailed()                        {}
+func (*NoopMetricsImpl) RecordOutstandingBonds(_ *big.Int, _ *big.Int) {}
+
 func (*NoopMetricsImpl) IncActiveExecutors() {}
 func (*NoopMetricsImpl) DecActiveExecutors() {}
 func (*NoopMetricsImpl) IncIdleExecutors()   {}
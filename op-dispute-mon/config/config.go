@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/oppprof"
+)
+
+var (
+	ErrMissingL1EthRPC           = errors.New("missing l1 eth rpc url")
+	ErrMissingGameFactoryAddress = errors.New("missing game factory address")
+	ErrMissingRollupRpc          = errors.New("missing rollup rpc url")
+	ErrMissingMonitorInterval    = errors.New("missing monitor interval")
+	ErrNegativeClockWarning      = errors.New("clock warning must not be negative")
+)
+
+const (
+	// DefaultMonitorInterval is the default interval between checks of the games.
+	DefaultMonitorInterval = time.Minute
+	// DefaultGameWindow is the default maximum time duration in the past
+	// that games are monitored. Matches the default game window of op-challenger.
+	DefaultGameWindow = time.Duration(11 * 24 * time.Hour)
+	// DefaultClockWarning is the default time remaining on a game clock below which
+	// an alert is raised if the game is heading to an incorrect resolution.
+	DefaultClockWarning = 24 * time.Hour
+)
+
+// Config is a well typed config that is parsed from the CLI params.
+// It also contains config options for auxiliary services.
+type Config struct {
+	L1EthRpc           string         // L1 RPC Url
+	GameFactoryAddress common.Address // Address of the dispute game factory
+	RollupRpc          string         // The rollup node RPC URL, trusted to provide the correct output roots
+
+	MonitorInterval time.Duration // Frequency to check the dispute games
+	GameWindow      time.Duration // Maximum window to look for games
+	ClockWarning    time.Duration // Remaining clock time below which games heading to an incorrect resolution are alerted on
+
+	MetricsConfig opmetrics.CLIConfig
+	PprofConfig   oppprof.CLIConfig
+}
+
+func NewConfig(gameFactoryAddress common.Address, l1EthRpc string, rollupRpc string) Config {
+	return Config{
+		L1EthRpc:           l1EthRpc,
+		GameFactoryAddress: gameFactoryAddress,
+		RollupRpc:          rollupRpc,
+
+		MonitorInterval: DefaultMonitorInterval,
+		GameWindow:      DefaultGameWindow,
+		ClockWarning:    DefaultClockWarning,
+
+		MetricsConfig: opmetrics.DefaultCLIConfig(),
+		PprofConfig:   oppprof.DefaultCLIConfig(),
+	}
+}
+
+func (c Config) Check() error {
+	if c.L1EthRpc == "" {
+		return ErrMissingL1EthRPC
+	}
+	if c.RollupRpc == "" {
+		return ErrMissingRollupRpc
+	}
+	if c.GameFactoryAddress == (common.Address{}) {
+		return ErrMissingGameFactoryAddress
+	}
+	if c.MonitorInterval == 0 {
+		return ErrMissingMonitorInterval
+	}
+	if c.ClockWarning < 0 {
+		return ErrNegativeClockWarning
+	}
+	if err := c.MetricsConfig.Check(); err != nil {
+		return fmt.Errorf("metrics config: %w", err)
+	}
+	if err := c.PprofConfig.Check(); err != nil {
+		return fmt.Errorf("pprof config: %w", err)
+	}
+	return nil
+}
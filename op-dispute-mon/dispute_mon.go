@@ -0,0 +1,20 @@
+package op_dispute_mon
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/config"
+	"github.com/ethereum-optimism/optimism/op-dispute-mon/mon"
+	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+)
+
+// Main is the programmatic entry-point for running op-dispute-mon with a given configuration.
+func Main(ctx context.Context, logger log.Logger, cfg *config.Config) (cliapp.Lifecycle, error) {
+	if err := cfg.Check(); err != nil {
+		return nil, err
+	}
+	srv, err := mon.NewService(ctx, logger, cfg)
+	return srv, err
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+)
+
+const Namespace = "op_dispute_mon"
+
+type Metricer interface {
+	RecordInfo(version string)
+	RecordUp()
+
+	RecordGameAgreement(complete bool, rootAgree bool, correct bool, count int)
+	RecordGamesExpiring(count int)
+	RecordGameErrors(count int)
+	RecordMonitorDuration(seconds float64)
+}
+
+// Metrics implementation must implement RegistryMetricer to allow the metrics server to work.
+var _ opmetrics.RegistryMetricer = (*Metrics)(nil)
+
+type Metrics struct {
+	ns       string
+	registry *prometheus.Registry
+	factory  opmetrics.Factory
+
+	info prometheus.GaugeVec
+	up   prometheus.Gauge
+
+	gamesAgreement  prometheus.GaugeVec
+	gamesExpiring   prometheus.Gauge
+	gameErrors      prometheus.Gauge
+	monitorDuration prometheus.Histogram
+}
+
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+var _ Metricer = (*Metrics)(nil)
+
+func NewMetrics() *Metrics {
+	registry := opmetrics.NewRegistry()
+	factory := opmetrics.With(registry)
+
+	return &Metrics{
+		ns:       Namespace,
+		registry: registry,
+		factory:  factory,
+
+		info: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "info",
+			Help:      "Pseudo-metric tracking version and config info",
+		}, []string{
+			"version",
+		}),
+		up: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "up",
+			Help:      "1 if the op-dispute-mon has finished starting up",
+		}),
+		gamesAgreement: *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "games_agreement",
+			Help:      "Number of games broken down by whether the result is (or is heading to be) correct",
+		}, []string{
+			"completion",
+			"root_agreement",
+			"result_correctness",
+		}),
+		gamesExpiring: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "games_expiring",
+			Help:      "Number of games heading to an incorrect resolution that are close to their clock expiry",
+		}),
+		gameErrors: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "game_errors",
+			Help:      "Number of games that could not be checked in the last update",
+		}),
+		monitorDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "monitor_duration_seconds",
+			Help:      "Time taken to check all dispute games",
+			Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600},
+		}),
+	}
+}
+
+func (m *Metrics) Start(host string, port int) (*httputil.HTTPServer, error) {
+	return opmetrics.StartServer(m.registry, host, port)
+}
+
+// RecordInfo sets a pseudo-metric that contains versioning and
+// config info for the op-dispute-mon.
+func (m *Metrics) RecordInfo(version string) {
+	m.info.WithLabelValues(version).Set(1)
+}
+
+// RecordUp sets the up metric to 1.
+func (m *Metrics) RecordUp() {
+	prometheus.MustRegister()
+	m.up.Set(1)
+}
+
+func (m *Metrics) Document() []opmetrics.DocumentedMetric {
+	return m.factory.Document()
+}
+
+func (m *Metrics) RecordGameAgreement(complete bool, rootAgree bool, correct bool, count int) {
+	completion := "in_progress"
+	if complete {
+		completion = "complete"
+	}
+	rootAgreement := "disagree"
+	if rootAgree {
+		rootAgreement = "agree"
+	}
+	correctness := "incorrect"
+	if correct {
+		correctness = "correct"
+	}
+	m.gamesAgreement.WithLabelValues(completion, rootAgreement, correctness).Set(float64(count))
+}
+
+func (m *Metrics) RecordGamesExpiring(count int) {
+	m.gamesExpiring.Set(float64(count))
+}
+
+func (m *Metrics) RecordGameErrors(count int) {
+	m.gameErrors.Set(float64(count))
+}
+
+func (m *Metrics) RecordMonitorDuration(seconds float64) {
+	m.monitorDuration.Observe(seconds)
+}
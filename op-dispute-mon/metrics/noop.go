@@ -0,0 +1,13 @@
+package metrics
+
+type NoopMetricsImpl struct{}
+
+var NoopMetrics Metricer = new(NoopMetricsImpl)
+
+func (*NoopMetricsImpl) RecordInfo(version string) {}
+func (*NoopMetricsImpl) RecordUp()                 {}
+
+func (*NoopMetricsImpl) RecordGameAgreement(_ bool, _ bool, _ bool, _ int) {}
+func (*NoopMetricsImpl) RecordGamesExpiring(_ int)                         {}
+func (*NoopMetricsImpl) RecordGameErrors(_ int)                            {}
+func (*NoopMetricsImpl) RecordMonitorDuration(_ float64)                   {}
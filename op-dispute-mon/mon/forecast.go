@@ -0,0 +1,62 @@
+package mon
+
+import (
+	"time"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+)
+
+// forecastResolution returns the status the game would resolve to if no further moves were made.
+// A claim is countered if any of its children are left uncountered, which is the rule the
+// FaultDisputeGame contract applies when resolving each subgame.
+func forecastResolution(claims []faultTypes.Claim) types.GameStatus {
+	if len(claims) == 0 {
+		return types.GameStatusInProgress
+	}
+	countered := make([]bool, len(claims))
+	// Children are always added after their parent so iterating in reverse visits
+	// all children of a claim before the claim itself.
+	for i := len(claims) - 1; i > 0; i-- {
+		if !countered[i] {
+			countered[claims[i].ParentContractIndex] = true
+		}
+	}
+	if countered[0] {
+		return types.GameStatusChallengerWon
+	}
+	return types.GameStatusDefenderWon
+}
+
+// maxClockRemaining returns the most time remaining to post a counter-claim to any claim in the game.
+// Each team has half the game duration on its chess clock. The duration used by a move is the duration
+// of the move's grandparent plus the time elapsed since its parent was posted.
+func maxClockRemaining(claims []faultTypes.Claim, gameDuration uint64, now time.Time) time.Duration {
+	maxDuration := gameDuration / 2
+	durations := make([]uint64, len(claims))
+	for i := 1; i < len(claims); i++ {
+		parent := claims[i].ParentContractIndex
+		durations[i] = claims[i].Clock - claims[parent].Clock
+		if parent != 0 {
+			durations[i] += durations[claims[parent].ParentContractIndex]
+		}
+	}
+	var remaining time.Duration
+	for i, claim := range claims {
+		// The duration a counter-claim to this claim would have if it was posted now.
+		var elapsed uint64
+		if i != 0 {
+			elapsed = durations[claim.ParentContractIndex]
+		}
+		if ts := uint64(now.Unix()); ts > claim.Clock {
+			elapsed += ts - claim.Clock
+		}
+		if elapsed >= maxDuration {
+			continue
+		}
+		if r := time.Duration(maxDuration-elapsed) * time.Second; r > remaining {
+			remaining = r
+		}
+	}
+	return remaining
+}
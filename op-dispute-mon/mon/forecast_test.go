@@ -0,0 +1,111 @@
+package mon
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastResolution(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   []faultTypes.Claim
+		expected types.GameStatus
+	}{
+		{
+			name:     "NoClaims",
+			expected: types.GameStatusInProgress,
+		},
+		{
+			name:     "UncounteredRoot",
+			claims:   []faultTypes.Claim{rootClaim(0)},
+			expected: types.GameStatusDefenderWon,
+		},
+		{
+			name:     "CounteredRoot",
+			claims:   []faultTypes.Claim{rootClaim(0), childClaim(0, 0)},
+			expected: types.GameStatusChallengerWon,
+		},
+		{
+			name:     "CounterCountered",
+			claims:   []faultTypes.Claim{rootClaim(0), childClaim(0, 0), childClaim(1, 0)},
+			expected: types.GameStatusDefenderWon,
+		},
+		{
+			name: "OneUncounteredChildIsEnough",
+			claims: []faultTypes.Claim{
+				rootClaim(0),
+				childClaim(0, 0), // Countered by 3
+				childClaim(0, 0), // Uncountered
+				childClaim(1, 0),
+			},
+			expected: types.GameStatusChallengerWon,
+		},
+		{
+			name: "DeepTree",
+			claims: []faultTypes.Claim{
+				rootClaim(0),
+				childClaim(0, 0),
+				childClaim(1, 0),
+				childClaim(2, 0),
+				childClaim(3, 0),
+			},
+			expected: types.GameStatusDefenderWon,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, forecastResolution(test.claims))
+		})
+	}
+}
+
+func TestMaxClockRemaining(t *testing.T) {
+	gameDuration := uint64(1000) // Each team has 500 seconds
+	start := uint64(10_000)
+
+	t.Run("RootOnly", func(t *testing.T) {
+		claims := []faultTypes.Claim{rootClaim(start)}
+		require.Equal(t, 500*time.Second, maxClockRemaining(claims, gameDuration, time.Unix(int64(start), 0)))
+		require.Equal(t, 400*time.Second, maxClockRemaining(claims, gameDuration, time.Unix(int64(start+100), 0)))
+		require.Equal(t, time.Duration(0), maxClockRemaining(claims, gameDuration, time.Unix(int64(start+500), 0)))
+		require.Equal(t, time.Duration(0), maxClockRemaining(claims, gameDuration, time.Unix(int64(start+600), 0)))
+	})
+
+	t.Run("UsesGrandparentDuration", func(t *testing.T) {
+		claims := []faultTypes.Claim{
+			rootClaim(start),
+			childClaim(0, start+100), // Challenger used 100s
+			childClaim(1, start+150), // Defender used 50s
+		}
+		now := time.Unix(int64(start+200), 0)
+		// Countering the root: challenger has used 200s
+		// Countering claim 1: defender has used 0 + 100s
+		// Countering claim 2: challenger has used 100s + 50s
+		require.Equal(t, 400*time.Second, maxClockRemaining(claims, gameDuration, now))
+	})
+
+	t.Run("AllExpired", func(t *testing.T) {
+		claims := []faultTypes.Claim{rootClaim(start), childClaim(0, start+100)}
+		require.Equal(t, time.Duration(0), maxClockRemaining(claims, gameDuration, time.Unix(int64(start+1000), 0)))
+	})
+}
+
+func rootClaim(clock uint64) faultTypes.Claim {
+	return faultTypes.Claim{
+		Clock:               clock,
+		ParentContractIndex: math.MaxUint32,
+	}
+}
+
+func childClaim(parentIdx int, clock uint64) faultTypes.Claim {
+	return faultTypes.Claim{
+		Clock:               clock,
+		ParentContractIndex: parentIdx,
+	}
+}
@@ -0,0 +1,207 @@
+package mon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type MonitorMetricer interface {
+	RecordGameAgreement(complete bool, rootAgree bool, correct bool, count int)
+	RecordGamesExpiring(count int)
+	RecordGameErrors(count int)
+	RecordMonitorDuration(seconds float64)
+}
+
+type blockHashFetcher func(ctx context.Context) (common.Hash, error)
+
+// gameSource loads information about the games in the dispute game factory.
+type gameSource interface {
+	FetchAllGamesAtBlock(ctx context.Context, earliest uint64, blockHash common.Hash) ([]types.GameMetadata, error)
+}
+
+type GameContract interface {
+	GetStatus(ctx context.Context) (types.GameStatus, error)
+	GetAllClaims(ctx context.Context) ([]faultTypes.Claim, error)
+	GetBlockRange(ctx context.Context) (prestateBlock uint64, poststateBlock uint64, retErr error)
+	GetGameDuration(ctx context.Context) (uint64, error)
+}
+
+type GameContractCreator func(game types.GameMetadata) (GameContract, error)
+
+type OutputRollupClient interface {
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+}
+
+// gameResult is the outcome of checking a single game.
+type gameResult struct {
+	complete bool
+	// rootAgree is true if the root claim of the game matches the output root from the trusted rollup node.
+	rootAgree bool
+	// correct is true if the game resolved, or is currently heading to resolve, to the correct result.
+	correct bool
+	// expiring is true if the game is heading to an incorrect result and is close to its clock expiry.
+	expiring bool
+}
+
+type gameMonitor struct {
+	logger         log.Logger
+	clock          clock.Clock
+	metrics        MonitorMetricer
+	source         gameSource
+	createContract GameContractCreator
+	rollupClient   OutputRollupClient
+	fetchBlockHash blockHashFetcher
+	gameWindow     time.Duration
+	clockWarning   time.Duration
+}
+
+func newGameMonitor(
+	logger log.Logger,
+	cl clock.Clock,
+	metrics MonitorMetricer,
+	source gameSource,
+	createContract GameContractCreator,
+	rollupClient OutputRollupClient,
+	fetchBlockHash blockHashFetcher,
+	gameWindow time.Duration,
+	clockWarning time.Duration,
+) *gameMonitor {
+	return &gameMonitor{
+		logger:         logger,
+		clock:          cl,
+		metrics:        metrics,
+		source:         source,
+		createContract: createContract,
+		rollupClient:   rollupClient,
+		fetchBlockHash: fetchBlockHash,
+		gameWindow:     gameWindow,
+		clockWarning:   clockWarning,
+	}
+}
+
+func (m *gameMonitor) minGameTimestamp() uint64 {
+	if m.gameWindow.Seconds() == 0 {
+		return 0
+	}
+	// time: "To compute t-d for a duration d, use t.Add(-d)."
+	// https://pkg.go.dev/time#Time.Sub
+	if m.clock.Now().Unix() > int64(m.gameWindow.Seconds()) {
+		return uint64(m.clock.Now().Add(-m.gameWindow).Unix())
+	}
+	return 0
+}
+
+// monitorGames checks all games in the game window and records the results.
+func (m *gameMonitor) monitorGames(ctx context.Context) error {
+	start := m.clock.Now()
+	blockHash, err := m.fetchBlockHash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch block hash: %w", err)
+	}
+	games, err := m.source.FetchAllGamesAtBlock(ctx, m.minGameTimestamp(), blockHash)
+	if err != nil {
+		return fmt.Errorf("failed to load games: %w", err)
+	}
+	counts := make(map[gameResult]int)
+	expiring := 0
+	errs := 0
+	for _, game := range games {
+		result, err := m.checkGame(ctx, game)
+		if err != nil {
+			m.logger.Warn("Failed to check game", "game", game.Proxy, "err", err)
+			errs++
+			continue
+		}
+		if result.expiring {
+			expiring++
+		}
+		result.expiring = false
+		counts[result]++
+	}
+	for _, complete := range []bool{true, false} {
+		for _, rootAgree := range []bool{true, false} {
+			for _, correct := range []bool{true, false} {
+				count := counts[gameResult{complete: complete, rootAgree: rootAgree, correct: correct}]
+				m.metrics.RecordGameAgreement(complete, rootAgree, correct, count)
+			}
+		}
+	}
+	m.metrics.RecordGamesExpiring(expiring)
+	m.metrics.RecordGameErrors(errs)
+	m.metrics.RecordMonitorDuration(m.clock.Now().Sub(start).Seconds())
+	m.logger.Info("Checked dispute games", "games", len(games), "errors", errs, "expiring", expiring)
+	return nil
+}
+
+func (m *gameMonitor) checkGame(ctx context.Context, game types.GameMetadata) (gameResult, error) {
+	contract, err := m.createContract(game)
+	if err != nil {
+		return gameResult{}, fmt.Errorf("failed to create game contract: %w", err)
+	}
+	status, err := contract.GetStatus(ctx)
+	if err != nil {
+		return gameResult{}, err
+	}
+	claims, err := contract.GetAllClaims(ctx)
+	if err != nil {
+		return gameResult{}, err
+	}
+	if len(claims) == 0 {
+		return gameResult{}, fmt.Errorf("game has no root claim")
+	}
+	_, l2BlockNum, err := contract.GetBlockRange(ctx)
+	if err != nil {
+		return gameResult{}, err
+	}
+	output, err := m.rollupClient.OutputAtBlock(ctx, l2BlockNum)
+	if err != nil {
+		return gameResult{}, fmt.Errorf("failed to fetch output at block %v: %w", l2BlockNum, err)
+	}
+	rootClaim := claims[0].Value
+	rootAgree := common.Hash(output.OutputRoot) == rootClaim
+	// The proposal is valid if the root claim agrees with the trusted output, so the defender should win.
+	expectedStatus := types.GameStatusChallengerWon
+	if rootAgree {
+		expectedStatus = types.GameStatusDefenderWon
+	}
+	result := gameResult{
+		complete:  status != types.GameStatusInProgress,
+		rootAgree: rootAgree,
+	}
+	if result.complete {
+		result.correct = status == expectedStatus
+		if !result.correct {
+			m.logger.Error("Game resolved incorrectly", "game", game.Proxy, "status", status,
+				"rootClaim", rootClaim, "expected", output.OutputRoot, "l2BlockNum", l2BlockNum)
+		}
+		return result, nil
+	}
+
+	forecast := forecastResolution(claims)
+	result.correct = forecast == expectedStatus
+	if result.correct {
+		return result, nil
+	}
+	duration, err := contract.GetGameDuration(ctx)
+	if err != nil {
+		return gameResult{}, err
+	}
+	remaining := maxClockRemaining(claims, duration, m.clock.Now())
+	if remaining < m.clockWarning {
+		result.expiring = true
+		m.logger.Error("Game clock expiring without a correct counter-claim", "game", game.Proxy, "forecast", forecast,
+			"expected", expectedStatus, "remaining", remaining, "rootClaim", rootClaim, "l2BlockNum", l2BlockNum)
+	} else {
+		m.logger.Warn("Game heading to incorrect resolution", "game", game.Proxy, "forecast", forecast,
+			"expected", expectedStatus, "remaining", remaining, "rootClaim", rootClaim, "l2BlockNum", l2BlockNum)
+	}
+	return result, nil
+}
@@ -0,0 +1,219 @@
+package mon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	faultTypes "github.com/ethereum-optimism/optimism/op-challenger/game/fault/types"
+	"github.com/ethereum-optimism/optimism/op-challenger/game/types"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	correctRoot = common.Hash{0xaa}
+	wrongRoot   = common.Hash{0xbb}
+	now         = time.Unix(100_000, 0)
+	mockErr     = errors.New("mock error")
+)
+
+func TestMonitorMinGameTimestamp(t *testing.T) {
+	t.Run("ZeroGameWindow", func(t *testing.T) {
+		monitor, _, _, _ := setupMonitorTest(t)
+		monitor.gameWindow = time.Duration(0)
+		require.Equal(t, uint64(0), monitor.minGameTimestamp())
+	})
+
+	t.Run("ComputedCorrectly", func(t *testing.T) {
+		monitor, _, _, _ := setupMonitorTest(t)
+		monitor.gameWindow = time.Minute
+		require.Equal(t, uint64(now.Add(-time.Minute).Unix()), monitor.minGameTimestamp())
+	})
+}
+
+func TestMonitorGames(t *testing.T) {
+	t.Run("CompleteGames", func(t *testing.T) {
+		monitor, source, contracts, m := setupMonitorTest(t)
+		addGame(source, contracts, common.Address{0x01}, &stubGameContract{status: types.GameStatusDefenderWon, claims: []faultTypes.Claim{root(correctRoot)}})
+		addGame(source, contracts, common.Address{0x02}, &stubGameContract{status: types.GameStatusChallengerWon, claims: []faultTypes.Claim{root(wrongRoot)}})
+		addGame(source, contracts, common.Address{0x03}, &stubGameContract{status: types.GameStatusChallengerWon, claims: []faultTypes.Claim{root(correctRoot)}})
+		addGame(source, contracts, common.Address{0x04}, &stubGameContract{status: types.GameStatusDefenderWon, claims: []faultTypes.Claim{root(wrongRoot)}})
+
+		require.NoError(t, monitor.monitorGames(context.Background()))
+		require.Equal(t, 1, m.agreement[gameResult{complete: true, rootAgree: true, correct: true}])
+		require.Equal(t, 1, m.agreement[gameResult{complete: true, rootAgree: false, correct: true}])
+		require.Equal(t, 1, m.agreement[gameResult{complete: true, rootAgree: true, correct: false}])
+		require.Equal(t, 1, m.agreement[gameResult{complete: true, rootAgree: false, correct: false}])
+		require.Zero(t, m.agreement[gameResult{complete: false, rootAgree: true, correct: true}])
+		require.Len(t, m.agreement, 8, "should record all combinations")
+		require.Zero(t, m.expiring)
+		require.Zero(t, m.errors)
+	})
+
+	t.Run("InProgressGames", func(t *testing.T) {
+		monitor, source, contracts, m := setupMonitorTest(t)
+		start := uint64(now.Unix()) - 100
+		// Valid root, uncountered so heading to the correct result
+		addGame(source, contracts, common.Address{0x01}, &stubGameContract{
+			status: types.GameStatusInProgress,
+			claims: []faultTypes.Claim{root(correctRoot)},
+		})
+		// Invalid root, countered so heading to the correct result
+		addGame(source, contracts, common.Address{0x02}, &stubGameContract{
+			status: types.GameStatusInProgress,
+			claims: []faultTypes.Claim{root(wrongRoot), child(0, start)},
+		})
+		// Invalid root, uncountered with plenty of time remaining
+		addGame(source, contracts, common.Address{0x03}, &stubGameContract{
+			status:   types.GameStatusInProgress,
+			claims:   []faultTypes.Claim{rootAt(wrongRoot, start)},
+			duration: 10_000,
+		})
+		// Valid root, countered with little time remaining
+		addGame(source, contracts, common.Address{0x04}, &stubGameContract{
+			status:   types.GameStatusInProgress,
+			claims:   []faultTypes.Claim{rootAt(correctRoot, start), child(0, start)},
+			duration: 300,
+		})
+
+		require.NoError(t, monitor.monitorGames(context.Background()))
+		require.Equal(t, 1, m.agreement[gameResult{complete: false, rootAgree: true, correct: true}])
+		require.Equal(t, 1, m.agreement[gameResult{complete: false, rootAgree: false, correct: true}])
+		require.Equal(t, 1, m.agreement[gameResult{complete: false, rootAgree: false, correct: false}])
+		require.Equal(t, 1, m.agreement[gameResult{complete: false, rootAgree: true, correct: false}])
+		require.Equal(t, 1, m.expiring)
+		require.Zero(t, m.errors)
+	})
+
+	t.Run("CountsGameErrors", func(t *testing.T) {
+		monitor, source, contracts, m := setupMonitorTest(t)
+		addGame(source, contracts, common.Address{0x01}, &stubGameContract{statusErr: mockErr})
+		addGame(source, contracts, common.Address{0x02}, &stubGameContract{status: types.GameStatusDefenderWon})
+		addGame(source, contracts, common.Address{0x03}, &stubGameContract{status: types.GameStatusDefenderWon, claims: []faultTypes.Claim{root(correctRoot)}, l2BlockNum: 999})
+		addGame(source, contracts, common.Address{0x04}, &stubGameContract{status: types.GameStatusDefenderWon, claims: []faultTypes.Claim{root(correctRoot)}})
+
+		require.NoError(t, monitor.monitorGames(context.Background()))
+		require.Equal(t, 3, m.errors)
+		require.Equal(t, 1, m.agreement[gameResult{complete: true, rootAgree: true, correct: true}])
+	})
+
+	t.Run("FailToLoadGames", func(t *testing.T) {
+		monitor, source, _, _ := setupMonitorTest(t)
+		source.err = mockErr
+		require.ErrorIs(t, monitor.monitorGames(context.Background()), mockErr)
+	})
+}
+
+func setupMonitorTest(t *testing.T) (*gameMonitor, *stubGameSource, map[common.Address]*stubGameContract, *stubMetrics) {
+	logger := testlog.Logger(t, log.LvlDebug)
+	source := &stubGameSource{}
+	contracts := make(map[common.Address]*stubGameContract)
+	createContract := func(game types.GameMetadata) (GameContract, error) {
+		contract, ok := contracts[game.Proxy]
+		if !ok {
+			return nil, errors.New("unknown game")
+		}
+		return contract, nil
+	}
+	rollupClient := &stubRollupClient{outputs: map[uint64]common.Hash{42: correctRoot}}
+	fetchBlockHash := func(ctx context.Context) (common.Hash, error) {
+		return common.Hash{0x01}, nil
+	}
+	m := &stubMetrics{agreement: make(map[gameResult]int)}
+	monitor := newGameMonitor(logger, clock.NewDeterministicClock(now), m, source, createContract, rollupClient,
+		fetchBlockHash, time.Duration(0), time.Minute)
+	return monitor, source, contracts, m
+}
+
+func addGame(source *stubGameSource, contracts map[common.Address]*stubGameContract, addr common.Address, contract *stubGameContract) {
+	if contract.l2BlockNum == 0 {
+		contract.l2BlockNum = 42
+	}
+	source.games = append(source.games, types.GameMetadata{Proxy: addr})
+	contracts[addr] = contract
+}
+
+func root(value common.Hash) faultTypes.Claim {
+	return rootAt(value, uint64(now.Unix()))
+}
+
+func rootAt(value common.Hash, clock uint64) faultTypes.Claim {
+	claim := rootClaim(clock)
+	claim.Value = value
+	return claim
+}
+
+func child(parentIdx int, clock uint64) faultTypes.Claim {
+	return childClaim(parentIdx, clock)
+}
+
+type stubGameSource struct {
+	games []types.GameMetadata
+	err   error
+}
+
+func (s *stubGameSource) FetchAllGamesAtBlock(_ context.Context, _ uint64, _ common.Hash) ([]types.GameMetadata, error) {
+	return s.games, s.err
+}
+
+type stubGameContract struct {
+	status     types.GameStatus
+	statusErr  error
+	claims     []faultTypes.Claim
+	l2BlockNum uint64
+	duration   uint64
+}
+
+func (s *stubGameContract) GetStatus(_ context.Context) (types.GameStatus, error) {
+	return s.status, s.statusErr
+}
+
+func (s *stubGameContract) GetAllClaims(_ context.Context) ([]faultTypes.Claim, error) {
+	return s.claims, nil
+}
+
+func (s *stubGameContract) GetBlockRange(_ context.Context) (uint64, uint64, error) {
+	return 0, s.l2BlockNum, nil
+}
+
+func (s *stubGameContract) GetGameDuration(_ context.Context) (uint64, error) {
+	return s.duration, nil
+}
+
+type stubRollupClient struct {
+	outputs map[uint64]common.Hash
+}
+
+func (s *stubRollupClient) OutputAtBlock(_ context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	output, ok := s.outputs[blockNum]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &eth.OutputResponse{OutputRoot: eth.Bytes32(output)}, nil
+}
+
+type stubMetrics struct {
+	agreement map[gameResult]int
+	expiring  int
+	errors    int
+}
+
+func (s *stubMetrics) RecordGameAgreement(complete bool, rootAgree bool, correct bool, count int) {
+	s.agreement[gameResult{complete: complete, rootAgree: rootAgree, correct: correct}] = count
+}
+
+func (s *stubMetrics) RecordGamesExpiring(count int) {
+	s.expiring = count
+}
+
+func (s *stubMetrics) RecordGameErrors(count int) {
+	s.errors = count
+}
+
+func (s *stubMetrics) RecordMonitorDuration(_ float64) {}
@@ -0,0 +1,179 @@
+package actions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/interop"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+// emitterAddr is a test contract that emits its calldata as the data of a log, to initiate messages.
+var emitterAddr = common.HexToAddress("0x00000000000000000000000000000000000e1171")
+
+// emitterCode emits a LOG0 with the calldata as data:
+// CALLDATASIZE PUSH1 0 PUSH1 0 CALLDATACOPY CALLDATASIZE PUSH1 0 LOG0 STOP
+var emitterCode = common.FromHex("0x366000600037366000a000")
+
+// crossL2InboxCode stands in for the CrossL2Inbox predeploy. It takes the payload hash and the ABI encoded identifier
+// as calldata, and emits them as an ExecutingMessage event without validating them, like the predeploy:
+// PUSH1 0xa0 PUSH1 0x20 PUSH1 0 CALLDATACOPY PUSH1 0 CALLDATALOAD PUSH32 <event> PUSH1 0xa0 PUSH1 0 LOG2 STOP
+var crossL2InboxCode = append(append(
+	common.FromHex("0x60a060206000376000357f"),
+	interop.ExecutingMessageEventABIHash.Bytes()...),
+	common.FromHex("0x60a06000a200")...)
+
+type interopChain struct {
+	sd        *e2eutils.SetupData
+	dp        *e2eutils.DeployParams
+	miner     *L1Miner
+	engine    *L2Engine
+	sequencer *L2Sequencer
+	batcher   *L2Batcher
+}
+
+func setupInteropChain(t Testing, log log.Logger, chainID uint64) *interopChain {
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	dp.DeployConfig.L2ChainID = chainID
+	interopOffset := hexutil.Uint64(0)
+	dp.DeployConfig.L2GenesisInteropTimeOffset = &interopOffset
+	sd := e2eutils.Setup(t, dp, &e2eutils.AllocParams{
+		PrefundTestUsers: true,
+		L2Alloc: core.GenesisAlloc{
+			emitterAddr:              {Code: emitterCode, Balance: new(big.Int)},
+			interop.CrossL2InboxAddr: {Code: crossL2InboxCode, Balance: new(big.Int)},
+		},
+	})
+	log = log.New("chain", chainID)
+	miner, engine, sequencer := setupSequencerTest(t, sd, log)
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+	}, sequencer.RollupClient(), miner.EthClient(), engine.EthClient(), engine.EngineClient(t, sd.RollupCfg))
+	sequencer.ActL2PipelineFull(t)
+	return &interopChain{sd: sd, dp: dp, miner: miner, engine: engine, sequencer: sequencer, batcher: batcher}
+}
+
+// ActL2BlockWithTx builds an L2 block including a transaction from Alice to the given address with data.
+func (c *interopChain) ActL2BlockWithTx(t Testing, to common.Address, data []byte) *types.Receipt {
+	cl := c.engine.EthClient()
+	n, err := cl.PendingNonceAt(t.Ctx(), c.dp.Addresses.Alice)
+	require.NoError(t, err)
+	tx := types.MustSignNewTx(c.dp.Secrets.Alice, types.LatestSigner(c.sd.L2Cfg.Config), &types.DynamicFeeTx{
+		ChainID:   c.sd.L2Cfg.Config.ChainID,
+		Nonce:     n,
+		GasTipCap: big.NewInt(2 * params.GWei),
+		GasFeeCap: new(big.Int).Add(c.miner.l1Chain.CurrentBlock().BaseFee, big.NewInt(2*params.GWei)),
+		Gas:       100_000,
+		To:        &to,
+		Data:      data,
+	})
+	require.NoError(t, cl.SendTransaction(t.Ctx(), tx))
+	c.sequencer.ActL2StartBlock(t)
+	c.engine.ActL2IncludeTx(c.dp.Addresses.Alice)(t)
+	c.sequencer.ActL2EndBlock(t)
+	receipt, err := cl.TransactionReceipt(t.Ctx(), tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	return receipt
+}
+
+// ActBatchToSafe submits all unsafe blocks to L1 and derives them, so they become safe.
+func (c *interopChain) ActBatchToSafe(t Testing) {
+	c.batcher.ActSubmitAll(t)
+	c.miner.ActL1StartBlock(12)(t)
+	c.miner.ActL1IncludeTx(c.dp.Addresses.Batcher)(t)
+	c.miner.ActL1EndBlock(t)
+	c.sequencer.ActL1HeadSignal(t)
+	c.sequencer.ActL2PipelineFull(t)
+	status := c.sequencer.SyncStatus()
+	require.Equal(t, status.UnsafeL2, status.SafeL2, "all blocks should be safe")
+}
+
+// executingMessageData encodes the calldata of crossL2InboxCode to execute the initiating message log.
+func executingMessageData(payloadHash common.Hash, id interop.Identifier) []byte {
+	data := payloadHash.Bytes()
+	data = append(data, common.LeftPadBytes(id.Origin.Bytes(), 32)...)
+	for _, v := range []uint64{id.BlockNumber, id.LogIndex, id.Timestamp, id.ChainID} {
+		data = append(data, common.LeftPadBytes(new(big.Int).SetUint64(v).Bytes(), 32)...)
+	}
+	return data
+}
+
+// TestInteropExecutingMessages runs two chains in one dependency set, and checks that the cross-safety heads of
+// the chain executing messages only advance past blocks with valid executing messages.
+func TestInteropExecutingMessages(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	log := testlog.Logger(t, log.LvlDebug)
+	chainA := setupInteropChain(t, log, 901)
+	chainB := setupInteropChain(t, log, 902)
+
+	dependencySet := map[uint64]interop.ChainSource{
+		901: chainA.engine.EngineClient(t, chainA.sd.RollupCfg),
+		902: chainB.engine.EngineClient(t, chainB.sd.RollupCfg),
+	}
+	chainB.sequencer.SetSupervisor(interop.NewInProcessSupervisor(log.New("module", "supervisor"), dependencySet))
+
+	// Chain A initiates a message
+	initReceipt := chainA.ActL2BlockWithTx(t, emitterAddr, []byte("hello chain B"))
+	require.Len(t, initReceipt.Logs, 1)
+	initLog := initReceipt.Logs[0]
+	initBlock := chainA.sequencer.L2Unsafe()
+	id := interop.Identifier{
+		Origin:      emitterAddr,
+		BlockNumber: initBlock.Number,
+		LogIndex:    uint64(initLog.Index),
+		Timestamp:   initBlock.Time,
+		ChainID:     901,
+	}
+
+	// Chain B executes the message, in a block no earlier than the initiating block
+	chainB.sequencer.ActBuildL2ToTime(t, initBlock.Time-chainB.sd.RollupCfg.BlockTime)
+	execReceipt := chainB.ActL2BlockWithTx(t, interop.CrossL2InboxAddr, executingMessageData(interop.PayloadHash(initLog), id))
+	require.Len(t, execReceipt.Logs, 1)
+	validBlock := chainB.sequencer.L2Unsafe()
+
+	// The initiating message is unsafe on chain A, so the executing block becomes cross-unsafe but not cross-safe
+	chainB.sequencer.ActL2CrossSafetyUpdate(t)
+	require.Equal(t, validBlock, chainB.sequencer.SyncStatus().CrossUnsafeL2)
+	require.Less(t, chainB.sequencer.SyncStatus().CrossSafeL2.Number, validBlock.Number)
+
+	// Chain B's block is safe, but the initiating message is not yet safe on chain A
+	chainB.ActBatchToSafe(t)
+	chainB.sequencer.ActL2CrossSafetyUpdate(t)
+	require.Equal(t, validBlock, chainB.sequencer.SyncStatus().SafeL2)
+	require.Less(t, chainB.sequencer.SyncStatus().CrossSafeL2.Number, validBlock.Number)
+
+	// Once the initiating message is safe on chain A, the executing block becomes cross-safe
+	chainA.ActBatchToSafe(t)
+	chainB.sequencer.ActL2CrossSafetyUpdate(t)
+	require.Equal(t, validBlock, chainB.sequencer.SyncStatus().CrossSafeL2)
+	require.Equal(t, validBlock, chainB.sequencer.SyncStatus().CrossUnsafeL2)
+
+	// Chain B executes a message with a payload that does not match the initiating message
+	chainB.ActL2BlockWithTx(t, interop.CrossL2InboxAddr, executingMessageData(common.Hash{0xba, 0xd0}, id))
+	invalidBlock := chainB.sequencer.L2Unsafe()
+	// Blocks built on top of the invalid block can't become cross-safe either
+	chainB.sequencer.ActL2StartBlock(t)
+	chainB.sequencer.ActL2EndBlock(t)
+
+	chainB.sequencer.ActL2CrossSafetyUpdate(t)
+	require.Equal(t, validBlock, chainB.sequencer.SyncStatus().CrossUnsafeL2, "invalid block should not be cross-unsafe")
+
+	chainB.ActBatchToSafe(t)
+	chainB.sequencer.ActL2CrossSafetyUpdate(t)
+	status := chainB.sequencer.SyncStatus()
+	require.Greater(t, status.SafeL2.Number, invalidBlock.Number)
+	require.Equal(t, validBlock, status.CrossSafeL2, "invalid block should not be cross-safe")
+	require.Equal(t, validBlock, status.CrossUnsafeL2)
+}
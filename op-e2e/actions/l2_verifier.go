@@ -6,15 +6,18 @@ import (
 	"io"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	gnode "github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum-optimism/optimism/op-node/node"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/interop"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -30,6 +33,7 @@ type L2Verifier struct {
 	eng interface {
 		derive.Engine
 		L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+		FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
 	}
 
 	// L2 rollup
@@ -42,6 +46,9 @@ type L2Verifier struct {
 	l2PipelineIdle bool
 	l2Building     bool
 
+	// Tracks the cross-unsafe and cross-safe heads, nil if the Interop fork is not scheduled.
+	crossSafety *interop.CrossSafetyTracker
+
 	rollupCfg *rollup.Config
 
 	rpc *rpc.Server
@@ -53,6 +60,7 @@ type L2API interface {
 	derive.Engine
 	L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
 	InfoByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, error)
+	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
 	// GetProof returns a proof of the account, it may return a nil result without error if the address was not found.
 	GetProof(ctx context.Context, address common.Address, storage []common.Hash, blockTag string) (*eth.AccountResult, error)
 	OutputV0AtBlock(ctx context.Context, blockHash common.Hash) (*eth.OutputV0, error)
@@ -69,7 +77,8 @@ func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cf
 	metrics := &testutils.TestDerivationMetrics{}
 	engine := derive.NewEngineController(eng, log, metrics, cfg, syncCfg.SyncMode)
 	blobsSrc := &EmptyBlobsSource{}
-	pipeline := derive.NewDerivationPipeline(log, cfg, l1, blobsSrc, eng, engine, metrics, syncCfg)
+	require.False(t, cfg.UsePlasma(), "plasma mode requires a DA server, which the L2 verifier does not have")
+	pipeline := derive.NewDerivationPipeline(log, cfg, l1, blobsSrc, nil, eng, engine, metrics, syncCfg, safedb.Disabled)
 	pipeline.Reset()
 
 	rollupNode := &L2Verifier{
@@ -85,6 +94,10 @@ func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cf
 		rpc:            rpc.NewServer(),
 	}
 	t.Cleanup(rollupNode.rpc.Stop)
+	if cfg.InteropTime != nil {
+		// Without a dependency set, no executing message can be valid
+		rollupNode.SetSupervisor(interop.NewInProcessSupervisor(log, nil))
+	}
 
 	// setup RPC server for rollup node, hooked to the actor as backend
 	m := &testutils.TestRPCMetrics{}
@@ -92,7 +105,7 @@ func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cf
 	apis := []rpc.API{
 		{
 			Namespace:     "optimism",
-			Service:       node.NewNodeAPI(cfg, eng, backend, log, m),
+			Service:       node.NewNodeAPI(cfg, eng, backend, safedb.Disabled, log, m),
 			Public:        true,
 			Authenticated: false,
 		},
@@ -138,6 +151,10 @@ func (s *l2VerifierBackend) SequencerActive(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+func (s *l2VerifierBackend) SetRecoverMode(ctx context.Context, mode bool) error {
+	return nil
+}
+
 func (s *l2VerifierBackend) OnUnsafeL2Payload(ctx context.Context, payload *eth.ExecutionPayload) error {
 	return nil
 }
@@ -158,7 +175,20 @@ func (s *L2Verifier) L2Unsafe() eth.L2BlockRef {
 	return s.engine.UnsafeL2Head()
 }
 
+// SetSupervisor sets the supervisor that checks executing messages against the chains in the dependency set.
+// The cross-safety heads are reset, and only tracked if the Interop fork is scheduled.
+func (s *L2Verifier) SetSupervisor(supervisor interop.Supervisor) {
+	if s.rollupCfg.InteropTime == nil {
+		return
+	}
+	s.crossSafety = interop.NewCrossSafetyTracker(s.log, s.rollupCfg, s.eng, supervisor)
+}
+
 func (s *L2Verifier) SyncStatus() *eth.SyncStatus {
+	crossUnsafe, crossSafe := s.L2Unsafe(), s.L2Safe()
+	if s.crossSafety != nil {
+		crossUnsafe, crossSafe = s.crossSafety.CrossUnsafe(), s.crossSafety.CrossSafe()
+	}
 	return &eth.SyncStatus{
 		CurrentL1:          s.derivation.Origin(),
 		CurrentL1Finalized: s.derivation.FinalizedL1(),
@@ -169,6 +199,8 @@ func (s *L2Verifier) SyncStatus() *eth.SyncStatus {
 		SafeL2:             s.L2Safe(),
 		FinalizedL2:        s.L2Finalized(),
 		PendingSafeL2:      s.L2PendingSafe(),
+		CrossUnsafeL2:      crossUnsafe,
+		CrossSafeL2:        crossSafe,
 	}
 }
 
@@ -254,6 +286,15 @@ func (s *L2Verifier) ActL2PipelineFull(t Testing) {
 	}
 }
 
+// ActL2CrossSafetyUpdate advances the cross-unsafe and cross-safe heads, as the driver does periodically.
+func (s *L2Verifier) ActL2CrossSafetyUpdate(t Testing) {
+	if s.crossSafety == nil {
+		t.InvalidAction("cross-safety is only tracked once the Interop fork is scheduled")
+		return
+	}
+	require.NoError(t, s.crossSafety.Update(t.Ctx(), s.L2Unsafe(), s.L2Safe(), s.L2Finalized()))
+}
+
 // ActL2UnsafeGossipReceive creates an action that can receive an unsafe execution payload, like gossipsub
 func (s *L2Verifier) ActL2UnsafeGossipReceive(payload *eth.ExecutionPayload) Action {
 	return func(t Testing) {
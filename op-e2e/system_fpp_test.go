@@ -274,6 +274,7 @@ func testFaultProofProgramScenario(t *testing.T, ctx context.Context, sys *Syste
 	preimageDir := t.TempDir()
 	fppConfig := oppconf.NewConfig(sys.RollupConfig, sys.L2GenesisCfg.Config, s.L1Head, s.L2Head, s.L2OutputRoot, common.Hash(s.L2Claim), s.L2ClaimBlockNumber)
 	fppConfig.L1URL = sys.NodeEndpoint("l1")
+	fppConfig.L1BeaconURL = sys.L1BeaconAPIAddr
 	fppConfig.L2URL = sys.NodeEndpoint("sequencer")
 	fppConfig.DataDir = preimageDir
 	if s.Detached {
@@ -301,6 +302,7 @@ func testFaultProofProgramScenario(t *testing.T, ctx context.Context, sys *Syste
 	// Should be able to rerun in offline mode using the pre-fetched images
 	fppConfig.L1URL = ""
 	fppConfig.L2URL = ""
+	fppConfig.L1BeaconURL = ""
 	err = opp.FaultProofProgram(ctx, log, fppConfig)
 	require.NoError(t, err)
 
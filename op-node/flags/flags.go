@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
+	plasma "github.com/ethereum-optimism/optimism/op-plasma"
 	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	opflags "github.com/ethereum-optimism/optimism/op-service/flags"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
@@ -148,6 +149,39 @@ var (
 		EnvVars: prefixEnvVars("SEQUENCER_L1_CONFS"),
 		Value:   4,
 	}
+	SequencerForcedTxsFlag = &cli.StringFlag{
+		Name:    "sequencer.forced-txs",
+		Usage:   "Path to a JSON list of hex-encoded transactions to include in the next sequenced blocks, ahead of the tx-pool.",
+		EnvVars: prefixEnvVars("SEQUENCER_FORCED_TXS"),
+	}
+	SequencerGasReservationFlag = &cli.Uint64Flag{
+		Name:    "sequencer.gas-reservation",
+		Usage:   "Gas reserved per block for forced and tx builder transactions. Limited by the block gas limit if 0.",
+		EnvVars: prefixEnvVars("SEQUENCER_GAS_RESERVATION"),
+		Value:   0,
+	}
+	SequencerTxBuilderURLFlag = &cli.StringFlag{
+		Name:    "sequencer.tx-builder-url",
+		Usage:   "HTTP endpoint of an external tx builder supplying transactions that sequenced blocks must include, before the block is built. Disabled if empty.",
+		EnvVars: prefixEnvVars("SEQUENCER_TX_BUILDER_URL"),
+	}
+	SequencerTxBuilderTimeoutFlag = &cli.DurationFlag{
+		Name:    "sequencer.tx-builder-timeout",
+		Usage:   "Time to wait for the tx builder before falling back to building the block from the local tx-pool.",
+		EnvVars: prefixEnvVars("SEQUENCER_TX_BUILDER_TIMEOUT"),
+		Value:   time.Millisecond * 200,
+	}
+	SequencerPayloadBuilderURLFlag = &cli.StringFlag{
+		Name:    "sequencer.payload-builder-url",
+		Usage:   "RPC endpoint of an external builder to request the payloads of sequenced blocks from. Payloads must start with the forced and tx builder transactions, and are validated by the local engine. Disabled if empty.",
+		EnvVars: prefixEnvVars("SEQUENCER_PAYLOAD_BUILDER_URL"),
+	}
+	SequencerPayloadBuilderTimeoutFlag = &cli.DurationFlag{
+		Name:    "sequencer.payload-builder-timeout",
+		Usage:   "Time to wait for the payload builder when sealing a block, before falling back to the payload built by the local engine. The payload is requested when the block building starts.",
+		EnvVars: prefixEnvVars("SEQUENCER_PAYLOAD_BUILDER_TIMEOUT"),
+		Value:   time.Millisecond * 500,
+	}
 	L1EpochPollIntervalFlag = &cli.DurationFlag{
 		Name:    "l1.epoch-poll-interval",
 		Usage:   "Poll interval for retrieving new L1 epoch updates such as safe and finalized block changes. Disabled if 0 or negative.",
@@ -208,6 +242,16 @@ var (
 		Usage:   "Load protocol versions from the superchain L1 ProtocolVersions contract (if available), and report in logs and metrics",
 		EnvVars: prefixEnvVars("ROLLUP_LOAD_PROTOCOL_VERSIONS"),
 	}
+	InteropDependencySet = &cli.StringFlag{
+		Name:    "interop.dependency-set",
+		Usage:   "Path to a JSON file with the chains, and their execution engine RPCs, the L2 chain may execute messages from. Only used once the Interop fork is scheduled.",
+		EnvVars: prefixEnvVars("INTEROP_DEPENDENCY_SET"),
+	}
+	SafeDBPath = &cli.StringFlag{
+		Name:    "safedb.path",
+		Usage:   "File path used to persist safe head update data. Disabled if not set.",
+		EnvVars: prefixEnvVars("SAFEDB_PATH"),
+	}
 	/* Deprecated Flags */
 	L2EngineSyncEnabled = &cli.BoolFlag{
 		Name:    "l2.engine-sync",
@@ -266,6 +310,12 @@ var optionalFlags = []cli.Flag{
 	SequencerStoppedFlag,
 	SequencerMaxSafeLagFlag,
 	SequencerL1Confs,
+	SequencerForcedTxsFlag,
+	SequencerGasReservationFlag,
+	SequencerTxBuilderURLFlag,
+	SequencerTxBuilderTimeoutFlag,
+	SequencerPayloadBuilderURLFlag,
+	SequencerPayloadBuilderTimeoutFlag,
 	L1EpochPollIntervalFlag,
 	RuntimeConfigReloadIntervalFlag,
 	RPCEnableAdmin,
@@ -280,6 +330,8 @@ var optionalFlags = []cli.Flag{
 	RollupHalt,
 	RollupLoadProtocolVersions,
 	L1RethDBPath,
+	InteropDependencySet,
+	SafeDBPath,
 }
 
 var DeprecatedFlags = []cli.Flag{
@@ -299,6 +351,7 @@ func init() {
 	optionalFlags = append(optionalFlags, P2PFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, oplog.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, plasma.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, DeprecatedFlags...)
 	optionalFlags = append(optionalFlags, opflags.CLIFlags(EnvVarPrefix)...)
 	Flags = append(requiredFlags, optionalFlags...)
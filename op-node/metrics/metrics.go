@@ -51,6 +51,9 @@ type Metricer interface {
 	RecordL1ReorgDepth(d uint64)
 	RecordSequencerInconsistentL1Origin(from eth.BlockID, to eth.BlockID)
 	RecordSequencerReset()
+	RecordSequencerRecoverMode(active bool)
+	RecordBuilderPayload(result string, latency time.Duration)
+	RecordForcedTxDropped(reason string)
 	RecordGossipEvent(evType int32)
 	IncPeerCount()
 	DecPeerCount()
@@ -104,6 +107,12 @@ type Metrics struct {
 
 	SequencerInconsistentL1Origin *metrics.Event
 	SequencerResets               *metrics.Event
+	SequencerRecoverMode          prometheus.Gauge
+
+	BuilderPayloads               *prometheus.CounterVec
+	BuilderRequestDurationSeconds prometheus.Histogram
+
+	ForcedTxsDropped metrics.EventVec
 
 	L1RequestDurationSeconds *prometheus.HistogramVec
 
@@ -199,6 +208,25 @@ func NewMetrics(procName string) *Metrics {
 
 		SequencerInconsistentL1Origin: metrics.NewEvent(factory, ns, "", "sequencer_inconsistent_l1_origin", "events when the sequencer selects an inconsistent L1 origin"),
 		SequencerResets:               metrics.NewEvent(factory, ns, "", "sequencer_resets", "sequencer resets"),
+		SequencerRecoverMode: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "sequencer_recover_mode",
+			Help:      "1 if the sequencer is in recover mode, building deposit-only blocks",
+		}),
+
+		ForcedTxsDropped: metrics.NewEventVec(factory, ns, "", "sequencer_forced_txs_dropped", "forced transactions dropped by the sequencer", []string{"reason"}),
+
+		BuilderPayloads: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "sequencer_builder_payloads_total",
+			Help:      "Count of payloads requested from the external block builder, by result: builder if the builder payload was used, or the reason for using the local payload",
+		}, []string{"result"}),
+		BuilderRequestDurationSeconds: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "sequencer_builder_request_duration_seconds",
+			Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5},
+			Help:      "Duration of payload requests to the external block builder",
+		}),
 
 		UnsafePayloadsBufferLen: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace: ns,
@@ -476,6 +504,23 @@ func (m *Metrics) RecordSequencerReset() {
 	m.SequencerResets.Record()
 }
 
+func (m *Metrics) RecordBuilderPayload(result string, latency time.Duration) {
+	m.BuilderPayloads.WithLabelValues(result).Inc()
+	m.BuilderRequestDurationSeconds.Observe(float64(latency) / float64(time.Second))
+}
+
+func (m *Metrics) RecordForcedTxDropped(reason string) {
+	m.ForcedTxsDropped.Record(reason)
+}
+
+func (m *Metrics) RecordSequencerRecoverMode(active bool) {
+	var val float64
+	if active {
+		val = 1
+	}
+	m.SequencerRecoverMode.Set(val)
+}
+
 func (m *Metrics) RecordGossipEvent(evType int32) {
 	m.GossipEventsTotal.WithLabelValues(pb.TraceEvent_Type_name[evType]).Inc()
 }
@@ -670,6 +715,15 @@ func (n *noopMetricer) RecordSequencerInconsistentL1Origin(from eth.BlockID, to
 func (n *noopMetricer) RecordSequencerReset() {
 }
 
+func (n *noopMetricer) RecordSequencerRecoverMode(active bool) {
+}
+
+func (n *noopMetricer) RecordBuilderPayload(result string, latency time.Duration) {
+}
+
+func (n *noopMetricer) RecordForcedTxDropped(reason string) {
+}
+
 func (n *noopMetricer) RecordGossipEvent(evType int32) {
 }
 
@@ -11,7 +11,9 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/interop"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
+	plasma "github.com/ethereum-optimism/optimism/op-plasma"
 	"github.com/ethereum-optimism/optimism/op-service/oppprof"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -64,6 +66,15 @@ type Config struct {
 
 	// [OPTIONAL] The reth DB path to read receipts from
 	RethDBPath string
+
+	// [OPTIONAL] The chains to check executing messages against, once the Interop fork is active.
+	DependencySet *interop.DependencySet
+
+	// [OPTIONAL] Path to the database recording the safe head at each L1 block. Disabled if empty.
+	SafeDBPath string
+
+	// Plasma configures the DA server to fetch the inputs committed to in plasma mode from.
+	Plasma plasma.CLIConfig
 }
 
 type RPCConfig struct {
@@ -133,6 +144,9 @@ func (cfg *Config) Check() error {
 	} else if cfg.Rollup.EcotoneTime != nil {
 		return fmt.Errorf("ecotone upgrade scheduled but no beacon endpoint is configured")
 	}
+	if err := cfg.Driver.Check(); err != nil {
+		return fmt.Errorf("driver config error: %w", err)
+	}
 	if err := cfg.Rollup.Check(); err != nil {
 		return fmt.Errorf("rollup config error: %w", err)
 	}
@@ -150,5 +164,16 @@ func (cfg *Config) Check() error {
 	if !(cfg.RollupHalt == "" || cfg.RollupHalt == "major" || cfg.RollupHalt == "minor" || cfg.RollupHalt == "patch") {
 		return fmt.Errorf("invalid rollup halting option: %q", cfg.RollupHalt)
 	}
+	if cfg.DependencySet != nil {
+		if err := cfg.DependencySet.Check(); err != nil {
+			return fmt.Errorf("interop dependency set error: %w", err)
+		}
+	}
+	if err := cfg.Plasma.Check(); err != nil {
+		return fmt.Errorf("plasma config error: %w", err)
+	}
+	if cfg.Rollup.UsePlasma() && !cfg.Plasma.Enabled() {
+		return errors.New("plasma mode is enabled in the rollup config, but no DA server is configured")
+	}
 	return nil
 }
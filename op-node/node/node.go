@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sync/atomic"
 	"time"
 
@@ -18,8 +19,11 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/heartbeat"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/interop"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
 	"github.com/ethereum-optimism/optimism/op-node/version"
 	"github.com/ethereum-optimism/optimism/op-service/client"
@@ -33,6 +37,12 @@ var (
 	ErrAlreadyClosed = errors.New("node is already closed")
 )
 
+type closableSafeDB interface {
+	derive.SafeHeadListener
+	SafeDBReader
+	io.Closer
+}
+
 type OpNode struct {
 	log        log.Logger
 	appVersion string
@@ -58,6 +68,12 @@ type OpNode struct {
 
 	beacon *sources.L1BeaconClient
 
+	dependencySources []*sources.EthClient // Clients of the chains in the interop dependency set
+
+	payloadBuilder *sources.BuilderClient // External builder of sequenced payloads, nil if disabled
+
+	safeDB closableSafeDB
+
 	// some resources cannot be stopped directly, like the p2p gossipsub router (not our design),
 	// and depend on this ctx to be closed.
 	resourcesCtx   context.Context
@@ -324,13 +340,91 @@ func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger
 		return err
 	}
 
-	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n.beacon, n, n, n.log, snapshotLog, n.metrics, cfg.ConfigPersistence, &cfg.Sync)
+	supervisor, err := n.initSupervisor(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.SafeDBPath != "" {
+		n.log.Info("Safe head database enabled", "path", cfg.SafeDBPath)
+		safeDB, err := safedb.NewSafeDB(n.log, cfg.SafeDBPath)
+		if err != nil {
+			return fmt.Errorf("failed to create safe head database at %v: %w", cfg.SafeDBPath, err)
+		}
+		n.safeDB = safeDB
+	} else {
+		n.safeDB = safedb.Disabled
+	}
+
+	var plasmaInputs derive.PlasmaInputFetcher
+	if cfg.Plasma.Enabled() {
+		plasmaInputs = cfg.Plasma.NewDAClient()
+	}
+
+	var txPolicy driver.TxPolicy
+	if cfg.Driver.SequencerEnabled {
+		txPolicy, err = driver.NewTxPolicy(n.log, &cfg.Driver, n.l2Source, n.metrics)
+		if err != nil {
+			return fmt.Errorf("failed to create sequencer transaction policy: %w", err)
+		}
+	}
+
+	var payloadBuilder derive.PayloadBuilder
+	if cfg.Driver.SequencerEnabled && cfg.Driver.SequencerPayloadBuilderURL != "" {
+		rpcClient, err := client.NewRPC(ctx, n.log, cfg.Driver.SequencerPayloadBuilderURL)
+		if err != nil {
+			return fmt.Errorf("failed to dial payload builder RPC: %w", err)
+		}
+		n.payloadBuilder = sources.NewBuilderClient(rpcClient)
+		payloadBuilder = n.payloadBuilder
+		n.log.Info("Payload builder enabled", "url", cfg.Driver.SequencerPayloadBuilderURL, "timeout", cfg.Driver.SequencerPayloadBuilderTimeout)
+	}
+
+	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n.beacon, plasmaInputs, n, n, n.log, snapshotLog, n.metrics, cfg.ConfigPersistence, txPolicy, payloadBuilder, &cfg.Sync, n.safeDB, supervisor)
 
 	return nil
 }
 
+// initSupervisor creates the supervisor to check executing messages against the chains in the dependency set.
+// No supervisor is created if the Interop fork is not scheduled.
+func (n *OpNode) initSupervisor(ctx context.Context, cfg *Config) (interop.Supervisor, error) {
+	if cfg.Rollup.InteropTime == nil {
+		return nil, nil
+	}
+	chains := make(map[uint64]interop.ChainSource)
+	if cfg.DependencySet == nil {
+		n.log.Warn("Interop fork is scheduled but no dependency set is configured, blocks executing messages will not become cross-safe")
+	} else {
+		for _, chain := range cfg.DependencySet.Chains {
+			rpcClient, err := client.NewRPC(ctx, n.log, chain.RPC, client.WithDialBackoff(10))
+			if err != nil {
+				return nil, fmt.Errorf("failed to dial RPC of chain %d in dependency set: %w", chain.ChainID, err)
+			}
+			src, err := sources.NewEthClient(rpcClient, n.log.New("chain", chain.ChainID), nil, &sources.EthClientConfig{
+				MaxRequestsPerBatch:   20,
+				MaxConcurrentRequests: 10,
+				ReceiptsCacheSize:     100,
+				TransactionsCacheSize: 100,
+				HeadersCacheSize:      100,
+				PayloadsCacheSize:     100,
+				TrustRPC:              false,
+				MustBePostMerge:       true,
+				RPCProviderKind:       sources.RPCKindStandard,
+				MethodResetDuration:   time.Minute,
+			})
+			if err != nil {
+				rpcClient.Close()
+				return nil, fmt.Errorf("failed to create client of chain %d in dependency set: %w", chain.ChainID, err)
+			}
+			n.dependencySources = append(n.dependencySources, src)
+			chains[chain.ChainID] = src
+		}
+	}
+	return interop.NewInProcessSupervisor(n.log.New("module", "supervisor"), chains), nil
+}
+
 func (n *OpNode) initRPCServer(ctx context.Context, cfg *Config) error {
-	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.log, n.appVersion, n.metrics)
+	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.safeDB, n.log, n.appVersion, n.metrics)
 	if err != nil {
 		return err
 	}
@@ -591,6 +685,12 @@ func (n *OpNode) Stop(ctx context.Context) error {
 		}
 	}
 
+	if n.safeDB != nil {
+		if err := n.safeDB.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close safe head db: %w", err))
+		}
+	}
+
 	// Wait for the runtime config loader to be done using the data sources before closing them
 	if n.runtimeConfigReloaderDone != nil {
 		<-n.runtimeConfigReloaderDone
@@ -601,6 +701,16 @@ func (n *OpNode) Stop(ctx context.Context) error {
 		n.l2Source.Close()
 	}
 
+	// close the payload builder RPC client
+	if n.payloadBuilder != nil {
+		n.payloadBuilder.Close()
+	}
+
+	// close the clients of the interop dependency set
+	for _, src := range n.dependencySources {
+		src.Close()
+	}
+
 	// close L1 data source
 	if n.l1Source != nil {
 		n.l1Source.Close()
@@ -0,0 +1,195 @@
+package safedb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidEntry = errors.New("invalid db entry")
+)
+
+const (
+	// keyPrefixSafeByL1BlockNum is the prefix of the keys mapping an L1 block number to the safe head at that L1 block.
+	keyPrefixSafeByL1BlockNum byte = 0
+
+	// safeByL1BlockNumValueSize is the size of the value: L1 block hash, L2 block hash and L2 block number.
+	safeByL1BlockNumValueSize = common.HashLength + common.HashLength + 8
+)
+
+func safeByL1BlockNumKey(l1BlockNum uint64) []byte {
+	key := make([]byte, 9)
+	key[0] = keyPrefixSafeByL1BlockNum
+	binary.BigEndian.PutUint64(key[1:], l1BlockNum)
+	return key
+}
+
+// safeByL1BlockNumUpperBound returns the exclusive upper bound of keys for entries up to and including l1BlockNum.
+func safeByL1BlockNumUpperBound(l1BlockNum uint64) []byte {
+	if l1BlockNum == math.MaxUint64 {
+		return []byte{keyPrefixSafeByL1BlockNum + 1}
+	}
+	return safeByL1BlockNumKey(l1BlockNum + 1)
+}
+
+func safeByL1BlockNumValue(l1 eth.BlockID, l2 eth.BlockID) []byte {
+	val := make([]byte, 0, safeByL1BlockNumValueSize)
+	val = append(val, l1.Hash.Bytes()...)
+	val = append(val, l2.Hash.Bytes()...)
+	val = binary.BigEndian.AppendUint64(val, l2.Number)
+	return val
+}
+
+func decodeSafeByL1BlockNum(key []byte, val []byte) (l1 eth.BlockID, l2 eth.BlockID, err error) {
+	if len(key) != 9 || len(val) != safeByL1BlockNumValueSize || key[0] != keyPrefixSafeByL1BlockNum {
+		err = ErrInvalidEntry
+		return
+	}
+	copy(l1.Hash[:], val[:common.HashLength])
+	l1.Number = binary.BigEndian.Uint64(key[1:])
+	copy(l2.Hash[:], val[common.HashLength:common.HashLength*2])
+	l2.Number = binary.BigEndian.Uint64(val[common.HashLength*2:])
+	return
+}
+
+// SafeDB is a persistent record of the L2 safe head as of each L1 block.
+// An entry is written each time the safe head changes, keyed by the L1 block that was being processed when the
+// safe head was updated. The safe head at any L1 block is the safe head of the latest entry at or before that block.
+type SafeDB struct {
+	// m ensures all read iterators are closed before closing the database by preventing concurrent read and write
+	// operations (with close considered a write).
+	m   sync.RWMutex
+	log log.Logger
+	db  *pebble.DB
+
+	writeOpts *pebble.WriteOptions
+
+	closed bool
+}
+
+func NewSafeDB(logger log.Logger, path string) (*SafeDB, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open safe head db at %v: %w", path, err)
+	}
+	return &SafeDB{
+		log:       logger,
+		db:        db,
+		writeOpts: &pebble.WriteOptions{Sync: true},
+	}, nil
+}
+
+func (d *SafeDB) Enabled() bool {
+	return true
+}
+
+// SafeHeadUpdated records that the safe head was updated to safeHead while processing l1Head.
+func (d *SafeDB) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Head eth.BlockID) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.log.Info("Record safe head", "l2", safeHead.ID(), "l1", l1Head)
+	batch := d.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Set(safeByL1BlockNumKey(l1Head.Number), safeByL1BlockNumValue(l1Head, safeHead.ID()), d.writeOpts); err != nil {
+		return fmt.Errorf("failed to record safe head update: %w", err)
+	}
+	if err := batch.Commit(d.writeOpts); err != nil {
+		return fmt.Errorf("failed to commit safe head update: %w", err)
+	}
+	return nil
+}
+
+// SafeHeadReset removes all entries that recorded a safe head after safeHead, when the derivation pipeline resets.
+// Entries are recorded again as the safe head advances beyond safeHead.
+func (d *SafeDB) SafeHeadReset(safeHead eth.L2BlockRef) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	iter, err := d.db.NewIter(&pebble.IterOptions{
+		LowerBound: safeByL1BlockNumKey(0),
+		UpperBound: safeByL1BlockNumUpperBound(math.MaxUint64),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create safe head iterator: %w", err)
+	}
+	defer iter.Close()
+	var deleteFrom []byte
+	for valid := iter.Last(); valid; valid = iter.Prev() {
+		_, l2, err := decodeSafeByL1BlockNum(iter.Key(), iter.Value())
+		if err != nil {
+			return err
+		}
+		if l2.Number < safeHead.Number || l2 == safeHead.ID() {
+			break
+		}
+		deleteFrom = slices.Clone(iter.Key())
+	}
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("failed to iterate safe heads: %w", err)
+	}
+	if deleteFrom == nil {
+		// Nothing to remove
+		return nil
+	}
+	batch := d.db.NewBatch()
+	defer batch.Close()
+	if err := batch.DeleteRange(deleteFrom, safeByL1BlockNumUpperBound(math.MaxUint64), d.writeOpts); err != nil {
+		return fmt.Errorf("failed to truncate safe head entries: %w", err)
+	}
+	if err := batch.Commit(d.writeOpts); err != nil {
+		return fmt.Errorf("failed to commit safe head truncation: %w", err)
+	}
+	d.log.Info("Truncated safe head db", "safeHead", safeHead.ID())
+	return nil
+}
+
+// SafeHeadAtL1 returns the L2 safe head as of the L1 block number l1BlockNum, along with the L1 block at which
+// it was recorded. Returns ErrNotFound if no safe head was recorded at or before l1BlockNum.
+func (d *SafeDB) SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	d.m.RLock()
+	defer d.m.RUnlock()
+	if d.closed {
+		err = errors.New("safe head db closed")
+		return
+	}
+	iter, err := d.db.NewIterWithContext(ctx, &pebble.IterOptions{
+		LowerBound: safeByL1BlockNumKey(0),
+		UpperBound: safeByL1BlockNumUpperBound(l1BlockNum),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to create safe head iterator: %w", err)
+		return
+	}
+	defer iter.Close()
+	if valid := iter.Last(); !valid {
+		if err = iter.Error(); err == nil {
+			err = ErrNotFound
+		}
+		return
+	}
+	// The value is only valid until the iterator is closed, but is copied when decoded.
+	l1Block, safeHead, err = decodeSafeByL1BlockNum(iter.Key(), iter.Value())
+	return
+}
+
+func (d *SafeDB) Close() error {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	return d.db.Close()
+}
@@ -0,0 +1,121 @@
+package safedb
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestStoreSafeHeads(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	dir := t.TempDir()
+	db, err := NewSafeDB(logger, dir)
+	require.NoError(t, err)
+	defer db.Close()
+	l2a := eth.L2BlockRef{Hash: common.Hash{0x02, 0xaa}, Number: 20}
+	l2b := eth.L2BlockRef{Hash: common.Hash{0x02, 0xbb}, Number: 25}
+	l1a := eth.BlockID{Hash: common.Hash{0x01, 0xaa}, Number: 100}
+	l1b := eth.BlockID{Hash: common.Hash{0x01, 0xbb}, Number: 150}
+	require.NoError(t, db.SafeHeadUpdated(l2a, l1a))
+	require.NoError(t, db.SafeHeadUpdated(l2b, l1b))
+
+	verifySafeHeads := func(db *SafeDB) {
+		_, _, err = db.SafeHeadAtL1(context.Background(), l1a.Number-1)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		actualL1, actualL2, err := db.SafeHeadAtL1(context.Background(), l1a.Number)
+		require.NoError(t, err)
+		require.Equal(t, l1a, actualL1)
+		require.Equal(t, l2a.ID(), actualL2)
+
+		actualL1, actualL2, err = db.SafeHeadAtL1(context.Background(), l1a.Number+1)
+		require.NoError(t, err)
+		require.Equal(t, l1a, actualL1)
+		require.Equal(t, l2a.ID(), actualL2)
+
+		actualL1, actualL2, err = db.SafeHeadAtL1(context.Background(), l1b.Number)
+		require.NoError(t, err)
+		require.Equal(t, l1b, actualL1)
+		require.Equal(t, l2b.ID(), actualL2)
+
+		actualL1, actualL2, err = db.SafeHeadAtL1(context.Background(), math.MaxUint64)
+		require.NoError(t, err)
+		require.Equal(t, l1b, actualL1)
+		require.Equal(t, l2b.ID(), actualL2)
+	}
+	verifySafeHeads(db)
+
+	// Data should persist after the db is reopened
+	require.NoError(t, db.Close())
+	db, err = NewSafeDB(logger, dir)
+	require.NoError(t, err)
+	verifySafeHeads(db)
+}
+
+func TestSafeHeadAtL1Empty(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	db, err := NewSafeDB(logger, t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, _, err = db.SafeHeadAtL1(context.Background(), 100)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.Close())
+	_, _, err = db.SafeHeadAtL1(context.Background(), 100)
+	require.ErrorContains(t, err, "closed")
+}
+
+func TestTruncateOnSafeHeadReset(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	db, err := NewSafeDB(logger, t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	l2a := eth.L2BlockRef{Hash: common.Hash{0x02, 0xaa}, Number: 20}
+	l2b := eth.L2BlockRef{Hash: common.Hash{0x02, 0xbb}, Number: 25}
+	l2c := eth.L2BlockRef{Hash: common.Hash{0x02, 0xcc}, Number: 30}
+	l2d := eth.L2BlockRef{Hash: common.Hash{0x02, 0xdd}, Number: 35}
+	l1a := eth.BlockID{Hash: common.Hash{0x01, 0xaa}, Number: 100}
+	l1b := eth.BlockID{Hash: common.Hash{0x01, 0xbb}, Number: 110}
+	l1c := eth.BlockID{Hash: common.Hash{0x01, 0xcc}, Number: 120}
+	l1d := eth.BlockID{Hash: common.Hash{0x01, 0xdd}, Number: 130}
+	require.NoError(t, db.SafeHeadUpdated(l2a, l1a))
+	require.NoError(t, db.SafeHeadUpdated(l2b, l1b))
+	require.NoError(t, db.SafeHeadUpdated(l2c, l1c))
+	require.NoError(t, db.SafeHeadUpdated(l2d, l1d))
+
+	// Reset to a block between entries removes all entries after it
+	require.NoError(t, db.SafeHeadReset(eth.L2BlockRef{Hash: common.Hash{0x02, 0xee}, Number: 28}))
+
+	actualL1, actualL2, err := db.SafeHeadAtL1(context.Background(), l1d.Number)
+	require.NoError(t, err)
+	require.Equal(t, l1b, actualL1)
+	require.Equal(t, l2b.ID(), actualL2)
+
+	// Reset to a recorded safe head keeps the entry for it
+	require.NoError(t, db.SafeHeadReset(l2b))
+	actualL1, actualL2, err = db.SafeHeadAtL1(context.Background(), l1d.Number)
+	require.NoError(t, err)
+	require.Equal(t, l1b, actualL1)
+	require.Equal(t, l2b.ID(), actualL2)
+
+	// Reset to a different block at the same height removes the entry
+	require.NoError(t, db.SafeHeadReset(eth.L2BlockRef{Hash: common.Hash{0x02, 0xff}, Number: l2b.Number}))
+	actualL1, actualL2, err = db.SafeHeadAtL1(context.Background(), l1d.Number)
+	require.NoError(t, err)
+	require.Equal(t, l1a, actualL1)
+	require.Equal(t, l2a.ID(), actualL2)
+
+	// Reset to before all entries removes everything
+	require.NoError(t, db.SafeHeadReset(eth.L2BlockRef{Hash: common.Hash{0x02, 0x00}, Number: 0}))
+	_, _, err = db.SafeHeadAtL1(context.Background(), l1d.Number)
+	require.ErrorIs(t, err, ErrNotFound)
+}
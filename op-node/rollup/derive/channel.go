@@ -1,11 +1,14 @@
 package derive
 
 import (
+	"bufio"
 	"bytes"
 	"compress/zlib"
 	"fmt"
 	"io"
 
+	"github.com/andybalholm/brotli"
+
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -144,14 +147,36 @@ func (ch *Channel) Reader() io.Reader {
 
 // BatchReader provides a function that iteratively consumes batches from the reader.
 // The L1Inclusion block is also provided at creation time.
+// Brotli compressed channels are only accepted if isFjord is true.
 // Warning: the batch reader can read every batch-type.
 // The caller of the batch-reader should filter the results.
-func BatchReader(r io.Reader) (func() (*BatchData, error), error) {
-	// Setup decompressor stage + RLP reader
-	zr, err := zlib.NewReader(r)
+func BatchReader(r io.Reader, isFjord bool) (func() (*BatchData, error), error) {
+	// Use a buffered reader to peek at the first byte, to determine the compression algorithm
+	bufReader := bufio.NewReader(r)
+	compressionType, err := bufReader.Peek(1)
 	if err != nil {
 		return nil, err
 	}
+
+	// Setup decompressor stage + RLP reader
+	var zr io.Reader
+	if cm := compressionType[0] & 0x0F; cm == zlibCM8 || cm == zlibCM15 {
+		zr, err = zlib.NewReader(bufReader)
+		if err != nil {
+			return nil, err
+		}
+	} else if compressionType[0] == ChannelVersionBrotli {
+		if !isFjord {
+			return nil, fmt.Errorf("cannot accept brotli compressed channel before Fjord")
+		}
+		// Discard the version byte
+		if _, err := bufReader.Discard(1); err != nil {
+			return nil, err
+		}
+		zr = brotli.NewReader(bufReader)
+	} else {
+		return nil, fmt.Errorf("cannot determine compression algorithm from channel version byte %d", compressionType[0])
+	}
 	rlpReader := rlp.NewStream(zr, MaxRLPBytesPerChannel)
 	// Read each batch iteratively
 	return func() (*BatchData, error) {
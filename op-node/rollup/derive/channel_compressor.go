@@ -0,0 +1,198 @@
+package derive
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ChannelVersionBrotli is the version byte prefixed to Brotli compressed channels.
+// Zlib compressed channels are not prefixed: a zlib stream starts with a CMF byte of which
+// the lower 4 bits, the compression method, are always 8 (deflate) or 15 (reserved),
+// so the version byte cannot be mistaken for the start of a zlib stream.
+const ChannelVersionBrotli byte = 0x01
+
+const (
+	zlibCM8  = 8
+	zlibCM15 = 15
+)
+
+// CompressionAlgo is the algorithm used to compress channels.
+type CompressionAlgo string
+
+const (
+	// Zlib is supported by all forks.
+	Zlib CompressionAlgo = "zlib"
+	// Brotli variants are only supported once Fjord is active.
+	// The suffix selects the compression level, Brotli uses level 10.
+	Brotli   CompressionAlgo = "brotli"
+	Brotli9  CompressionAlgo = "brotli-9"
+	Brotli10 CompressionAlgo = "brotli-10"
+	Brotli11 CompressionAlgo = "brotli-11"
+)
+
+var CompressionAlgos = []CompressionAlgo{
+	Zlib,
+	Brotli,
+	Brotli9,
+	Brotli10,
+	Brotli11,
+}
+
+var brotliLevels = map[CompressionAlgo]int{
+	Brotli:   10,
+	Brotli9:  9,
+	Brotli10: 10,
+	Brotli11: 11,
+}
+
+func (algo CompressionAlgo) String() string {
+	return string(algo)
+}
+
+func (algo *CompressionAlgo) Set(value string) error {
+	if !ValidCompressionAlgo(CompressionAlgo(value)) {
+		return fmt.Errorf("unknown compression algo: %q", value)
+	}
+	*algo = CompressionAlgo(value)
+	return nil
+}
+
+func (algo *CompressionAlgo) Clone() any {
+	cpy := *algo
+	return &cpy
+}
+
+// IsBrotli returns true if the algorithm is one of the Brotli variants.
+func (algo CompressionAlgo) IsBrotli() bool {
+	_, ok := brotliLevels[algo]
+	return ok
+}
+
+func ValidCompressionAlgo(value CompressionAlgo) bool {
+	for _, algo := range CompressionAlgos {
+		if algo == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelCompressor compresses channel data with one of the supported compression algorithms,
+// including the channel version prefix if the algorithm requires one.
+type ChannelCompressor interface {
+	// Write compresses p into the buffer.
+	io.Writer
+	// Flush flushes any pending compressed data to the buffer.
+	Flush() error
+	// Close finishes the compressed stream. It must be called before reading the final compressed data.
+	Close() error
+	// Reset discards the buffered data and starts a new compressed stream.
+	Reset()
+	// Len returns the length of the compressed data in the buffer.
+	Len() int
+	// Read reads compressed data from the buffer.
+	io.Reader
+	// GetCompressed returns the buffer holding the compressed data.
+	GetCompressed() *bytes.Buffer
+}
+
+type baseChannelCompressor struct {
+	writer     compressorWriter
+	compressed *bytes.Buffer
+}
+
+type compressorWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+func (bcc *baseChannelCompressor) Write(p []byte) (int, error) {
+	return bcc.writer.Write(p)
+}
+
+func (bcc *baseChannelCompressor) Flush() error {
+	return bcc.writer.Flush()
+}
+
+func (bcc *baseChannelCompressor) Close() error {
+	return bcc.writer.Close()
+}
+
+func (bcc *baseChannelCompressor) Len() int {
+	return bcc.compressed.Len()
+}
+
+func (bcc *baseChannelCompressor) Read(p []byte) (int, error) {
+	return bcc.compressed.Read(p)
+}
+
+func (bcc *baseChannelCompressor) GetCompressed() *bytes.Buffer {
+	return bcc.compressed
+}
+
+type zlibCompressor struct {
+	baseChannelCompressor
+}
+
+func (zc *zlibCompressor) Reset() {
+	zc.compressed.Reset()
+	zc.writer.Reset(zc.compressed)
+}
+
+// brotliCompressor writes the channel version byte lazily, before the first compressed output,
+// so that Len is zero until data has been written to the compressor, like with zlib.
+type brotliCompressor struct {
+	baseChannelCompressor
+	versionWritten bool
+}
+
+func (bc *brotliCompressor) writeVersion() {
+	if !bc.versionWritten {
+		bc.compressed.WriteByte(ChannelVersionBrotli)
+		bc.versionWritten = true
+	}
+}
+
+func (bc *brotliCompressor) Write(p []byte) (int, error) {
+	bc.writeVersion()
+	return bc.writer.Write(p)
+}
+
+func (bc *brotliCompressor) Flush() error {
+	bc.writeVersion()
+	return bc.writer.Flush()
+}
+
+func (bc *brotliCompressor) Close() error {
+	bc.writeVersion()
+	return bc.writer.Close()
+}
+
+func (bc *brotliCompressor) Reset() {
+	bc.compressed.Reset()
+	bc.versionWritten = false
+	bc.writer.Reset(bc.compressed)
+}
+
+// NewChannelCompressor creates a ChannelCompressor for the given algorithm.
+func NewChannelCompressor(algo CompressionAlgo) (ChannelCompressor, error) {
+	compressed := &bytes.Buffer{}
+	if algo == Zlib {
+		writer, err := zlib.NewWriterLevel(compressed, zlib.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		return &zlibCompressor{baseChannelCompressor{writer: writer, compressed: compressed}}, nil
+	}
+	level, ok := brotliLevels[algo]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression algo: %q", algo)
+	}
+	writer := brotli.NewWriterLevel(compressed, level)
+	return &brotliCompressor{baseChannelCompressor: baseChannelCompressor{writer: writer, compressed: compressed}}, nil
+}
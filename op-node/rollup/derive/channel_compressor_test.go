@@ -0,0 +1,67 @@
+package derive
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelCompressor(t *testing.T) {
+	data := bytes.Repeat([]byte("channel data "), 1000)
+	for _, algo := range CompressionAlgos {
+		algo := algo
+		t.Run(algo.String(), func(t *testing.T) {
+			c, err := NewChannelCompressor(algo)
+			require.NoError(t, err)
+			require.Zero(t, c.Len(), "empty compressor should have no output")
+
+			// Reset the compressor after writing, to check the result is unaffected by previous data
+			_, err = c.Write([]byte("discarded"))
+			require.NoError(t, err)
+			require.NoError(t, c.Flush())
+			c.Reset()
+			require.Zero(t, c.Len(), "reset compressor should have no output")
+
+			_, err = c.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, c.Close())
+			require.Less(t, c.Len(), len(data))
+
+			compressed, err := io.ReadAll(c)
+			require.NoError(t, err)
+			require.Zero(t, c.Len())
+
+			var r io.Reader
+			if algo.IsBrotli() {
+				require.Equal(t, ChannelVersionBrotli, compressed[0])
+				r = brotli.NewReader(bytes.NewReader(compressed[1:]))
+			} else {
+				r, err = zlib.NewReader(bytes.NewReader(compressed))
+				require.NoError(t, err)
+			}
+			decompressed, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, data, decompressed)
+		})
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := NewChannelCompressor("gzip")
+		require.ErrorContains(t, err, "unsupported compression algo")
+	})
+}
+
+func TestCompressionAlgo(t *testing.T) {
+	for _, algo := range CompressionAlgos {
+		var set CompressionAlgo
+		require.NoError(t, set.Set(algo.String()))
+		require.Equal(t, algo, set)
+		require.Equal(t, algo != Zlib, algo.IsBrotli())
+	}
+	var set CompressionAlgo
+	require.ErrorContains(t, set.Set("gzip"), "unknown compression algo")
+}
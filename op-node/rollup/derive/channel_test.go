@@ -1,6 +1,10 @@
 package derive
 
 import (
+	"bytes"
+	"io"
+	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -99,3 +103,50 @@ func TestFrameValidity(t *testing.T) {
 		t.Run(tc.name, tc.Run)
 	}
 }
+
+func TestBatchReader(t *testing.T) {
+	rng := rand.New(rand.NewSource(0x543331))
+	singularBatch := RandomSingularBatch(rng, 20, big.NewInt(333))
+	batchDataInput := NewBatchData(singularBatch)
+
+	encodedBatch := &bytes.Buffer{}
+	require.NoError(t, batchDataInput.EncodeRLP(encodedBatch))
+
+	for _, algo := range CompressionAlgos {
+		algo := algo
+		t.Run(algo.String(), func(t *testing.T) {
+			compressor, err := NewChannelCompressor(algo)
+			require.NoError(t, err)
+			_, err = compressor.Write(encodedBatch.Bytes())
+			require.NoError(t, err)
+			require.NoError(t, compressor.Close())
+			compressed := compressor.GetCompressed().Bytes()
+
+			reader, err := BatchReader(bytes.NewReader(compressed), true)
+			require.NoError(t, err)
+			batchData, err := reader()
+			require.NoError(t, err)
+			require.Equal(t, batchDataInput, batchData)
+			_, err = reader()
+			require.ErrorIs(t, err, io.EOF)
+
+			// Brotli compressed channels are not accepted before Fjord
+			_, err = BatchReader(bytes.NewReader(compressed), false)
+			if algo.IsBrotli() {
+				require.ErrorContains(t, err, "before Fjord")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		_, err := BatchReader(bytes.NewReader([]byte{0x02, 0x00}), true)
+		require.ErrorContains(t, err, "cannot determine compression algorithm")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		_, err := BatchReader(bytes.NewReader(nil), true)
+		require.ErrorIs(t, err, io.EOF)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
@@ -42,6 +43,15 @@ type EngineController struct {
 	buildingID   eth.PayloadID
 	buildingSafe bool
 	safeAttrs    *AttributesWithParent
+	// buildingAttrs are the attributes of the current block building job, requested from the external builder.
+	buildingAttrs *eth.PayloadAttributes
+	// builderRequest is the pending request for the payload of the current block building job to the external builder.
+	builderRequest *builderRequest
+
+	// External builder of the blocks built by the sequencer. Disabled if nil.
+	builder        PayloadBuilder
+	builderTimeout time.Duration
+	builderMetrics PayloadBuilderMetrics
 }
 
 func NewEngineController(engine ExecEngine, log log.Logger, metrics Metrics, rollupCfg *rollup.Config, syncMode sync.Mode) *EngineController {
@@ -132,9 +142,17 @@ func (e *EngineController) StartPayload(ctx context.Context, parent eth.L2BlockR
 	e.buildingID = id
 	e.buildingSafe = updateSafe
 	e.buildingOnto = parent
+	e.buildingAttrs = attrs.attributes
 	if updateSafe {
 		e.safeAttrs = attrs
 	}
+	if e.builderRequest != nil {
+		e.builderRequest.cancel()
+		e.builderRequest = nil
+	}
+	if e.builder != nil && !updateSafe {
+		e.builderRequest = requestBuilderPayload(e.builder, parent, attrs.attributes)
+	}
 
 	return BlockInsertOK, nil
 }
@@ -153,7 +171,12 @@ func (e *EngineController) ConfirmPayload(ctx context.Context) (out *eth.Executi
 	}
 	// Update the safe head if the payload is built with the last attributes in the batch.
 	updateSafe := e.buildingSafe && e.safeAttrs != nil && e.safeAttrs.isLastInSpan
-	payload, errTyp, err := confirmPayload(ctx, e.log, e.engine, fc, e.buildingID, updateSafe)
+	var payload *eth.ExecutionPayload
+	if e.builder != nil && !e.buildingSafe {
+		payload, errTyp, err = e.confirmBuilderPayload(ctx, fc)
+	} else {
+		payload, errTyp, err = confirmPayload(ctx, e.log, e.engine, fc, e.buildingID, updateSafe)
+	}
 	if err != nil {
 		return nil, errTyp, fmt.Errorf("failed to complete building on top of L2 chain %s, id: %s, error (%d): %w", e.buildingOnto, e.buildingID, errTyp, err)
 	}
@@ -200,6 +223,11 @@ func (e *EngineController) resetBuildingState() {
 	e.buildingOnto = eth.L2BlockRef{}
 	e.buildingSafe = false
 	e.safeAttrs = nil
+	e.buildingAttrs = nil
+	if e.builderRequest != nil {
+		e.builderRequest.cancel()
+		e.builderRequest = nil
+	}
 }
 
 // Misc Setters only used by the engine queue
@@ -90,6 +90,23 @@ type LocalEngineControl interface {
 	SetPendingSafeL2Head(eth.L2BlockRef)
 }
 
+// SafeHeadListener is called when the safe head is updated.
+// The safe head may advance by more than one block in a single update.
+// The l1Block specified is the L1 block that was being processed when the safe head was updated,
+// i.e. the first L1 block that includes sufficient information to derive the new safe head.
+type SafeHeadListener interface {
+	// Enabled reports if this safe head listener is actively using the posted data. This allows the engine queue to
+	// optionally skip making calls that may be expensive to prepare.
+	Enabled() bool
+
+	// SafeHeadUpdated indicates that the safe head has been updated in response to processing batch data.
+	SafeHeadUpdated(newSafeHead eth.L2BlockRef, l1Block eth.BlockID) error
+
+	// SafeHeadReset indicates that the derivation pipeline reset back to the specified safe head.
+	// The L1 block that made the new safe head safe is unknown.
+	SafeHeadReset(resetSafeHead eth.L2BlockRef) error
+}
+
 // Max memory used for buffering unsafe payloads
 const maxUnsafePayloadsMemory = 500 * 1024 * 1024
 
@@ -108,6 +125,13 @@ const maxUnsafePayloadsMemory = 500 * 1024 * 1024
 // And then we add 1 to make pruning easier by leaving room for a new item without pruning the 32*4.
 const finalityLookback = 4*32 + 1
 
+// calcFinalityLookback returns the amount of L1<>L2 relations to track for finalization purposes.
+// In plasma mode, L2 blocks are only finalized once the challenge and resolve windows of the L1 block they were
+// derived from have passed on the finalized L1 chain, so the relations have to be retained for longer.
+func calcFinalityLookback(cfg *rollup.Config) uint64 {
+	return finalityLookback + cfg.PlasmaFinalityDelay()
+}
+
 // finalityDelay is the number of L1 blocks to traverse before trying to finalize L2 blocks again.
 // We do not want to do this too often, since it requires fetching a L1 block by number, so no cache data.
 const finalityDelay = 64
@@ -139,7 +163,9 @@ type EngineQueue struct {
 	unsafePayloads *PayloadsQueue // queue of unsafe payloads, ordered by ascending block number, may have gaps and duplicates
 
 	// Tracks which L2 blocks where last derived from which L1 block. At most finalityLookback large.
-	finalityData []FinalityData
+	// The lookback is extended by the plasma finality delay in plasma mode.
+	finalityData     []FinalityData
+	finalityLookback uint64
 
 	engine L2Source
 	prev   NextAttributesProvider
@@ -151,21 +177,27 @@ type EngineQueue struct {
 	l1Fetcher L1Fetcher
 
 	syncCfg *sync.Config
+
+	safeHeadNotifs       SafeHeadListener // notified when safe head is updated
+	lastNotifiedSafeHead eth.L2BlockRef
 }
 
 // NewEngineQueue creates a new EngineQueue, which should be Reset(origin) before use.
-func NewEngineQueue(log log.Logger, cfg *rollup.Config, l2Source L2Source, engine LocalEngineControl, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, syncCfg *sync.Config) *EngineQueue {
+func NewEngineQueue(log log.Logger, cfg *rollup.Config, l2Source L2Source, engine LocalEngineControl, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, syncCfg *sync.Config, safeHeadNotifs SafeHeadListener) *EngineQueue {
+	lookback := calcFinalityLookback(cfg)
 	return &EngineQueue{
-		log:            log,
-		cfg:            cfg,
-		ec:             engine,
-		engine:         l2Source,
-		metrics:        metrics,
-		finalityData:   make([]FinalityData, 0, finalityLookback),
-		unsafePayloads: NewPayloadsQueue(maxUnsafePayloadsMemory, payloadMemSize),
-		prev:           prev,
-		l1Fetcher:      l1Fetcher,
-		syncCfg:        syncCfg,
+		log:              log,
+		cfg:              cfg,
+		ec:               engine,
+		engine:           l2Source,
+		metrics:          metrics,
+		finalityData:     make([]FinalityData, 0, lookback),
+		finalityLookback: lookback,
+		unsafePayloads:   NewPayloadsQueue(maxUnsafePayloadsMemory, payloadMemSize),
+		prev:             prev,
+		l1Fetcher:        l1Fetcher,
+		syncCfg:          syncCfg,
+		safeHeadNotifs:   safeHeadNotifs,
 	}
 }
 
@@ -269,7 +301,10 @@ func (eq *EngineQueue) Step(ctx context.Context) error {
 		return err
 	}
 	eq.origin = newOrigin
-	eq.postProcessSafeL2() // make sure we track the last L2 safe head for every new L1 block
+	// make sure we track the last L2 safe head for every new L1 block
+	if err := eq.postProcessSafeL2(); err != nil {
+		return err
+	}
 	// try to finalize the L2 blocks we have synced so far (no-op if L1 finality is behind)
 	if err := eq.tryFinalizePastL2Blocks(ctx); err != nil {
 		return err
@@ -368,7 +403,7 @@ func (eq *EngineQueue) tryFinalizeL2() {
 	finalizedL2 := eq.ec.Finalized()
 	// go through the latest inclusion data, and find the last L2 block that was derived from a finalized L1 block
 	for _, fd := range eq.finalityData {
-		if fd.L2Block.Number > finalizedL2.Number && fd.L1Block.Number <= eq.finalizedL1.Number {
+		if fd.L2Block.Number > finalizedL2.Number && fd.L1Block.Number+eq.cfg.PlasmaFinalityDelay() <= eq.finalizedL1.Number {
 			finalizedL2 = fd.L2Block
 		}
 	}
@@ -377,10 +412,20 @@ func (eq *EngineQueue) tryFinalizeL2() {
 
 // postProcessSafeL2 buffers the L1 block the safe head was fully derived from,
 // to finalize it once the L1 block, or later, finalizes.
-func (eq *EngineQueue) postProcessSafeL2() {
+// It also notifies the safe head listener if the safe head changed.
+func (eq *EngineQueue) postProcessSafeL2() error {
+	if eq.safeHeadNotifs != nil && eq.safeHeadNotifs.Enabled() && eq.lastNotifiedSafeHead != eq.ec.SafeL2Head() {
+		if err := eq.safeHeadNotifs.SafeHeadUpdated(eq.ec.SafeL2Head(), eq.origin.ID()); err != nil {
+			// At this point our state is in a potentially inconsistent state as we've updated the safe head
+			// in the execution client but failed to post process it. Reset the pipeline so the safe head rolls back
+			// a little (it always rolls back at least 1 block) and then it will retry storing the entry
+			return NewResetError(fmt.Errorf("safe head notifications failed: %w", err))
+		}
+		eq.lastNotifiedSafeHead = eq.ec.SafeL2Head()
+	}
 	// prune finality data if necessary
-	if len(eq.finalityData) >= finalityLookback {
-		eq.finalityData = append(eq.finalityData[:0], eq.finalityData[1:finalityLookback]...)
+	if uint64(len(eq.finalityData)) >= eq.finalityLookback {
+		eq.finalityData = append(eq.finalityData[:0], eq.finalityData[1:eq.finalityLookback]...)
 	}
 	// remember the last L2 block that we fully derived from the given finality data
 	if len(eq.finalityData) == 0 || eq.finalityData[len(eq.finalityData)-1].L1Block.Number < eq.origin.Number {
@@ -399,6 +444,7 @@ func (eq *EngineQueue) postProcessSafeL2() {
 			eq.log.Debug("updated finality-data", "last_l1", last.L1Block, "last_l2", last.L2Block)
 		}
 	}
+	return nil
 }
 
 func (eq *EngineQueue) logSyncProgress(reason string) {
@@ -517,7 +563,9 @@ func (eq *EngineQueue) consolidateNextSafeAttributes(ctx context.Context) error
 	eq.ec.SetPendingSafeL2Head(ref)
 	if eq.safeAttributes.isLastInSpan {
 		eq.ec.SetSafeHead(ref)
-		eq.postProcessSafeL2()
+		if err := eq.postProcessSafeL2(); err != nil {
+			return err
+		}
 	}
 	// unsafe head stays the same, we did not reorg the chain.
 	eq.safeAttributes = nil
@@ -578,7 +626,9 @@ func (eq *EngineQueue) forceNextSafeAttributes(ctx context.Context) error {
 	eq.safeAttributes = nil
 	eq.logSyncProgress("processed safe block derived from L1")
 	if lastInSpan {
-		eq.postProcessSafeL2()
+		if err := eq.postProcessSafeL2(); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -646,6 +696,12 @@ func (eq *EngineQueue) Reset(ctx context.Context, _ eth.L1BlockRef, _ eth.System
 	eq.ec.SetSafeHead(safe)
 	eq.ec.SetPendingSafeL2Head(safe)
 	eq.ec.SetFinalizedHead(finalized)
+	if eq.safeHeadNotifs != nil && eq.safeHeadNotifs.Enabled() {
+		if err := eq.safeHeadNotifs.SafeHeadReset(safe); err != nil {
+			return NewTemporaryError(fmt.Errorf("failed to reset safe head db: %w", err))
+		}
+	}
+	eq.lastNotifiedSafeHead = safe
 	eq.safeAttributes = nil
 	eq.ec.ResetBuildingState()
 	eq.finalityData = eq.finalityData[:0]
@@ -15,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -249,7 +250,7 @@ func TestEngineQueue_Finalize(t *testing.T) {
 	prev := &fakeAttributesQueue{}
 
 	ec := NewEngineController(eng, logger, metrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	require.Equal(t, refB1, ec.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -485,7 +486,7 @@ func TestEngineQueue_ResetWhenUnsafeOriginNotCanonical(t *testing.T) {
 	prev := &fakeAttributesQueue{origin: refE}
 
 	ec := NewEngineController(eng, logger, metrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	require.Equal(t, refB1, ec.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -815,7 +816,7 @@ func TestVerifyNewL1Origin(t *testing.T) {
 
 			prev := &fakeAttributesQueue{origin: refE}
 			ec := NewEngineController(eng, logger, metrics, &rollup.Config{}, sync.CLSync)
-			eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{})
+			eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 			require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 			require.Equal(t, refB1, ec.SafeL2Head(), "L2 reset should go back to sequence window ago: blocks with origin E and D are not safe until we reconcile, C is extra, and B1 is the end we look for")
@@ -912,7 +913,7 @@ func TestBlockBuildingRace(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refA, attrs: attrs, islastInSpan: true}
 	ec := NewEngineController(eng, logger, metrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
 
 	id := eth.PayloadID{0xff}
@@ -1082,8 +1083,9 @@ func TestResetLoop(t *testing.T) {
 
 	prev := &fakeAttributesQueue{origin: refA, attrs: attrs, islastInSpan: true}
 
+	safeHeads := &recordingSafeHeadListener{}
 	ec := NewEngineController(eng, logger, metrics.NoopMetrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, prev, l1F, &sync.Config{}, safeHeads)
 	eq.ec.SetUnsafeHead(refA2)
 	eq.ec.SetSafeHead(refA1)
 	eq.ec.SetFinalizedHead(refA0)
@@ -1103,6 +1105,8 @@ func TestResetLoop(t *testing.T) {
 
 	// Perform the reset
 	require.ErrorIs(t, eq.Reset(context.Background(), eth.L1BlockRef{}, eth.SystemConfig{}), io.EOF)
+	require.Equal(t, []eth.L2BlockRef{refA1}, safeHeads.updated, "should record safe head on first step")
+	require.Equal(t, []eth.L2BlockRef{refA0}, safeHeads.reset, "should notify reset to the new safe head")
 
 	// Expect a FCU after the reset
 	postFc := &eth.ForkchoiceState{
@@ -1120,6 +1124,25 @@ func TestResetLoop(t *testing.T) {
 	eng.AssertExpectations(t)
 }
 
+type recordingSafeHeadListener struct {
+	updated []eth.L2BlockRef
+	reset   []eth.L2BlockRef
+}
+
+func (r *recordingSafeHeadListener) Enabled() bool {
+	return true
+}
+
+func (r *recordingSafeHeadListener) SafeHeadUpdated(newSafeHead eth.L2BlockRef, _ eth.BlockID) error {
+	r.updated = append(r.updated, newSafeHead)
+	return nil
+}
+
+func (r *recordingSafeHeadListener) SafeHeadReset(resetSafeHead eth.L2BlockRef) error {
+	r.reset = append(r.reset, resetSafeHead)
+	return nil
+}
+
 func TestEngineQueue_StepPopOlderUnsafe(t *testing.T) {
 	logger := testlog.Logger(t, log.LvlInfo)
 	eng := &testutils.MockEngine{}
@@ -1189,7 +1212,7 @@ func TestEngineQueue_StepPopOlderUnsafe(t *testing.T) {
 	prev := &fakeAttributesQueue{origin: refA}
 
 	ec := NewEngineController(eng, logger, metrics.NoopMetrics, &rollup.Config{}, sync.CLSync)
-	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, prev, l1F, &sync.Config{})
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, prev, l1F, &sync.Config{}, safedb.Disabled)
 	eq.ec.SetUnsafeHead(refA2)
 	eq.ec.SetSafeHead(refA0)
 	eq.ec.SetFinalizedHead(refA0)
@@ -1214,3 +1237,31 @@ func TestEngineQueue_StepPopOlderUnsafe(t *testing.T) {
 	l1F.AssertExpectations(t)
 	eng.AssertExpectations(t)
 }
+
+func TestEngineQueue_PlasmaFinalityDelay(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	eng := &testutils.MockEngine{}
+	l1F := &testutils.MockL1Source{}
+	rng := rand.New(rand.NewSource(1234))
+
+	cfg := &rollup.Config{
+		BlockTime:    1,
+		PlasmaConfig: &rollup.PlasmaConfig{DAChallengeWindow: 10, DAResolveWindow: 5},
+	}
+	ec := NewEngineController(eng, logger, metrics.NoopMetrics, cfg, sync.CLSync)
+	eq := NewEngineQueue(logger, cfg, eng, ec, metrics.NoopMetrics, &fakeAttributesQueue{}, l1F, &sync.Config{}, safedb.Disabled)
+	require.Equal(t, uint64(finalityLookback+15), eq.finalityLookback)
+
+	l1Ref := testutils.RandomBlockRef(rng)
+	l2Ref := testutils.RandomL2BlockRef(rng)
+	eq.finalityData = append(eq.finalityData, FinalityData{L2Block: l2Ref, L1Block: l1Ref.ID()})
+
+	// The L1 block the L2 block was derived from is finalized, but the challenge and resolve windows are not over yet
+	eq.finalizedL1 = eth.L1BlockRef{Number: l1Ref.Number + 14}
+	eq.tryFinalizeL2()
+	require.Equal(t, eth.L2BlockRef{}, ec.Finalized())
+
+	eq.finalizedL1 = eth.L1BlockRef{Number: l1Ref.Number + 15}
+	eq.tryFinalizeL2()
+	require.Equal(t, l2Ref, ec.Finalized())
+}
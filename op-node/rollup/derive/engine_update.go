@@ -123,6 +123,11 @@ func confirmPayload(ctx context.Context, log log.Logger, eng ExecEngine, fc eth.
 		// even if it is an input-error (unknown payload ID), it is temporary, since we will re-attempt the full payload building, not just the retrieval of the payload.
 		return nil, BlockInsertTemporaryErr, fmt.Errorf("failed to get execution payload: %w", err)
 	}
+	return insertPayload(ctx, log, eng, fc, payload, updateSafe)
+}
+
+// insertPayload inserts the payload in the engine, and makes it canonical.
+func insertPayload(ctx context.Context, log log.Logger, eng ExecEngine, fc eth.ForkchoiceState, payload *eth.ExecutionPayload, updateSafe bool) (out *eth.ExecutionPayload, errTyp BlockInsertionErrType, err error) {
 	if err := sanityCheckPayload(payload); err != nil {
 		return nil, BlockInsertPayloadErr, err
 	}
@@ -137,7 +142,12 @@ func confirmPayload(ctx context.Context, log log.Logger, eng ExecEngine, fc eth.
 	if status.Status != eth.ExecutionValid {
 		return nil, BlockInsertTemporaryErr, eth.NewPayloadErr(payload, status)
 	}
+	return makePayloadCanonical(ctx, log, eng, fc, payload, updateSafe)
+}
 
+// makePayloadCanonical updates the forkchoice state to make the payload, which the engine already executed,
+// the canonical head, and also the safe head if updateSafe is set.
+func makePayloadCanonical(ctx context.Context, log log.Logger, eng ExecEngine, fc eth.ForkchoiceState, payload *eth.ExecutionPayload, updateSafe bool) (out *eth.ExecutionPayload, errTyp BlockInsertionErrType, err error) {
 	fc.HeadBlockHash = payload.BlockHash
 	if updateSafe {
 		fc.SafeBlockHash = payload.BlockHash
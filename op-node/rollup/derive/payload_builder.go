@@ -0,0 +1,201 @@
+package derive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// Results of requesting a payload from the external builder, as recorded in the builder metrics.
+const (
+	BuilderPayloadWon     = "builder"
+	BuilderPayloadTimeout = "timeout"
+	BuilderPayloadError   = "error"
+	BuilderPayloadInvalid = "invalid"
+)
+
+// PayloadBuilder is an external block builder, building payloads for the sequencer.
+// The attributes include any transactions added by the sequencer's transaction inclusion policy,
+// which the payload must start with.
+type PayloadBuilder interface {
+	// GetPayload returns a payload built on top of the given parent with the given attributes.
+	GetPayload(ctx context.Context, parent common.Hash, attrs *eth.PayloadAttributes) (*eth.ExecutionPayload, error)
+}
+
+type PayloadBuilderMetrics interface {
+	RecordBuilderPayload(result string, latency time.Duration)
+}
+
+// SetPayloadBuilder makes the engine controller request the payloads of blocks built by the sequencer
+// from the given external builder. The payload is requested when the local engine starts building the block,
+// and collected when the block is sealed. The payload built by the local engine is used instead
+// if the builder does not return a valid payload within the timeout after sealing starts.
+func (e *EngineController) SetPayloadBuilder(builder PayloadBuilder, timeout time.Duration, metrics PayloadBuilderMetrics) {
+	e.builder = builder
+	e.builderTimeout = timeout
+	e.builderMetrics = metrics
+}
+
+// builderRequest is a request for a payload to the external builder, running in the background
+// while the local engine builds the same block.
+type builderRequest struct {
+	start  time.Time
+	cancel context.CancelFunc
+	// done is closed once the request completed, after which payload and err are set.
+	done    chan struct{}
+	payload *eth.ExecutionPayload
+	err     error
+}
+
+// requestBuilderPayload requests the payload for the block built on top of parent with the given attributes.
+// The request runs until it completes, or until it is cancelled.
+func requestBuilderPayload(builder PayloadBuilder, parent eth.L2BlockRef, attrs *eth.PayloadAttributes) *builderRequest {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := &builderRequest{
+		start:  time.Now(),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(req.done)
+		req.payload, req.err = builder.GetPayload(ctx, parent.Hash, attrs)
+	}()
+	return req
+}
+
+// errBuilderTimeout is the error of a builder request that did not complete within the timeout.
+var errBuilderTimeout = errors.New("timed out waiting for block builder payload")
+
+// confirmBuilderPayload ends the local payload building process, and persists either the payload of the
+// external builder or the local payload as the canonical head.
+func (e *EngineController) confirmBuilderPayload(ctx context.Context, fc eth.ForkchoiceState) (out *eth.ExecutionPayload, errTyp BlockInsertionErrType, err error) {
+	sealStart := time.Now()
+	payload, err := e.engine.GetPayload(ctx, e.buildingID)
+	if err != nil {
+		// even if it is an input-error (unknown payload ID), it is temporary, since we will re-attempt the full payload building, not just the retrieval of the payload.
+		return nil, BlockInsertTemporaryErr, fmt.Errorf("failed to get execution payload: %w", err)
+	}
+	if builderPayload := e.getBuilderPayload(ctx, sealStart); builderPayload != nil {
+		e.log.Info("using block builder payload", "local", payload.ID(), "builder", builderPayload.ID(),
+			"local_txs", len(payload.Transactions), "builder_txs", len(builderPayload.Transactions))
+		// The builder payload was already executed when it was validated, so only has to be made canonical.
+		return makePayloadCanonical(ctx, e.log, e.engine, fc, builderPayload, false)
+	}
+	return insertPayload(ctx, e.log, e.engine, fc, payload, false)
+}
+
+// getBuilderPayload waits for the pending request to the external builder for the payload of the
+// current block building job, until the timeout after sealStart, and returns the payload if it is valid.
+// The returned payload has been executed by the engine, but is not canonical yet.
+// It returns nil if the local payload should be used instead.
+func (e *EngineController) getBuilderPayload(ctx context.Context, sealStart time.Time) *eth.ExecutionPayload {
+	req := e.builderRequest
+	if req == nil {
+		// The block building job started before the builder was set
+		return nil
+	}
+	var payload *eth.ExecutionPayload
+	var err error
+	select {
+	case <-req.done:
+		// Completed while the local payload was retrieved, even if the timeout has passed since
+		payload, err = req.payload, req.err
+	default:
+		timer := time.NewTimer(time.Until(sealStart.Add(e.builderTimeout)))
+		defer timer.Stop()
+		select {
+		case <-req.done:
+			payload, err = req.payload, req.err
+		case <-timer.C:
+			err = errBuilderTimeout
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	latency := time.Since(req.start)
+	if err != nil {
+		req.cancel()
+		result := BuilderPayloadError
+		if errors.Is(err, errBuilderTimeout) {
+			result = BuilderPayloadTimeout
+		}
+		e.builderMetrics.RecordBuilderPayload(result, latency)
+		e.log.Warn("failed to get block builder payload, using local payload", "onto", e.buildingOnto, "latency", latency, "err", err)
+		return nil
+	}
+	if err := checkBuilderPayload(e.buildingOnto, e.buildingAttrs, payload); err != nil {
+		e.builderMetrics.RecordBuilderPayload(BuilderPayloadInvalid, latency)
+		e.log.Warn("block builder payload does not match attributes, using local payload", "onto", e.buildingOnto, "err", err)
+		return nil
+	}
+	if err := sanityCheckPayload(payload); err != nil {
+		e.builderMetrics.RecordBuilderPayload(BuilderPayloadInvalid, latency)
+		e.log.Warn("block builder payload is malformed, using local payload", "payload", payload.ID(), "err", err)
+		return nil
+	}
+	// Execute the payload, so an invalid payload never replaces the local payload.
+	// The payload is only made canonical after it has been chosen.
+	status, err := e.engine.NewPayload(ctx, payload)
+	if err != nil {
+		e.builderMetrics.RecordBuilderPayload(BuilderPayloadError, latency)
+		e.log.Warn("failed to validate block builder payload, using local payload", "payload", payload.ID(), "err", err)
+		return nil
+	}
+	if status.Status != eth.ExecutionValid {
+		e.builderMetrics.RecordBuilderPayload(BuilderPayloadInvalid, latency)
+		e.log.Warn("block builder payload is invalid, using local payload", "payload", payload.ID(), "err", eth.NewPayloadErr(payload, status))
+		return nil
+	}
+	e.builderMetrics.RecordBuilderPayload(BuilderPayloadWon, latency)
+	return payload
+}
+
+// checkBuilderPayload checks that the payload is built on top of parent with the given attributes,
+// and that it does not include any deposits besides those of the attributes.
+func checkBuilderPayload(parent eth.L2BlockRef, attrs *eth.PayloadAttributes, payload *eth.ExecutionPayload) error {
+	if payload.ParentHash != parent.Hash || uint64(payload.BlockNumber) != parent.Number+1 {
+		return fmt.Errorf("payload %s does not build on top of parent %s", payload.ID(), parent)
+	}
+	if payload.Timestamp != attrs.Timestamp {
+		return fmt.Errorf("payload timestamp %d does not match attributes timestamp %d", payload.Timestamp, attrs.Timestamp)
+	}
+	if payload.PrevRandao != attrs.PrevRandao {
+		return fmt.Errorf("payload prevRandao %s does not match attributes prevRandao %s", payload.PrevRandao, attrs.PrevRandao)
+	}
+	if payload.FeeRecipient != attrs.SuggestedFeeRecipient {
+		return fmt.Errorf("payload fee recipient %s does not match attributes fee recipient %s", payload.FeeRecipient, attrs.SuggestedFeeRecipient)
+	}
+	if attrs.GasLimit != nil && payload.GasLimit != *attrs.GasLimit {
+		return fmt.Errorf("payload gas limit %d does not match attributes gas limit %d", payload.GasLimit, *attrs.GasLimit)
+	}
+	if (attrs.Withdrawals == nil) != (payload.Withdrawals == nil) {
+		return errors.New("payload withdrawals do not match attributes withdrawals")
+	}
+	if len(payload.Transactions) < len(attrs.Transactions) {
+		return fmt.Errorf("payload has %d transactions, but attributes force %d transactions", len(payload.Transactions), len(attrs.Transactions))
+	}
+	for i, tx := range attrs.Transactions {
+		if !bytes.Equal(payload.Transactions[i], tx) {
+			return fmt.Errorf("payload transaction %d does not match forced transaction", i)
+		}
+	}
+	if attrs.NoTxPool && len(payload.Transactions) != len(attrs.Transactions) {
+		return fmt.Errorf("payload has %d transactions, but attributes only allow %d", len(payload.Transactions), len(attrs.Transactions))
+	}
+	for i := len(attrs.Transactions); i < len(payload.Transactions); i++ {
+		deposit, err := isDepositTx(payload.Transactions[i])
+		if err != nil {
+			return fmt.Errorf("failed to decode transaction %d: %w", i, err)
+		}
+		if deposit {
+			return fmt.Errorf("payload transaction %d is a deposit not included in the attributes", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+type fakePayloadBuilder struct {
+	payload *eth.ExecutionPayload
+	err     error
+	delay   time.Duration
+	// cancelled receives the context error if the request is cancelled. Ignored if nil.
+	cancelled chan error
+}
+
+func (b *fakePayloadBuilder) GetPayload(ctx context.Context, parent common.Hash, attrs *eth.PayloadAttributes) (*eth.ExecutionPayload, error) {
+	select {
+	case <-ctx.Done():
+		if b.cancelled != nil {
+			b.cancelled <- ctx.Err()
+		}
+		return nil, ctx.Err()
+	case <-time.After(b.delay):
+		return b.payload, b.err
+	}
+}
+
+type recordingBuilderMetrics struct {
+	results []string
+}
+
+func (m *recordingBuilderMetrics) RecordBuilderPayload(result string, latency time.Duration) {
+	m.results = append(m.results, result)
+}
+
+func TestEngineControllerPayloadBuilder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	cfg := &rollup.Config{BlockTime: 2}
+	parent := eth.L2BlockRef{Hash: common.Hash{0xaa}, Number: 10, Time: 1000}
+
+	l1InfoTx, err := L1InfoDepositBytes(cfg, eth.SystemConfig{}, 1, testutils.RandomBlockInfo(rng), parent.Time+2)
+	require.NoError(t, err)
+	gasLimit := eth.Uint64Quantity(30_000_000)
+	attrs := &eth.PayloadAttributes{
+		Timestamp:             eth.Uint64Quantity(parent.Time + 2),
+		PrevRandao:            eth.Bytes32{0x01},
+		SuggestedFeeRecipient: common.Address{0x02},
+		Transactions:          []eth.Data{l1InfoTx},
+		GasLimit:              &gasLimit,
+	}
+	makePayload := func(hash common.Hash, txs ...eth.Data) *eth.ExecutionPayload {
+		return &eth.ExecutionPayload{
+			ParentHash:   parent.Hash,
+			FeeRecipient: attrs.SuggestedFeeRecipient,
+			PrevRandao:   attrs.PrevRandao,
+			BlockNumber:  eth.Uint64Quantity(parent.Number + 1),
+			GasLimit:     gasLimit,
+			Timestamp:    attrs.Timestamp,
+			BlockHash:    hash,
+			Transactions: append([]eth.Data{l1InfoTx}, txs...),
+		}
+	}
+	localPayload := makePayload(common.Hash{0x03}, eth.Data{0x02, 0x01})
+	builderPayload := makePayload(common.Hash{0x04}, eth.Data{0x02, 0x02}, eth.Data{0x02, 0x03})
+	wrongParentPayload := makePayload(common.Hash{0x05})
+	wrongParentPayload.ParentHash = common.Hash{0xbb}
+
+	tests := []struct {
+		name    string
+		builder *fakePayloadBuilder
+		// buildTime is the time between starting and sealing the block.
+		buildTime time.Duration
+		// sealTime is the time the local engine takes to return the local payload.
+		sealTime time.Duration
+		// ctxTimeout is the timeout of the context used to seal the block, no timeout if zero.
+		ctxTimeout time.Duration
+		// builderStatus is the status the engine returns when validating the builder payload, if validated.
+		builderStatus eth.ExecutePayloadStatus
+		expected      *eth.ExecutionPayload
+		result        string
+	}{
+		{name: "BuilderWins", builder: &fakePayloadBuilder{payload: builderPayload}, builderStatus: eth.ExecutionValid, expected: builderPayload, result: BuilderPayloadWon},
+		{name: "Timeout", builder: &fakePayloadBuilder{payload: builderPayload, delay: time.Second}, expected: localPayload, result: BuilderPayloadTimeout},
+		// The payload is requested when the block building starts, so the builder may take longer than the timeout
+		{name: "RequestedAtStart", builder: &fakePayloadBuilder{payload: builderPayload, delay: 150 * time.Millisecond}, buildTime: 100 * time.Millisecond, builderStatus: eth.ExecutionValid, expected: builderPayload, result: BuilderPayloadWon},
+		// The timeout starts when sealing starts, not after the local payload is retrieved
+		{name: "SlowLocalPayload", builder: &fakePayloadBuilder{payload: builderPayload, delay: 200 * time.Millisecond}, sealTime: 150 * time.Millisecond, expected: localPayload, result: BuilderPayloadTimeout},
+		// A payload returned while the local payload is retrieved is used, even if that took longer than the timeout
+		{name: "DoneWhileSealing", builder: &fakePayloadBuilder{payload: builderPayload, delay: 50 * time.Millisecond}, sealTime: 150 * time.Millisecond, builderStatus: eth.ExecutionValid, expected: builderPayload, result: BuilderPayloadWon},
+		// Expiry of the sealing context is an error, not a builder timeout
+		{name: "ContextExpired", builder: &fakePayloadBuilder{payload: builderPayload, delay: time.Second}, ctxTimeout: 50 * time.Millisecond, expected: localPayload, result: BuilderPayloadError},
+		{name: "Error", builder: &fakePayloadBuilder{err: errors.New("builder down")}, expected: localPayload, result: BuilderPayloadError},
+		{name: "MismatchedAttributes", builder: &fakePayloadBuilder{payload: wrongParentPayload}, expected: localPayload, result: BuilderPayloadInvalid},
+		{name: "InvalidPayload", builder: &fakePayloadBuilder{payload: builderPayload}, builderStatus: eth.ExecutionInvalid, expected: localPayload, result: BuilderPayloadInvalid},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			eng := &testutils.MockEngine{}
+			m := &recordingBuilderMetrics{}
+			ec := NewEngineController(eng, testlog.Logger(t, log.LvlError), metrics.NoopMetrics, cfg, sync.CLSync)
+			ec.SetUnsafeHead(parent)
+			ec.SetPayloadBuilder(test.builder, 100*time.Millisecond, m)
+
+			id := eth.PayloadID{0x01}
+			eng.ExpectForkchoiceUpdate(&eth.ForkchoiceState{HeadBlockHash: parent.Hash}, attrs,
+				&eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: eth.ExecutionValid}, PayloadID: &id}, nil)
+			_, err := ec.StartPayload(context.Background(), parent, NewAttributesWithParent(attrs, parent, false), false)
+			require.NoError(t, err)
+			time.Sleep(test.buildTime)
+
+			eng.Mock.On("GetPayload", id).Once().After(test.sealTime).Return(localPayload, nil)
+			if test.builderStatus != "" {
+				eng.ExpectNewPayload(builderPayload, &eth.PayloadStatusV1{Status: test.builderStatus}, nil)
+			}
+			// The builder payload is executed once, when it is validated
+			if test.expected != builderPayload {
+				eng.ExpectNewPayload(test.expected, &eth.PayloadStatusV1{Status: eth.ExecutionValid}, nil)
+			}
+			eng.ExpectForkchoiceUpdate(&eth.ForkchoiceState{HeadBlockHash: test.expected.BlockHash}, nil,
+				&eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: eth.ExecutionValid}}, nil)
+			ctx := context.Background()
+			if test.ctxTimeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.ctxTimeout)
+				defer cancel()
+			}
+			payload, _, err := ec.ConfirmPayload(ctx)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, payload)
+			require.Equal(t, test.expected.BlockHash, ec.UnsafeL2Head().Hash)
+			require.Equal(t, []string{test.result}, m.results)
+			eng.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEngineControllerPayloadBuilderCancel(t *testing.T) {
+	cfg := &rollup.Config{BlockTime: 2}
+	parent := eth.L2BlockRef{Hash: common.Hash{0xaa}, Number: 10, Time: 1000}
+	attrs := &eth.PayloadAttributes{Timestamp: eth.Uint64Quantity(parent.Time + 2)}
+
+	eng := &testutils.MockEngine{}
+	ec := NewEngineController(eng, testlog.Logger(t, log.LvlError), metrics.NoopMetrics, cfg, sync.CLSync)
+	ec.SetUnsafeHead(parent)
+	builder := &fakePayloadBuilder{delay: time.Hour, cancelled: make(chan error, 1)}
+	ec.SetPayloadBuilder(builder, 100*time.Millisecond, &recordingBuilderMetrics{})
+
+	id := eth.PayloadID{0x01}
+	eng.ExpectForkchoiceUpdate(&eth.ForkchoiceState{HeadBlockHash: parent.Hash}, attrs,
+		&eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: eth.ExecutionValid}, PayloadID: &id}, nil)
+	_, err := ec.StartPayload(context.Background(), parent, NewAttributesWithParent(attrs, parent, false), false)
+	require.NoError(t, err)
+
+	// cancelling the block building job cancels the pending builder request
+	eng.ExpectGetPayload(id, &eth.ExecutionPayload{}, nil)
+	require.NoError(t, ec.CancelPayload(context.Background(), false))
+	select {
+	case err := <-builder.cancelled:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("builder request was not cancelled")
+	}
+	eng.AssertExpectations(t)
+}
+
+func TestCheckBuilderPayload(t *testing.T) {
+	parent := eth.L2BlockRef{Hash: common.Hash{0xaa}, Number: 10, Time: 1000}
+	deposit := eth.Data{0x7e, 0x01}
+	attrs := &eth.PayloadAttributes{Timestamp: 1002, Transactions: []eth.Data{deposit}}
+	valid := func() *eth.ExecutionPayload {
+		return &eth.ExecutionPayload{
+			ParentHash:   parent.Hash,
+			BlockNumber:  11,
+			Timestamp:    1002,
+			Transactions: []eth.Data{deposit, {0x02, 0x01}},
+		}
+	}
+	require.NoError(t, checkBuilderPayload(parent, attrs, valid()))
+
+	missingDeposit := valid()
+	missingDeposit.Transactions = []eth.Data{{0x02, 0x01}}
+	require.ErrorContains(t, checkBuilderPayload(parent, attrs, missingDeposit), "forced transaction")
+
+	extraDeposit := valid()
+	extraDeposit.Transactions = append(extraDeposit.Transactions, eth.Data{0x7e, 0x02})
+	require.ErrorContains(t, checkBuilderPayload(parent, attrs, extraDeposit), "deposit")
+
+	wrongTime := valid()
+	wrongTime.Timestamp = 1004
+	require.ErrorContains(t, checkBuilderPayload(parent, attrs, wrongTime), "timestamp")
+
+	noTxPool := *attrs
+	noTxPool.NoTxPool = true
+	require.ErrorContains(t, checkBuilderPayload(parent, &noTxPool, valid()), "only allow")
+}
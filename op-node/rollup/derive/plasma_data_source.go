@@ -0,0 +1,133 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	plasma "github.com/ethereum-optimism/optimism/op-plasma"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// PlasmaInputFetcher fetches the inputs that batcher transactions commit to in plasma mode.
+type PlasmaInputFetcher interface {
+	// GetInput returns the input committed to, or plasma.ErrNotFound if it is not available.
+	GetInput(ctx context.Context, comm plasma.Keccak256Commitment) ([]byte, error)
+}
+
+// plasmaCommitment is a commitment, and the L1 block that included it.
+type plasmaCommitment struct {
+	comm plasma.Keccak256Commitment
+	ref  eth.L1BlockRef
+}
+
+// plasmaPending holds the commitments of which the input was not available yet when their L1 block was derived from.
+// They are retried when deriving from the next L1 block, until their input is available or the window expires.
+// It is shared by the data sources of subsequent L1 blocks.
+type plasmaPending struct {
+	commitments []plasmaCommitment
+}
+
+// open returns the pending commitments to retry when deriving from the given L1 block.
+// Commitments included at or after the block are discarded, as the pipeline is deriving from that block again after a reset.
+func (p *plasmaPending) open(ref eth.L1BlockRef) []plasmaCommitment {
+	var retry []plasmaCommitment
+	for _, c := range p.commitments {
+		if c.ref.Number < ref.Number {
+			retry = append(retry, c)
+		}
+	}
+	p.commitments = nil
+	return retry
+}
+
+// PlasmaDataSource wraps the data source of a L1 block in plasma mode.
+// Data prefixed with plasma.TxDataVersion1 is a commitment, which is replaced with the input it commits to.
+// Any other data is returned as-is, so batches may still be submitted directly to L1.
+//
+// Inputs that are not available yet are retried when deriving from the next L1 blocks, before the data of these blocks.
+// Commitments are skipped if their input does not match, or if their input is still not available when deriving from
+// the L1 block that is more than the challenge and resolve windows past the L1 block that included the commitment.
+// Expiry only depends on the L1 block derived from, not on the L1 head, so all nodes agree on it.
+type PlasmaDataSource struct {
+	log     log.Logger
+	src     DataIter
+	fetcher PlasmaInputFetcher
+	// ref is the L1 block derived from.
+	ref eth.L1BlockRef
+	// window is the number of L1 blocks after the inclusion of a commitment during which its input may become available.
+	window uint64
+	// pending receives the commitments of which the input is not available yet.
+	pending *plasmaPending
+	// retry are the pending commitments of previous L1 blocks, retried before the data of ref.
+	retry []plasmaCommitment
+	// comm is the commitment of which the input is being fetched, retained to retry after temporary errors.
+	comm *plasmaCommitment
+}
+
+func NewPlasmaDataSource(log log.Logger, src DataIter, fetcher PlasmaInputFetcher, ref eth.L1BlockRef, window uint64, pending *plasmaPending) *PlasmaDataSource {
+	return &PlasmaDataSource{
+		log:     log,
+		src:     src,
+		fetcher: fetcher,
+		ref:     ref,
+		window:  window,
+		pending: pending,
+		retry:   pending.open(ref),
+	}
+}
+
+func (s *PlasmaDataSource) Next(ctx context.Context) (eth.Data, error) {
+	if s.comm == nil {
+		if len(s.retry) > 0 {
+			s.comm = &s.retry[0]
+			s.retry = s.retry[1:]
+		} else {
+			data, err := s.src.Next(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if len(data) == 0 || data[0] != plasma.TxDataVersion1 {
+				return data, nil
+			}
+			comm, err := plasma.DecodeTxData(data)
+			if err != nil {
+				s.log.Warn("Skipping invalid commitment", "err", err)
+				return nil, NotEnoughData
+			}
+			s.comm = &plasmaCommitment{comm: comm, ref: s.ref}
+		}
+	}
+	comm := s.comm.comm
+	input, err := s.fetcher.GetInput(ctx, comm)
+	if errors.Is(err, plasma.ErrNotFound) {
+		if s.ref.Number > s.comm.ref.Number+s.window {
+			s.log.Warn("Skipping commitment with input not available within the challenge and resolve windows",
+				"commitment", comm, "l1_inclusion", s.comm.ref, "l1_origin", s.ref)
+		} else {
+			// The input may still become available within the challenge and resolve windows,
+			// so it is retried when deriving from the next L1 block.
+			s.log.Info("Input of commitment not available yet", "commitment", comm, "l1_inclusion", s.comm.ref, "l1_origin", s.ref)
+			s.pending.commitments = append(s.pending.commitments, *s.comm)
+		}
+		s.comm = nil
+		return nil, NotEnoughData
+	} else if err != nil && !errors.Is(err, plasma.ErrCommitmentMismatch) && !errors.Is(err, plasma.ErrInputTooLarge) {
+		return nil, NewTemporaryError(fmt.Errorf("failed to fetch input for commitment %s: %w", comm, err))
+	}
+	// Never derive from an input that does not match the commitment, regardless of the DA server behavior.
+	// A mismatching or oversized input is invalid data, and is skipped like an invalid commitment.
+	if err == nil {
+		err = comm.Verify(input)
+	}
+	if err != nil {
+		s.log.Warn("Skipping commitment with invalid input", "commitment", comm, "err", err)
+		s.comm = nil
+		return nil, NotEnoughData
+	}
+	s.log.Debug("Fetched input for commitment", "commitment", comm, "size", len(input), "l1_inclusion", s.comm.ref)
+	s.comm = nil
+	return input, nil
+}
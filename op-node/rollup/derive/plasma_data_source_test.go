@@ -0,0 +1,250 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	plasma "github.com/ethereum-optimism/optimism/op-plasma"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+type fakePlasmaInputs map[plasma.Keccak256Commitment][]byte
+
+func (f fakePlasmaInputs) GetInput(_ context.Context, comm plasma.Keccak256Commitment) ([]byte, error) {
+	input, ok := f[comm]
+	if !ok {
+		return nil, plasma.ErrNotFound
+	}
+	return input, nil
+}
+
+func TestPlasmaDataSource(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+
+	frames := eth.Data{DerivationVersion0, 0x01, 0x02}
+	input := []byte{DerivationVersion0, 0xaa, 0xbb}
+	comm := plasma.Keccak256(input)
+	missing := []byte{DerivationVersion0, 0xcc}
+	missingComm := plasma.Keccak256(missing)
+	corruptComm := plasma.Keccak256([]byte{DerivationVersion0, 0xdd})
+
+	inputs := fakePlasmaInputs{
+		comm:        input,
+		corruptComm: {DerivationVersion0, 0xee},
+	}
+	ref := eth.L1BlockRef{Number: 100}
+	pending := &plasmaPending{}
+	src := NewPlasmaDataSource(logger, &fakeDataIter{
+		data: []eth.Data{
+			frames,
+			comm.TxData(),
+			{plasma.TxDataVersion1, 0x01},
+			missingComm.TxData(),
+			corruptComm.TxData(),
+			nil,
+		},
+		errs: []error{nil, nil, nil, nil, nil, io.EOF},
+	}, inputs, ref, 10, pending)
+
+	// Frames submitted directly to L1 are passed through
+	data, err := src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, frames, data)
+
+	// Commitments are replaced with the input they commit to
+	data, err = src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(input), data)
+
+	// Invalid commitments are skipped
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, NotEnoughData)
+
+	// Missing inputs are deferred to the next L1 block
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, NotEnoughData)
+
+	// Inputs not matching the commitment are never returned, and skipped
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, NotEnoughData)
+
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, io.EOF)
+
+	// Missing inputs are retried first when deriving from the next L1 blocks, within the challenge and resolve windows
+	src = NewPlasmaDataSource(logger, &fakeDataIter{data: []eth.Data{nil}, errs: []error{io.EOF}}, inputs, eth.L1BlockRef{Number: ref.Number + 1}, 10, pending)
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, NotEnoughData)
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, io.EOF)
+
+	inputs[missingComm] = missing
+	src = NewPlasmaDataSource(logger, &fakeDataIter{data: []eth.Data{frames, nil}, errs: []error{nil, io.EOF}}, inputs, eth.L1BlockRef{Number: ref.Number + 10}, 10, pending)
+	data, err = src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(missing), data)
+	data, err = src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, frames, data)
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, io.EOF)
+	require.Empty(t, pending.commitments)
+}
+
+func TestPlasmaDataSourceSkipExpired(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+
+	missingComm := plasma.Keccak256([]byte{DerivationVersion0, 0xcc})
+	ref := eth.L1BlockRef{Number: 100}
+	pending := &plasmaPending{}
+	src := NewPlasmaDataSource(logger, &fakeDataIter{
+		data: []eth.Data{missingComm.TxData(), nil},
+		errs: []error{nil, io.EOF},
+	}, fakePlasmaInputs{}, ref, 10, pending)
+	_, err := src.Next(ctx)
+	require.ErrorIs(t, err, NotEnoughData)
+	require.Len(t, pending.commitments, 1)
+
+	// The input is still not available when deriving from the L1 block past the challenge and resolve windows
+	src = NewPlasmaDataSource(logger, &fakeDataIter{data: []eth.Data{nil}, errs: []error{io.EOF}}, fakePlasmaInputs{}, eth.L1BlockRef{Number: ref.Number + 11}, 10, pending)
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, NotEnoughData)
+
+	// The commitment is skipped, not retried
+	require.Empty(t, pending.commitments)
+	_, err = src.Next(ctx)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestPlasmaDataSourceReset(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+
+	pending := &plasmaPending{}
+	for _, n := range []uint64{100, 101} {
+		comm := plasma.Keccak256([]byte{DerivationVersion0, byte(n)})
+		src := NewPlasmaDataSource(logger, &fakeDataIter{data: []eth.Data{comm.TxData()}, errs: []error{nil}}, fakePlasmaInputs{}, eth.L1BlockRef{Number: n}, 10, pending)
+		_, err := src.Next(ctx)
+		require.ErrorIs(t, err, NotEnoughData)
+	}
+
+	// Deriving from an earlier L1 block again discards the commitments it will find again
+	src := NewPlasmaDataSource(logger, &fakeDataIter{}, fakePlasmaInputs{}, eth.L1BlockRef{Number: 101}, 10, pending)
+	require.Len(t, src.retry, 1)
+	require.Equal(t, uint64(100), src.retry[0].ref.Number)
+}
+
+// TestPlasmaDataSourceIndependentOfL1Head derives from the same L1 blocks with two data source factories that only
+// differ in the L1 head they see, and checks that they derive the same data, and expire the same commitments.
+func TestPlasmaDataSourceIndependentOfL1Head(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+	rng := rand.New(rand.NewSource(1234))
+
+	batcherPriv := testutils.RandomKey()
+	cfg := &rollup.Config{
+		L1ChainID:         big.NewInt(100),
+		BatchInboxAddress: testutils.RandomAddress(rng),
+		PlasmaConfig:      &rollup.PlasmaConfig{DAChallengeWindow: 4, DAResolveWindow: 4},
+	}
+	batcherAddr := crypto.PubkeyToAddress(batcherPriv.PublicKey)
+	signer := cfg.L1Signer()
+
+	available := []byte{DerivationVersion0, 0xaa}
+	missing := []byte{DerivationVersion0, 0xbb}
+	inputs := fakePlasmaInputs{plasma.Keccak256(available): available}
+	commitTx := func(input []byte) *types.Transaction {
+		tx, err := types.SignNewTx(batcherPriv, signer, &types.DynamicFeeTx{
+			ChainID: signer.ChainID(),
+			Gas:     100_000,
+			To:      &cfg.BatchInboxAddress,
+			Data:    plasma.Keccak256(input).TxData(),
+		})
+		require.NoError(t, err)
+		return tx
+	}
+
+	first := testutils.RandomBlockRef(rng)
+	refs := make([]eth.L1BlockRef, cfg.PlasmaFinalityDelay()+2)
+	for i := range refs {
+		refs[i] = first
+		refs[i].Number += uint64(i)
+		refs[i].Hash = testutils.RandomHash(rng)
+	}
+	derive := func(head eth.L1BlockRef) (out []eth.Data) {
+		l1 := &testutils.MockL1Source{}
+		l1.Mock.On("L1BlockRefByLabel", eth.Unsafe).Maybe().Return(head, nil)
+		for i, ref := range refs {
+			var txs types.Transactions
+			if i == 0 {
+				txs = types.Transactions{commitTx(missing), commitTx(available)}
+			}
+			l1.ExpectInfoAndTxsByHash(ref.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+		}
+		factory := NewDataSourceFactory(logger, cfg, l1, nil, inputs)
+		for _, ref := range refs {
+			src, err := factory.OpenData(ctx, ref, batcherAddr)
+			require.NoError(t, err)
+			for {
+				data, err := src.Next(ctx)
+				if errors.Is(err, io.EOF) {
+					break
+				} else if errors.Is(err, NotEnoughData) {
+					out = append(out, nil)
+					continue
+				}
+				require.NoError(t, err)
+				out = append(out, data)
+			}
+		}
+		require.Empty(t, factory.plasmaPending.commitments, "missing input should have expired")
+		return out
+	}
+
+	// One node sees the L1 head at the first block, the other sees the L1 head far past the windows
+	atFirst := derive(refs[0])
+	pastWindows := derive(refs[len(refs)-1])
+	require.Equal(t, atFirst, pastWindows)
+	require.Contains(t, atFirst, eth.Data(available))
+}
+
+type mismatchPlasmaInputs struct{}
+
+func (mismatchPlasmaInputs) GetInput(_ context.Context, _ plasma.Keccak256Commitment) ([]byte, error) {
+	return nil, plasma.ErrCommitmentMismatch
+}
+
+func TestPlasmaDataSourceSkipVerifiedMismatch(t *testing.T) {
+	ctx := context.Background()
+	logger := testlog.Logger(t, log.LvlDebug)
+
+	frames := eth.Data{DerivationVersion0, 0x01, 0x02}
+	comm := plasma.Keccak256([]byte{DerivationVersion0, 0xcc})
+	ref := eth.L1BlockRef{Number: 100}
+	src := NewPlasmaDataSource(logger, &fakeDataIter{
+		data: []eth.Data{comm.TxData(), frames},
+		errs: []error{nil, nil},
+	}, mismatchPlasmaInputs{}, ref, 10, &plasmaPending{})
+
+	// A DA client that verifies inputs reports the mismatch, which is skipped like an unverified mismatch
+	_, err := src.Next(ctx)
+	require.ErrorIs(t, err, NotEnoughData)
+
+	data, err := src.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, frames, data)
+}
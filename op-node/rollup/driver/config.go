@@ -1,5 +1,12 @@
 package driver
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
 type Config struct {
 	// VerifierConfDepth is the distance to keep from the L1 head when reading L1 data for L2 derivation.
 	VerifierConfDepth uint64 `json:"verifier_conf_depth"`
@@ -20,4 +27,60 @@ type Config struct {
 	// SequencerMaxSafeLag is the maximum number of L2 blocks for restricting the distance between L2 safe and unsafe.
 	// Disabled if 0.
 	SequencerMaxSafeLag uint64 `json:"sequencer_max_safe_lag"`
+
+	// SequencerForcedTxsPath is the path to a JSON list of transactions to include in the next sequenced blocks.
+	// Transactions with used nonces, or that repeatedly fail to be included, are dropped.
+	// Disabled if empty.
+	SequencerForcedTxsPath string `json:"sequencer_forced_txs_path"`
+
+	// SequencerGasReservation is the gas reserved per block for forced and tx builder transactions.
+	// Limited by the block gas limit if 0.
+	SequencerGasReservation uint64 `json:"sequencer_gas_reservation"`
+
+	// SequencerTxBuilderURL is the HTTP endpoint of an external tx builder, supplying transactions that are
+	// added to the payload attributes of sequenced blocks, after any forced transactions. Disabled if empty.
+	SequencerTxBuilderURL string `json:"sequencer_tx_builder_url"`
+
+	// SequencerTxBuilderTimeout is the time to wait for the tx builder,
+	// before falling back to building the block from the local tx-pool.
+	SequencerTxBuilderTimeout time.Duration `json:"sequencer_tx_builder_timeout"`
+
+	// SequencerPayloadBuilderURL is the RPC endpoint of an external builder of the payloads of sequenced blocks.
+	// Disabled if empty.
+	//
+	// The payload builder is requested to build on top of the payload attributes, after the forced and tx builder
+	// transactions have been added to them. Its payload must start with all the transactions of the attributes,
+	// so both builders can be used together: the tx builder decides the transactions every block must include,
+	// and the payload builder may only add to them.
+	SequencerPayloadBuilderURL string `json:"sequencer_payload_builder_url"`
+
+	// SequencerPayloadBuilderTimeout is the time to wait for the payload builder when sealing the block,
+	// before falling back to the payload built by the local engine. The payload is requested from the builder
+	// when the block building starts, so the builder has the full block building time plus this timeout.
+	SequencerPayloadBuilderTimeout time.Duration `json:"sequencer_payload_builder_timeout"`
+}
+
+// TxPolicyEnabled returns true if the sequencer includes transactions besides those of the tx-pool.
+func (c *Config) TxPolicyEnabled() bool {
+	return c.SequencerForcedTxsPath != "" || c.SequencerTxBuilderURL != ""
+}
+
+func (c *Config) Check() error {
+	if c.SequencerTxBuilderURL != "" {
+		if _, err := url.ParseRequestURI(c.SequencerTxBuilderURL); err != nil {
+			return fmt.Errorf("invalid tx builder url %q: %w", c.SequencerTxBuilderURL, err)
+		}
+		if c.SequencerTxBuilderTimeout <= 0 {
+			return errors.New("tx builder timeout must be positive")
+		}
+	}
+	if c.SequencerPayloadBuilderURL != "" {
+		if _, err := url.ParseRequestURI(c.SequencerPayloadBuilderURL); err != nil {
+			return fmt.Errorf("invalid payload builder url %q: %w", c.SequencerPayloadBuilderURL, err)
+		}
+		if c.SequencerPayloadBuilderTimeout <= 0 {
+			return errors.New("payload builder timeout must be positive")
+		}
+	}
+	return nil
 }
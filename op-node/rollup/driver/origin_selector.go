@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/log"
@@ -23,6 +24,10 @@ type L1OriginSelector struct {
 	cfg *rollup.Config
 
 	l1 L1Blocks
+
+	// recoverMode makes the selector return an error if the next origin can't be fetched,
+	// rather than repeating the current origin.
+	recoverMode atomic.Bool
 }
 
 func NewL1OriginSelector(log log.Logger, cfg *rollup.Config, l1 L1Blocks) *L1OriginSelector {
@@ -33,9 +38,17 @@ func NewL1OriginSelector(log log.Logger, cfg *rollup.Config, l1 L1Blocks) *L1Ori
 	}
 }
 
+// SetRecoverMode enables or disables recover mode. In recover mode the sequencer builds the blocks derivation
+// will produce, which adopt the next L1 origin as soon as the L2 time reaches it, so the current origin is
+// never repeated because the next origin could not be fetched.
+func (los *L1OriginSelector) SetRecoverMode(mode bool) {
+	los.recoverMode.Store(mode)
+}
+
 // FindL1Origin determines what the next L1 Origin should be.
 // The L1 Origin is either the L2 Head's Origin, or the following L1 block
 // if the next L2 block's time is greater than or equal to the L2 Head's Origin.
+// In recover mode, an error is returned if the following L1 block can't be fetched.
 func (los *L1OriginSelector) FindL1Origin(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error) {
 	// Grab a reference to the current L1 origin block. This call is by hash and thus easily cached.
 	currentOrigin, err := los.l1.L1BlockRefByHash(ctx, l2Head.L1Origin.Hash)
@@ -60,6 +73,9 @@ func (los *L1OriginSelector) FindL1Origin(ctx context.Context, l2Head eth.L2Bloc
 		if pastSeqDrift {
 			return eth.L1BlockRef{}, fmt.Errorf("cannot build next L2 block past current L1 origin %s by more than sequencer time drift, and failed to find next L1 origin: %w", currentOrigin, err)
 		}
+		if los.recoverMode.Load() {
+			return eth.L1BlockRef{}, fmt.Errorf("cannot repeat current L1 origin %s in recover mode, failed to find next L1 origin: %w", currentOrigin, err)
+		}
 		if errors.Is(err, ethereum.NotFound) {
 			log.Debug("No next L1 block found, repeating current origin")
 		} else {
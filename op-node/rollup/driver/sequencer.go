@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -22,11 +23,13 @@ type Downloader interface {
 
 type L1OriginSelectorIface interface {
 	FindL1Origin(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error)
+	SetRecoverMode(mode bool)
 }
 
 type SequencerMetrics interface {
 	RecordSequencerInconsistentL1Origin(from eth.BlockID, to eth.BlockID)
 	RecordSequencerReset()
+	RecordSequencerRecoverMode(active bool)
 }
 
 // Sequencer implements the sequencing interface of the driver: it starts and completes block building jobs.
@@ -39,20 +42,22 @@ type Sequencer struct {
 	attrBuilder      derive.AttributesBuilder
 	l1OriginSelector L1OriginSelectorIface
 
+	// txPolicy contributes transactions to new blocks. Disabled if nil.
+	txPolicy TxPolicy
+
 	metrics SequencerMetrics
 
 	// timeNow enables sequencer testing to mock the time
 	timeNow func() time.Time
 
 	nextAction time.Time
-}
 
-type RewardDistributedEvent struct {
-    Recipient common.Address
-    Amount    *big.Int
+	// recoverMode makes the sequencer build deposit-only blocks,
+	// to catch up with the blocks derivation will produce after the sequencing window has passed.
+	recoverMode atomic.Bool
 }
 
-func NewSequencer(log log.Logger, rollupCfg *rollup.Config, engine derive.EngineControl, attributesBuilder derive.AttributesBuilder, l1OriginSelector L1OriginSelectorIface, metrics SequencerMetrics) *Sequencer {
+func NewSequencer(log log.Logger, rollupCfg *rollup.Config, engine derive.EngineControl, attributesBuilder derive.AttributesBuilder, l1OriginSelector L1OriginSelectorIface, txPolicy TxPolicy, metrics SequencerMetrics) *Sequencer {
 	return &Sequencer{
 		log:              log,
 		rollupCfg:        rollupCfg,
@@ -60,61 +65,12 @@ func NewSequencer(log log.Logger, rollupCfg *rollup.Config, engine derive.Engine
 		timeNow:          time.Now,
 		attrBuilder:      attributesBuilder,
 		l1OriginSelector: l1OriginSelector,
+		txPolicy:         txPolicy,
 		metrics:          metrics,
 	}
 
 }
 
-// Listen for RewardDistributed events emitted by OptimismPortal.sol
-func (d *Sequencer) ListenForRewardDistributedEvents(ctx context.Context, ethClient *eth.Client) error {
-    // Subscribe to RewardDistributed events emitted by OptimismPortal.sol
-    rewardDistributedCh := make(chan *RewardDistributedEvent)
-    subscription, err := ethClient.SubscribeToRewardDistributedEvents(ctx, rewardDistributedCh)
-    if err != nil {
-        return err
-    }
-    defer subscription.Unsubscribe()
-
-    // Process incoming RewardDistributed events
-    for {
-        select {
-        case event := <-rewardDistributedCh:
-            // Include event data in the block being built
-            d.IncludeRewardDistributedEventInBlock(event)
-        case <-ctx.Done():
-            return nil
-        }
-    }
-}
-
-// AddTransactionToBlock adds a transaction to the block being built
-func (engine *Engine) AddTransactionToBlock(tx *types.Transaction) {
-    engine.pendingTransactions = append(engine.pendingTransactions, tx)
-}
-
-
-// Include RewardDistributed event data in the block being built
-func (d *Sequencer) IncludeRewardDistributedEventInBlock(event *RewardDistributedEvent) {
-    // Create a new transaction with the event data
-    tx := types.NewTransaction(
-        nonce, // Nonce for the transaction
-        event.Recipient, // Recipient address
-        event.Amount, // Amount of reward
-        gasLimit, // Gas limit
-        gasPrice, // Gas price
-        nil, // Data (can be empty)
-    )
-
-    // Add the transaction to the block being built
-    d.engine.AddTransactionToBlock(tx)
-
-    // Log that the RewardDistributed event has been included in the block
-    d.log.Info("RewardDistributed event included in the block", "recipient", event.Recipient, "amount", event.Amount)
-}
-
-
-
-
 // StartBuildingBlock initiates a block building job on top of the given L2 head, safe and finalized blocks, and using the provided l1Origin.
 func (d *Sequencer) StartBuildingBlock(ctx context.Context) error {
 	l2Head := d.engine.UnsafeL2Head()
@@ -147,27 +103,71 @@ func (d *Sequencer) StartBuildingBlock(ctx context.Context) error {
 	// from the transaction pool.
 	attrs.NoTxPool = uint64(attrs.Timestamp) > l1Origin.Time+d.rollupCfg.MaxSequencerDrift
 
+	// In recover mode only deposits are included, matching the blocks derivation
+	// produces when no batches are submitted within the sequencing window.
+	recoverMode := d.recoverMode.Load()
+	if recoverMode {
+		attrs.NoTxPool = true
+	}
+
+	if d.txPolicy != nil && !attrs.NoTxPool {
+		if err := d.txPolicy.ApplyTxPolicy(fetchCtx, l2Head, attrs); err != nil {
+			return derive.NewTemporaryError(fmt.Errorf("failed to apply transaction policy: %w", err))
+		}
+	}
+
 	d.log.Debug("prepared attributes for new block",
 		"num", l2Head.Number+1, "time", uint64(attrs.Timestamp),
-		"origin", l1Origin, "origin_time", l1Origin.Time, "noTxPool", attrs.NoTxPool)
+		"origin", l1Origin, "origin_time", l1Origin.Time, "noTxPool", attrs.NoTxPool, "recoverMode", recoverMode, "txs", len(attrs.Transactions))
 
 	// Start a payload building process.
 	withParent := derive.NewAttributesWithParent(attrs, l2Head, false)
 	errTyp, err := d.engine.StartPayload(ctx, l2Head, withParent, false)
 	if err != nil {
+		if d.txPolicy != nil && !attrs.NoTxPool {
+			d.txPolicy.FailTxPolicy(err)
+		}
 		return fmt.Errorf("failed to start building on top of L2 chain %s, error (%d): %w", l2Head, errTyp, err)
 	}
 	return nil
 }
 
+// SetRecoverMode enables or disables recover mode.
+// In recover mode the sequencer builds deposit-only blocks, so the unsafe chain matches what derivation will produce.
+// The L1 origin selector then adopts the next L1 origin as soon as possible, and block building is retried
+// rather than repeating the current L1 origin if the next one can't be fetched.
+func (d *Sequencer) SetRecoverMode(mode bool) {
+	if d.recoverMode.Swap(mode) == mode {
+		return
+	}
+	d.l1OriginSelector.SetRecoverMode(mode)
+	if mode {
+		d.log.Warn("Sequencer recover mode enabled, building deposit-only blocks")
+	} else {
+		d.log.Info("Sequencer recover mode disabled")
+	}
+	d.metrics.RecordSequencerRecoverMode(mode)
+}
+
+// RecoverMode returns true if the sequencer is in recover mode.
+func (d *Sequencer) RecoverMode() bool {
+	return d.recoverMode.Load()
+}
+
 // CompleteBuildingBlock takes the current block that is being built, and asks the engine to complete the building, seal the block, and persist it as canonical.
 // Warning: the safe and finalized L2 blocks as viewed during the initiation of the block building are reused for completion of the block building.
 // The Execution engine should not change the safe and finalized blocks between start and completion of block building.
 func (d *Sequencer) CompleteBuildingBlock(ctx context.Context) (*eth.ExecutionPayload, error) {
 	payload, errTyp, err := d.engine.ConfirmPayload(ctx)
 	if err != nil {
+		if d.txPolicy != nil {
+			d.txPolicy.FailTxPolicy(err)
+		}
 		return nil, fmt.Errorf("failed to complete building block: error (%d): %w", errTyp, err)
 	}
+	if d.txPolicy != nil {
+		d.txPolicy.ConfirmTxPolicy(payload)
+	}
 	return payload, nil
 }
 
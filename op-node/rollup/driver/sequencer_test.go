@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
@@ -141,6 +142,8 @@ func (fn testOriginSelectorFn) FindL1Origin(ctx context.Context, l2Head eth.L2Bl
 	return fn(ctx, l2Head)
 }
 
+func (fn testOriginSelectorFn) SetRecoverMode(mode bool) {}
+
 var _ L1OriginSelectorIface = (testOriginSelectorFn)(nil)
 
 // TestSequencerChaosMonkey runs the sequencer in a mocked adversarial environment with
@@ -298,7 +301,7 @@ func TestSequencerChaosMonkey(t *testing.T) {
 		}
 	})
 
-	seq := NewSequencer(log, cfg, engControl, attrBuilder, originSelector, metrics.NoopMetrics)
+	seq := NewSequencer(log, cfg, engControl, attrBuilder, originSelector, nil, metrics.NoopMetrics)
 	seq.timeNow = clockFn
 
 	// try to build 1000 blocks, with 5x as many planning attempts, to handle errors and clock problems
@@ -376,3 +379,204 @@ func TestSequencerChaosMonkey(t *testing.T) {
 	require.Greater(t, engControl.avgBuildingTime(), time.Second, "With 2 second block time and 1 second error backoff and healthy-on-average errors, building time should at least be a second")
 	require.Greater(t, engControl.avgTxsPerBlock(), 3.0, "We expect at least 1 system tx per block, but with a mocked 0-10 txs we expect an higher avg")
 }
+
+func TestSequencerRecoverMode(t *testing.T) {
+	cfg := &rollup.Config{
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+	}
+	l1Origin := eth.L1BlockRef{Hash: common.Hash{0x01}, Number: 100, Time: 1000}
+	head := eth.L2BlockRef{Hash: common.Hash{0x02}, Number: 200, Time: 1000, L1Origin: l1Origin.ID()}
+	engControl := &FakeEngineControl{
+		unsafe:  head,
+		cfg:     cfg,
+		timeNow: time.Now,
+	}
+	attrBuilder := testAttrBuilderFn(func(ctx context.Context, l2Parent eth.L2BlockRef, epoch eth.BlockID) (*eth.PayloadAttributes, error) {
+		return &eth.PayloadAttributes{Timestamp: eth.Uint64Quantity(l2Parent.Time + cfg.BlockTime)}, nil
+	})
+	originSelector := testOriginSelectorFn(func(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error) {
+		return l1Origin, nil
+	})
+	seq := NewSequencer(testlog.Logger(t, log.LvlError), cfg, engControl, attrBuilder, originSelector, nil, metrics.NoopMetrics)
+
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.False(t, engControl.buildingAttrs.NoTxPool, "txs are included within the sequencer drift")
+
+	seq.SetRecoverMode(true)
+	require.True(t, seq.RecoverMode())
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.True(t, engControl.buildingAttrs.NoTxPool, "only deposits are included in recover mode")
+
+	seq.SetRecoverMode(false)
+	require.False(t, seq.RecoverMode())
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.False(t, engControl.buildingAttrs.NoTxPool)
+}
+
+func TestSequencerRecoverModeOrigin(t *testing.T) {
+	cfg := &rollup.Config{
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+	}
+	a := eth.L1BlockRef{Hash: common.Hash{0xa}, Number: 100, Time: 1000}
+	b := eth.L1BlockRef{Hash: common.Hash{0xb}, Number: 101, Time: 1012, ParentHash: a.Hash}
+	head := eth.L2BlockRef{Hash: common.Hash{0x02}, Number: 200, Time: 1008, L1Origin: a.ID()}
+	engControl := &FakeEngineControl{
+		unsafe:  head,
+		cfg:     cfg,
+		timeNow: time.Now,
+	}
+	var epoch eth.BlockID
+	attrBuilder := testAttrBuilderFn(func(ctx context.Context, l2Parent eth.L2BlockRef, e eth.BlockID) (*eth.PayloadAttributes, error) {
+		epoch = e
+		return &eth.PayloadAttributes{Timestamp: eth.Uint64Quantity(l2Parent.Time + cfg.BlockTime)}, nil
+	})
+	l1 := &testutils.MockL1Source{}
+	defer l1.AssertExpectations(t)
+	logger := testlog.Logger(t, log.LvlError)
+	seq := NewSequencer(logger, cfg, engControl, attrBuilder, NewL1OriginSelector(logger, cfg, l1), nil, metrics.NoopMetrics)
+	seq.SetRecoverMode(true)
+
+	// the current origin is kept while the next L2 block is before the next origin
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByNumber(b.Number, b, nil)
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.Equal(t, a.ID(), epoch)
+
+	// the next origin is adopted as soon as the next L2 block time reaches it
+	engControl.unsafe.Time = b.Time - cfg.BlockTime
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByNumber(b.Number, b, nil)
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.Equal(t, b.ID(), epoch)
+
+	// the current origin is never repeated because the next origin can't be fetched, building is retried instead
+	epoch = eth.BlockID{}
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByNumber(b.Number, eth.L1BlockRef{}, errors.New("fetch failed"))
+	require.Error(t, seq.StartBuildingBlock(context.Background()))
+	require.Equal(t, eth.BlockID{}, epoch)
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByNumber(b.Number, eth.L1BlockRef{}, ethereum.NotFound)
+	require.Error(t, seq.StartBuildingBlock(context.Background()))
+	require.Equal(t, eth.BlockID{}, epoch)
+
+	// outside of recover mode, the current origin is repeated instead
+	seq.SetRecoverMode(false)
+	l1.ExpectL1BlockRefByHash(a.Hash, a, nil)
+	l1.ExpectL1BlockRefByNumber(b.Number, eth.L1BlockRef{}, errors.New("fetch failed"))
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.Equal(t, a.ID(), epoch)
+}
+
+func TestSequencerForcedTxsRetried(t *testing.T) {
+	cfg := &rollup.Config{
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+	}
+	l1Origin := eth.L1BlockRef{Hash: common.Hash{0x01}, Number: 100, Time: 1000}
+	head := eth.L2BlockRef{Hash: common.Hash{0x02}, Number: 200, Time: 1000, L1Origin: l1Origin.ID()}
+	// Sealed payloads are treated as genesis, so they don't need an L1 info deposit
+	cfg.Genesis.L2 = eth.BlockID{Hash: common.Hash{0x03}, Number: head.Number + 1}
+	cfg.Genesis.L1 = l1Origin.ID()
+	engControl := &FakeEngineControl{
+		unsafe:  head,
+		cfg:     cfg,
+		timeNow: time.Now,
+		makePayload: func(onto eth.L2BlockRef, attrs *eth.PayloadAttributes) *eth.ExecutionPayload {
+			return &eth.ExecutionPayload{
+				ParentHash:   onto.Hash,
+				BlockNumber:  eth.Uint64Quantity(onto.Number + 1),
+				BlockHash:    cfg.Genesis.L2.Hash,
+				Timestamp:    attrs.Timestamp,
+				Transactions: attrs.Transactions,
+			}
+		},
+	}
+	attrBuilder := testAttrBuilderFn(func(ctx context.Context, l2Parent eth.L2BlockRef, epoch eth.BlockID) (*eth.PayloadAttributes, error) {
+		return &eth.PayloadAttributes{Timestamp: eth.Uint64Quantity(l2Parent.Time + cfg.BlockTime)}, nil
+	})
+	originSelector := testOriginSelectorFn(func(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error) {
+		return l1Origin, nil
+	})
+	logger := testlog.Logger(t, log.LvlError)
+	forced := policyTestTx(0, 21_000)
+	policy := NewInclusionPolicy(logger, &recordingPolicyMetrics{}, nil, []*types.Transaction{forced}, 100_000, nil, 0)
+	seq := NewSequencer(logger, cfg, engControl, attrBuilder, originSelector, policy, metrics.NoopMetrics)
+	forcedData := eth.Data(encodePolicyTestTx(t, forced))
+
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.Equal(t, []eth.Data{forcedData}, engControl.buildingAttrs.Transactions)
+
+	// the forced tx is retried if the block fails to be sealed
+	engControl.err = derive.NewTemporaryError(errors.New("seal failed"))
+	engControl.errTyp = derive.BlockInsertTemporaryErr
+	_, err := seq.CompleteBuildingBlock(context.Background())
+	require.ErrorIs(t, err, derive.ErrTemporary)
+	engControl.err = nil
+	seq.CancelBuildingBlock(context.Background())
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.Equal(t, []eth.Data{forcedData}, engControl.buildingAttrs.Transactions)
+
+	// the forced tx is not included again once it is sealed
+	payload, err := seq.CompleteBuildingBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []eth.Data{forcedData}, payload.Transactions)
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.Empty(t, engControl.buildingAttrs.Transactions)
+}
+
+func TestSequencerForcedTxRejected(t *testing.T) {
+	cfg := &rollup.Config{
+		BlockTime:         2,
+		MaxSequencerDrift: 600,
+	}
+	l1Origin := eth.L1BlockRef{Hash: common.Hash{0x01}, Number: 100, Time: 1000}
+	head := eth.L2BlockRef{Hash: common.Hash{0x02}, Number: 200, Time: 1000, L1Origin: l1Origin.ID()}
+	// Sealed payloads are treated as genesis, so they don't need an L1 info deposit
+	cfg.Genesis.L2 = eth.BlockID{Hash: common.Hash{0x03}, Number: head.Number + 1}
+	cfg.Genesis.L1 = l1Origin.ID()
+	engControl := &FakeEngineControl{
+		unsafe:  head,
+		cfg:     cfg,
+		timeNow: time.Now,
+		makePayload: func(onto eth.L2BlockRef, attrs *eth.PayloadAttributes) *eth.ExecutionPayload {
+			return &eth.ExecutionPayload{
+				ParentHash:   onto.Hash,
+				BlockNumber:  eth.Uint64Quantity(onto.Number + 1),
+				BlockHash:    cfg.Genesis.L2.Hash,
+				Timestamp:    attrs.Timestamp,
+				Transactions: attrs.Transactions,
+			}
+		},
+	}
+	attrBuilder := testAttrBuilderFn(func(ctx context.Context, l2Parent eth.L2BlockRef, epoch eth.BlockID) (*eth.PayloadAttributes, error) {
+		return &eth.PayloadAttributes{Timestamp: eth.Uint64Quantity(l2Parent.Time + cfg.BlockTime)}, nil
+	})
+	originSelector := testOriginSelectorFn(func(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, error) {
+		return l1Origin, nil
+	})
+	logger := testlog.Logger(t, log.LvlError)
+	bad := policyTestTx(0, 21_000)
+	policy := NewInclusionPolicy(logger, &recordingPolicyMetrics{}, nil, []*types.Transaction{bad}, 100_000, nil, 0)
+	seq := NewSequencer(logger, cfg, engControl, attrBuilder, originSelector, policy, metrics.NoopMetrics)
+
+	// the engine rejects every block with the forced tx, until the sequencer drops it
+	for i := 0; i < maxForcedTxAttempts; i++ {
+		require.NoError(t, seq.StartBuildingBlock(context.Background()))
+		require.Equal(t, []eth.Data{eth.Data(encodePolicyTestTx(t, bad))}, engControl.buildingAttrs.Transactions)
+		engControl.err = derive.NewTemporaryError(errors.New("invalid forced tx"))
+		engControl.errTyp = derive.BlockInsertPayloadErr
+		_, err := seq.CompleteBuildingBlock(context.Background())
+		require.ErrorIs(t, err, derive.ErrTemporary)
+		engControl.err = nil
+		seq.CancelBuildingBlock(context.Background())
+	}
+
+	// the next block builds without the forced tx
+	require.NoError(t, seq.StartBuildingBlock(context.Background()))
+	require.Empty(t, engControl.buildingAttrs.Transactions)
+	_, err := seq.CompleteBuildingBlock(context.Background())
+	require.NoError(t, err)
+}
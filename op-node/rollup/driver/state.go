@@ -15,6 +15,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/interop"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
 )
@@ -78,6 +79,9 @@ type Driver struct {
 	// Interface to signal the L2 block range to sync.
 	altSync AltSync
 
+	// Tracks the cross-unsafe and cross-safe heads, nil if the Interop fork is not scheduled.
+	crossSafety *interop.CrossSafetyTracker
+
 	// L2 Signals:
 
 	unsafeL2Payloads chan *eth.ExecutionPayload
@@ -235,6 +239,14 @@ func (s *Driver) eventLoop() {
 	defer altSyncTicker.Stop()
 	lastUnsafeL2 := s.engineController.UnsafeL2Head()
 
+	// Create a ticker to advance the cross-safety heads, if the Interop fork is scheduled.
+	var crossSafetyCh <-chan time.Time
+	if s.crossSafety != nil {
+		crossSafetyTicker := time.NewTicker(time.Duration(s.config.BlockTime) * time.Second)
+		defer crossSafetyTicker.Stop()
+		crossSafetyCh = crossSafetyTicker.C
+	}
+
 	for {
 		if s.driverCtx.Err() != nil { // don't try to schedule/handle more work when we are closing.
 			return
@@ -300,6 +312,13 @@ func (s *Driver) eventLoop() {
 			if err != nil {
 				s.log.Warn("failed to check for unsafe L2 blocks to sync", "err", err)
 			}
+		case <-crossSafetyCh:
+			ctx, cancel := context.WithTimeout(s.driverCtx, time.Second*2)
+			err := s.crossSafety.Update(ctx, s.engineController.UnsafeL2Head(), s.engineController.SafeL2Head(), s.engineController.Finalized())
+			cancel()
+			if err != nil {
+				s.log.Warn("failed to update cross-safety heads", "err", err)
+			}
 		case payload := <-s.unsafeL2Payloads:
 			s.snapshot("New unsafe payload")
 			s.log.Info("Optimistically queueing unsafe L2 execution payload", "id", payload.ID())
@@ -481,9 +500,23 @@ func (s *Driver) SequencerActive(ctx context.Context) (bool, error) {
 	}
 }
 
+// SetRecoverMode toggles the recover mode of the sequencer,
+// in which it only builds deposit-only blocks to catch up with derivation after an outage.
+func (s *Driver) SetRecoverMode(ctx context.Context, mode bool) error {
+	if !s.driverConfig.SequencerEnabled {
+		return errors.New("sequencer is not enabled")
+	}
+	s.sequencer.SetRecoverMode(mode)
+	return nil
+}
+
 // syncStatus returns the current sync status, and should only be called synchronously with
 // the driver event loop to avoid retrieval of an inconsistent status.
 func (s *Driver) syncStatus() *eth.SyncStatus {
+	crossUnsafe, crossSafe := s.engineController.UnsafeL2Head(), s.engineController.SafeL2Head()
+	if s.crossSafety != nil {
+		crossUnsafe, crossSafe = s.crossSafety.CrossUnsafe(), s.crossSafety.CrossSafe()
+	}
 	return &eth.SyncStatus{
 		CurrentL1:          s.derivation.Origin(),
 		CurrentL1Finalized: s.derivation.FinalizedL1(),
@@ -494,6 +527,8 @@ func (s *Driver) syncStatus() *eth.SyncStatus {
 		SafeL2:             s.engineController.SafeL2Head(),
 		FinalizedL2:        s.engineController.Finalized(),
 		PendingSafeL2:      s.engineController.PendingSafeL2Head(),
+		CrossUnsafeL2:      crossUnsafe,
+		CrossSafeL2:        crossSafe,
 	}
 }
 
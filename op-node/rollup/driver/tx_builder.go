@@ -0,0 +1,74 @@
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// BuilderRequest requests the transactions to include in the block built on top of the given parent.
+type BuilderRequest struct {
+	ParentHash   common.Hash    `json:"parentHash"`
+	ParentNumber hexutil.Uint64 `json:"parentNumber"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	// GasLimit is the maximum total gas of the returned transactions.
+	GasLimit hexutil.Uint64 `json:"gasLimit"`
+}
+
+// BuilderResponse lists the transactions to include in the requested block, after any forced transactions.
+type BuilderResponse struct {
+	Transactions []hexutil.Bytes `json:"transactions"`
+	// NoTxPool excludes the transactions of the local tx-pool from the block.
+	NoTxPool bool `json:"noTxPool"`
+}
+
+// TxBuilder is an external tx builder supplying transactions to the sequencer, before a block is built.
+// Unlike derive.PayloadBuilder, it does not build the block itself.
+type TxBuilder interface {
+	GetTransactions(ctx context.Context, req *BuilderRequest) (*BuilderResponse, error)
+}
+
+// HTTPTxBuilder requests transactions by posting a BuilderRequest as JSON to the builder endpoint.
+type HTTPTxBuilder struct {
+	url string
+}
+
+var _ TxBuilder = (*HTTPTxBuilder)(nil)
+
+func NewHTTPTxBuilder(url string) *HTTPTxBuilder {
+	return &HTTPTxBuilder{url: url}
+}
+
+func (b *HTTPTxBuilder) GetTransactions(ctx context.Context, req *BuilderRequest) (*BuilderResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode builder request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create builder request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request builder transactions: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected builder response status %d", resp.StatusCode)
+	}
+	var res BuilderResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxBuilderResponseSize)).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode builder response: %w", err)
+	}
+	return &res, nil
+}
+
+// maxBuilderResponseSize bounds the size of a builder response, well above the size of any block.
+const maxBuilderResponseSize = 32 * 1024 * 1024
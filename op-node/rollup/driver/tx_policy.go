@@ -0,0 +1,368 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var ErrDepositTx = errors.New("deposit transactions cannot be included by the sequencer")
+
+// TxPolicy contributes transactions to the blocks built by the sequencer,
+// on top of the transactions the execution engine selects from its tx-pool.
+type TxPolicy interface {
+	// ApplyTxPolicy appends transactions to the attributes of the block built on top of parent.
+	// The attributes already contain the deposits of the block. Transactions of the tx-pool are
+	// included after the transactions of the policy, unless the policy sets NoTxPool.
+	// It is not called for blocks that already exclude the tx-pool.
+	ApplyTxPolicy(ctx context.Context, parent eth.L2BlockRef, attrs *eth.PayloadAttributes) error
+	// ConfirmTxPolicy is called with every payload sealed by the sequencer. Transactions applied to blocks
+	// that fail to be built, or that are not included in the sealed payload, may be applied again to the next block.
+	ConfirmTxPolicy(payload *eth.ExecutionPayload)
+	// FailTxPolicy is called when the block the policy was last applied to fails to be built or sealed.
+	FailTxPolicy(err error)
+}
+
+// maxForcedTxAttempts is the number of blocks a forced transaction may fail to be included in before it is dropped.
+// A single failure is not enough, since building may also fail for reasons unrelated to the transaction.
+const maxForcedTxAttempts = 3
+
+// Reasons for dropping forced transactions, as recorded in the metrics.
+const (
+	ForcedTxDroppedGas     = "gas"
+	ForcedTxDroppedNonce   = "nonce"
+	ForcedTxDroppedInvalid = "invalid"
+	ForcedTxDroppedFailed  = "failed"
+)
+
+type TxPolicyMetrics interface {
+	RecordForcedTxDropped(reason string)
+}
+
+// L2AccountFetcher retrieves the state of L2 accounts, to check the nonces of forced transactions.
+type L2AccountFetcher interface {
+	GetProof(ctx context.Context, address common.Address, storage []common.Hash, blockTag string) (*eth.AccountResult, error)
+}
+
+// InclusionPolicy includes forced transactions, followed by the transactions of an external tx builder,
+// within a per-block gas reservation. If the tx builder fails or is slow, the block is built from the
+// local tx-pool instead. The transactions are added to the payload attributes, so an external payload builder
+// (see derive.PayloadBuilder) must include them as well.
+type InclusionPolicy struct {
+	log     log.Logger
+	metrics TxPolicyMetrics
+
+	// l2 is used to drop forced transactions with nonces that were already used. The nonces are not checked if nil.
+	l2 L2AccountFetcher
+
+	// gasReservation is the gas reserved per block for policy transactions. Limited by the block gas limit if 0.
+	gasReservation uint64
+
+	builder        TxBuilder
+	builderTimeout time.Duration
+
+	mu sync.Mutex
+	// forced are the transactions still to be included, in order.
+	// Forced transactions are only removed once a sealed payload includes them,
+	// so they are retried if the block fails to be built, up to maxForcedTxAttempts times.
+	forced []*types.Transaction
+	// applied are the forced transactions applied to the block currently being built on top of appliedParent.
+	applied       []common.Hash
+	appliedParent common.Hash
+	// attempts counts the failed inclusion attempts per forced transaction.
+	attempts map[common.Hash]int
+	// isolate makes the next block include only the first forced transaction,
+	// to attribute the failure of a block with multiple forced transactions to one of them.
+	isolate bool
+}
+
+var _ TxPolicy = (*InclusionPolicy)(nil)
+
+// NewInclusionPolicy creates a new InclusionPolicy. The builder may be nil if no external tx builder is used.
+func NewInclusionPolicy(log log.Logger, metrics TxPolicyMetrics, l2 L2AccountFetcher, forced []*types.Transaction, gasReservation uint64, builder TxBuilder, builderTimeout time.Duration) *InclusionPolicy {
+	return &InclusionPolicy{
+		log:            log,
+		metrics:        metrics,
+		l2:             l2,
+		forced:         forced,
+		attempts:       make(map[common.Hash]int),
+		gasReservation: gasReservation,
+		builder:        builder,
+		builderTimeout: builderTimeout,
+	}
+}
+
+// NewTxPolicy creates the TxPolicy of the sequencer from the driver config.
+// It returns nil if no transaction inclusion policy is configured.
+func NewTxPolicy(log log.Logger, cfg *Config, l2 L2AccountFetcher, metrics TxPolicyMetrics) (TxPolicy, error) {
+	if !cfg.TxPolicyEnabled() {
+		return nil, nil
+	}
+	var forced []*types.Transaction
+	if cfg.SequencerForcedTxsPath != "" {
+		txs, err := LoadForcedTxs(cfg.SequencerForcedTxsPath)
+		if err != nil {
+			return nil, err
+		}
+		forced = txs
+	}
+	var builder TxBuilder
+	if cfg.SequencerTxBuilderURL != "" {
+		builder = NewHTTPTxBuilder(cfg.SequencerTxBuilderURL)
+	}
+	log.Info("Sequencer transaction inclusion policy enabled", "forced_txs", len(forced),
+		"gas_reservation", cfg.SequencerGasReservation, "tx_builder", cfg.SequencerTxBuilderURL)
+	return NewInclusionPolicy(log, metrics, l2, forced, cfg.SequencerGasReservation, builder, cfg.SequencerTxBuilderTimeout), nil
+}
+
+// LoadForcedTxs reads a JSON list of hex-encoded transactions to force-include from the given file.
+func LoadForcedTxs(path string) ([]*types.Transaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read forced transactions file: %w", err)
+	}
+	var encoded []hexutil.Bytes
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("failed to decode forced transactions file: %w", err)
+	}
+	txs, err := decodePolicyTxs(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forced transaction: %w", err)
+	}
+	return txs, nil
+}
+
+func decodePolicyTxs(encoded []hexutil.Bytes) ([]*types.Transaction, error) {
+	txs := make([]*types.Transaction, 0, len(encoded))
+	for i, data := range encoded {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %d: %w", i, err)
+		}
+		if tx.Type() == types.DepositTxType {
+			return nil, fmt.Errorf("transaction %d: %w", i, ErrDepositTx)
+		}
+		txs = append(txs, &tx)
+	}
+	return txs, nil
+}
+
+func (p *InclusionPolicy) ApplyTxPolicy(ctx context.Context, parent eth.L2BlockRef, attrs *eth.PayloadAttributes) error {
+	gasLimit := p.gasReservation
+	if attrs.GasLimit != nil && (gasLimit == 0 || uint64(*attrs.GasLimit) < gasLimit) {
+		gasLimit = uint64(*attrs.GasLimit)
+	}
+
+	gasUsed, err := p.applyForcedTxs(ctx, parent, attrs, gasLimit)
+	if err != nil {
+		return err
+	}
+	if p.builder != nil {
+		p.applyBuilderTxs(ctx, parent, attrs, gasLimit-gasUsed)
+	}
+	return nil
+}
+
+// applyForcedTxs includes the queued forced transactions, in order, as long as they fit in the gas limit.
+// It returns the gas reserved by the included transactions. The transactions stay queued until confirmed.
+func (p *InclusionPolicy) applyForcedTxs(ctx context.Context, parent eth.L2BlockRef, attrs *eth.PayloadAttributes, gasLimit uint64) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Forced transactions that can never fit in the reservation would block the queue, so they are dropped.
+	forced := make([]*types.Transaction, 0, len(p.forced))
+	for _, tx := range p.forced {
+		if tx.Gas() > gasLimit {
+			p.drop(tx, ForcedTxDroppedGas, "Dropping forced transaction exceeding the gas reservation", "gas", tx.Gas(), "reservation", gasLimit)
+			continue
+		}
+		forced = append(forced, tx)
+	}
+	p.forced = forced
+
+	var gasUsed uint64
+	nonces := make(map[common.Address]uint64)
+	p.applied, p.appliedParent = nil, parent.Hash
+	for i := 0; i < len(p.forced); i++ {
+		tx := p.forced[i]
+		if gasUsed+tx.Gas() > gasLimit {
+			break
+		}
+		if p.l2 != nil {
+			stale, err := p.staleNonce(ctx, parent, tx, nonces)
+			if err != nil {
+				return 0, err
+			}
+			if stale {
+				p.forced = append(p.forced[:i], p.forced[i+1:]...)
+				i--
+				continue
+			}
+		}
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode forced transaction %s: %w", tx.Hash(), err)
+		}
+		attrs.Transactions = append(attrs.Transactions, data)
+		gasUsed += tx.Gas()
+		p.applied = append(p.applied, tx.Hash())
+		p.log.Info("Including forced transaction", "tx", tx.Hash(), "gas", tx.Gas(), "attempts", p.attempts[tx.Hash()])
+		// After a failure, forced transactions are included one by one until the failing transaction is found.
+		if p.isolate || p.attempts[tx.Hash()] > 0 {
+			break
+		}
+	}
+	return gasUsed, nil
+}
+
+// staleNonce returns true, and drops the forced transaction, if its nonce was already used as of the parent block.
+// The transaction is dropped as well if its sender can't be recovered. The state nonces of the senders are cached in nonces.
+func (p *InclusionPolicy) staleNonce(ctx context.Context, parent eth.L2BlockRef, tx *types.Transaction, nonces map[common.Address]uint64) (bool, error) {
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		p.drop(tx, ForcedTxDroppedInvalid, "Dropping forced transaction with invalid signature", "err", err)
+		return true, nil
+	}
+	nonce, ok := nonces[sender]
+	if !ok {
+		acc, err := p.l2.GetProof(ctx, sender, nil, parent.Hash.String())
+		if err != nil {
+			return false, fmt.Errorf("failed to fetch nonce of forced transaction sender %s at %s: %w", sender, parent, err)
+		}
+		nonce = uint64(acc.Nonce)
+		nonces[sender] = nonce
+	}
+	if tx.Nonce() < nonce {
+		p.drop(tx, ForcedTxDroppedNonce, "Dropping forced transaction with used nonce", "sender", sender, "nonce", tx.Nonce(), "state_nonce", nonce, "parent", parent)
+		return true, nil
+	}
+	return false, nil
+}
+
+// ConfirmTxPolicy removes the forced transactions included in the sealed payload from the queue.
+// Applied forced transactions missing from the payload count as failed inclusion attempts.
+func (p *InclusionPolicy) ConfirmTxPolicy(payload *eth.ExecutionPayload) {
+	included := make(map[common.Hash]struct{}, len(payload.Transactions))
+	for _, data := range payload.Transactions {
+		// The hash of a transaction is the hash of its binary encoding
+		included[crypto.Keccak256Hash(data)] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var missing []common.Hash
+	if payload.ParentHash == p.appliedParent {
+		for _, h := range p.applied {
+			if _, ok := included[h]; !ok {
+				missing = append(missing, h)
+			}
+		}
+	}
+	p.applied = nil
+	p.isolate = false
+
+	remaining := make([]*types.Transaction, 0, len(p.forced))
+	for _, tx := range p.forced {
+		if _, ok := included[tx.Hash()]; ok {
+			p.log.Info("Forced transaction included", "tx", tx.Hash(), "block", payload.ID())
+			delete(p.attempts, tx.Hash())
+			continue
+		}
+		remaining = append(remaining, tx)
+	}
+	p.forced = remaining
+	for _, h := range missing {
+		p.recordFailure(h, errors.New("not included in sealed payload"))
+	}
+}
+
+// FailTxPolicy records a failed inclusion attempt of the forced transaction applied to the failed block.
+// If multiple forced transactions were applied, the failure can't be attributed to one of them,
+// and the next block includes only the first forced transaction instead.
+func (p *InclusionPolicy) FailTxPolicy(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	applied := p.applied
+	p.applied = nil
+	switch len(applied) {
+	case 0:
+		return
+	case 1:
+		p.recordFailure(applied[0], err)
+	default:
+		p.log.Warn("Block with forced transactions failed, including them one by one", "txs", len(applied), "err", err)
+		p.isolate = true
+	}
+}
+
+// recordFailure counts a failed inclusion attempt of the queued forced transaction with the given hash,
+// and drops the transaction once it reaches maxForcedTxAttempts.
+func (p *InclusionPolicy) recordFailure(h common.Hash, err error) {
+	for i, tx := range p.forced {
+		if tx.Hash() != h {
+			continue
+		}
+		p.attempts[h]++
+		if p.attempts[h] < maxForcedTxAttempts {
+			p.log.Warn("Failed to include forced transaction", "tx", h, "attempts", p.attempts[h], "err", err)
+			return
+		}
+		p.forced = append(p.forced[:i], p.forced[i+1:]...)
+		p.drop(tx, ForcedTxDroppedFailed, "Dropping forced transaction that repeatedly failed to be included", "attempts", p.attempts[h], "err", err)
+		return
+	}
+}
+
+// drop logs and records a forced transaction that is removed from the queue. The caller removes it from the queue.
+func (p *InclusionPolicy) drop(tx *types.Transaction, reason string, msg string, ctx ...any) {
+	p.log.Error(msg, append([]any{"tx", tx.Hash()}, ctx...)...)
+	delete(p.attempts, tx.Hash())
+	p.metrics.RecordForcedTxDropped(reason)
+}
+
+// applyBuilderTxs includes the transactions of the external tx builder.
+// Any failure of the builder falls back to building the block from the local tx-pool.
+func (p *InclusionPolicy) applyBuilderTxs(ctx context.Context, parent eth.L2BlockRef, attrs *eth.PayloadAttributes, gasLimit uint64) {
+	ctx, cancel := context.WithTimeout(ctx, p.builderTimeout)
+	defer cancel()
+	res, err := p.builder.GetTransactions(ctx, &BuilderRequest{
+		ParentHash:   parent.Hash,
+		ParentNumber: hexutil.Uint64(parent.Number),
+		Timestamp:    hexutil.Uint64(attrs.Timestamp),
+		GasLimit:     hexutil.Uint64(gasLimit),
+	})
+	if err != nil {
+		p.log.Warn("Tx builder failed, falling back to local tx-pool", "parent", parent, "err", err)
+		return
+	}
+	txs, err := decodePolicyTxs(res.Transactions)
+	if err != nil {
+		p.log.Warn("Tx builder returned invalid transactions, falling back to local tx-pool", "parent", parent, "err", err)
+		return
+	}
+	var gas uint64
+	for _, tx := range txs {
+		gas += tx.Gas()
+	}
+	if gas > gasLimit {
+		p.log.Warn("Tx builder exceeded the gas reservation, falling back to local tx-pool", "parent", parent, "gas", gas, "reservation", gasLimit)
+		return
+	}
+	for _, data := range res.Transactions {
+		attrs.Transactions = append(attrs.Transactions, eth.Data(data))
+	}
+	attrs.NoTxPool = res.NoTxPool
+	p.log.Debug("Including tx builder transactions", "parent", parent, "txs", len(txs), "gas", gas, "noTxPool", res.NoTxPool)
+}
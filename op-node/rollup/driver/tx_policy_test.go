@@ -0,0 +1,219 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func policyTestTx(nonce uint64, gas uint64) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{Nonce: nonce, Gas: gas})
+}
+
+func encodePolicyTestTx(t *testing.T, tx *types.Transaction) hexutil.Bytes {
+	data, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return data
+}
+
+type recordingPolicyMetrics struct {
+	dropped []string
+}
+
+func (m *recordingPolicyMetrics) RecordForcedTxDropped(reason string) {
+	m.dropped = append(m.dropped, reason)
+}
+
+type fakeAccountFetcher map[common.Address]uint64
+
+func (f fakeAccountFetcher) GetProof(ctx context.Context, address common.Address, storage []common.Hash, blockTag string) (*eth.AccountResult, error) {
+	return &eth.AccountResult{Address: address, Nonce: hexutil.Uint64(f[address])}, nil
+}
+
+func newPolicyTestAttrs(gasLimit uint64) *eth.PayloadAttributes {
+	limit := eth.Uint64Quantity(gasLimit)
+	return &eth.PayloadAttributes{
+		Timestamp:    1000,
+		Transactions: []eth.Data{{0x7e}},
+		GasLimit:     &limit,
+	}
+}
+
+func TestInclusionPolicyForcedTxs(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlError)
+	txA, txB, txC, txD := policyTestTx(0, 40_000), policyTestTx(1, 40_000), policyTestTx(2, 200_000), policyTestTx(3, 30_000)
+	policy := NewInclusionPolicy(logger, &recordingPolicyMetrics{}, nil, []*types.Transaction{txA, txB, txC, txD}, 100_000, nil, 0)
+	parent := eth.L2BlockRef{Number: 10}
+
+	// forced txs are included in order, after the deposits, up to the gas reservation
+	attrs := newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, txA)), eth.Data(encodePolicyTestTx(t, txB))}, attrs.Transactions)
+	require.False(t, attrs.NoTxPool)
+
+	// forced txs stay queued until a sealed payload includes them, so they are retried if the block fails
+	attrs = newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, txA)), eth.Data(encodePolicyTestTx(t, txB))}, attrs.Transactions)
+	policy.ConfirmTxPolicy(&eth.ExecutionPayload{Transactions: attrs.Transactions})
+
+	// txs that can never fit the reservation are dropped, the remaining txs are included in the next block
+	attrs = newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, txD))}, attrs.Transactions)
+
+	// forced txs missing from the sealed payload are retried
+	policy.ConfirmTxPolicy(&eth.ExecutionPayload{Transactions: []eth.Data{{0x7e}}})
+	attrs = newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, txD))}, attrs.Transactions)
+
+	// forced txs are included only once
+	policy.ConfirmTxPolicy(&eth.ExecutionPayload{Transactions: attrs.Transactions})
+	attrs = newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}}, attrs.Transactions)
+}
+
+func TestInclusionPolicyFailedForcedTxs(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlError)
+	bad, good := policyTestTx(0, 40_000), policyTestTx(1, 40_000)
+	m := &recordingPolicyMetrics{}
+	policy := NewInclusionPolicy(logger, m, nil, []*types.Transaction{bad, good}, 100_000, nil, 0)
+	parent := eth.L2BlockRef{Number: 10}
+	buildErr := errors.New("invalid forced tx")
+
+	// the failure of a block with multiple forced txs can't be attributed, so they are included one by one
+	attrs := newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, bad)), eth.Data(encodePolicyTestTx(t, good))}, attrs.Transactions)
+	policy.FailTxPolicy(buildErr)
+
+	// the failing forced tx is dropped after maxForcedTxAttempts
+	for i := 0; i < maxForcedTxAttempts; i++ {
+		attrs = newPolicyTestAttrs(30_000_000)
+		require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+		require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, bad))}, attrs.Transactions)
+		policy.FailTxPolicy(buildErr)
+	}
+	require.Equal(t, []string{ForcedTxDroppedFailed}, m.dropped)
+
+	// the next forced tx is included
+	attrs = newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, good))}, attrs.Transactions)
+	policy.ConfirmTxPolicy(&eth.ExecutionPayload{Transactions: attrs.Transactions})
+	require.Empty(t, policy.forced)
+}
+
+func TestInclusionPolicyStaleNonce(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlError)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(big.NewInt(901))
+	signedTx := func(nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(901), Nonce: nonce, Gas: 21_000})
+	}
+	used, next := signedTx(4), signedTx(5)
+	unsigned := policyTestTx(6, 21_000)
+	l2 := fakeAccountFetcher{crypto.PubkeyToAddress(key.PublicKey): 5}
+	m := &recordingPolicyMetrics{}
+	policy := NewInclusionPolicy(logger, m, l2, []*types.Transaction{used, unsigned, next}, 100_000, nil, 0)
+
+	// forced txs with nonces used as of the parent block, or without valid signature, are dropped
+	attrs := newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), eth.L2BlockRef{Number: 10}, attrs))
+	require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, next))}, attrs.Transactions)
+	require.Equal(t, []string{ForcedTxDroppedNonce, ForcedTxDroppedInvalid}, m.dropped)
+	require.Equal(t, []*types.Transaction{next}, policy.forced)
+}
+
+func TestInclusionPolicyBuilder(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlError)
+	forced := policyTestTx(0, 40_000)
+	builderTx := policyTestTx(1, 50_000)
+
+	var lastReq BuilderRequest
+	res := BuilderResponse{Transactions: []hexutil.Bytes{encodePolicyTestTx(t, builderTx)}, NoTxPool: true}
+	delay := time.Duration(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&lastReq))
+		time.Sleep(delay)
+		require.NoError(t, json.NewEncoder(w).Encode(&res))
+	}))
+	t.Cleanup(server.Close)
+
+	policy := NewInclusionPolicy(logger, &recordingPolicyMetrics{}, nil, []*types.Transaction{forced}, 100_000, NewHTTPTxBuilder(server.URL), 100*time.Millisecond)
+	parent := eth.L2BlockRef{Hash: common.Hash{0xaa}, Number: 10}
+
+	// builder txs follow the forced txs, within the remaining gas reservation
+	attrs := newPolicyTestAttrs(30_000_000)
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}, eth.Data(encodePolicyTestTx(t, forced)), eth.Data(encodePolicyTestTx(t, builderTx))}, attrs.Transactions)
+	require.True(t, attrs.NoTxPool)
+	require.Equal(t, BuilderRequest{ParentHash: parent.Hash, ParentNumber: 10, Timestamp: 1000, GasLimit: 60_000}, lastReq)
+	policy.ConfirmTxPolicy(&eth.ExecutionPayload{Transactions: attrs.Transactions})
+
+	// builder txs exceeding the gas reservation fall back to the tx-pool
+	attrs = newPolicyTestAttrs(30_000_000)
+	res.Transactions = append(res.Transactions, encodePolicyTestTx(t, policyTestTx(2, 60_000)))
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}}, attrs.Transactions)
+	require.False(t, attrs.NoTxPool)
+
+	// deposits supplied by the builder fall back to the tx-pool
+	attrs = newPolicyTestAttrs(30_000_000)
+	res.Transactions = []hexutil.Bytes{encodePolicyTestTx(t, types.NewTx(&types.DepositTx{Gas: 10_000}))}
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}}, attrs.Transactions)
+	require.False(t, attrs.NoTxPool)
+
+	// a slow builder falls back to the tx-pool
+	attrs = newPolicyTestAttrs(30_000_000)
+	res.Transactions = []hexutil.Bytes{encodePolicyTestTx(t, builderTx)}
+	delay = 500 * time.Millisecond
+	require.NoError(t, policy.ApplyTxPolicy(context.Background(), parent, attrs))
+	require.Equal(t, []eth.Data{{0x7e}}, attrs.Transactions)
+	require.False(t, attrs.NoTxPool)
+}
+
+func TestLoadForcedTxs(t *testing.T) {
+	dir := t.TempDir()
+	tx := policyTestTx(0, 21_000)
+
+	path := filepath.Join(dir, "forced.json")
+	data, err := json.Marshal([]hexutil.Bytes{encodePolicyTestTx(t, tx)})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	txs, err := LoadForcedTxs(path)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.Equal(t, tx.Hash(), txs[0].Hash())
+
+	depositPath := filepath.Join(dir, "deposit.json")
+	data, err = json.Marshal([]hexutil.Bytes{encodePolicyTestTx(t, types.NewTx(&types.DepositTx{}))})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(depositPath, data, 0o644))
+	_, err = LoadForcedTxs(depositPath)
+	require.ErrorIs(t, err, ErrDepositTx)
+
+	_, err = LoadForcedTxs(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
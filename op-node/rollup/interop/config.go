@@ -0,0 +1,65 @@
+package interop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	ErrNoChains            = errors.New("dependency set has no chains")
+	ErrDuplicateChain      = errors.New("duplicate chain in dependency set")
+	ErrMissingChainID      = errors.New("missing chain ID")
+	ErrMissingChainRPCAddr = errors.New("missing chain RPC address")
+)
+
+// DependencyChain is a chain in the dependency set, from which the local chain may execute messages.
+type DependencyChain struct {
+	ChainID uint64 `json:"chainId"`
+	// RPC is the address of the execution engine RPC of the chain.
+	// Its safe and finalized block labels must be driven by an op-node of the chain.
+	RPC string `json:"rpc"`
+}
+
+// DependencySet is the set of chains the local chain may execute messages from.
+// The local chain must be included to allow messages between contracts of the same chain.
+type DependencySet struct {
+	Chains []DependencyChain `json:"chains"`
+}
+
+// LoadDependencySet reads a JSON encoded DependencySet from the file at path.
+func LoadDependencySet(path string) (*DependencySet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dependency set: %w", err)
+	}
+	defer file.Close()
+	var deps DependencySet
+	dec := json.NewDecoder(file)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&deps); err != nil {
+		return nil, fmt.Errorf("failed to decode dependency set: %w", err)
+	}
+	return &deps, nil
+}
+
+func (d *DependencySet) Check() error {
+	if len(d.Chains) == 0 {
+		return ErrNoChains
+	}
+	seen := make(map[uint64]bool)
+	for i, chain := range d.Chains {
+		if chain.ChainID == 0 {
+			return fmt.Errorf("chain %d: %w", i, ErrMissingChainID)
+		}
+		if chain.RPC == "" {
+			return fmt.Errorf("chain %d: %w", chain.ChainID, ErrMissingChainRPCAddr)
+		}
+		if seen[chain.ChainID] {
+			return fmt.Errorf("chain %d: %w", chain.ChainID, ErrDuplicateChain)
+		}
+		seen[chain.ChainID] = true
+	}
+	return nil
+}
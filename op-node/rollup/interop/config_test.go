@@ -0,0 +1,52 @@
+package interop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDependencySet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deps.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"chains":[{"chainId":900,"rpc":"http://localhost:8545"},{"chainId":901,"rpc":"http://localhost:9545"}]}`), 0o644))
+	deps, err := LoadDependencySet(path)
+	require.NoError(t, err)
+	require.Equal(t, &DependencySet{Chains: []DependencyChain{
+		{ChainID: 900, RPC: "http://localhost:8545"},
+		{ChainID: 901, RPC: "http://localhost:9545"},
+	}}, deps)
+	require.NoError(t, deps.Check())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"chains":[],"unknown":true}`), 0o644))
+	_, err = LoadDependencySet(path)
+	require.ErrorContains(t, err, "unknown")
+}
+
+func TestDependencySetCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		chains   []DependencyChain
+		expected error
+	}{
+		{name: "NoChains", expected: ErrNoChains},
+		{name: "MissingChainID", chains: []DependencyChain{{RPC: "http://localhost:8545"}}, expected: ErrMissingChainID},
+		{name: "MissingRPC", chains: []DependencyChain{{ChainID: 900}}, expected: ErrMissingChainRPCAddr},
+		{
+			name: "Duplicate",
+			chains: []DependencyChain{
+				{ChainID: 900, RPC: "http://localhost:8545"},
+				{ChainID: 900, RPC: "http://localhost:9545"},
+			},
+			expected: ErrDuplicateChain,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			deps := &DependencySet{Chains: test.chains}
+			require.ErrorIs(t, deps.Check(), test.expected)
+		})
+	}
+}
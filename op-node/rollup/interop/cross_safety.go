@@ -0,0 +1,152 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// maxBlocksPerUpdate limits the number of blocks the cross-safety heads advance by in a single update,
+// to not block the driver for too long while catching up.
+const maxBlocksPerUpdate = 32
+
+// L2Source provides the blocks and receipts of the local chain.
+type L2Source interface {
+	L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
+}
+
+// CrossSafetyTracker tracks the cross-unsafe and cross-safe heads of the local chain.
+//
+// A block is cross-unsafe when it is unsafe and all of its executing messages, and those of its ancestors,
+// refer to initiating messages that exist in the unsafe chains of the dependency set.
+// A block is cross-safe when it is safe and all of its executing messages, and those of its ancestors,
+// refer to initiating messages that exist in the safe chains of the dependency set.
+// Blocks before the Interop fork cannot execute messages, and are cross-safe as soon as they are safe.
+//
+// The tracker only tracks the heads: a block with an invalid executing message halts the progress of the heads,
+// it does not reorg the local chain.
+type CrossSafetyTracker struct {
+	log        log.Logger
+	cfg        *rollup.Config
+	l2         L2Source
+	supervisor Supervisor
+
+	crossUnsafe eth.L2BlockRef
+	crossSafe   eth.L2BlockRef
+}
+
+func NewCrossSafetyTracker(log log.Logger, cfg *rollup.Config, l2 L2Source, supervisor Supervisor) *CrossSafetyTracker {
+	return &CrossSafetyTracker{
+		log:        log,
+		cfg:        cfg,
+		l2:         l2,
+		supervisor: supervisor,
+	}
+}
+
+// CrossUnsafe returns the current cross-unsafe head.
+func (t *CrossSafetyTracker) CrossUnsafe() eth.L2BlockRef {
+	return t.crossUnsafe
+}
+
+// CrossSafe returns the current cross-safe head.
+func (t *CrossSafetyTracker) CrossSafe() eth.L2BlockRef {
+	return t.crossSafe
+}
+
+// Update advances the cross-unsafe and cross-safe heads towards the local unsafe and safe heads.
+// The finalized head is used as the starting point, and is assumed to be cross-safe.
+func (t *CrossSafetyTracker) Update(ctx context.Context, unsafe, safe, finalized eth.L2BlockRef) error {
+	if finalized == (eth.L2BlockRef{}) {
+		// Not yet initialized
+		return nil
+	}
+	var err error
+	t.crossSafe, err = t.advance(ctx, "cross-safe", t.crossSafe, safe, finalized, Safe)
+	if err != nil {
+		return err
+	}
+	if t.crossUnsafe.Number < t.crossSafe.Number {
+		t.crossUnsafe = t.crossSafe
+	}
+	t.crossUnsafe, err = t.advance(ctx, "cross-unsafe", t.crossUnsafe, unsafe, t.crossSafe, Unsafe)
+	return err
+}
+
+// advance moves the cross head towards the local head, one block at a time, as long as the executing messages of
+// each block are at least as safe as minSafety. If the cross head is no longer canonical, it is reset to fallback.
+func (t *CrossSafetyTracker) advance(ctx context.Context, name string, cross, local, fallback eth.L2BlockRef, minSafety SafetyLevel) (eth.L2BlockRef, error) {
+	if cross.Number < fallback.Number || cross.Number > local.Number {
+		cross = fallback
+	}
+	if cross != fallback {
+		canonical, err := t.l2.L2BlockRefByNumber(ctx, cross.Number)
+		if err != nil {
+			return cross, fmt.Errorf("failed to check %s head %s: %w", name, cross, err)
+		}
+		if canonical.Hash != cross.Hash {
+			t.log.Warn("Resetting cross head after reorg", "head", name, "old", cross, "new", fallback)
+			cross = fallback
+		}
+	}
+	for i := 0; i < maxBlocksPerUpdate && cross.Number < local.Number; i++ {
+		next, err := t.l2.L2BlockRefByNumber(ctx, cross.Number+1)
+		if err != nil {
+			return cross, fmt.Errorf("failed to fetch block %d: %w", cross.Number+1, err)
+		}
+		if next.ParentHash != cross.Hash {
+			// The chain reorged while advancing, retry in the next update
+			return cross, nil
+		}
+		ok, err := t.checkBlock(ctx, next, minSafety)
+		if err != nil {
+			return cross, fmt.Errorf("failed to check executing messages of block %s: %w", next, err)
+		}
+		if !ok {
+			break
+		}
+		cross = next
+	}
+	return cross, nil
+}
+
+// checkBlock returns true if all the executing messages in the block are at least as safe as minSafety.
+func (t *CrossSafetyTracker) checkBlock(ctx context.Context, block eth.L2BlockRef, minSafety SafetyLevel) (bool, error) {
+	if !t.cfg.IsInterop(block.Time) {
+		return true, nil
+	}
+	_, receipts, err := t.l2.FetchReceipts(ctx, block.Hash)
+	if err != nil {
+		return false, err
+	}
+	msgs, err := ExecutingMessagesFromReceipts(receipts)
+	if err != nil {
+		return false, err
+	}
+	for _, msg := range msgs {
+		if msg.Identifier.Timestamp > block.Time {
+			t.log.Warn("Executing message refers to initiating message in the future", "block", block, "id", msg.Identifier)
+			return false, nil
+		}
+		lvl, err := t.supervisor.CheckMessage(ctx, msg.Identifier, msg.PayloadHash)
+		if err != nil {
+			return false, err
+		}
+		if lvl == Invalid {
+			t.log.Warn("Block contains invalid executing message", "block", block, "id", msg.Identifier, "payloadHash", msg.PayloadHash)
+			return false, nil
+		}
+		if !lvl.AtLeastAsSafe(minSafety) {
+			t.log.Debug("Executing message not yet safe enough", "block", block, "id", msg.Identifier, "safety", lvl, "required", minSafety)
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,157 @@
+package interop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+const (
+	localChainID  = 900
+	remoteChainID = 901
+)
+
+// setupCrossSafety creates a local chain that may execute messages from itself and a remote chain.
+// The local chain has 4 blocks before the Interop fork.
+func setupCrossSafety(t *testing.T) (*CrossSafetyTracker, *fakeChain, *fakeChain) {
+	interopTime := uint64(10)
+	cfg := &rollup.Config{BlockTime: 2, InteropTime: &interopTime}
+	local := newFakeChain(localChainID, 0)
+	remote := newFakeChain(remoteChainID, 0)
+	for i := 0; i < 4; i++ {
+		local.addBlock()
+	}
+	require.False(t, cfg.IsInterop(local.head().Time))
+	logger := testlog.Logger(t, log.LvlInfo)
+	supervisor := NewInProcessSupervisor(logger, map[uint64]ChainSource{
+		localChainID:  local,
+		remoteChainID: remote,
+	})
+	return NewCrossSafetyTracker(logger, cfg, local, supervisor), local, remote
+}
+
+// sendMessage adds a block with an initiating message to the chain, and returns the log that executes it.
+func sendMessage(chain *fakeChain, payload string) *types.Log {
+	initiating := &types.Log{Address: common.Address{0xaa}, Topics: []common.Hash{{0x01}}, Data: []byte(payload)}
+	block := chain.addBlock(initiating)
+	return executingMessageLog(Identifier{
+		Origin:      initiating.Address,
+		BlockNumber: block.Number,
+		LogIndex:    uint64(initiating.Index),
+		Timestamp:   block.Time,
+		ChainID:     chain.chainID,
+	}, PayloadHash(initiating))
+}
+
+func TestCrossSafetyTracker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("NotInitialized", func(t *testing.T) {
+		tracker, local, _ := setupCrossSafety(t)
+		require.NoError(t, tracker.Update(ctx, local.head(), local.head(), eth.L2BlockRef{}))
+		require.Zero(t, tracker.CrossUnsafe())
+		require.Zero(t, tracker.CrossSafe())
+	})
+
+	t.Run("PreInterop", func(t *testing.T) {
+		tracker, local, _ := setupCrossSafety(t)
+		require.NoError(t, tracker.Update(ctx, local.ref(4), local.ref(2), local.ref(0)))
+		require.Equal(t, local.ref(4), tracker.CrossUnsafe())
+		require.Equal(t, local.ref(2), tracker.CrossSafe())
+	})
+
+	t.Run("RemoteMessage", func(t *testing.T) {
+		tracker, local, remote := setupCrossSafety(t)
+		msg := sendMessage(remote, "hello")
+		block := local.addBlock(msg)
+		require.True(t, tracker.cfg.IsInterop(block.Time))
+
+		// The initiating message is only unsafe on the remote chain
+		require.NoError(t, tracker.Update(ctx, block, block, local.ref(0)))
+		require.Equal(t, block, tracker.CrossUnsafe())
+		require.Equal(t, local.ref(4), tracker.CrossSafe())
+
+		remote.safe = remote.head().Number
+		require.NoError(t, tracker.Update(ctx, block, block, local.ref(0)))
+		require.Equal(t, block, tracker.CrossUnsafe())
+		require.Equal(t, block, tracker.CrossSafe())
+	})
+
+	t.Run("LocalMessage", func(t *testing.T) {
+		tracker, local, _ := setupCrossSafety(t)
+		msg := sendMessage(local, "hello")
+		block := local.addBlock(msg)
+		local.safe = block.Number
+		require.NoError(t, tracker.Update(ctx, block, block, local.ref(0)))
+		require.Equal(t, block, tracker.CrossUnsafe())
+		require.Equal(t, block, tracker.CrossSafe())
+	})
+
+	t.Run("RemoteBlockNotYetSynced", func(t *testing.T) {
+		tracker, local, remote := setupCrossSafety(t)
+		msg := sendMessage(remote, "hello")
+		remote.reorg(0)
+		block := local.addBlock(msg)
+		require.NoError(t, tracker.Update(ctx, block, block, local.ref(0)))
+		require.Equal(t, local.ref(4), tracker.CrossUnsafe())
+		require.Equal(t, local.ref(4), tracker.CrossSafe())
+	})
+
+	t.Run("InvalidMessage", func(t *testing.T) {
+		tracker, local, remote := setupCrossSafety(t)
+		msg := sendMessage(remote, "hello")
+		msg.Topics[1] = common.Hash{0xff}
+		invalid := local.addBlock(msg)
+		local.addBlock()
+		require.NoError(t, tracker.Update(ctx, local.head(), local.head(), local.ref(0)))
+		require.Equal(t, local.ref(invalid.Number-1), tracker.CrossUnsafe())
+		require.Equal(t, local.ref(invalid.Number-1), tracker.CrossSafe())
+	})
+
+	t.Run("MessageFromFuture", func(t *testing.T) {
+		tracker, local, remote := setupCrossSafety(t)
+		for i := 0; i < 10; i++ {
+			remote.addBlock()
+		}
+		msg := sendMessage(remote, "hello")
+		block := local.addBlock(msg)
+		require.Greater(t, remote.head().Time, block.Time)
+		require.NoError(t, tracker.Update(ctx, block, block, local.ref(0)))
+		require.Equal(t, local.ref(4), tracker.CrossUnsafe())
+	})
+
+	t.Run("Reorg", func(t *testing.T) {
+		tracker, local, remote := setupCrossSafety(t)
+		local.addBlock()
+		require.NoError(t, tracker.Update(ctx, local.head(), local.ref(2), local.ref(0)))
+		require.Equal(t, local.ref(5), tracker.CrossUnsafe())
+
+		local.reorg(4)
+		msg := sendMessage(remote, "hello")
+		msg.Topics[1] = common.Hash{0xff}
+		local.addBlockOnFork(1, msg)
+		local.addBlockOnFork(1)
+		require.NoError(t, tracker.Update(ctx, local.head(), local.ref(2), local.ref(0)))
+		require.Equal(t, local.ref(4), tracker.CrossUnsafe())
+		require.Equal(t, local.ref(2), tracker.CrossSafe())
+	})
+
+	t.Run("LimitBlocksPerUpdate", func(t *testing.T) {
+		tracker, local, _ := setupCrossSafety(t)
+		for i := 0; i < maxBlocksPerUpdate; i++ {
+			local.addBlock()
+		}
+		require.NoError(t, tracker.Update(ctx, local.head(), local.ref(0), local.ref(0)))
+		require.Equal(t, local.ref(maxBlocksPerUpdate), tracker.CrossUnsafe())
+		require.NoError(t, tracker.Update(ctx, local.head(), local.ref(0), local.ref(0)))
+		require.Equal(t, local.head(), tracker.CrossUnsafe())
+	})
+}
@@ -0,0 +1,115 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+// Supervisor checks initiating messages on the chains in the dependency set.
+type Supervisor interface {
+	// CheckMessage returns the safety level of the initiating message identified by id,
+	// or Invalid if the initiating message does not exist or does not match payloadHash.
+	CheckMessage(ctx context.Context, id Identifier, payloadHash common.Hash) (SafetyLevel, error)
+}
+
+// ChainSource provides the blocks and receipts of a chain in the dependency set.
+// The safe and finalized labels reflect the forkchoice state the op-node of that chain applied to its execution engine.
+type ChainSource interface {
+	InfoByNumber(ctx context.Context, number uint64) (eth.BlockInfo, error)
+	InfoByLabel(ctx context.Context, label eth.BlockLabel) (eth.BlockInfo, error)
+	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
+}
+
+// InProcessSupervisor is a Supervisor that checks initiating messages directly against the nodes of the other chains.
+type InProcessSupervisor struct {
+	log    log.Logger
+	chains map[uint64]ChainSource
+}
+
+var _ Supervisor = (*InProcessSupervisor)(nil)
+
+func NewInProcessSupervisor(log log.Logger, chains map[uint64]ChainSource) *InProcessSupervisor {
+	return &InProcessSupervisor{
+		log:    log,
+		chains: chains,
+	}
+}
+
+func (s *InProcessSupervisor) CheckMessage(ctx context.Context, id Identifier, payloadHash common.Hash) (SafetyLevel, error) {
+	chain, ok := s.chains[id.ChainID]
+	if !ok {
+		s.log.Warn("Executing message from chain outside of dependency set", "id", id)
+		return Invalid, nil
+	}
+	info, err := chain.InfoByNumber(ctx, id.BlockNumber)
+	if errors.Is(err, ethereum.NotFound) {
+		// The other chain may not have synced the block yet
+		return Unknown, nil
+	} else if err != nil {
+		return Unknown, fmt.Errorf("failed to fetch block %d of chain %d: %w", id.BlockNumber, id.ChainID, err)
+	}
+	if info.Time() != id.Timestamp {
+		s.log.Warn("Executing message timestamp does not match initiating block", "id", id, "time", info.Time())
+		return Invalid, nil
+	}
+	_, receipts, err := chain.FetchReceipts(ctx, info.Hash())
+	if err != nil {
+		return Unknown, fmt.Errorf("failed to fetch receipts of block %s of chain %d: %w", info.Hash(), id.ChainID, err)
+	}
+	initiating := findLog(receipts, id.LogIndex)
+	if initiating == nil {
+		s.log.Warn("Initiating message log does not exist", "id", id)
+		return Invalid, nil
+	}
+	if initiating.Address != id.Origin {
+		s.log.Warn("Initiating message emitted by different origin", "id", id, "origin", initiating.Address)
+		return Invalid, nil
+	}
+	if actual := PayloadHash(initiating); actual != payloadHash {
+		s.log.Warn("Initiating message payload does not match", "id", id, "expected", payloadHash, "actual", actual)
+		return Invalid, nil
+	}
+	return s.safetyLevel(ctx, chain, id.BlockNumber)
+}
+
+// safetyLevel determines the safety of the block at number, which must be canonical on the chain.
+func (s *InProcessSupervisor) safetyLevel(ctx context.Context, chain ChainSource, number uint64) (SafetyLevel, error) {
+	for _, check := range []struct {
+		label eth.BlockLabel
+		level SafetyLevel
+	}{
+		{label: eth.Finalized, level: Finalized},
+		{label: eth.Safe, level: Safe},
+	} {
+		head, err := chain.InfoByLabel(ctx, check.label)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		} else if err != nil {
+			return Unknown, fmt.Errorf("failed to fetch %s head: %w", check.label, err)
+		}
+		if head.NumberU64() >= number {
+			return check.level, nil
+		}
+	}
+	return Unsafe, nil
+}
+
+// findLog returns the log with the given block-level log index, or nil if there is no such log.
+func findLog(receipts types.Receipts, logIndex uint64) *types.Log {
+	for _, rec := range receipts {
+		for _, l := range rec.Logs {
+			if uint64(l.Index) == logIndex {
+				return l
+			}
+		}
+	}
+	return nil
+}
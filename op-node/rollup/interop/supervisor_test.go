@@ -0,0 +1,204 @@
+package interop
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+func TestInProcessSupervisor(t *testing.T) {
+	origin := common.Address{0xaa}
+	chain := newFakeChain(901, 0)
+	chain.addBlock()
+	initiating := &types.Log{Address: origin, Topics: []common.Hash{{0x01}, {0x02}}, Data: []byte("hello")}
+	block := chain.addBlock(&types.Log{Address: common.Address{0x01}}, initiating)
+	validID := Identifier{
+		Origin:      origin,
+		BlockNumber: block.Number,
+		LogIndex:    1,
+		Timestamp:   block.Time,
+		ChainID:     901,
+	}
+	payloadHash := PayloadHash(initiating)
+	supervisor := NewInProcessSupervisor(testlog.Logger(t, log.LvlInfo), map[uint64]ChainSource{901: chain})
+
+	check := func(t *testing.T, id Identifier, payloadHash common.Hash, expected SafetyLevel) {
+		lvl, err := supervisor.CheckMessage(context.Background(), id, payloadHash)
+		require.NoError(t, err)
+		require.Equal(t, expected, lvl)
+	}
+
+	t.Run("Unsafe", func(t *testing.T) {
+		check(t, validID, payloadHash, Unsafe)
+	})
+
+	t.Run("Safe", func(t *testing.T) {
+		chain.safe = block.Number
+		check(t, validID, payloadHash, Safe)
+	})
+
+	t.Run("Finalized", func(t *testing.T) {
+		chain.finalized = block.Number
+		check(t, validID, payloadHash, Finalized)
+	})
+
+	t.Run("UnknownBlock", func(t *testing.T) {
+		id := validID
+		id.BlockNumber = 10
+		check(t, id, payloadHash, Unknown)
+	})
+
+	t.Run("ChainNotInDependencySet", func(t *testing.T) {
+		id := validID
+		id.ChainID = 902
+		check(t, id, payloadHash, Invalid)
+	})
+
+	t.Run("WrongTimestamp", func(t *testing.T) {
+		id := validID
+		id.Timestamp++
+		check(t, id, payloadHash, Invalid)
+	})
+
+	t.Run("WrongLogIndex", func(t *testing.T) {
+		id := validID
+		id.LogIndex = 0
+		check(t, id, payloadHash, Invalid)
+		id.LogIndex = 2
+		check(t, id, payloadHash, Invalid)
+	})
+
+	t.Run("WrongOrigin", func(t *testing.T) {
+		id := validID
+		id.Origin = common.Address{0xbb}
+		check(t, id, payloadHash, Invalid)
+	})
+
+	t.Run("WrongPayloadHash", func(t *testing.T) {
+		check(t, validID, common.Hash{0xcc}, Invalid)
+	})
+}
+
+type fakeBlock struct {
+	ref      eth.L2BlockRef
+	receipts types.Receipts
+}
+
+// fakeChain is an in-memory chain that serves as both the local chain and as chains in the dependency set.
+type fakeChain struct {
+	chainID   uint64
+	genesis   uint64
+	blocks    []fakeBlock
+	safe      uint64
+	finalized uint64
+}
+
+var (
+	_ ChainSource = (*fakeChain)(nil)
+	_ L2Source    = (*fakeChain)(nil)
+)
+
+func newFakeChain(chainID uint64, genesisTime uint64) *fakeChain {
+	c := &fakeChain{chainID: chainID, genesis: genesisTime}
+	c.blocks = append(c.blocks, fakeBlock{ref: eth.L2BlockRef{
+		Hash: c.blockHash(0, 0),
+		Time: genesisTime,
+	}})
+	return c
+}
+
+func (c *fakeChain) blockHash(num uint64, fork byte) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(c.chainID<<32 | uint64(fork)<<24 | num))
+}
+
+// addBlock adds a block, with a single successful receipt containing the logs, to the chain.
+func (c *fakeChain) addBlock(logs ...*types.Log) eth.L2BlockRef {
+	return c.addBlockOnFork(0, logs...)
+}
+
+func (c *fakeChain) addBlockOnFork(fork byte, logs ...*types.Log) eth.L2BlockRef {
+	parent := c.head()
+	ref := eth.L2BlockRef{
+		Hash:       c.blockHash(parent.Number+1, fork),
+		Number:     parent.Number + 1,
+		ParentHash: parent.Hash,
+		Time:       parent.Time + 2,
+	}
+	for i, l := range logs {
+		l.Index = uint(i)
+		l.BlockNumber = ref.Number
+		l.BlockHash = ref.Hash
+	}
+	receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: logs}}
+	c.blocks = append(c.blocks, fakeBlock{ref: ref, receipts: receipts})
+	return ref
+}
+
+// reorg removes all blocks after num from the chain.
+func (c *fakeChain) reorg(num uint64) {
+	c.blocks = c.blocks[:num+1]
+}
+
+func (c *fakeChain) head() eth.L2BlockRef {
+	return c.blocks[len(c.blocks)-1].ref
+}
+
+func (c *fakeChain) ref(num uint64) eth.L2BlockRef {
+	return c.blocks[num].ref
+}
+
+func (c *fakeChain) info(ref eth.L2BlockRef) eth.BlockInfo {
+	return &testutils.MockBlockInfo{
+		InfoHash:       ref.Hash,
+		InfoParentHash: ref.ParentHash,
+		InfoNum:        ref.Number,
+		InfoTime:       ref.Time,
+	}
+}
+
+func (c *fakeChain) L2BlockRefByNumber(_ context.Context, num uint64) (eth.L2BlockRef, error) {
+	if num >= uint64(len(c.blocks)) {
+		return eth.L2BlockRef{}, ethereum.NotFound
+	}
+	return c.blocks[num].ref, nil
+}
+
+func (c *fakeChain) InfoByNumber(ctx context.Context, num uint64) (eth.BlockInfo, error) {
+	ref, err := c.L2BlockRefByNumber(ctx, num)
+	if err != nil {
+		return nil, err
+	}
+	return c.info(ref), nil
+}
+
+func (c *fakeChain) InfoByLabel(ctx context.Context, label eth.BlockLabel) (eth.BlockInfo, error) {
+	switch label {
+	case eth.Unsafe:
+		return c.info(c.head()), nil
+	case eth.Safe:
+		return c.InfoByNumber(ctx, c.safe)
+	case eth.Finalized:
+		return c.InfoByNumber(ctx, c.finalized)
+	default:
+		return nil, ethereum.NotFound
+	}
+}
+
+func (c *fakeChain) FetchReceipts(_ context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	for _, block := range c.blocks {
+		if block.ref.Hash == blockHash {
+			return c.info(block.ref), block.receipts, nil
+		}
+	}
+	return nil, nil, ethereum.NotFound
+}
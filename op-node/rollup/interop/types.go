@@ -0,0 +1,164 @@
+package interop
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+var (
+	// CrossL2InboxAddr is the address of the predeploy that emits an ExecutingMessage event
+	// for every executed cross-chain message.
+	CrossL2InboxAddr = common.HexToAddress("0x4200000000000000000000000000000000000022")
+
+	// ExecutingMessageEventABI is the signature of the event emitted by the CrossL2Inbox.
+	ExecutingMessageEventABI     = "ExecutingMessage(bytes32,(address,uint256,uint256,uint256,uint256))"
+	ExecutingMessageEventABIHash = crypto.Keccak256Hash([]byte(ExecutingMessageEventABI))
+
+	ErrInvalidExecutingMessage = errors.New("invalid executing message log")
+)
+
+// identifierDataSize is the size of the ABI encoded Identifier tuple, which is the (only) non-indexed event argument.
+const identifierDataSize = 5 * 32
+
+// SafetyLevel describes how safe an initiating message is, as seen by the chain it was emitted on.
+type SafetyLevel string
+
+const (
+	// Invalid indicates the initiating message does not exist, or does not match the executing message.
+	Invalid SafetyLevel = "invalid"
+	// Unknown indicates the initiating message could not be found yet, but may still appear.
+	Unknown SafetyLevel = "unknown"
+	// Unsafe indicates the initiating message is included in the unsafe chain.
+	Unsafe SafetyLevel = "unsafe"
+	// Safe indicates the initiating message is included in the safe chain.
+	Safe SafetyLevel = "safe"
+	// Finalized indicates the initiating message is included in the finalized chain.
+	Finalized SafetyLevel = "finalized"
+)
+
+func (lvl SafetyLevel) String() string {
+	return string(lvl)
+}
+
+func (lvl SafetyLevel) rank() int {
+	switch lvl {
+	case Unsafe:
+		return 1
+	case Safe:
+		return 2
+	case Finalized:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// AtLeastAsSafe returns true if the level is at least as safe as min.
+// Invalid and Unknown messages are never considered safe.
+func (lvl SafetyLevel) AtLeastAsSafe(min SafetyLevel) bool {
+	return lvl.rank() > 0 && lvl.rank() >= min.rank()
+}
+
+// Identifier uniquely identifies an initiating message: a log emitted on a chain in the dependency set.
+type Identifier struct {
+	Origin      common.Address `json:"origin"`
+	BlockNumber uint64         `json:"blockNumber"`
+	LogIndex    uint64         `json:"logIndex"`
+	Timestamp   uint64         `json:"timestamp"`
+	ChainID     uint64         `json:"chainID"`
+}
+
+func (id Identifier) String() string {
+	return fmt.Sprintf("chain %d, block %d, log %d, origin %s", id.ChainID, id.BlockNumber, id.LogIndex, id.Origin)
+}
+
+// ExecutingMessage is a cross-chain message executed on the local chain, which is valid only if the initiating message
+// it refers to exists on the chain identified by the Identifier with the same payload hash.
+type ExecutingMessage struct {
+	Identifier Identifier
+	// PayloadHash is the hash of the topics and data of the initiating message log.
+	PayloadHash common.Hash
+}
+
+// DecodeExecutingMessage decodes an ExecutingMessage event emitted by the CrossL2Inbox.
+// It returns nil, without error, if the log is not an ExecutingMessage event.
+func DecodeExecutingMessage(l *types.Log) (*ExecutingMessage, error) {
+	if l.Address != CrossL2InboxAddr || len(l.Topics) == 0 || l.Topics[0] != ExecutingMessageEventABIHash {
+		return nil, nil
+	}
+	if len(l.Topics) != 2 {
+		return nil, fmt.Errorf("%w: expected 2 topics but got %d", ErrInvalidExecutingMessage, len(l.Topics))
+	}
+	if len(l.Data) != identifierDataSize {
+		return nil, fmt.Errorf("%w: expected %d bytes of data but got %d", ErrInvalidExecutingMessage, identifierDataSize, len(l.Data))
+	}
+	var origin common.Address
+	if !isZero(l.Data[:12]) {
+		return nil, fmt.Errorf("%w: origin is not an address", ErrInvalidExecutingMessage)
+	}
+	copy(origin[:], l.Data[12:32])
+	var fields [4]uint64
+	for i := range fields {
+		word := l.Data[32*(i+1) : 32*(i+2)]
+		v := new(uint256.Int).SetBytes(word)
+		if !v.IsUint64() {
+			return nil, fmt.Errorf("%w: identifier field %d overflows uint64", ErrInvalidExecutingMessage, i+1)
+		}
+		fields[i] = v.Uint64()
+	}
+	return &ExecutingMessage{
+		Identifier: Identifier{
+			Origin:      origin,
+			BlockNumber: fields[0],
+			LogIndex:    fields[1],
+			Timestamp:   fields[2],
+			ChainID:     fields[3],
+		},
+		PayloadHash: l.Topics[1],
+	}, nil
+}
+
+// ExecutingMessagesFromReceipts decodes all the executing messages from the receipts of a block.
+// Logs of failed transactions are not included in receipts, so are ignored.
+func ExecutingMessagesFromReceipts(receipts types.Receipts) ([]*ExecutingMessage, error) {
+	var msgs []*ExecutingMessage
+	for i, rec := range receipts {
+		if rec.Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		for j, l := range rec.Logs {
+			msg, err := DecodeExecutingMessage(l)
+			if err != nil {
+				return nil, fmt.Errorf("malformed executing message in log %d of receipt %d: %w", j, i, err)
+			}
+			if msg != nil {
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+	return msgs, nil
+}
+
+// PayloadHash computes the hash of an initiating message log, committing to its topics and data.
+func PayloadHash(l *types.Log) common.Hash {
+	msg := make([]byte, 0, 32*len(l.Topics)+len(l.Data))
+	for _, topic := range l.Topics {
+		msg = append(msg, topic.Bytes()...)
+	}
+	msg = append(msg, l.Data...)
+	return crypto.Keccak256Hash(msg)
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
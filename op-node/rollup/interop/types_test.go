@@ -0,0 +1,119 @@
+package interop
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeExecutingMessage(t *testing.T) {
+	id := Identifier{
+		Origin:      common.Address{0xaa},
+		BlockNumber: 12,
+		LogIndex:    3,
+		Timestamp:   1000,
+		ChainID:     901,
+	}
+	payloadHash := common.Hash{0xbb}
+
+	t.Run("Valid", func(t *testing.T) {
+		msg, err := DecodeExecutingMessage(executingMessageLog(id, payloadHash))
+		require.NoError(t, err)
+		require.Equal(t, &ExecutingMessage{Identifier: id, PayloadHash: payloadHash}, msg)
+	})
+
+	t.Run("IgnoreOtherAddress", func(t *testing.T) {
+		l := executingMessageLog(id, payloadHash)
+		l.Address = common.Address{0x01}
+		msg, err := DecodeExecutingMessage(l)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+	})
+
+	t.Run("IgnoreOtherEvent", func(t *testing.T) {
+		l := executingMessageLog(id, payloadHash)
+		l.Topics[0] = common.Hash{0x01}
+		msg, err := DecodeExecutingMessage(l)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+	})
+
+	t.Run("MissingPayloadHash", func(t *testing.T) {
+		l := executingMessageLog(id, payloadHash)
+		l.Topics = l.Topics[:1]
+		_, err := DecodeExecutingMessage(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessage)
+	})
+
+	t.Run("TruncatedData", func(t *testing.T) {
+		l := executingMessageLog(id, payloadHash)
+		l.Data = l.Data[:len(l.Data)-1]
+		_, err := DecodeExecutingMessage(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessage)
+	})
+
+	t.Run("InvalidOrigin", func(t *testing.T) {
+		l := executingMessageLog(id, payloadHash)
+		l.Data[0] = 1
+		_, err := DecodeExecutingMessage(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessage)
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		l := executingMessageLog(id, payloadHash)
+		l.Data[32] = 1
+		_, err := DecodeExecutingMessage(l)
+		require.ErrorIs(t, err, ErrInvalidExecutingMessage)
+	})
+}
+
+func TestExecutingMessagesFromReceipts(t *testing.T) {
+	id1 := Identifier{ChainID: 901, BlockNumber: 1}
+	id2 := Identifier{ChainID: 902, BlockNumber: 2}
+	id3 := Identifier{ChainID: 903, BlockNumber: 3}
+	receipts := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{
+			{Address: common.Address{0x01}, Topics: []common.Hash{{0x02}}},
+			executingMessageLog(id1, common.Hash{0x01}),
+		}},
+		{Status: types.ReceiptStatusFailed, Logs: []*types.Log{
+			executingMessageLog(id2, common.Hash{0x02}),
+		}},
+		{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{
+			executingMessageLog(id3, common.Hash{0x03}),
+		}},
+	}
+	msgs, err := ExecutingMessagesFromReceipts(receipts)
+	require.NoError(t, err)
+	require.Equal(t, []*ExecutingMessage{
+		{Identifier: id1, PayloadHash: common.Hash{0x01}},
+		{Identifier: id3, PayloadHash: common.Hash{0x03}},
+	}, msgs)
+}
+
+func TestSafetyLevel(t *testing.T) {
+	require.True(t, Finalized.AtLeastAsSafe(Safe))
+	require.True(t, Safe.AtLeastAsSafe(Safe))
+	require.True(t, Safe.AtLeastAsSafe(Unsafe))
+	require.False(t, Unsafe.AtLeastAsSafe(Safe))
+	require.False(t, Unknown.AtLeastAsSafe(Unsafe))
+	require.False(t, Invalid.AtLeastAsSafe(Unsafe))
+	require.False(t, Invalid.AtLeastAsSafe(Invalid))
+}
+
+// executingMessageLog creates the log emitted by the CrossL2Inbox when executing a message.
+func executingMessageLog(id Identifier, payloadHash common.Hash) *types.Log {
+	data := make([]byte, 0, identifierDataSize)
+	data = append(data, common.LeftPadBytes(id.Origin.Bytes(), 32)...)
+	for _, v := range []uint64{id.BlockNumber, id.LogIndex, id.Timestamp, id.ChainID} {
+		data = append(data, common.BigToHash(new(big.Int).SetUint64(v)).Bytes()...)
+	}
+	return &types.Log{
+		Address: CrossL2InboxAddr,
+		Topics:  []common.Hash{ExecutingMessageEventABIHash, payloadHash},
+		Data:    data,
+	}
+}
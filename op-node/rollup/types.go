@@ -34,6 +34,8 @@ var (
 	ErrChainIDsSame                  = errors.New("L1 and L2 chain IDs must be different")
 	ErrL1ChainIDNotPositive          = errors.New("L1 chain ID must be non-zero and positive")
 	ErrL2ChainIDNotPositive          = errors.New("L2 chain ID must be non-zero and positive")
+	ErrMissingDAChallengeWindow      = errors.New("plasma mode requires a DA challenge window")
+	ErrMissingDAResolveWindow        = errors.New("plasma mode requires a DA resolve window")
 )
 
 type Genesis struct {
@@ -110,6 +112,20 @@ type Config struct {
 
 	// L1 block timestamp to start reading blobs as batch data-source. Optional.
 	BlobsEnabledL1Timestamp *uint64 `json:"blobs_data,omitempty"`
+
+	// PlasmaConfig enables the plasma mode if set, in which batcher transactions may carry commitments to
+	// batch data stored on an external DA server, instead of the batch data itself. Optional.
+	PlasmaConfig *PlasmaConfig `json:"plasma_config,omitempty"`
+}
+
+// PlasmaConfig configures the windows during which the availability of the data committed to on L1 may be disputed.
+type PlasmaConfig struct {
+	// DAChallengeWindow is the number of L1 blocks after the inclusion of a commitment,
+	// during which the availability of its data may be challenged.
+	DAChallengeWindow uint64 `json:"da_challenge_window"`
+	// DAResolveWindow is the number of L1 blocks after a challenge,
+	// during which the challenged data must be made available.
+	DAResolveWindow uint64 `json:"da_resolve_window"`
 }
 
 // ValidateL1Config checks L1 config variables for errors.
@@ -274,6 +290,14 @@ func (cfg *Config) Check() error {
 	if cfg.L2ChainID.Sign() < 1 {
 		return ErrL2ChainIDNotPositive
 	}
+	if cfg.PlasmaConfig != nil {
+		if cfg.PlasmaConfig.DAChallengeWindow == 0 {
+			return ErrMissingDAChallengeWindow
+		}
+		if cfg.PlasmaConfig.DAResolveWindow == 0 {
+			return ErrMissingDAResolveWindow
+		}
+	}
 	return nil
 }
 
@@ -319,6 +343,20 @@ func (c *Config) IsInterop(timestamp uint64) bool {
 	return c.InteropTime != nil && timestamp >= *c.InteropTime
 }
 
+// UsePlasma returns true if batcher transactions may carry commitments to data stored on a DA server.
+func (c *Config) UsePlasma() bool {
+	return c.PlasmaConfig != nil
+}
+
+// PlasmaFinalityDelay returns the number of L1 blocks, after the L1 block an L2 block was derived from is finalized,
+// before the L2 block may be finalized. The data of a commitment may be challenged, and resolved, within this delay.
+func (c *Config) PlasmaFinalityDelay() uint64 {
+	if c.PlasmaConfig == nil {
+		return 0
+	}
+	return c.PlasmaConfig.DAChallengeWindow + c.PlasmaConfig.DAResolveWindow
+}
+
 // Description outputs a banner describing the important parts of rollup configuration in a human-readable form.
 // Optionally provide a mapping of L2 chain IDs to network names to label the L2 chain with if not unknown.
 // The config should be config.Check()-ed before creating a description.
@@ -351,6 +389,10 @@ func (c *Config) Description(l2Chains map[string]string) string {
 	banner += fmt.Sprintf("  - Ecotone: %s\n", fmtForkTimeOrUnset(c.EcotoneTime))
 	banner += fmt.Sprintf("  - Fjord: %s\n", fmtForkTimeOrUnset(c.FjordTime))
 	banner += fmt.Sprintf("  - Interop: %s\n", fmtForkTimeOrUnset(c.InteropTime))
+	if c.PlasmaConfig != nil {
+		banner += fmt.Sprintf("Plasma mode: challenge window %d, resolve window %d L1 blocks\n",
+			c.PlasmaConfig.DAChallengeWindow, c.PlasmaConfig.DAResolveWindow)
+	}
 	// Report the protocol version
 	banner += fmt.Sprintf("Node supports up to OP-Stack Protocol Version: %s\n", OPStackSupport)
 	return banner
@@ -381,6 +423,7 @@ func (c *Config) LogDescription(log log.Logger, l2Chains map[string]string) {
 		"ecotone_time", fmtForkTimeOrUnset(c.EcotoneTime),
 		"fjord_time", fmtForkTimeOrUnset(c.FjordTime),
 		"interop_time", fmtForkTimeOrUnset(c.InteropTime),
+		"plasma", c.UsePlasma(),
 	)
 }
 
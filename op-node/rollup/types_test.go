@@ -386,6 +386,16 @@ func TestConfig_Check(t *testing.T) {
 			modifier:    func(cfg *Config) { cfg.L2ChainID = big.NewInt(0) },
 			expectedErr: ErrL2ChainIDNotPositive,
 		},
+		{
+			name:        "PlasmaMissingChallengeWindow",
+			modifier:    func(cfg *Config) { cfg.PlasmaConfig = &PlasmaConfig{DAResolveWindow: 10} },
+			expectedErr: ErrMissingDAChallengeWindow,
+		},
+		{
+			name:        "PlasmaMissingResolveWindow",
+			modifier:    func(cfg *Config) { cfg.PlasmaConfig = &PlasmaConfig{DAChallengeWindow: 10} },
+			expectedErr: ErrMissingDAResolveWindow,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
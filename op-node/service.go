@@ -22,7 +22,9 @@ import (
 	p2pcli "github.com/ethereum-optimism/optimism/op-node/p2p/cli"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/interop"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
+	plasma "github.com/ethereum-optimism/optimism/op-plasma"
 	opflags "github.com/ethereum-optimism/optimism/op-service/flags"
 )
 
@@ -68,6 +70,11 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		return nil, fmt.Errorf("failed to create the sync config: %w", err)
 	}
 
+	dependencySet, err := NewDependencySet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	haltOption := ctx.String(flags.RollupHalt.Name)
 	if haltOption == "none" {
 		haltOption = ""
@@ -102,6 +109,9 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		Sync:              *syncConfig,
 		RollupHalt:        haltOption,
 		RethDBPath:        ctx.String(flags.L1RethDBPath.Name),
+		DependencySet:     dependencySet,
+		SafeDBPath:        ctx.String(flags.SafeDBPath.Name),
+		Plasma:            plasma.ReadCLIConfig(ctx),
 	}
 
 	if err := cfg.LoadPersisted(log); err != nil {
@@ -114,6 +124,15 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 	return cfg, nil
 }
 
+// NewDependencySet loads the interop dependency set, or returns nil if none is configured.
+func NewDependencySet(ctx *cli.Context) (*interop.DependencySet, error) {
+	path := ctx.String(flags.InteropDependencySet.Name)
+	if path == "" {
+		return nil, nil
+	}
+	return interop.LoadDependencySet(path)
+}
+
 func NewL1EndpointConfig(ctx *cli.Context) *node.L1EndpointConfig {
 	return &node.L1EndpointConfig{
 		L1NodeAddr:       ctx.String(flags.L1NodeAddr.Name),
@@ -171,6 +190,14 @@ func NewDriverConfig(ctx *cli.Context) *driver.Config {
 		SequencerEnabled:    ctx.Bool(flags.SequencerEnabledFlag.Name),
 		SequencerStopped:    ctx.Bool(flags.SequencerStoppedFlag.Name),
 		SequencerMaxSafeLag: ctx.Uint64(flags.SequencerMaxSafeLagFlag.Name),
+
+		SequencerForcedTxsPath:    ctx.String(flags.SequencerForcedTxsFlag.Name),
+		SequencerGasReservation:   ctx.Uint64(flags.SequencerGasReservationFlag.Name),
+		SequencerTxBuilderURL:     ctx.String(flags.SequencerTxBuilderURLFlag.Name),
+		SequencerTxBuilderTimeout: ctx.Duration(flags.SequencerTxBuilderTimeoutFlag.Name),
+
+		SequencerPayloadBuilderURL:     ctx.String(flags.SequencerPayloadBuilderURLFlag.Name),
+		SequencerPayloadBuilderTimeout: ctx.Duration(flags.SequencerPayloadBuilderTimeoutFlag.Name),
 	}
 }
 
@@ -0,0 +1,62 @@
+package plasma
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/urfave/cli/v2"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+)
+
+const (
+	DaServerAddressFlagName = "plasma.da-server"
+	VerifyOnReadFlagName    = "plasma.verify-on-read"
+)
+
+func CLIFlags(envPrefix string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    DaServerAddressFlagName,
+			Usage:   "HTTP address of the DA server storing the inputs committed to on L1. Required in plasma mode.",
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "PLASMA_DA_SERVER"),
+		},
+		&cli.BoolFlag{
+			Name:    VerifyOnReadFlagName,
+			Usage:   "Verify that the inputs retrieved from the DA server match their commitment",
+			Value:   true,
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "PLASMA_VERIFY_ON_READ"),
+		},
+	}
+}
+
+type CLIConfig struct {
+	DAServerURL  string
+	VerifyOnRead bool
+}
+
+// Enabled returns true if a DA server is configured.
+func (c CLIConfig) Enabled() bool {
+	return c.DAServerURL != ""
+}
+
+func (c CLIConfig) Check() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if _, err := url.ParseRequestURI(c.DAServerURL); err != nil {
+		return fmt.Errorf("invalid DA server url %q: %w", c.DAServerURL, err)
+	}
+	return nil
+}
+
+func (c CLIConfig) NewDAClient() *DAClient {
+	return NewDAClient(c.DAServerURL, c.VerifyOnRead)
+}
+
+func ReadCLIConfig(ctx *cli.Context) CLIConfig {
+	return CLIConfig{
+		DAServerURL:  ctx.String(DaServerAddressFlagName),
+		VerifyOnRead: ctx.Bool(VerifyOnReadFlagName),
+	}
+}
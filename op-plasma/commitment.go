@@ -0,0 +1,79 @@
+package plasma
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrInvalidCommitment is returned when the commitment cannot be decoded into a known commitment type.
+	ErrInvalidCommitment = errors.New("invalid commitment")
+	// ErrCommitmentMismatch is returned when the commitment does not match the input it commits to.
+	ErrCommitmentMismatch = errors.New("commitment mismatch")
+)
+
+// TxDataVersion1 is the version byte of batcher transaction data that carries a DA commitment.
+// Batcher transactions carrying frames directly are prefixed with derivation version 0 instead,
+// so both kinds of batcher transactions can be told apart by their first byte.
+const TxDataVersion1 = 1
+
+// CommitmentType identifies the scheme used to commit to the input stored on the DA server.
+type CommitmentType byte
+
+const (
+	// Keccak256CommitmentType commits to the input by its keccak256 hash.
+	Keccak256CommitmentType CommitmentType = 0
+)
+
+// Keccak256Commitment is the keccak256 hash of the input stored on the DA server.
+type Keccak256Commitment common.Hash
+
+// Keccak256 computes the commitment to the given input.
+func Keccak256(input []byte) Keccak256Commitment {
+	return Keccak256Commitment(crypto.Keccak256Hash(input))
+}
+
+// DecodeKeccak256 decodes an encoded commitment, i.e. the commitment type byte followed by the hash.
+func DecodeKeccak256(commitment []byte) (Keccak256Commitment, error) {
+	if len(commitment) != 1+common.HashLength {
+		return Keccak256Commitment{}, fmt.Errorf("%w: unexpected length %d", ErrInvalidCommitment, len(commitment))
+	}
+	if CommitmentType(commitment[0]) != Keccak256CommitmentType {
+		return Keccak256Commitment{}, fmt.Errorf("%w: unknown commitment type %d", ErrInvalidCommitment, commitment[0])
+	}
+	return Keccak256Commitment(common.BytesToHash(commitment[1:])), nil
+}
+
+// DecodeTxData decodes the commitment from batcher transaction data, as produced by TxData.
+func DecodeTxData(data []byte) (Keccak256Commitment, error) {
+	if len(data) == 0 || data[0] != TxDataVersion1 {
+		return Keccak256Commitment{}, fmt.Errorf("%w: not a version %d tx data", ErrInvalidCommitment, TxDataVersion1)
+	}
+	return DecodeKeccak256(data[1:])
+}
+
+// Encode returns the commitment type byte followed by the hash.
+// The encoded commitment is used as key on the DA server.
+func (c Keccak256Commitment) Encode() []byte {
+	return append([]byte{byte(Keccak256CommitmentType)}, c[:]...)
+}
+
+// TxData returns the data of the batcher transaction that submits the commitment to L1.
+func (c Keccak256Commitment) TxData() []byte {
+	return append([]byte{TxDataVersion1}, c.Encode()...)
+}
+
+// Verify checks that the commitment matches the given input.
+func (c Keccak256Commitment) Verify(input []byte) error {
+	if Keccak256(input) != c {
+		return ErrCommitmentMismatch
+	}
+	return nil
+}
+
+func (c Keccak256Commitment) String() string {
+	return common.Hash(c).String()
+}
@@ -0,0 +1,49 @@
+package plasma
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeccak256CommitmentEncoding(t *testing.T) {
+	input := []byte("hello plasma")
+	comm := Keccak256(input)
+	require.NoError(t, comm.Verify(input))
+	require.ErrorIs(t, comm.Verify([]byte("other input")), ErrCommitmentMismatch)
+
+	encoded := comm.Encode()
+	require.Len(t, encoded, 1+common.HashLength)
+	require.Equal(t, byte(Keccak256CommitmentType), encoded[0])
+	decoded, err := DecodeKeccak256(encoded)
+	require.NoError(t, err)
+	require.Equal(t, comm, decoded)
+
+	txData := comm.TxData()
+	require.Equal(t, byte(TxDataVersion1), txData[0])
+	decoded, err = DecodeTxData(txData)
+	require.NoError(t, err)
+	require.Equal(t, comm, decoded)
+}
+
+func TestDecodeInvalidCommitment(t *testing.T) {
+	comm := Keccak256([]byte("hello plasma"))
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty", data: nil},
+		{name: "FrameVersion", data: append([]byte{0}, comm.Encode()...)},
+		{name: "Truncated", data: comm.TxData()[:20]},
+		{name: "TooLong", data: append(comm.TxData(), 0)},
+		{name: "UnknownType", data: append([]byte{TxDataVersion1, 0xff}, comm[:]...)},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeTxData(test.data)
+			require.ErrorIs(t, err, ErrInvalidCommitment)
+		})
+	}
+}
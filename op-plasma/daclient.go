@@ -0,0 +1,97 @@
+package plasma
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrNotFound is returned when the DA server does not hold the input for a commitment.
+var ErrNotFound = errors.New("not found")
+
+// ErrInputTooLarge is returned when an input exceeds MaxInputSize.
+var ErrInputTooLarge = errors.New("input too large")
+
+// MaxInputSize is the maximum size of an input, matching the maximum size of the channel data derived from it
+// (derive.MaxRLPBytesPerChannel). Larger inputs are never stored or read.
+const MaxInputSize = 10_000_000
+
+// DAClient stores and retrieves the inputs committed to on L1 on a DA server, over a simple HTTP API:
+// inputs are stored with PUT /put/<hex encoded commitment>, and retrieved with GET /get/<hex encoded commitment>.
+type DAClient struct {
+	url string
+	// verify checks that the retrieved input matches the commitment.
+	verify bool
+	client *http.Client
+}
+
+func NewDAClient(url string, verify bool) *DAClient {
+	return &DAClient{
+		url:    url,
+		verify: verify,
+		client: &http.Client{},
+	}
+}
+
+// GetInput returns the input committed to by the given commitment.
+// Returns ErrNotFound if the DA server does not hold the input.
+func (c *DAClient) GetInput(ctx context.Context, comm Keccak256Commitment) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/get/%s", c.url, hexutil.Encode(comm.Encode())), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get input %s: unexpected status %d", comm, resp.StatusCode)
+	}
+	input, err := io.ReadAll(io.LimitReader(resp.Body, MaxInputSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input %s: %w", comm, err)
+	}
+	if len(input) > MaxInputSize {
+		return nil, fmt.Errorf("%w: input %s exceeds %d bytes", ErrInputTooLarge, comm, MaxInputSize)
+	}
+	if c.verify {
+		if err := comm.Verify(input); err != nil {
+			return nil, err
+		}
+	}
+	return input, nil
+}
+
+// SetInput stores the input on the DA server and returns the commitment to it.
+func (c *DAClient) SetInput(ctx context.Context, input []byte) (Keccak256Commitment, error) {
+	if len(input) == 0 {
+		return Keccak256Commitment{}, errors.New("input is empty")
+	}
+	if len(input) > MaxInputSize {
+		return Keccak256Commitment{}, fmt.Errorf("%w: %d bytes exceeds %d", ErrInputTooLarge, len(input), MaxInputSize)
+	}
+	comm := Keccak256(input)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/put/%s", c.url, hexutil.Encode(comm.Encode())), bytes.NewReader(input))
+	if err != nil {
+		return Keccak256Commitment{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Keccak256Commitment{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Keccak256Commitment{}, fmt.Errorf("failed to store input %s: unexpected status %d", comm, resp.StatusCode)
+	}
+	return comm, nil
+}
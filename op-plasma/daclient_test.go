@@ -0,0 +1,82 @@
+package plasma
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+func TestDAClient(t *testing.T) {
+	store := NewMemStore()
+	server := NewDAServer("127.0.0.1", 0, store, testlog.Logger(t, log.LvlDebug))
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		require.NoError(t, server.Stop())
+	})
+
+	ctx := context.Background()
+	client := NewDAClient(server.HttpEndpoint(), true)
+
+	input := []byte("hello plasma")
+	comm, err := client.SetInput(ctx, input)
+	require.NoError(t, err)
+	require.Equal(t, Keccak256(input), comm)
+
+	stored, err := client.GetInput(ctx, comm)
+	require.NoError(t, err)
+	require.Equal(t, input, stored)
+
+	_, err = client.GetInput(ctx, Keccak256([]byte("unknown input")))
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.SetInput(ctx, nil)
+	require.Error(t, err)
+
+	// Inputs that do not match the commitment are detected when verifying
+	require.NoError(t, store.Put(ctx, comm.Encode(), []byte("corrupted input")))
+	_, err = client.GetInput(ctx, comm)
+	require.ErrorIs(t, err, ErrCommitmentMismatch)
+	stored, err = NewDAClient(server.HttpEndpoint(), false).GetInput(ctx, comm)
+	require.NoError(t, err)
+	require.Equal(t, []byte("corrupted input"), stored)
+
+	store.Delete(comm.Encode())
+	_, err = client.GetInput(ctx, comm)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Inputs exceeding the maximum size are neither stored nor read
+	large := make([]byte, MaxInputSize+1)
+	_, err = client.SetInput(ctx, large)
+	require.ErrorIs(t, err, ErrInputTooLarge)
+	largeComm := Keccak256(large)
+	require.NoError(t, store.Put(ctx, largeComm.Encode(), large))
+	_, err = NewDAClient(server.HttpEndpoint(), false).GetInput(ctx, largeComm)
+	require.ErrorIs(t, err, ErrInputTooLarge)
+}
+
+func TestDAServerRejectsMismatchedInput(t *testing.T) {
+	server := NewDAServer("127.0.0.1", 0, NewMemStore(), testlog.Logger(t, log.LvlDebug))
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		require.NoError(t, server.Stop())
+	})
+
+	comm := Keccak256([]byte("hello plasma"))
+	req, err := http.NewRequest(http.MethodPut, server.HttpEndpoint()+"/put/"+hexutil.Encode(comm.Encode()), nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.HttpEndpoint() + "/get/invalid")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
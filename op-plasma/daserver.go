@@ -0,0 +1,170 @@
+package plasma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
+)
+
+// maxInputSize is the maximum size of an input accepted by the DA server.
+const maxInputSize = 16 * 1024 * 1024
+
+// KVStore stores the inputs of the DA server, keyed by encoded commitment.
+type KVStore interface {
+	// Get returns the value stored under key, or ErrNotFound if there is none.
+	Get(ctx context.Context, key []byte) ([]byte, error)
+	// Put stores the value under key.
+	Put(ctx context.Context, key []byte, value []byte) error
+}
+
+// DAServer serves the DA server HTTP API used by DAClient, backed by a KVStore.
+// Inputs are only accepted if they match the commitment they are stored under.
+type DAServer struct {
+	log        log.Logger
+	endpoint   string
+	store      KVStore
+	httpServer *httputil.HTTPServer
+}
+
+func NewDAServer(host string, port int, store KVStore, log log.Logger) *DAServer {
+	return &DAServer{
+		log:      log,
+		endpoint: net.JoinHostPort(host, strconv.Itoa(port)),
+		store:    store,
+	}
+}
+
+func (d *DAServer) Start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/get/", d.HandleGet)
+	mux.HandleFunc("/put/", d.HandlePut)
+	srv, err := httputil.StartHTTPServer(d.endpoint, mux)
+	if err != nil {
+		return fmt.Errorf("failed to start DA server: %w", err)
+	}
+	d.httpServer = srv
+	d.log.Info("Started DA server", "endpoint", d.HttpEndpoint())
+	return nil
+}
+
+// HttpEndpoint returns the URL of the running server, to configure the DAClient with.
+func (d *DAServer) HttpEndpoint() string {
+	return "http://" + d.httpServer.Addr().String()
+}
+
+func (d *DAServer) HandleGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	key, err := decodeKey(r.URL.Path)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	input, err := d.store.Get(r.Context(), key)
+	if errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		d.log.Error("Failed to read input", "key", hexutil.Encode(key), "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if _, err := w.Write(input); err != nil {
+		d.log.Error("Failed to write response", "err", err)
+	}
+}
+
+func (d *DAServer) HandlePut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	key, err := decodeKey(r.URL.Path)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	comm, err := DecodeKeccak256(key)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	input, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInputSize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := comm.Verify(input); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := d.store.Put(r.Context(), key, input); err != nil {
+		d.log.Error("Failed to store input", "commitment", comm, "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func decodeKey(urlPath string) ([]byte, error) {
+	key := path.Base(urlPath)
+	if !strings.HasPrefix(key, "0x") {
+		return nil, errors.New("key must be 0x-prefixed")
+	}
+	return hexutil.Decode(key)
+}
+
+func (d *DAServer) Stop() error {
+	if d.httpServer == nil {
+		return nil
+	}
+	return d.httpServer.Stop(context.Background())
+}
+
+// MemStore is an in-memory KVStore, for local testing.
+type MemStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{data: make(map[string][]byte)}
+}
+
+func (m *MemStore) Get(_ context.Context, key []byte) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.data[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+func (m *MemStore) Put(_ context.Context, key []byte, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[string(key)] = value
+	return nil
+}
+
+// Delete removes the value stored under key, to simulate data that is not available.
+func (m *MemStore) Delete(key []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, string(key))
+}
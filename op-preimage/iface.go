@@ -34,6 +34,15 @@ const (
 	LocalKeyType KeyType = 1
 	// Keccak256KeyType is for keccak256 pre-images, for any global shared pre-images.
 	Keccak256KeyType KeyType = 2
+	// Sha256KeyType is for sha256 pre-images, for any global shared pre-images.
+	// Sha256, Blob and Precompile pre-images can't be loaded on-chain, see ExtendedKeyTypes.
+	Sha256KeyType KeyType = 4
+	// BlobKeyType is for blob point pre-images: the field element of a blob at a point,
+	// keyed by the hash of the blob KZG commitment and the point.
+	BlobKeyType KeyType = 5
+	// PrecompileKeyType is for precompile result pre-images: the result of a precompile call,
+	// keyed by the hash of the precompile address and input.
+	PrecompileKeyType KeyType = 6
 )
 
 // LocalIndexKey is a key local to the program, indexing a special program input.
@@ -62,6 +71,61 @@ func (k Keccak256Key) TerminalString() string {
 	return "0x" + hex.EncodeToString(k[:])
 }
 
+// Sha256Key wraps a sha256 hash to use it as a typed pre-image key.
+type Sha256Key [32]byte
+
+func (k Sha256Key) PreimageKey() (out [32]byte) {
+	out = k                      // copy the sha256 hash
+	out[0] = byte(Sha256KeyType) // apply prefix
+	return
+}
+
+func (k Sha256Key) String() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+func (k Sha256Key) TerminalString() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+// BlobKey is the hash of a blob commitment and `z` value to use as a typed pre-image key.
+// The pre-image of the key is the 32 byte field element of the blob at `z`.
+// The commitment and `z` are available as the pre-image of the Keccak256Key with the same hash.
+type BlobKey [32]byte
+
+func (k BlobKey) PreimageKey() (out [32]byte) {
+	out = k
+	out[0] = byte(BlobKeyType)
+	return
+}
+
+func (k BlobKey) String() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+func (k BlobKey) TerminalString() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+// PrecompileKey is the hash of a precompile address and its input data to use as a typed pre-image key.
+// The pre-image of the key is a status byte (1 for success, 0 for failure) followed by the precompile output.
+// The address and input are available as the pre-image of the Keccak256Key with the same hash.
+type PrecompileKey [32]byte
+
+func (k PrecompileKey) PreimageKey() (out [32]byte) {
+	out = k
+	out[0] = byte(PrecompileKeyType)
+	return
+}
+
+func (k PrecompileKey) String() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
+func (k PrecompileKey) TerminalString() string {
+	return "0x" + hex.EncodeToString(k[:])
+}
+
 // Hint is an interface to enable any program type to function as a hint,
 // when passed to the Hinter interface, returning a string representation
 // of what data the host should prepare pre-images for.
@@ -0,0 +1,13 @@
+package preimage
+
+// OnChainKeyType returns true if pre-images of the key type can be loaded into the PreimageOracle contract.
+// The contract only supports local and keccak256 pre-images.
+func OnChainKeyType(t KeyType) bool {
+	return t == LocalKeyType || t == Keccak256KeyType
+}
+
+// KeyTypeEnabled returns true if pre-images of the key type can be used in this build of the program.
+// The key types that can't be loaded on-chain are only enabled if ExtendedKeyTypes is true.
+func KeyTypeEnabled(t KeyType) bool {
+	return OnChainKeyType(t) || ExtendedKeyTypes
+}
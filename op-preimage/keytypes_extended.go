@@ -0,0 +1,8 @@
+//go:build !mips && !mips64
+
+package preimage
+
+// ExtendedKeyTypes enables the Sha256, Blob and Precompile key types, which can't be loaded into the
+// PreimageOracle contract. They are disabled in fault-proof builds, i.e. builds for the MIPS VMs,
+// so a fault-proof program never depends on pre-images that can't be provided on-chain.
+const ExtendedKeyTypes = true
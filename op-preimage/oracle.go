@@ -21,6 +21,9 @@ var _ Oracle = (*OracleClient)(nil)
 
 func (o *OracleClient) Get(key Key) []byte {
 	h := key.PreimageKey()
+	if !KeyTypeEnabled(KeyType(h[0])) {
+		panic(fmt.Errorf("%w: key %s (%T) can't be loaded on-chain", ErrUnsupportedKeyType, key, key))
+	}
 	if _, err := o.rw.Write(h[:]); err != nil {
 		panic(fmt.Errorf("failed to write key %s (%T) to pre-image oracle: %w", key, key, err))
 	}
@@ -0,0 +1,401 @@
+package preimage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The pre-image oracle and hinter protocols can be served over stream sockets (TCP or Unix) instead of the file
+// descriptors inherited by a client subprocess. Each client execution opens one connection per channel, and starts
+// each connection with a handshake:
+//
+//	magic (4 bytes) ++ version (1 byte) ++ channel (1 byte) ++ session ID (16 bytes) ++ local pre-image count (4 bytes)
+//	++ [local index (8 bytes) ++ length (4 bytes) ++ pre-image]*
+//
+// The server replies with a single status byte, after which the connection carries the unmodified oracle or hinter
+// protocol. The session ID is chosen by the client and pairs the oracle and hinter connections of one execution,
+// allowing a single server to serve many concurrent executions. The local pre-images are the boot inputs of the
+// execution, such as the claim being verified, and are only sent on the oracle connection. They are served to that
+// session alone, so executions for different claims can share a server.
+
+// Channel identifies the protocol carried by a socket connection.
+type Channel byte
+
+const (
+	OracleChannel Channel = 1
+	HintChannel   Channel = 2
+)
+
+func (c Channel) String() string {
+	switch c {
+	case OracleChannel:
+		return "oracle"
+	case HintChannel:
+		return "hint"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(c))
+	}
+}
+
+// SessionID pairs the oracle and hinter connections of a single client execution.
+type SessionID [16]byte
+
+// LocalPreimages are the pre-images of local keys provided by a client for its own session, keyed by local index.
+type LocalPreimages map[LocalIndexKey][]byte
+
+var handshakeMagic = [4]byte{'O', 'P', 'P', 'I'}
+
+const (
+	handshakeVersion = 1
+	// handshakeSize is the size of the fixed part of the handshake, up to and including the local pre-image count.
+	handshakeSize = len(handshakeMagic) + 1 + 1 + len(SessionID{}) + 4
+
+	// maxLocalPreimages is the maximum number of local pre-images a client may provide for its session.
+	maxLocalPreimages = 256
+	// maxLocalPreimageSize is the maximum size of a single local pre-image provided by a client.
+	maxLocalPreimageSize = 1 << 20
+
+	statusAccepted byte = 0
+	statusRejected byte = 1
+
+	// DefaultHandshakeTimeout is the time allowed for a new connection to complete the handshake.
+	DefaultHandshakeTimeout = 10 * time.Second
+)
+
+var (
+	ErrInvalidAddress    = errors.New("invalid pre-image server address")
+	ErrInvalidHandshake  = errors.New("invalid pre-image handshake")
+	ErrHandshakeRejected = errors.New("pre-image handshake rejected")
+	ErrListenerClosed    = errors.New("pre-image listener closed")
+)
+
+// ParseAddress parses a pre-image server address of the form unix://<path> or tcp://<host>:<port>
+// into the network and address to pass to net.Dial or net.Listen.
+func ParseAddress(addr string) (network string, address string, err error) {
+	network, address, ok := strings.Cut(addr, "://")
+	if !ok || address == "" {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidAddress, addr)
+	}
+	switch network {
+	case "unix", "tcp":
+		return network, address, nil
+	default:
+		return "", "", fmt.Errorf("%w: unsupported network %q", ErrInvalidAddress, network)
+	}
+}
+
+// Dial connects to the pre-image server at addr, opening the oracle and hinter connections of a new session.
+// The local pre-images are served to the new session in place of the server's own, and may be nil.
+func Dial(ctx context.Context, addr string, local LocalPreimages) (oracle net.Conn, hint net.Conn, err error) {
+	network, address, err := ParseAddress(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	var session SessionID
+	if _, err := rand.Read(session[:]); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	oracle, err = dialChannel(ctx, network, address, session, OracleChannel, local)
+	if err != nil {
+		return nil, nil, err
+	}
+	hint, err = dialChannel(ctx, network, address, session, HintChannel, nil)
+	if err != nil {
+		_ = oracle.Close()
+		return nil, nil, err
+	}
+	return oracle, hint, nil
+}
+
+func dialChannel(ctx context.Context, network string, address string, session SessionID, channel Channel, local LocalPreimages) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect %v channel to %v: %w", channel, address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if err := writeHandshake(conn, session, channel, local); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	var status [1]byte
+	if _, err := io.ReadFull(conn, status[:]); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to read %v handshake response: %w", channel, err)
+	}
+	if status[0] != statusAccepted {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w: %v channel", ErrHandshakeRejected, channel)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func writeHandshake(w io.Writer, session SessionID, channel Channel, local LocalPreimages) error {
+	if len(local) > maxLocalPreimages {
+		return fmt.Errorf("too many local pre-images: %d", len(local))
+	}
+	buf := make([]byte, 0, handshakeSize)
+	buf = append(buf, handshakeMagic[:]...)
+	buf = append(buf, handshakeVersion, byte(channel))
+	buf = append(buf, session[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(local)))
+	for key, value := range local {
+		if len(value) > maxLocalPreimageSize {
+			return fmt.Errorf("local pre-image %d too large: %d bytes", key, len(value))
+		}
+		buf = binary.BigEndian.AppendUint64(buf, uint64(key))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(value)))
+		buf = append(buf, value...)
+	}
+	if _, err := w.Write(buf); err != nil {
+		return fmt.Errorf("failed to write %v handshake: %w", channel, err)
+	}
+	return nil
+}
+
+func readHandshake(r io.Reader) (SessionID, Channel, LocalPreimages, error) {
+	var buf [handshakeSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return SessionID{}, 0, nil, fmt.Errorf("failed to read handshake: %w", err)
+	}
+	if !bytes.Equal(buf[:len(handshakeMagic)], handshakeMagic[:]) {
+		return SessionID{}, 0, nil, fmt.Errorf("%w: incorrect magic", ErrInvalidHandshake)
+	}
+	if v := buf[len(handshakeMagic)]; v != handshakeVersion {
+		return SessionID{}, 0, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidHandshake, v)
+	}
+	channel := Channel(buf[len(handshakeMagic)+1])
+	if channel != OracleChannel && channel != HintChannel {
+		return SessionID{}, 0, nil, fmt.Errorf("%w: unknown channel %v", ErrInvalidHandshake, channel)
+	}
+	var session SessionID
+	copy(session[:], buf[len(handshakeMagic)+2:])
+	count := binary.BigEndian.Uint32(buf[handshakeSize-4:])
+	if count > maxLocalPreimages {
+		return SessionID{}, 0, nil, fmt.Errorf("%w: too many local pre-images: %d", ErrInvalidHandshake, count)
+	}
+	if count > 0 && channel != OracleChannel {
+		return SessionID{}, 0, nil, fmt.Errorf("%w: local pre-images sent on %v channel", ErrInvalidHandshake, channel)
+	}
+	var local LocalPreimages
+	if count > 0 {
+		local = make(LocalPreimages, count)
+	}
+	for i := uint32(0); i < count; i++ {
+		var header [12]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return SessionID{}, 0, nil, fmt.Errorf("failed to read local pre-image: %w", err)
+		}
+		key := LocalIndexKey(binary.BigEndian.Uint64(header[:8]))
+		size := binary.BigEndian.Uint32(header[8:])
+		if size > maxLocalPreimageSize {
+			return SessionID{}, 0, nil, fmt.Errorf("%w: local pre-image %d too large: %d bytes", ErrInvalidHandshake, key, size)
+		}
+		value := make([]byte, size)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return SessionID{}, 0, nil, fmt.Errorf("failed to read local pre-image: %w", err)
+		}
+		local[key] = value
+	}
+	return session, channel, local, nil
+}
+
+// Session is the pair of connections used by a single client execution.
+type Session struct {
+	ID     SessionID
+	Oracle net.Conn
+	Hint   net.Conn
+	// Local are the local pre-images provided by the client for this session, nil if none were provided.
+	Local LocalPreimages
+}
+
+// Close closes both connections of the session.
+func (s *Session) Close() error {
+	return errors.Join(s.Oracle.Close(), s.Hint.Close())
+}
+
+// Listener accepts client connections and pairs them into sessions.
+type Listener struct {
+	ln               net.Listener
+	handshakeTimeout time.Duration
+
+	mu      sync.Mutex
+	pending map[SessionID]*Session
+
+	sessions  chan *Session
+	closed    chan struct{}
+	closeOnce sync.Once
+	err       error
+}
+
+// Listen starts listening for client connections at addr, of the form unix://<path> or tcp://<host>:<port>.
+func Listen(addr string) (*Listener, error) {
+	network, address, err := ParseAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %v: %w", addr, err)
+	}
+	return NewListener(ln, DefaultHandshakeTimeout), nil
+}
+
+// NewListener creates a Listener that accepts connections from ln.
+// The Listener takes ownership of ln and closes it when the Listener is closed.
+func NewListener(ln net.Listener, handshakeTimeout time.Duration) *Listener {
+	l := &Listener{
+		ln:               ln,
+		handshakeTimeout: handshakeTimeout,
+		pending:          make(map[SessionID]*Session),
+		sessions:         make(chan *Session),
+		closed:           make(chan struct{}),
+	}
+	go l.acceptLoop()
+	return l
+}
+
+// Addr returns the address the Listener is accepting connections on.
+func (l *Listener) Addr() net.Addr {
+	return l.ln.Addr()
+}
+
+// Accept waits for both connections of the next session to complete their handshakes.
+func (l *Listener) Accept() (*Session, error) {
+	select {
+	case s := <-l.sessions:
+		return s, nil
+	case <-l.closed:
+		if l.err != nil {
+			return nil, l.err
+		}
+		return nil, ErrListenerClosed
+	}
+}
+
+// Close stops accepting connections and closes the connections of any incomplete sessions.
+// Sessions that have already been returned by Accept are not affected.
+func (l *Listener) Close() error {
+	return l.close(nil)
+}
+
+func (l *Listener) close(cause error) error {
+	var err error
+	l.closeOnce.Do(func() {
+		l.err = cause
+		close(l.closed)
+		err = l.ln.Close()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for id, s := range l.pending {
+			closeConns(s)
+			delete(l.pending, id)
+		}
+	})
+	return err
+}
+
+func (l *Listener) acceptLoop() {
+	for {
+		conn, err := l.ln.Accept()
+		if err != nil {
+			select {
+			case <-l.closed:
+			default:
+				_ = l.close(fmt.Errorf("failed to accept connection: %w", err))
+			}
+			return
+		}
+		go l.handshake(conn)
+	}
+}
+
+func (l *Listener) handshake(conn net.Conn) {
+	_ = conn.SetDeadline(time.Now().Add(l.handshakeTimeout))
+	id, channel, local, err := readHandshake(conn)
+	if err != nil {
+		if errors.Is(err, ErrInvalidHandshake) {
+			_, _ = conn.Write([]byte{statusRejected})
+		}
+		_ = conn.Close()
+		return
+	}
+
+	l.mu.Lock()
+	s, ok := l.pending[id]
+	if !ok {
+		s = &Session{ID: id}
+		l.pending[id] = s
+	}
+	if (channel == OracleChannel && s.Oracle != nil) || (channel == HintChannel && s.Hint != nil) {
+		l.mu.Unlock()
+		// Each session may only have a single connection per channel
+		_, _ = conn.Write([]byte{statusRejected})
+		_ = conn.Close()
+		return
+	}
+	if channel == OracleChannel {
+		s.Oracle = conn
+		s.Local = local
+	} else {
+		s.Hint = conn
+	}
+	complete := s.Oracle != nil && s.Hint != nil
+	if complete {
+		delete(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	if _, err := conn.Write([]byte{statusAccepted}); err != nil {
+		l.removePending(s)
+		closeConns(s)
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+	if !complete {
+		// Drop the session if the other connection doesn't arrive in time
+		time.AfterFunc(l.handshakeTimeout, func() {
+			if l.removePending(s) {
+				closeConns(s)
+			}
+		})
+		return
+	}
+	select {
+	case l.sessions <- s:
+	case <-l.closed:
+		closeConns(s)
+	}
+}
+
+// removePending removes s from the incomplete sessions, returning true if it was still pending.
+func (l *Listener) removePending(s *Session) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.pending[s.ID] != s {
+		return false
+	}
+	delete(l.pending, s.ID)
+	return true
+}
+
+func closeConns(s *Session) {
+	if s.Oracle != nil {
+		_ = s.Oracle.Close()
+	}
+	if s.Hint != nil {
+		_ = s.Hint.Close()
+	}
+}
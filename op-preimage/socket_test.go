@@ -0,0 +1,177 @@
+package preimage
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		addr    string
+		network string
+		address string
+	}{
+		{addr: "unix:///tmp/preimage.sock", network: "unix", address: "/tmp/preimage.sock"},
+		{addr: "tcp://localhost:8888", network: "tcp", address: "localhost:8888"},
+		{addr: "tcp://127.0.0.1:0", network: "tcp", address: "127.0.0.1:0"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.addr, func(t *testing.T) {
+			network, address, err := ParseAddress(test.addr)
+			require.NoError(t, err)
+			require.Equal(t, test.network, network)
+			require.Equal(t, test.address, address)
+		})
+	}
+
+	for _, addr := range []string{"", "localhost:8888", "tcp://", "udp://localhost:8888", "http://localhost:8888"} {
+		addr := addr
+		t.Run("Invalid-"+addr, func(t *testing.T) {
+			_, _, err := ParseAddress(addr)
+			require.ErrorIs(t, err, ErrInvalidAddress)
+		})
+	}
+}
+
+func TestSocket(t *testing.T) {
+	t.Run("TCP", func(t *testing.T) {
+		testSocket(t, "tcp://127.0.0.1:0")
+	})
+	t.Run("Unix", func(t *testing.T) {
+		testSocket(t, "unix://"+filepath.Join(t.TempDir(), "preimage.sock"))
+	})
+}
+
+func testSocket(t *testing.T, addr string) {
+	l, err := Listen(addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	serverAddr := l.Addr().Network() + "://" + l.Addr().String()
+
+	preimages := map[[32]byte][]byte{}
+	for i := 0; i < 5; i++ {
+		p := []byte(fmt.Sprintf("preimage %d", i))
+		preimages[Keccak256Key(Keccak256(p)).PreimageKey()] = p
+	}
+
+	// Serve each session until the client disconnects
+	go func() {
+		for {
+			s, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer s.Close()
+				oracle := NewOracleServer(s.Oracle)
+				hints := NewHintReader(s.Hint)
+				go func() {
+					for hints.NextHint(func(hint string) error { return nil }) == nil {
+					}
+				}()
+				for oracle.NextPreimageRequest(func(key [32]byte) ([]byte, error) {
+					return preimages[key], nil
+				}) == nil {
+				}
+			}()
+		}
+	}()
+
+	// Run several clients concurrently, each with its own session
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			oracleConn, hintConn, err := Dial(ctx, serverAddr, nil)
+			require.NoError(t, err)
+			defer oracleConn.Close()
+			defer hintConn.Close()
+			oracle := NewOracleClient(oracleConn)
+			hinter := NewHintWriter(hintConn)
+			for key, expected := range preimages {
+				hinter.Hint(rawHint("hint"))
+				require.Equal(t, expected, oracle.Get(Keccak256Key(key)))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSocketHandshake(t *testing.T) {
+	l, err := Listen("tcp://127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	dial := func(t *testing.T, session SessionID, channel Channel, local LocalPreimages) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return dialChannel(ctx, "tcp", l.Addr().String(), session, channel, local)
+	}
+
+	t.Run("RejectInvalidMagic", func(t *testing.T) {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write(make([]byte, handshakeSize))
+		require.NoError(t, err)
+		var status [1]byte
+		_, err = conn.Read(status[:])
+		require.NoError(t, err)
+		require.Equal(t, statusRejected, status[0])
+	})
+
+	t.Run("RejectUnknownChannel", func(t *testing.T) {
+		_, err := dial(t, SessionID{0x01}, Channel(3), nil)
+		require.ErrorIs(t, err, ErrHandshakeRejected)
+	})
+
+	t.Run("RejectDuplicateChannel", func(t *testing.T) {
+		conn, err := dial(t, SessionID{0x02}, OracleChannel, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = dial(t, SessionID{0x02}, OracleChannel, nil)
+		require.ErrorIs(t, err, ErrHandshakeRejected)
+	})
+
+	t.Run("RejectLocalPreimagesOnHintChannel", func(t *testing.T) {
+		_, err := dial(t, SessionID{0x04}, HintChannel, LocalPreimages{1: {0x01}})
+		require.ErrorIs(t, err, ErrHandshakeRejected)
+	})
+
+	t.Run("PairSession", func(t *testing.T) {
+		local := LocalPreimages{1: {0x11}, 2: {0x22, 0x22}, 3: {}}
+		hint, err := dial(t, SessionID{0x03}, HintChannel, nil)
+		require.NoError(t, err)
+		defer hint.Close()
+		oracle, err := dial(t, SessionID{0x03}, OracleChannel, local)
+		require.NoError(t, err)
+		defer oracle.Close()
+
+		s, err := l.Accept()
+		require.NoError(t, err)
+		defer s.Close()
+		require.Equal(t, SessionID{0x03}, s.ID)
+		require.Equal(t, oracle.LocalAddr(), s.Oracle.RemoteAddr())
+		require.Equal(t, hint.LocalAddr(), s.Hint.RemoteAddr())
+		require.Equal(t, local, s.Local)
+	})
+}
+
+func TestListenerClose(t *testing.T) {
+	l, err := Listen("tcp://127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+	_, err = l.Accept()
+	require.ErrorIs(t, err, ErrListenerClosed)
+}
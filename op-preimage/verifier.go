@@ -1,6 +1,7 @@
 package preimage
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"slices"
@@ -19,6 +20,9 @@ func WithVerification(source PreimageGetter) PreimageGetter {
 			return nil, err
 		}
 
+		if !KeyTypeEnabled(KeyType(key[0])) {
+			return nil, fmt.Errorf("%w: %v can't be loaded on-chain", ErrUnsupportedKeyType, key[0])
+		}
 		switch KeyType(key[0]) {
 		case LocalKeyType:
 			return data, nil
@@ -28,6 +32,17 @@ func WithVerification(source PreimageGetter) PreimageGetter {
 				return nil, fmt.Errorf("%w for key %v, hash: %v data: %x", ErrIncorrectData, key, hash, data)
 			}
 			return data, nil
+		case Sha256KeyType:
+			hash := sha256.Sum256(data)
+			if !slices.Equal(hash[1:], key[1:]) {
+				return nil, fmt.Errorf("%w for key %v, hash: %x data: %x", ErrIncorrectData, key, hash, data)
+			}
+			return data, nil
+		case BlobKeyType, PrecompileKeyType:
+			// Blob field elements and precompile results can't be verified from the key alone: the inputs
+			// they are for are only available as a separate keccak256 pre-image. They are only served to
+			// builds with ExtendedKeyTypes, which don't run on-chain.
+			return data, nil
 		default:
 			return nil, fmt.Errorf("%w: %v", ErrUnsupportedKeyType, key[0])
 		}
@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"math"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
@@ -22,8 +23,15 @@ const (
 	// These local keys are only used for custom chains
 	L2ChainConfigLocalIndex
 	RollupConfigLocalIndex
+
+	// L2IntermediateClaimsLocalIndex is only used when running the client in-process to verify a span of blocks.
+	// It is never requested by the client when run in a VM.
+	L2IntermediateClaimsLocalIndex
 )
 
+// outputClaimLen is the length of an encoded OutputClaim: block number (uint64) ++ output root (32 bytes)
+const outputClaimLen = 8 + common.HashLength
+
 // CustomChainIDIndicator is used to detect when the program should load custom chain configuration
 const CustomChainIDIndicator = uint64(math.MaxUint64)
 
@@ -38,6 +46,37 @@ type BootInfo struct {
 	RollupConfig  *rollup.Config
 }
 
+// OutputClaim is a claimed L2 output root at a specific block number.
+type OutputClaim struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	OutputRoot  common.Hash `json:"outputRoot"`
+}
+
+// EncodeOutputClaims encodes the claims as the concatenation of each claim's block number and output root.
+func EncodeOutputClaims(claims []OutputClaim) []byte {
+	out := make([]byte, 0, len(claims)*outputClaimLen)
+	for _, claim := range claims {
+		out = binary.BigEndian.AppendUint64(out, claim.BlockNumber)
+		out = append(out, claim.OutputRoot[:]...)
+	}
+	return out
+}
+
+// DecodeOutputClaims decodes claims encoded with EncodeOutputClaims.
+func DecodeOutputClaims(data []byte) ([]OutputClaim, error) {
+	if len(data)%outputClaimLen != 0 {
+		return nil, fmt.Errorf("invalid output claims length: %v", len(data))
+	}
+	claims := make([]OutputClaim, 0, len(data)/outputClaimLen)
+	for i := 0; i < len(data); i += outputClaimLen {
+		claims = append(claims, OutputClaim{
+			BlockNumber: binary.BigEndian.Uint64(data[i:]),
+			OutputRoot:  common.BytesToHash(data[i+8 : i+outputClaimLen]),
+		})
+	}
+	return claims, nil
+}
+
 type oracleClient interface {
 	Get(key preimage.Key) []byte
 }
@@ -92,3 +131,12 @@ func (br *BootstrapClient) BootInfo() *BootInfo {
 		RollupConfig:       rollupConfig,
 	}
 }
+
+// IntermediateClaims returns the claimed output roots of blocks before the L2 claim block, in ascending block order.
+func (br *BootstrapClient) IntermediateClaims() []OutputClaim {
+	claims, err := DecodeOutputClaims(br.r.Get(L2IntermediateClaimsLocalIndex))
+	if err != nil {
+		panic(fmt.Errorf("failed to bootstrap intermediate claims: %w", err))
+	}
+	return claims
+}
@@ -23,7 +23,7 @@ func TestBootstrapClient(t *testing.T) {
 		L2ChainConfig:      chainconfig.OPGoerliChainConfig,
 		RollupConfig:       chaincfg.Goerli,
 	}
-	mockOracle := &mockBoostrapOracle{bootInfo, false}
+	mockOracle := &mockBoostrapOracle{b: bootInfo}
 	readBootInfo := NewBootstrapClient(mockOracle).BootInfo()
 	require.EqualValues(t, bootInfo, readBootInfo)
 }
@@ -38,7 +38,7 @@ func TestBootstrapClient_CustomChain(t *testing.T) {
 		L2ChainConfig:      chainconfig.OPGoerliChainConfig,
 		RollupConfig:       chaincfg.Goerli,
 	}
-	mockOracle := &mockBoostrapOracle{bootInfo, true}
+	mockOracle := &mockBoostrapOracle{b: bootInfo, custom: true}
 	readBootInfo := NewBootstrapClient(mockOracle).BootInfo()
 	require.EqualValues(t, bootInfo, readBootInfo)
 }
@@ -51,14 +51,30 @@ func TestBootstrapClient_UnknownChainPanics(t *testing.T) {
 		L2ClaimBlockNumber: 1,
 		L2ChainID:          uint64(0xdead),
 	}
-	mockOracle := &mockBoostrapOracle{bootInfo, false}
+	mockOracle := &mockBoostrapOracle{b: bootInfo}
 	client := NewBootstrapClient(mockOracle)
 	require.Panics(t, func() { client.BootInfo() })
 }
 
+func TestBootstrapClient_IntermediateClaims(t *testing.T) {
+	claims := []OutputClaim{
+		{BlockNumber: 5, OutputRoot: common.HexToHash("0x5555")},
+		{BlockNumber: 6, OutputRoot: common.HexToHash("0x6666")},
+	}
+	mockOracle := &mockBoostrapOracle{b: &BootInfo{}, claims: EncodeOutputClaims(claims)}
+	require.Equal(t, claims, NewBootstrapClient(mockOracle).IntermediateClaims())
+
+	mockOracle.claims = []byte{}
+	require.Empty(t, NewBootstrapClient(mockOracle).IntermediateClaims())
+
+	mockOracle.claims = []byte{1, 2, 3}
+	require.Panics(t, func() { NewBootstrapClient(mockOracle).IntermediateClaims() })
+}
+
 type mockBoostrapOracle struct {
 	b      *BootInfo
 	custom bool
+	claims []byte
 }
 
 func (o *mockBoostrapOracle) Get(key preimage.Key) []byte {
@@ -85,6 +101,8 @@ func (o *mockBoostrapOracle) Get(key preimage.Key) []byte {
 		}
 		b, _ := json.Marshal(o.b.RollupConfig)
 		return b
+	case L2IntermediateClaimsLocalIndex.PreimageKey():
+		return o.claims
 	default:
 		panic("unknown key")
 	}
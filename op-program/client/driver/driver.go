@@ -7,6 +7,7 @@ import (
 	"io"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/sync"
@@ -18,6 +19,21 @@ var (
 	ErrClaimNotValid = errors.New("invalid claim")
 )
 
+// InvalidClaimError reports a claimed output root that does not match the output root of the derived chain.
+type InvalidClaimError struct {
+	BlockNumber uint64
+	Claimed     eth.Bytes32
+	Actual      eth.Bytes32
+}
+
+func (e *InvalidClaimError) Error() string {
+	return fmt.Sprintf("%v: block: %d claim: %v actual: %v", ErrClaimNotValid, e.BlockNumber, e.Claimed, e.Actual)
+}
+
+func (e *InvalidClaimError) Unwrap() error {
+	return ErrClaimNotValid
+}
+
 type Derivation interface {
 	Step(ctx context.Context) error
 }
@@ -39,9 +55,9 @@ type Driver struct {
 	targetBlockNum uint64
 }
 
-func NewDriver(logger log.Logger, cfg *rollup.Config, l1Source derive.L1Fetcher, l2Source L2Source, targetBlockNum uint64) *Driver {
+func NewDriver(logger log.Logger, cfg *rollup.Config, l1Source derive.L1Fetcher, l1BlobsSource derive.L1BlobsFetcher, l2Source L2Source, targetBlockNum uint64) *Driver {
 	engine := derive.NewEngineController(l2Source, logger, metrics.NoopMetrics, cfg, sync.CLSync)
-	pipeline := derive.NewDerivationPipeline(logger, cfg, l1Source, nil, l2Source, engine, metrics.NoopMetrics, &sync.Config{})
+	pipeline := derive.NewDerivationPipeline(logger, cfg, l1Source, l1BlobsSource, nil, l2Source, engine, metrics.NoopMetrics, &sync.Config{}, safedb.Disabled)
 	pipeline.Reset()
 	return &Driver{
 		logger:         logger,
@@ -88,9 +104,9 @@ func (d *Driver) ValidateClaim(l2ClaimBlockNum uint64, claimedOutputRoot eth.Byt
 	if err != nil {
 		return fmt.Errorf("calculate L2 output root: %w", err)
 	}
-	d.logger.Info("Validating claim", "head", d.SafeHead(), "output", outputRoot, "claim", claimedOutputRoot)
+	d.logger.Info("Validating claim", "head", d.SafeHead(), "block", l2ClaimBlockNum, "output", outputRoot, "claim", claimedOutputRoot)
 	if claimedOutputRoot != outputRoot {
-		return fmt.Errorf("%w: claim: %v actual: %v", ErrClaimNotValid, claimedOutputRoot, outputRoot)
+		return &InvalidClaimError{BlockNumber: l2ClaimBlockNum, Claimed: claimedOutputRoot, Actual: outputRoot}
 	}
 	return nil
 }
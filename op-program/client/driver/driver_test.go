@@ -85,8 +85,11 @@ func TestValidateClaim(t *testing.T) {
 		driver.l2OutputRoot = func(_ uint64) (eth.Bytes32, error) {
 			return eth.Bytes32{0x22}, nil
 		}
-		err := driver.ValidateClaim(uint64(0), eth.Bytes32{0x11})
+		err := driver.ValidateClaim(uint64(10), eth.Bytes32{0x11})
 		require.ErrorIs(t, err, ErrClaimNotValid)
+		var invalidClaim *InvalidClaimError
+		require.ErrorAs(t, err, &invalidClaim)
+		require.Equal(t, &InvalidClaimError{BlockNumber: 10, Claimed: eth.Bytes32{0x11}, Actual: eth.Bytes32{0x22}}, invalidClaim)
 	})
 
 	t.Run("Error", func(t *testing.T) {
@@ -1,5 +1,7 @@
 package client
 
+import "time"
+
 const (
 	// 0,1,2 used for stdin,stdout,stderr
 	HClientRFd = iota + 3
@@ -8,3 +10,19 @@ const (
 	PClientWFd
 	MaxFd
 )
+
+// PreimageServerEnvVar is the environment variable specifying the address of a pre-image server to connect to instead
+// of using the inherited file descriptors, in the form unix://<path> or tcp://<host>:<port>.
+const PreimageServerEnvVar = "OP_PROGRAM_PREIMAGE_SERVER"
+
+// dialTimeout is the maximum time to wait to connect to the pre-image server.
+const dialTimeout = 30 * time.Second
+
+// The boot inputs a client provides for its own session when connecting to a pre-image server. They use the same
+// environment variables as the corresponding host flags. Any that are unset are served from the server's config.
+const (
+	L1HeadEnvVar             = "OP_PROGRAM_L1_HEAD"
+	L2OutputRootEnvVar       = "OP_PROGRAM_L2_OUTPUT_ROOT"
+	L2ClaimEnvVar            = "OP_PROGRAM_L2_CLAIM"
+	L2ClaimBlockNumberEnvVar = "OP_PROGRAM_L2_BLOCK_NUM"
+)
@@ -0,0 +1,39 @@
+package l1
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-program/client/l1/test"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+func TestBlobFetcher(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	stub := test.NewStubOracle(t)
+	fetcher := NewBlobFetcher(testlog.Logger(t, log.LvlDebug), stub)
+	ref := eth.InfoToL1BlockRef(testutils.RandomBlockInfo(rng))
+
+	hashes := []eth.IndexedBlobHash{
+		{Hash: testutils.RandomHash(rng), Index: 0},
+		{Hash: testutils.RandomHash(rng), Index: 2},
+	}
+	expected := []*eth.Blob{randomBlob(t, rng), randomBlob(t, rng)}
+	for i, hash := range hashes {
+		stub.Blobs[hash.Hash] = expected[i]
+	}
+
+	blobs, err := fetcher.GetBlobs(context.Background(), ref, hashes)
+	require.NoError(t, err)
+	require.Equal(t, expected, blobs)
+
+	blobs, err = fetcher.GetBlobs(context.Background(), ref, nil)
+	require.NoError(t, err)
+	require.Empty(t, blobs)
+}
@@ -71,3 +71,23 @@ func TestCachingOracle_ReceiptsByBlockHash(t *testing.T) {
 	require.Equal(t, eth.BlockToInfo(block), actualBlock)
 	require.EqualValues(t, rcpts, actualRcpts)
 }
+
+func TestCachingOracle_GetBlob(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	stub := test.NewStubOracle(t)
+	oracle := NewCachingOracle(stub)
+
+	l1BlockRef := eth.InfoToL1BlockRef(testutils.RandomBlockInfo(rng))
+	indexedBlobHash := eth.IndexedBlobHash{Hash: testutils.RandomHash(rng), Index: 0}
+	blob := randomBlob(t, rng)
+
+	// Initial call retrieves from the stub
+	stub.Blobs[indexedBlobHash.Hash] = blob
+	actualBlob := oracle.GetBlob(l1BlockRef, indexedBlobHash)
+	require.Equal(t, blob, actualBlob)
+
+	// Later calls should retrieve from cache
+	delete(stub.Blobs, indexedBlobHash.Hash)
+	actualBlob = oracle.GetBlob(l1BlockRef, indexedBlobHash)
+	require.Equal(t, blob, actualBlob)
+}
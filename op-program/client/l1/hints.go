@@ -2,6 +2,7 @@ package l1
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 )
@@ -10,6 +11,7 @@ const (
 	HintL1BlockHeader  = "l1-block-header"
 	HintL1Transactions = "l1-transactions"
 	HintL1Receipts     = "l1-receipts"
+	HintL1Blob         = "l1-blob"
 )
 
 type BlockHeaderHint common.Hash
@@ -35,3 +37,14 @@ var _ preimage.Hint = ReceiptsHint{}
 func (l ReceiptsHint) Hint() string {
 	return HintL1Receipts + " " + (common.Hash)(l).String()
 }
+
+// BlobHint requests the commitment and field elements of a blob.
+// It is the versioned hash of the blob, followed by the 8 byte big-endian index of the blob in the block
+// and the 8 byte big-endian timestamp of the block, which identifies the beacon slot of the blob sidecar.
+type BlobHint []byte
+
+var _ preimage.Hint = BlobHint{}
+
+func (l BlobHint) Hint() string {
+	return HintL1Blob + " " + hexutil.Encode(l)
+}
@@ -0,0 +1,32 @@
+package l1
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// RootsOfUnity are the points the blob polynomial is evaluated at, in the bit-reversed order used by EIP-4844.
+// Field element i of a blob is the evaluation of the blob polynomial at RootsOfUnity[i].
+var RootsOfUnity = generateRootsOfUnity()
+
+func generateRootsOfUnity() [params.BlobTxFieldElementsPerBlob]fr.Element {
+	// 7 is the primitive root of the BLS12-381 scalar field used by EIP-4844,
+	// so 7^((r-1)/4096) is a primitive 4096th root of unity.
+	exp := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	exp.Div(exp, big.NewInt(params.BlobTxFieldElementsPerBlob))
+	var root fr.Element
+	root.SetUint64(7)
+	root.Exp(root, exp)
+
+	var roots [params.BlobTxFieldElementsPerBlob]fr.Element
+	shift := 32 - bits.Len(params.BlobTxFieldElementsPerBlob-1)
+	current := fr.One()
+	for i := uint32(0); i < params.BlobTxFieldElementsPerBlob; i++ {
+		roots[bits.Reverse32(i)>>shift] = current
+		current.Mul(&current, &root)
+	}
+	return roots
+}
@@ -0,0 +1,33 @@
+package l1
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+func TestRootsOfUnity(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	blob := randomBlob(t, rng)
+	for _, i := range []int{0, 1, 2, 7, 2048, 4095} {
+		// Evaluating the blob polynomial at the root must give the field element at the same index
+		_, claim, err := kzg4844.ComputeProof(kzg4844.Blob(*blob), RootsOfUnity[i].Bytes())
+		require.NoError(t, err)
+		require.EqualValues(t, blob[i*32:(i+1)*32], claim[:], "field element %v", i)
+	}
+	one := fr.One()
+	require.True(t, RootsOfUnity[0].Equal(&one))
+}
+
+func randomBlob(t *testing.T, rng *rand.Rand) *eth.Blob {
+	data := make([]byte, 100_000)
+	_, _ = rng.Read(data)
+	var blob eth.Blob
+	require.NoError(t, blob.FromData(data))
+	return &blob
+}
@@ -1,10 +1,13 @@
 package l1
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
@@ -21,6 +24,9 @@ type Oracle interface {
 
 	// ReceiptsByBlockHash retrieves the receipts from the block with the given hash.
 	ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockInfo, types.Receipts)
+
+	// GetBlob retrieves the blob with the given hash, confirmed in the given block.
+	GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) *eth.Blob
 }
 
 // PreimageOracle implements Oracle using by interfacing with the pure preimage.Oracle
@@ -86,3 +92,33 @@ func (p *PreimageOracle) ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockIn
 
 	return info, receipts
 }
+
+// GetBlob retrieves the blob through Sha256 and Blob pre-images. These can't be loaded on-chain,
+// so the pre-image oracle client panics if blobs are needed in fault-proof builds (see preimage.ExtendedKeyTypes).
+func (p *PreimageOracle) GetBlob(ref eth.L1BlockRef, blobHash eth.IndexedBlobHash) *eth.Blob {
+	// Send a hint for the blob commitment and field elements.
+	blobReqMeta := make([]byte, 16)
+	binary.BigEndian.PutUint64(blobReqMeta[0:8], blobHash.Index)
+	binary.BigEndian.PutUint64(blobReqMeta[8:16], ref.Time)
+	p.hint.Hint(BlobHint(append(blobHash.Hash[:], blobReqMeta...)))
+
+	commitment := p.oracle.Get(preimage.Sha256Key(blobHash.Hash))
+	if len(commitment) != 48 {
+		panic(fmt.Errorf("invalid commitment for blob %s: %x", blobHash.Hash, commitment))
+	}
+
+	// Reconstruct the full blob from its field elements, each keyed by the commitment and point they are for.
+	var blob eth.Blob
+	fieldElemKey := make([]byte, 80)
+	copy(fieldElemKey[:48], commitment)
+	for i := 0; i < params.BlobTxFieldElementsPerBlob; i++ {
+		rootOfUnity := RootsOfUnity[i].Bytes()
+		copy(fieldElemKey[48:], rootOfUnity[:])
+		fieldElement := p.oracle.Get(preimage.BlobKey(crypto.Keccak256(fieldElemKey)))
+		if len(fieldElement) != 32 {
+			panic(fmt.Errorf("invalid field element %d for blob %s: %x", i, blobHash.Hash, fieldElement))
+		}
+		copy(blob[i<<5:(i+1)<<5], fieldElement)
+	}
+	return &blob
+}
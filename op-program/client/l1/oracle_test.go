@@ -1,6 +1,7 @@
 package l1
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -9,6 +10,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -103,3 +106,41 @@ func TestPreimageOracleBlockByHash(t *testing.T) {
 		})
 	}
 }
+
+func TestPreimageOracleGetBlob(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	blob := randomBlob(t, rng)
+	commitment, err := kzg4844.BlobToCommitment(kzg4844.Blob(*blob))
+	require.NoError(t, err)
+	blobHash := eth.IndexedBlobHash{Hash: eth.KZGToVersionedHash(commitment), Index: 3}
+	ref := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Time: 1234}
+
+	preimages := make(map[common.Hash][]byte)
+	preimages[preimage.Sha256Key(blobHash.Hash).PreimageKey()] = commitment[:]
+	for i := 0; i < params.BlobTxFieldElementsPerBlob; i++ {
+		point := RootsOfUnity[i].Bytes()
+		key := crypto.Keccak256(commitment[:], point[:])
+		preimages[preimage.BlobKey(key).PreimageKey()] = blob[i*32 : (i+1)*32]
+	}
+
+	var hints mock.Mock
+	po := &PreimageOracle{
+		oracle: preimage.OracleFn(func(key preimage.Key) []byte {
+			v, ok := preimages[key.PreimageKey()]
+			require.True(t, ok, "preimage must exist")
+			return v
+		}),
+		hint: preimage.HinterFn(func(v preimage.Hint) {
+			hints.MethodCalled("hint", v.Hint())
+		}),
+	}
+
+	expectedHint := make([]byte, 48)
+	copy(expectedHint, blobHash.Hash[:])
+	binary.BigEndian.PutUint64(expectedHint[32:], blobHash.Index)
+	binary.BigEndian.PutUint64(expectedHint[40:], ref.Time)
+	hints.On("hint", BlobHint(expectedHint).Hint()).Once().Return()
+	actual := po.GetBlob(ref, blobHash)
+	hints.AssertExpectations(t)
+	require.Equal(t, blob, actual)
+}
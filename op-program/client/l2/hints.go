@@ -2,6 +2,7 @@ package l2
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 )
@@ -12,6 +13,7 @@ const (
 	HintL2Code         = "l2-code"
 	HintL2StateNode    = "l2-state-node"
 	HintL2Output       = "l2-output"
+	HintL2Precompile   = "l2-precompile"
 )
 
 type BlockHeaderHint common.Hash
@@ -53,3 +55,13 @@ var _ preimage.Hint = L2OutputHint{}
 func (l L2OutputHint) Hint() string {
 	return HintL2Output + " " + (common.Hash)(l).String()
 }
+
+// PrecompileHint requests the result of a precompile call.
+// It is the 20 byte address of the precompile followed by the input to the precompile.
+type PrecompileHint []byte
+
+var _ preimage.Hint = PrecompileHint{}
+
+func (l PrecompileHint) Hint() string {
+	return HintL2Precompile + " " + hexutil.Encode(l)
+}
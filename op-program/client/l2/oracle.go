@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
@@ -44,6 +45,7 @@ type PreimageOracle struct {
 }
 
 var _ Oracle = (*PreimageOracle)(nil)
+var _ PrecompileOracle = (*PreimageOracle)(nil)
 
 func NewPreimageOracle(raw preimage.Oracle, hint preimage.Hinter) *PreimageOracle {
 	return &PreimageOracle{
@@ -102,3 +104,14 @@ func (p *PreimageOracle) OutputByRoot(l2OutputRoot common.Hash) eth.Output {
 	}
 	return output
 }
+
+func (p *PreimageOracle) Precompile(address common.Address, input []byte) ([]byte, bool) {
+	hintBytes := append(address.Bytes(), input...)
+	p.hint.Hint(PrecompileHint(hintBytes))
+	key := preimage.PrecompileKey(crypto.Keccak256Hash(hintBytes))
+	result := p.oracle.Get(key)
+	if len(result) == 0 {
+		panic(fmt.Errorf("invalid precompile result for %s with input %x", address, input))
+	}
+	return result[1:], result[0] == 1
+}
@@ -140,3 +140,30 @@ func TestPreimageOracleOutputByRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestPreimageOraclePrecompile(t *testing.T) {
+	address := common.Address{0x1}
+	input := []byte{1, 2, 3, 4}
+	hintBytes := append(address.Bytes(), input...)
+	key := preimage.PrecompileKey(crypto.Keccak256Hash(hintBytes)).PreimageKey()
+
+	t.Run("Success", func(t *testing.T) {
+		po, hints, preimages := mockPreimageOracle(t)
+		preimages[key] = []byte{1, 5, 6, 7}
+		hints.On("hint", PrecompileHint(hintBytes).Hint()).Once().Return()
+		result, ok := po.Precompile(address, input)
+		hints.AssertExpectations(t)
+		require.True(t, ok)
+		require.Equal(t, []byte{5, 6, 7}, result)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		po, hints, preimages := mockPreimageOracle(t)
+		preimages[key] = []byte{0}
+		hints.On("hint", PrecompileHint(hintBytes).Hint()).Once().Return()
+		result, ok := po.Precompile(address, input)
+		hints.AssertExpectations(t)
+		require.False(t, ok)
+		require.Empty(t, result)
+	})
+}
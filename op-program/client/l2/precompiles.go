@@ -0,0 +1,80 @@
+package l2
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+var (
+	ecrecoverPrecompileAddress          = common.BytesToAddress([]byte{0x1})
+	bn256PairingPrecompileAddress       = common.BytesToAddress([]byte{0x8})
+	kzgPointEvaluationPrecompileAddress = common.BytesToAddress([]byte{0xa})
+)
+
+// AcceleratedPrecompiles are the precompiles that are expensive to execute in the fault proof VM,
+// so have their results retrieved from the pre-image oracle instead.
+var AcceleratedPrecompiles = []common.Address{
+	ecrecoverPrecompileAddress,
+	bn256PairingPrecompileAddress,
+	kzgPointEvaluationPrecompileAddress,
+}
+
+var ErrPrecompileFailed = errors.New("precompile failed")
+
+// PrecompileOracle retrieves the result of precompile calls.
+type PrecompileOracle interface {
+	// Precompile returns the output of the precompile at address for the given input,
+	// and whether the precompile executed successfully.
+	Precompile(address common.Address, input []byte) ([]byte, bool)
+}
+
+// OverridePrecompiles replaces the accelerated precompiles in the EVM with implementations that retrieve their
+// results from the oracle, rather than computing them.
+// This modifies the global precompile sets used by all EVM instances in the process, so must only be used when
+// the client program is the only user of the EVM, i.e. when running as a separate program.
+func OverridePrecompiles(oracle PrecompileOracle) {
+	overridePrecompiles(oracle,
+		vm.PrecompiledContractsHomestead,
+		vm.PrecompiledContractsByzantium,
+		vm.PrecompiledContractsIstanbul,
+		vm.PrecompiledContractsBerlin,
+		vm.PrecompiledContractsCancun)
+}
+
+func overridePrecompiles(oracle PrecompileOracle, precompileSets ...map[common.Address]vm.PrecompiledContract) {
+	for _, precompiles := range precompileSets {
+		for _, addr := range AcceleratedPrecompiles {
+			orig, ok := precompiles[addr]
+			if !ok {
+				continue
+			}
+			if _, ok := orig.(*precompileOverride); ok {
+				// Already overridden
+				continue
+			}
+			precompiles[addr] = &precompileOverride{address: addr, orig: orig, oracle: oracle}
+		}
+	}
+}
+
+// precompileOverride uses the oracle to retrieve the result of a precompile.
+// Gas costs are unchanged and calculated by the original implementation.
+type precompileOverride struct {
+	address common.Address
+	orig    vm.PrecompiledContract
+	oracle  PrecompileOracle
+}
+
+func (p *precompileOverride) RequiredGas(input []byte) uint64 {
+	return p.orig.RequiredGas(input)
+}
+
+func (p *precompileOverride) Run(input []byte) ([]byte, error) {
+	result, ok := p.oracle.Precompile(p.address, input)
+	if !ok {
+		return nil, ErrPrecompileFailed
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package l2
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverridePrecompiles(t *testing.T) {
+	oracle := &stubPrecompileOracle{results: make(map[common.Address]precompileResult)}
+	precompiles := make(map[common.Address]vm.PrecompiledContract)
+	for addr, precompile := range vm.PrecompiledContractsCancun {
+		precompiles[addr] = precompile
+	}
+	overridePrecompiles(oracle, precompiles)
+
+	for _, addr := range AcceleratedPrecompiles {
+		require.IsType(t, &precompileOverride{}, precompiles[addr], "precompile %v", addr)
+	}
+	// Other precompiles are unchanged
+	identityAddr := common.BytesToAddress([]byte{0x4})
+	require.Equal(t, vm.PrecompiledContractsCancun[identityAddr], precompiles[identityAddr])
+
+	// Overriding again does not wrap the overrides
+	overridePrecompiles(oracle, precompiles)
+	for _, addr := range AcceleratedPrecompiles {
+		require.IsType(t, vm.PrecompiledContractsCancun[addr], precompiles[addr].(*precompileOverride).orig)
+	}
+
+	t.Run("UseOracleResult", func(t *testing.T) {
+		input := []byte{1, 2, 3}
+		oracle.results[ecrecoverPrecompileAddress] = precompileResult{output: []byte{4, 5, 6}, ok: true}
+		precompile := precompiles[ecrecoverPrecompileAddress]
+		result, err := precompile.Run(input)
+		require.NoError(t, err)
+		require.Equal(t, []byte{4, 5, 6}, result)
+		require.Equal(t, input, oracle.inputs[len(oracle.inputs)-1])
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		oracle.results[bn256PairingPrecompileAddress] = precompileResult{ok: false}
+		_, err := precompiles[bn256PairingPrecompileAddress].Run([]byte{1})
+		require.ErrorIs(t, err, ErrPrecompileFailed)
+	})
+
+	t.Run("UseOriginalGasCost", func(t *testing.T) {
+		input := make([]byte, 192)
+		expected := vm.PrecompiledContractsCancun[bn256PairingPrecompileAddress].RequiredGas(input)
+		require.Equal(t, expected, precompiles[bn256PairingPrecompileAddress].RequiredGas(input))
+	})
+}
+
+func TestPrecompileOverrideMatchesOriginal(t *testing.T) {
+	// An oracle that runs the original precompiles, as the host does
+	oracle := &nativePrecompileOracle{}
+	precompiles := make(map[common.Address]vm.PrecompiledContract)
+	for addr, precompile := range vm.PrecompiledContractsCancun {
+		precompiles[addr] = precompile
+	}
+	overridePrecompiles(oracle, precompiles)
+
+	// A valid ecrecover input: hash, v, r, s
+	input := common.FromHex("0x456e9aea5e197a1f1af7a3e85a3212fa4049a3ba34c2289b4c860fc0b0c64ef3000000000000000000000000000000000000000000000000000000000000001c9242685bf161793cc25603c231bc2f568eb630ea16aa137d2664ac80388256084f8ae3bd7535248d0bd448298cc2e2071e56992d0774dc340c368ae950852ada")
+	expected, err := vm.PrecompiledContractsCancun[ecrecoverPrecompileAddress].Run(input)
+	require.NoError(t, err)
+	actual, err := precompiles[ecrecoverPrecompileAddress].Run(input)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	// Invalid KZG point evaluation input
+	_, err = precompiles[kzgPointEvaluationPrecompileAddress].Run([]byte{1, 2, 3})
+	require.ErrorIs(t, err, ErrPrecompileFailed)
+}
+
+type precompileResult struct {
+	output []byte
+	ok     bool
+}
+
+type stubPrecompileOracle struct {
+	results map[common.Address]precompileResult
+	inputs  [][]byte
+}
+
+func (s *stubPrecompileOracle) Precompile(address common.Address, input []byte) ([]byte, bool) {
+	s.inputs = append(s.inputs, input)
+	result, ok := s.results[address]
+	if !ok {
+		panic(errors.New("unexpected precompile call"))
+	}
+	return result.output, result.ok
+}
+
+type nativePrecompileOracle struct{}
+
+func (n *nativePrecompileOracle) Precompile(address common.Address, input []byte) ([]byte, bool) {
+	result, err := vm.PrecompiledContractsCancun[address].Run(input)
+	return result, err == nil
+}
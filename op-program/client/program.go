@@ -2,12 +2,15 @@ package client
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 
@@ -24,9 +27,12 @@ import (
 // The client runtime environment must be preset before calling this function.
 func Main(logger log.Logger) {
 	log.Info("Starting fault proof program client")
-	preimageOracle := CreatePreimageChannel()
-	preimageHinter := CreateHinterChannel()
-	if err := RunProgram(logger, preimageOracle, preimageHinter); errors.Is(err, cldr.ErrClaimNotValid) {
+	preimageOracle, preimageHinter, err := createChannels()
+	if err != nil {
+		log.Error("Failed to connect to pre-image server", "err", err)
+		os.Exit(2)
+	}
+	if err := runProgram(logger, preimageOracle, preimageHinter, true); errors.Is(err, cldr.ErrClaimNotValid) {
 		log.Error("Claim is invalid", "err", err)
 		os.Exit(1)
 	} else if err != nil {
@@ -39,15 +45,32 @@ func Main(logger log.Logger) {
 }
 
 // RunProgram executes the Program, while attached to an IO based pre-image oracle, to be served by a host.
+// Any intermediate claims supplied by the host are validated before the L2 claim, and the first invalid claim
+// is reported as a driver.InvalidClaimError.
 func RunProgram(logger log.Logger, preimageOracle io.ReadWriter, preimageHinter io.ReadWriter) error {
+	return runProgram(logger, preimageOracle, preimageHinter, false)
+}
 
+func runProgram(logger log.Logger, preimageOracle io.ReadWriter, preimageHinter io.ReadWriter, detached bool) error {
 	pClient := preimage.NewOracleClient(preimageOracle)
 	hClient := preimage.NewHintWriter(preimageHinter)
+	if detached && preimage.ExtendedKeyTypes {
+		// Only safe when running as a separate program, as the precompiles of every EVM in the process are replaced.
+		// Precompile results can't be loaded on-chain, so fault-proof builds execute the precompiles instead.
+		l2.OverridePrecompiles(l2.NewPreimageOracle(pClient, hClient))
+	}
 	l1PreimageOracle := l1.NewCachingOracle(l1.NewPreimageOracle(pClient, hClient))
 	l2PreimageOracle := l2.NewCachingOracle(l2.NewPreimageOracle(pClient, hClient))
 
-	bootInfo := NewBootstrapClient(pClient).BootInfo()
+	bootClient := NewBootstrapClient(pClient)
+	bootInfo := bootClient.BootInfo()
 	logger.Info("Program Bootstrapped", "bootInfo", bootInfo)
+	var intermediateClaims []OutputClaim
+	if !detached {
+		// Intermediate claims can't be loaded on-chain, so are only read when running in the host process.
+		intermediateClaims = bootClient.IntermediateClaims()
+		logger.Info("Verifying intermediate claims", "count", len(intermediateClaims))
+	}
 	return runDerivation(
 		logger,
 		bootInfo.RollupConfig,
@@ -56,14 +79,20 @@ func RunProgram(logger log.Logger, preimageOracle io.ReadWriter, preimageHinter
 		bootInfo.L2OutputRoot,
 		bootInfo.L2Claim,
 		bootInfo.L2ClaimBlockNumber,
+		intermediateClaims,
 		l1PreimageOracle,
 		l2PreimageOracle,
 	)
 }
 
 // runDerivation executes the L2 state transition, given a minimal interface to retrieve data.
-func runDerivation(logger log.Logger, cfg *rollup.Config, l2Cfg *params.ChainConfig, l1Head common.Hash, l2OutputRoot common.Hash, l2Claim common.Hash, l2ClaimBlockNum uint64, l1Oracle l1.Oracle, l2Oracle l2.Oracle) error {
+// The intermediate claims, which must be in ascending block order, are validated before the L2 claim.
+func runDerivation(logger log.Logger, cfg *rollup.Config, l2Cfg *params.ChainConfig, l1Head common.Hash, l2OutputRoot common.Hash, l2Claim common.Hash, l2ClaimBlockNum uint64, intermediateClaims []OutputClaim, l1Oracle l1.Oracle, l2Oracle l2.Oracle) error {
+	if cfg.UsePlasma() {
+		return errors.New("plasma mode is not supported: inputs committed to on L1 can't be fetched from a DA server")
+	}
 	l1Source := l1.NewOracleL1Client(logger, l1Oracle, l1Head)
+	l1BlobsSource := l1.NewBlobFetcher(logger, l1Oracle)
 	engineBackend, err := l2.NewOracleBackedL2Chain(logger, l2Oracle, l2Cfg, l2OutputRoot)
 	if err != nil {
 		return fmt.Errorf("failed to create oracle-backed L2 chain: %w", err)
@@ -71,7 +100,7 @@ func runDerivation(logger log.Logger, cfg *rollup.Config, l2Cfg *params.ChainCon
 	l2Source := l2.NewOracleEngine(cfg, logger, engineBackend)
 
 	logger.Info("Starting derivation")
-	d := cldr.NewDriver(logger, cfg, l1Source, l2Source, l2ClaimBlockNum)
+	d := cldr.NewDriver(logger, cfg, l1Source, l1BlobsSource, l2Source, l2ClaimBlockNum)
 	for {
 		if err = d.Step(context.Background()); errors.Is(err, io.EOF) {
 			break
@@ -79,9 +108,63 @@ func runDerivation(logger log.Logger, cfg *rollup.Config, l2Cfg *params.ChainCon
 			return err
 		}
 	}
+	for _, claim := range intermediateClaims {
+		if err := d.ValidateClaim(claim.BlockNumber, eth.Bytes32(claim.OutputRoot)); err != nil {
+			return err
+		}
+	}
 	return d.ValidateClaim(l2ClaimBlockNum, eth.Bytes32(l2Claim))
 }
 
+// createChannels returns the pre-image oracle and hinter channels of a detached client. If PreimageServerEnvVar is set
+// the client connects to the pre-image server at that address, otherwise the inherited file descriptors are used.
+func createChannels() (io.ReadWriter, io.ReadWriter, error) {
+	addr := os.Getenv(PreimageServerEnvVar)
+	if addr == "" {
+		return CreatePreimageChannel(), CreateHinterChannel(), nil
+	}
+	local, err := sessionBootInputs()
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	log.Info("Connecting to pre-image server", "addr", addr, "bootInputs", len(local))
+	return preimage.Dial(ctx, addr, local)
+}
+
+// sessionBootInputs reads the boot inputs set in the environment, to be served to this client's session only.
+func sessionBootInputs() (preimage.LocalPreimages, error) {
+	local := make(preimage.LocalPreimages)
+	hashes := []struct {
+		envVar string
+		index  preimage.LocalIndexKey
+	}{
+		{L1HeadEnvVar, L1HeadLocalIndex},
+		{L2OutputRootEnvVar, L2OutputRootLocalIndex},
+		{L2ClaimEnvVar, L2ClaimLocalIndex},
+	}
+	for _, input := range hashes {
+		value := os.Getenv(input.envVar)
+		if value == "" {
+			continue
+		}
+		hash, err := hexutil.Decode(value)
+		if err != nil || len(hash) != common.HashLength {
+			return nil, fmt.Errorf("invalid %v: %q", input.envVar, value)
+		}
+		local[input.index] = hash
+	}
+	if value := os.Getenv(L2ClaimBlockNumberEnvVar); value != "" {
+		num, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %w", L2ClaimBlockNumberEnvVar, err)
+		}
+		local[L2ClaimBlockNumberLocalIndex] = binary.BigEndian.AppendUint64(nil, num)
+	}
+	return local, nil
+}
+
 func CreateHinterChannel() oppio.FileChannel {
 	r := os.NewFile(HClientRFd, "preimage-hint-read")
 	w := os.NewFile(HClientWFd, "preimage-hint-write")
@@ -0,0 +1,245 @@
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// A bundle is a single file containing the boot inputs of a fault proof program run and the pre-images it used.
+// The layout of the file is:
+//
+//	magic (4 bytes) ++ version (1 byte)
+//	boot info length (uint32) ++ boot info JSON
+//	for each pre-image: pre-image data
+//	for each pre-image, sorted by key: key (32 bytes) ++ data offset (uint64) ++ data length (uint32)
+//	index offset (uint64) ++ number of pre-images (uint32)
+//
+// All integers are big-endian. The index at the end of the file allows pre-images to be read without loading the
+// whole bundle into memory.
+
+var magic = [4]byte{'O', 'P', 'P', 'B'}
+
+const (
+	version     = 1
+	headerSize  = len(magic) + 1
+	entrySize   = common.HashLength + 8 + 4
+	trailerSize = 8 + 4
+)
+
+var (
+	ErrNotFound       = errors.New("not found in bundle")
+	ErrInvalidBundle  = errors.New("invalid bundle")
+	ErrUnknownVersion = errors.New("unknown bundle version")
+)
+
+// BootInfo is the set of local inputs the fault proof program was run with.
+type BootInfo struct {
+	L1Head             common.Hash `json:"l1Head"`
+	L2Head             common.Hash `json:"l2Head"`
+	L2OutputRoot       common.Hash `json:"l2OutputRoot"`
+	L2Claim            common.Hash `json:"l2Claim"`
+	L2ClaimBlockNumber uint64      `json:"l2ClaimBlockNumber"`
+
+	L2IntermediateClaims []client.OutputClaim `json:"l2IntermediateClaims,omitempty"`
+
+	Rollup        *rollup.Config      `json:"rollupConfig"`
+	L2ChainConfig *params.ChainConfig `json:"l2ChainConfig"`
+	// IsCustomChainConfig indicates that the L2 chain config is not one of the predefined chain configs
+	IsCustomChainConfig bool `json:"isCustomChainConfig"`
+}
+
+type indexEntry struct {
+	offset uint64
+	length uint32
+}
+
+// Write writes a bundle containing the boot info and pre-images to the file at path.
+// The file is written to a temporary file first, and only moved into place once complete.
+func Write(path string, boot *BootInfo, preimages map[common.Hash][]byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer os.Remove(f.Name()) // Clean up the temp file if it doesn't get moved into place
+	if err := write(f, boot, preimages); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close bundle file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to move bundle to %v: %w", path, err)
+	}
+	return nil
+}
+
+func write(out io.Writer, boot *BootInfo, preimages map[common.Hash][]byte) error {
+	bootData, err := json.Marshal(boot)
+	if err != nil {
+		return fmt.Errorf("failed to encode boot info: %w", err)
+	}
+	keys := make([]common.Hash, 0, len(preimages))
+	for key := range preimages {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+
+	w := bufio.NewWriter(out)
+	var buf []byte
+	buf = append(buf, magic[:]...)
+	buf = append(buf, version)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(bootData)))
+	if _, err := w.Write(buf); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	if _, err := w.Write(bootData); err != nil {
+		return fmt.Errorf("failed to write boot info: %w", err)
+	}
+
+	offset := uint64(len(buf) + len(bootData))
+	index := make([]byte, 0, len(keys)*entrySize)
+	for _, key := range keys {
+		data := preimages[key]
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write pre-image %v: %w", key, err)
+		}
+		index = append(index, key[:]...)
+		index = binary.BigEndian.AppendUint64(index, offset)
+		index = binary.BigEndian.AppendUint32(index, uint32(len(data)))
+		offset += uint64(len(data))
+	}
+	index = binary.BigEndian.AppendUint64(index, offset)
+	index = binary.BigEndian.AppendUint32(index, uint32(len(keys)))
+	if _, err := w.Write(index); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return w.Flush()
+}
+
+// Reader reads the boot info and pre-images from a bundle.
+// Reader is safe for concurrent use.
+type Reader struct {
+	f     *os.File
+	boot  *BootInfo
+	index map[common.Hash]indexEntry
+}
+
+// Open opens the bundle at path and reads its boot info and index.
+// The bundle must be closed by calling Close once it is no longer required.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	r, err := newReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func newReader(f *os.File) (*Reader, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat bundle: %w", err)
+	}
+	size := uint64(info.Size())
+	if size < uint64(headerSize+4+trailerSize) {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidBundle)
+	}
+
+	header := make([]byte, headerSize+4)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if !bytes.Equal(header[:len(magic)], magic[:]) {
+		return nil, fmt.Errorf("%w: incorrect magic", ErrInvalidBundle)
+	}
+	if header[len(magic)] != version {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownVersion, header[len(magic)])
+	}
+	bootLen := uint64(binary.BigEndian.Uint32(header[headerSize:]))
+	dataStart := uint64(len(header)) + bootLen
+	if dataStart > size-trailerSize {
+		return nil, fmt.Errorf("%w: boot info exceeds bundle size", ErrInvalidBundle)
+	}
+	bootData := make([]byte, bootLen)
+	if _, err := f.ReadAt(bootData, int64(len(header))); err != nil {
+		return nil, fmt.Errorf("failed to read boot info: %w", err)
+	}
+	var boot BootInfo
+	if err := json.Unmarshal(bootData, &boot); err != nil {
+		return nil, fmt.Errorf("failed to decode boot info: %w", err)
+	}
+
+	trailer := make([]byte, trailerSize)
+	if _, err := f.ReadAt(trailer, int64(size-trailerSize)); err != nil {
+		return nil, fmt.Errorf("failed to read index location: %w", err)
+	}
+	indexOffset := binary.BigEndian.Uint64(trailer)
+	count := uint64(binary.BigEndian.Uint32(trailer[8:]))
+	if indexOffset < dataStart || indexOffset+count*uint64(entrySize) != size-trailerSize {
+		return nil, fmt.Errorf("%w: index location does not match bundle size", ErrInvalidBundle)
+	}
+	indexData := make([]byte, count*uint64(entrySize))
+	if _, err := f.ReadAt(indexData, int64(indexOffset)); err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	index := make(map[common.Hash]indexEntry, count)
+	for i := uint64(0); i < count; i++ {
+		entry := indexData[i*uint64(entrySize) : (i+1)*uint64(entrySize)]
+		key := common.BytesToHash(entry[:common.HashLength])
+		offset := binary.BigEndian.Uint64(entry[common.HashLength:])
+		length := binary.BigEndian.Uint32(entry[common.HashLength+8:])
+		if offset < dataStart || offset+uint64(length) > indexOffset {
+			return nil, fmt.Errorf("%w: pre-image %v out of bounds", ErrInvalidBundle, key)
+		}
+		index[key] = indexEntry{offset: offset, length: length}
+	}
+	return &Reader{f: f, boot: &boot, index: index}, nil
+}
+
+// BootInfo returns the boot info the bundle was created with.
+func (r *Reader) BootInfo() *BootInfo {
+	return r.boot
+}
+
+// Len returns the number of pre-images in the bundle.
+func (r *Reader) Len() int {
+	return len(r.index)
+}
+
+// Get retrieves the pre-image with key k from the bundle.
+// It returns ErrNotFound when the pre-image is not included in the bundle.
+func (r *Reader) Get(k common.Hash) ([]byte, error) {
+	entry, ok := r.index[k]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	data := make([]byte, entry.length)
+	if _, err := r.f.ReadAt(data, int64(entry.offset)); err != nil {
+		return nil, fmt.Errorf("failed to read pre-image %v from bundle: %w", k, err)
+	}
+	return data, nil
+}
+
+func (r *Reader) Close() error {
+	return r.f.Close()
+}
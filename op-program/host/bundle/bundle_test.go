@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
+	"github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	boot := &BootInfo{
+		L1Head:             common.Hash{0x11},
+		L2Head:             common.Hash{0x22},
+		L2OutputRoot:       common.Hash{0x33},
+		L2Claim:            common.Hash{0x44},
+		L2ClaimBlockNumber: 1000,
+		L2IntermediateClaims: []client.OutputClaim{
+			{BlockNumber: 999, OutputRoot: common.Hash{0x55}},
+		},
+		Rollup:              chaincfg.Goerli,
+		L2ChainConfig:       chainconfig.OPGoerliChainConfig,
+		IsCustomChainConfig: true,
+	}
+	preimages := map[common.Hash][]byte{
+		{0xaa}: {1, 2, 3},
+		{0xbb}: {},
+		{0x01}: []byte("hello world"),
+	}
+	path := filepath.Join(t.TempDir(), "bundle.bin")
+	require.NoError(t, Write(path, boot, preimages))
+
+	r, err := Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+	require.Equal(t, boot, r.BootInfo())
+	require.Equal(t, len(preimages), r.Len())
+	for key, expected := range preimages {
+		actual, err := r.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+	_, err = r.Get(common.Hash{0xcc})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle.bin")
+	require.NoError(t, Write(path, &BootInfo{}, nil))
+
+	r, err := Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+	require.Equal(t, &BootInfo{}, r.BootInfo())
+	require.Zero(t, r.Len())
+}
+
+func TestInvalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bundle.bin")
+	require.NoError(t, Write(path, &BootInfo{L1Head: common.Hash{0x11}}, map[common.Hash][]byte{{0xaa}: {1, 2, 3}}))
+	valid, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	openModified := func(t *testing.T, modify func(data []byte) []byte) error {
+		data := modify(append([]byte{}, valid...))
+		modifiedPath := filepath.Join(t.TempDir(), "modified.bin")
+		require.NoError(t, os.WriteFile(modifiedPath, data, 0644))
+		r, err := Open(modifiedPath)
+		if err == nil {
+			_ = r.Close()
+		}
+		return err
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := Open(filepath.Join(dir, "missing.bin"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("TooShort", func(t *testing.T) {
+		err := openModified(t, func(data []byte) []byte {
+			return data[:10]
+		})
+		require.ErrorIs(t, err, ErrInvalidBundle)
+	})
+
+	t.Run("IncorrectMagic", func(t *testing.T) {
+		err := openModified(t, func(data []byte) []byte {
+			data[0] = 'X'
+			return data
+		})
+		require.ErrorIs(t, err, ErrInvalidBundle)
+	})
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		err := openModified(t, func(data []byte) []byte {
+			data[len(magic)] = version + 1
+			return data
+		})
+		require.ErrorIs(t, err, ErrUnknownVersion)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		err := openModified(t, func(data []byte) []byte {
+			return data[:len(data)-1]
+		})
+		require.ErrorIs(t, err, ErrInvalidBundle)
+	})
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/ethereum-optimism/optimism/op-program/host"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum-optimism/optimism/op-program/host/flags"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
 	"github.com/ethereum-optimism/optimism/op-program/host/version"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
@@ -23,17 +26,39 @@ var VersionWithMeta = opservice.FormatVersion(version.Version, GitCommit, GitDat
 
 func main() {
 	args := os.Args
-	if err := run(args, host.Main); err != nil {
+	if err := run(args, host.Main, host.ExportBundle, verifyRange); err != nil {
 		log.Crit("Application failed", "err", err)
 	}
 }
 
 type ConfigAction func(log log.Logger, config *config.Config) error
 
+// ExportAction runs the program with the supplied config and writes a bundle to output.
+type ExportAction func(log log.Logger, config *config.Config, output string) error
+
+var OutputFlag = &cli.StringFlag{
+	Name:     "output",
+	Usage:    "Path to write the bundle to",
+	Required: true,
+}
+
+var (
+	MigrateSourceFlag = &cli.StringFlag{
+		Name:     "source",
+		Usage:    "Data directory containing pre-images in the directory data format",
+		Required: true,
+	}
+	MigrateDestFlag = &cli.StringFlag{
+		Name:     "dest",
+		Usage:    "Data directory to write pre-images to in the pebble data format",
+		Required: true,
+	}
+)
+
 // run parses the supplied args to create a config.Config instance, sets up logging
-// then calls the supplied ConfigAction.
+// then calls the supplied ConfigAction, or the action for the export-bundle or verify-range command.
 // This allows testing the translation from CLI arguments to Config
-func run(args []string, action ConfigAction) error {
+func run(args []string, action ConfigAction, export ExportAction, verifyRange VerifyRangeAction) error {
 	// Set up logger with a default INFO level in case we fail to parse flags,
 	// otherwise the final critical log won't show what the parsing error was.
 	oplog.SetupDefaults()
@@ -57,10 +82,62 @@ func run(args []string, action ConfigAction) error {
 		}
 		return action(logger, cfg)
 	}
+	app.Commands = []*cli.Command{
+		{
+			Name:        "export-bundle",
+			Usage:       "Runs the program and exports its inputs and all pre-images it used to a single bundle file",
+			Description: "Runs the program with the same options as op-program, then writes a bundle containing the program inputs and every pre-image requested by the client to --output. The bundle can be used with --bundle to rerun the program without network access.",
+			Flags:       append([]cli.Flag{OutputFlag}, flags.Flags...),
+			Action: func(ctx *cli.Context) error {
+				logger, err := setupLogging(ctx)
+				if err != nil {
+					return err
+				}
+				logger.Info("Exporting fault proof program bundle", "version", VersionWithMeta)
+
+				cfg, err := config.NewConfigFromCLI(logger, ctx)
+				if err != nil {
+					return err
+				}
+				return export(logger, cfg, ctx.String(OutputFlag.Name))
+			},
+		},
+		{
+			Name:        "migrate-datadir",
+			Usage:       "Copies all pre-images from a data directory in the directory format to a new data directory in the pebble format",
+			Description: "Copies all pre-images from the --source data directory, which must use the directory data format, to --dest in the pebble data format. The source data directory is not modified.",
+			Flags:       append([]cli.Flag{MigrateSourceFlag, MigrateDestFlag}, oplog.CLIFlags(flags.EnvVarPrefix)...),
+			Action:      migrateDataDir,
+		},
+		verifyRangeCommand(verifyRange),
+	}
 
 	return app.Run(args)
 }
 
+func migrateDataDir(ctx *cli.Context) error {
+	logger, err := setupLogging(ctx)
+	if err != nil {
+		return err
+	}
+	src := ctx.String(MigrateSourceFlag.Name)
+	dest := ctx.String(MigrateDestFlag.Name)
+	logger.Info("Migrating data directory", "source", src, "dest", dest)
+	kv, err := kvstore.NewPebbleKV(dest)
+	if err != nil {
+		return err
+	}
+	count, err := kvstore.MigrateDiskKV(logger, src, kv)
+	if closeErr := kv.Close(); closeErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to close database: %w", closeErr))
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("Migrated data directory", "preimages", count)
+	return nil
+}
+
 func setupLogging(ctx *cli.Context) (log.Logger, error) {
 	logCfg := oplog.ReadCLIConfig(ctx)
 	logger := oplog.NewLogger(oplog.AppOut(ctx), logCfg)
@@ -2,13 +2,20 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
+	"github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum-optimism/optimism/op-program/host/bundle"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -188,6 +195,19 @@ func TestL1(t *testing.T) {
 	require.Equal(t, expected, cfg.L1URL)
 }
 
+func TestL1Beacon(t *testing.T) {
+	t.Run("DefaultEmpty", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, "", cfg.L1BeaconURL)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		expected := "https://example.com:5052"
+		cfg := configForArgs(t, addRequiredArgs("--l1.beacon", expected))
+		require.Equal(t, expected, cfg.L1BeaconURL)
+	})
+}
+
 func TestL1TrustRPC(t *testing.T) {
 	t.Run("DefaultFalse", func(t *testing.T) {
 		cfg := configForArgs(t, addRequiredArgs())
@@ -251,6 +271,30 @@ func TestL2Claim(t *testing.T) {
 	})
 }
 
+func TestL2IntermediateClaims(t *testing.T) {
+	t.Run("DefaultEmpty", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Empty(t, cfg.L2IntermediateClaims)
+	})
+	t.Run("Valid", func(t *testing.T) {
+		claim1 := common.HexToHash("0x5555")
+		claim2 := common.HexToHash("0x6666")
+		cfg := configForArgs(t, addRequiredArgs(
+			"--l2.intermediateclaims", "1201:"+claim1.Hex(),
+			"--l2.intermediateclaims", "1202:"+claim2.Hex()))
+		require.Equal(t, []client.OutputClaim{{BlockNumber: 1201, OutputRoot: claim1}, {BlockNumber: 1202, OutputRoot: claim2}}, cfg.L2IntermediateClaims)
+	})
+	t.Run("MissingSeparator", func(t *testing.T) {
+		verifyArgsInvalid(t, config.ErrInvalidIntermediateClaims.Error(), addRequiredArgs("--l2.intermediateclaims", "1201"))
+	})
+	t.Run("InvalidBlockNumber", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid block number", addRequiredArgs("--l2.intermediateclaims", "abc:"+l2ClaimValue))
+	})
+	t.Run("InvalidOutputRoot", func(t *testing.T) {
+		verifyArgsInvalid(t, "invalid output root", addRequiredArgs("--l2.intermediateclaims", "1201:0x1234"))
+	})
+}
+
 func TestL2BlockNumber(t *testing.T) {
 	t.Run("Required", func(t *testing.T) {
 		verifyArgsInvalid(t, "flag l2.blocknumber is required", addRequiredArgsExcept("--l2.blocknumber"))
@@ -266,6 +310,46 @@ func TestL2BlockNumber(t *testing.T) {
 	})
 }
 
+func TestDataFormat(t *testing.T) {
+	t.Run("DefaultDirectory", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, types.DataFormatDirectory, cfg.DataFormat)
+	})
+	for _, format := range types.SupportedDataFormats {
+		format := format
+		t.Run(fmt.Sprintf("Valid-%v", format), func(t *testing.T) {
+			cfg := configForArgs(t, addRequiredArgs("--data.format", string(format)))
+			require.Equal(t, format, cfg.DataFormat)
+		})
+	}
+	t.Run("Invalid", func(t *testing.T) {
+		verifyArgsInvalid(t, "unknown data format: \"foo\"", addRequiredArgs("--data.format", "foo"))
+	})
+}
+
+func TestMigrateDataDir(t *testing.T) {
+	t.Run("RequiresSource", func(t *testing.T) {
+		verifyArgsInvalid(t, "Required flag \"source\" not set", []string{"migrate-datadir", "--dest", t.TempDir()})
+	})
+	t.Run("RequiresDest", func(t *testing.T) {
+		verifyArgsInvalid(t, "Required flag \"dest\" not set", []string{"migrate-datadir", "--source", t.TempDir()})
+	})
+	t.Run("Migrate", func(t *testing.T) {
+		src := t.TempDir()
+		dest := filepath.Join(t.TempDir(), "pebble")
+		require.NoError(t, kvstore.NewDiskKV(src).Put(common.Hash{0xaa}, []byte{1, 2, 3}))
+		_, _, err := runWithArgs([]string{"migrate-datadir", "--source", src, "--dest", dest})
+		require.NoError(t, err)
+
+		kv, err := kvstore.NewPebbleKV(dest)
+		require.NoError(t, err)
+		defer kv.Close()
+		actual, err := kv.Get(common.Hash{0xaa})
+		require.NoError(t, err)
+		require.Equal(t, []byte{1, 2, 3}, actual)
+	})
+}
+
 func TestExec(t *testing.T) {
 	t.Run("DefaultEmpty", func(t *testing.T) {
 		cfg := configForArgs(t, addRequiredArgs())
@@ -300,6 +384,122 @@ func TestServerMode(t *testing.T) {
 	})
 }
 
+func TestServerAddr(t *testing.T) {
+	t.Run("DefaultEmpty", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, "", cfg.ServerAddr)
+	})
+	t.Run("Set", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs("--server", "--server.addr", "unix:///tmp/op-program.sock"))
+		require.True(t, cfg.ServerMode)
+		require.Equal(t, "unix:///tmp/op-program.sock", cfg.ServerAddr)
+	})
+}
+
+func TestBundle(t *testing.T) {
+	t.Run("DefaultEmpty", func(t *testing.T) {
+		cfg := configForArgs(t, addRequiredArgs())
+		require.Equal(t, "", cfg.Bundle)
+	})
+	t.Run("LoadInputsFromBundle", func(t *testing.T) {
+		expected := configForArgs(t, addRequiredArgs())
+		path := filepath.Join(t.TempDir(), "bundle.bin")
+		require.NoError(t, bundle.Write(path, expected.BootInfo(), nil))
+
+		cfg := configForArgs(t, []string{"--bundle", path})
+		expected.Bundle = path
+		require.Equal(t, expected, cfg)
+		require.NoError(t, cfg.Check())
+	})
+	t.Run("WithServerMode", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bundle.bin")
+		require.NoError(t, bundle.Write(path, configForArgs(t, addRequiredArgs()).BootInfo(), nil))
+		cfg := configForArgs(t, []string{"--bundle", path, "--server"})
+		require.True(t, cfg.ServerMode)
+	})
+	t.Run("Missing", func(t *testing.T) {
+		verifyArgsInvalid(t, "failed to open bundle", []string{"--bundle", filepath.Join(t.TempDir(), "missing.bin")})
+	})
+	for _, arg := range []string{"--network", "--l1.head", "--l2.claim", "--datadir", "--l1", "--l2"} {
+		arg := arg
+		t.Run("Reject_"+arg, func(t *testing.T) {
+			verifyArgsInvalid(t, fmt.Sprintf("flag %s cannot be used with bundle", arg[2:]), []string{"--bundle", "bundle.bin", arg, "foo"})
+		})
+	}
+}
+
+func TestExportBundle(t *testing.T) {
+	t.Run("RequiresOutput", func(t *testing.T) {
+		_, _, err := runExportWithArgs(addRequiredArgs())
+		require.ErrorContains(t, err, "Required flag \"output\" not set")
+	})
+	t.Run("Valid", func(t *testing.T) {
+		cfg, output, err := runExportWithArgs(addRequiredArgs("--output", "/tmp/bundle.bin", "--datadir", "/tmp/data"))
+		require.NoError(t, err)
+		require.Equal(t, "/tmp/bundle.bin", output)
+		require.Equal(t, common.HexToHash(l1HeadValue), cfg.L1Head)
+		require.Equal(t, "/tmp/data", cfg.DataDir)
+	})
+	t.Run("RequiresProgramArgs", func(t *testing.T) {
+		_, _, err := runExportWithArgs([]string{"--output", "/tmp/bundle.bin"})
+		require.ErrorContains(t, err, "flag rollup.config or network is required")
+	})
+}
+
+func TestVerifyRange(t *testing.T) {
+	rangeArgs := func(args ...string) []string {
+		return append([]string{"--network", "goerli", "--l1", "http://localhost:8545", "--l2", "http://localhost:9545",
+			"--start", "100", "--end", "200", "--report", "/tmp/report.json"}, args...)
+	}
+	t.Run("Valid", func(t *testing.T) {
+		args, err := runVerifyRangeWithArgs(rangeArgs())
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), args.start)
+		require.Equal(t, uint64(200), args.end)
+		require.Equal(t, 4, args.workers)
+		require.Equal(t, "/tmp/report.json", args.reportPath)
+		require.Equal(t, "http://localhost:8545", args.cfg.L1URL)
+		require.Equal(t, "http://localhost:9545", args.cfg.L2URL)
+		require.Equal(t, common.Hash{}, args.cfg.L1Head)
+		require.Equal(t, chainconfig.OPGoerliChainConfig, args.cfg.L2ChainConfig)
+		require.False(t, args.cfg.IsCustomChainConfig)
+	})
+	t.Run("Options", func(t *testing.T) {
+		args, err := runVerifyRangeWithArgs(rangeArgs("--workers", "8", "--l1.head", l1HeadValue, "--datadir", "/tmp/data"))
+		require.NoError(t, err)
+		require.Equal(t, 8, args.workers)
+		require.Equal(t, common.HexToHash(l1HeadValue), args.cfg.L1Head)
+		require.Equal(t, "/tmp/data", args.cfg.DataDir)
+	})
+	t.Run("CustomChain", func(t *testing.T) {
+		genesisFile := writeValidGenesis(t)
+		rollupFile := writeValidRollupConfig(t)
+		args, err := runVerifyRangeWithArgs([]string{"--rollup.config", rollupFile, "--l2.genesis", genesisFile,
+			"--start", "100", "--end", "200", "--report", "/tmp/report.json"})
+		require.NoError(t, err)
+		require.Equal(t, l2GenesisConfig, args.cfg.L2ChainConfig)
+		require.True(t, args.cfg.IsCustomChainConfig)
+	})
+	t.Run("RequiresNetwork", func(t *testing.T) {
+		_, err := runVerifyRangeWithArgs([]string{"--start", "100", "--end", "200", "--report", "/tmp/report.json"})
+		require.ErrorContains(t, err, "flag rollup.config or network is required")
+	})
+	for _, flag := range []string{"start", "end", "report"} {
+		flag := flag
+		t.Run("Requires-"+flag, func(t *testing.T) {
+			args := rangeArgs()
+			for i, arg := range args {
+				if arg == "--"+flag {
+					args = append(args[:i], args[i+2:]...)
+					break
+				}
+			}
+			_, err := runVerifyRangeWithArgs(args)
+			require.ErrorContains(t, err, fmt.Sprintf("Required flag \"%v\" not set", flag))
+		})
+	}
+}
+
 func verifyArgsInvalid(t *testing.T, messageContains string, cliArgs []string) {
 	_, _, err := runWithArgs(cliArgs)
 	require.ErrorContains(t, err, messageContains)
@@ -319,10 +519,52 @@ func runWithArgs(cliArgs []string) (log.Logger, *config.Config, error) {
 		logger = log
 		cfg = config
 		return nil
-	})
+	}, func(log log.Logger, config *config.Config, output string) error {
+		return errors.New("unexpected export")
+	}, unexpectedVerifyRange)
 	return logger, cfg, err
 }
 
+func unexpectedVerifyRange(log log.Logger, config *config.Config, start uint64, end uint64, workers int, reportPath string) error {
+	return errors.New("unexpected verify-range")
+}
+
+type verifyRangeArgs struct {
+	cfg        *config.Config
+	start      uint64
+	end        uint64
+	workers    int
+	reportPath string
+}
+
+func runVerifyRangeWithArgs(cliArgs []string) (*verifyRangeArgs, error) {
+	var result *verifyRangeArgs
+	fullArgs := append([]string{"op-program", "verify-range"}, cliArgs...)
+	err := run(fullArgs, func(log log.Logger, config *config.Config) error {
+		return errors.New("unexpected run")
+	}, func(log log.Logger, config *config.Config, output string) error {
+		return errors.New("unexpected export")
+	}, func(log log.Logger, config *config.Config, start uint64, end uint64, workers int, reportPath string) error {
+		result = &verifyRangeArgs{cfg: config, start: start, end: end, workers: workers, reportPath: reportPath}
+		return nil
+	})
+	return result, err
+}
+
+func runExportWithArgs(cliArgs []string) (*config.Config, string, error) {
+	cfg := new(config.Config)
+	var output string
+	fullArgs := append([]string{"op-program", "export-bundle"}, cliArgs...)
+	err := run(fullArgs, func(log log.Logger, config *config.Config) error {
+		return errors.New("unexpected run")
+	}, func(log log.Logger, config *config.Config, out string) error {
+		cfg = config
+		output = out
+		return nil
+	}, unexpectedVerifyRange)
+	return cfg, output, err
+}
+
 func addRequiredArgs(args ...string) []string {
 	req := requiredArgs()
 	combined := toArgList(req)
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-program/host/flags"
+	"github.com/ethereum-optimism/optimism/op-program/host/verify"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+)
+
+// VerifyRangeAction verifies the output roots of the blocks after start up to and including end.
+type VerifyRangeAction func(log log.Logger, config *config.Config, start uint64, end uint64, workers int, reportPath string) error
+
+var (
+	StartFlag = &cli.Uint64Flag{
+		Name:     "start",
+		Usage:    "L2 block number to start verifying from. The output root at this block is trusted.",
+		Required: true,
+	}
+	EndFlag = &cli.Uint64Flag{
+		Name:     "end",
+		Usage:    "Last L2 block number to verify the output root of",
+		Required: true,
+	}
+	WorkersFlag = &cli.IntFlag{
+		Name:  "workers",
+		Usage: "Number of blocks to verify in parallel",
+		Value: 4,
+	}
+	ReportFlag = &cli.StringFlag{
+		Name:     "report",
+		Usage:    "Path to write the JSON report to",
+		Required: true,
+	}
+)
+
+func verifyRangeCommand(action VerifyRangeAction) *cli.Command {
+	cmdFlags := []cli.Flag{
+		flags.RollupConfig,
+		flags.Network,
+		flags.L2GenesisPath,
+		flags.L1NodeAddr,
+		flags.L1BeaconAddr,
+		flags.L1TrustRPC,
+		flags.L1RPCProviderKind,
+		flags.L2NodeAddr,
+		flags.L1Head,
+		flags.DataDir,
+		flags.DataFormat,
+		StartFlag,
+		EndFlag,
+		WorkersFlag,
+		ReportFlag,
+	}
+	return &cli.Command{
+		Name:  "verify-range",
+		Usage: "Runs the program natively to verify the output root of each block in a range against an L2 node",
+		Description: "Runs the program in-process for each block after --start up to and including --end, using the output root of " +
+			"the previous block reported by the L2 node as the agreed starting point and the block's output root as the claim. " +
+			"Blocks are verified in parallel and the result, duration and pre-images used for each block are written to --report. " +
+			"If --l1.head is not set, the finalized L1 block is used. Exits with an error if any block fails verification.",
+		Flags: append(cmdFlags, oplog.CLIFlags(flags.EnvVarPrefix)...),
+		Action: func(ctx *cli.Context) error {
+			logger, err := setupLogging(ctx)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.NewBaseConfigFromCLI(logger, ctx)
+			if err != nil {
+				return err
+			}
+			return action(logger, cfg, ctx.Uint64(StartFlag.Name), ctx.Uint64(EndFlag.Name), ctx.Int(WorkersFlag.Name), ctx.String(ReportFlag.Name))
+		},
+	}
+}
+
+func verifyRange(logger log.Logger, cfg *config.Config, start uint64, end uint64, workers int, reportPath string) error {
+	logger.Info("Verifying block range", "start", start, "end", end, "workers", workers)
+	report, err := verify.Run(context.Background(), logger, cfg, start, end, workers)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(reportPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	logger.Info("Verification complete", "passed", report.Passed, "failed", report.Failed, "report", reportPath)
+	if report.Failed > 0 {
+		return fmt.Errorf("%w: %v of %v blocks", verify.ErrVerificationFailed, report.Failed, len(report.Results))
+	}
+	return nil
+}
@@ -5,12 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 
 	opnode "github.com/ethereum-optimism/optimism/op-node"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum-optimism/optimism/op-program/host/bundle"
 	"github.com/ethereum-optimism/optimism/op-program/host/flags"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -20,16 +26,21 @@ import (
 )
 
 var (
-	ErrMissingRollupConfig = errors.New("missing rollup config")
-	ErrMissingL2Genesis    = errors.New("missing l2 genesis")
-	ErrInvalidL1Head       = errors.New("invalid l1 head")
-	ErrInvalidL2Head       = errors.New("invalid l2 head")
-	ErrInvalidL2OutputRoot = errors.New("invalid l2 output root")
-	ErrL1AndL2Inconsistent = errors.New("l1 and l2 options must be specified together or both omitted")
-	ErrInvalidL2Claim      = errors.New("invalid l2 claim")
-	ErrInvalidL2ClaimBlock = errors.New("invalid l2 claim block number")
-	ErrDataDirRequired     = errors.New("datadir must be specified when in non-fetching mode")
-	ErrNoExecInServerMode  = errors.New("exec command must not be set when in server mode")
+	ErrMissingRollupConfig            = errors.New("missing rollup config")
+	ErrMissingL2Genesis               = errors.New("missing l2 genesis")
+	ErrInvalidL1Head                  = errors.New("invalid l1 head")
+	ErrInvalidL2Head                  = errors.New("invalid l2 head")
+	ErrInvalidL2OutputRoot            = errors.New("invalid l2 output root")
+	ErrL1AndL2Inconsistent            = errors.New("l1 and l2 options must be specified together or both omitted")
+	ErrInvalidL2Claim                 = errors.New("invalid l2 claim")
+	ErrInvalidL2ClaimBlock            = errors.New("invalid l2 claim block number")
+	ErrInvalidIntermediateClaims      = errors.New("intermediate claims must be in ascending block order and before the l2 claim block")
+	ErrIntermediateClaimsNotInProcess = errors.New("intermediate claims require the client program to run in-process")
+	ErrDataDirRequired                = errors.New("datadir must be specified when in non-fetching mode")
+	ErrInvalidDataFormat              = errors.New("invalid data format")
+	ErrNoExecInServerMode             = errors.New("exec command must not be set when in server mode")
+	ErrServerAddrWithoutServerMode    = errors.New("server address can only be used in server mode")
+	ErrBundleWithPreimages            = errors.New("datadir and fetching must not be used with a bundle")
 )
 
 type Config struct {
@@ -37,12 +48,17 @@ type Config struct {
 	// DataDir is the directory to read/write pre-image data from/to.
 	//If not set, an in-memory key-value store is used and fetching data must be enabled
 	DataDir string
+	// DataFormat is the format used to store pre-images in DataDir
+	DataFormat types.DataFormat
 
 	// L1Head is the block has of the L1 chain head block
-	L1Head     common.Hash
-	L1URL      string
-	L1TrustRPC bool
-	L1RPCKind  sources.RPCProviderKind
+	L1Head common.Hash
+	L1URL  string
+	// L1BeaconURL is the address of the L1 beacon API, used to fetch blobs.
+	// Optional, but required to fetch data when the batcher posts blobs.
+	L1BeaconURL string
+	L1TrustRPC  bool
+	L1RPCKind   sources.RPCProviderKind
 
 	// L2Head is the l2 block hash contained in the L2 Output referenced by the L2OutputRoot
 	// TODO(inphi): This can be made optional with hardcoded rollup configs and output oracle addresses by searching the oracle for the l2 output root
@@ -55,6 +71,9 @@ type Config struct {
 	// L2ClaimBlockNumber is the block number the claimed L2 output root is from
 	// Must be above 0 and to be a valid claim needs to be above the L2Head block.
 	L2ClaimBlockNumber uint64
+	// L2IntermediateClaims are claimed output roots of blocks before L2ClaimBlockNumber, in ascending block order.
+	// They are validated in a single execution of the client program along with L2Claim.
+	L2IntermediateClaims []client.OutputClaim
 	// L2ChainConfig is the op-geth chain config for the L2 execution engine
 	L2ChainConfig *params.ChainConfig
 	// ExecCmd specifies the client program to execute in a separate process.
@@ -64,9 +83,16 @@ type Config struct {
 	// ServerMode indicates that the program should run in pre-image server mode and wait for requests.
 	// No client program is run.
 	ServerMode bool
+	// ServerAddr is the unix://<path> or tcp://<host>:<port> address to accept client program connections on in
+	// server mode. If unset, a single client program is served via inherited file descriptors.
+	ServerAddr string
 
 	// IsCustomChainConfig indicates that the program uses a custom chain configuration
 	IsCustomChainConfig bool
+
+	// Bundle is the path to a bundle created by export-bundle to read all pre-images from.
+	// If set, no data is fetched and DataDir is not used.
+	Bundle string
 }
 
 func (c *Config) Check() error {
@@ -91,15 +117,41 @@ func (c *Config) Check() error {
 	if c.L2ChainConfig == nil {
 		return ErrMissingL2Genesis
 	}
+	if len(c.L2IntermediateClaims) > 0 {
+		if c.ExecCmd != "" || c.ServerMode {
+			return ErrIntermediateClaimsNotInProcess
+		}
+		prev := uint64(0)
+		for _, claim := range c.L2IntermediateClaims {
+			if claim.BlockNumber <= prev || claim.BlockNumber >= c.L2ClaimBlockNumber {
+				return fmt.Errorf("%w: block %v", ErrInvalidIntermediateClaims, claim.BlockNumber)
+			}
+			prev = claim.BlockNumber
+		}
+	}
 	if (c.L1URL != "") != (c.L2URL != "") {
 		return ErrL1AndL2Inconsistent
 	}
-	if !c.FetchingEnabled() && c.DataDir == "" {
+	if c.Bundle != "" && (c.FetchingEnabled() || c.DataDir != "") {
+		return ErrBundleWithPreimages
+	}
+	if !c.FetchingEnabled() && c.DataDir == "" && c.Bundle == "" {
 		return ErrDataDirRequired
 	}
+	if !types.ValidDataFormat(c.DataFormat) {
+		return fmt.Errorf("%w: %v", ErrInvalidDataFormat, c.DataFormat)
+	}
 	if c.ServerMode && c.ExecCmd != "" {
 		return ErrNoExecInServerMode
 	}
+	if c.ServerAddr != "" {
+		if !c.ServerMode {
+			return ErrServerAddrWithoutServerMode
+		}
+		if _, _, err := preimage.ParseAddress(c.ServerAddr); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -128,6 +180,7 @@ func NewConfig(
 		L2Claim:             l2Claim,
 		L2ClaimBlockNumber:  l2ClaimBlockNum,
 		L1RPCKind:           sources.RPCKindStandard,
+		DataFormat:          types.DataFormatDirectory,
 		IsCustomChainConfig: isCustomConfig,
 	}
 }
@@ -136,6 +189,9 @@ func NewConfigFromCLI(log log.Logger, ctx *cli.Context) (*Config, error) {
 	if err := flags.CheckRequired(ctx); err != nil {
 		return nil, err
 	}
+	if bundlePath := ctx.String(flags.Bundle.Name); bundlePath != "" {
+		return newConfigFromBundle(ctx, bundlePath)
+	}
 	rollupCfg, err := opnode.NewRollupConfig(log, ctx)
 	if err != nil {
 		return nil, err
@@ -157,50 +213,163 @@ func NewConfigFromCLI(log log.Logger, ctx *cli.Context) (*Config, error) {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidL2Claim, strClaim)
 	}
 	l2ClaimBlockNum := ctx.Uint64(flags.L2BlockNumber.Name)
+	intermediateClaims, err := parseOutputClaims(ctx.StringSlice(flags.L2IntermediateClaims.Name))
+	if err != nil {
+		return nil, err
+	}
 	l1Head := common.HexToHash(ctx.String(flags.L1Head.Name))
 	if l1Head == (common.Hash{}) {
 		return nil, ErrInvalidL1Head
 	}
-	l2GenesisPath := ctx.String(flags.L2GenesisPath.Name)
-	var l2ChainConfig *params.ChainConfig
-	var isCustomConfig bool
-	if l2GenesisPath == "" {
-		networkName := ctx.String(flags.Network.Name)
-		ch := chaincfg.ChainByName(networkName)
-		if ch == nil {
-			return nil, fmt.Errorf("flag %s is required for network %s", flags.L2GenesisPath.Name, networkName)
-		}
-		cfg, err := params.LoadOPStackChainConfig(ch.ChainID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load chain config for chain %d: %w", ch.ChainID, err)
-		}
-		l2ChainConfig = cfg
-	} else {
-		l2ChainConfig, err = loadChainConfigFromGenesis(l2GenesisPath)
-		isCustomConfig = true
+	l2ChainConfig, isCustomConfig, err := loadL2ChainConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{
+		Rollup:               rollupCfg,
+		DataDir:              ctx.String(flags.DataDir.Name),
+		DataFormat:           types.DataFormat(ctx.String(flags.DataFormat.Name)),
+		L2URL:                ctx.String(flags.L2NodeAddr.Name),
+		L2ChainConfig:        l2ChainConfig,
+		L2Head:               l2Head,
+		L2OutputRoot:         l2OutputRoot,
+		L2Claim:              l2Claim,
+		L2ClaimBlockNumber:   l2ClaimBlockNum,
+		L2IntermediateClaims: intermediateClaims,
+		L1Head:               l1Head,
+		L1URL:                ctx.String(flags.L1NodeAddr.Name),
+		L1BeaconURL:          ctx.String(flags.L1BeaconAddr.Name),
+		L1TrustRPC:           ctx.Bool(flags.L1TrustRPC.Name),
+		L1RPCKind:            sources.RPCProviderKind(ctx.String(flags.L1RPCProviderKind.Name)),
+		ExecCmd:              ctx.String(flags.Exec.Name),
+		ServerMode:           ctx.Bool(flags.Server.Name),
+		ServerAddr:           ctx.String(flags.ServerAddr.Name),
+		IsCustomChainConfig:  isCustomConfig,
+	}, nil
+}
+
+// NewBaseConfigFromCLI creates a Config with the chain configuration and pre-image source options, but without the
+// program inputs (L2 head, output root, claim etc). It is used by commands that run the program for many claims, which
+// must set the program inputs for each run.
+func NewBaseConfigFromCLI(log log.Logger, ctx *cli.Context) (*Config, error) {
+	if err := flags.CheckChainConfig(ctx); err != nil {
+		return nil, err
 	}
+	rollupCfg, err := opnode.NewRollupConfig(log, ctx)
 	if err != nil {
-		return nil, fmt.Errorf("invalid genesis: %w", err)
+		return nil, err
+	}
+	l2ChainConfig, isCustomConfig, err := loadL2ChainConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var l1Head common.Hash
+	if ctx.IsSet(flags.L1Head.Name) {
+		l1Head = common.HexToHash(ctx.String(flags.L1Head.Name))
+		if l1Head == (common.Hash{}) {
+			return nil, ErrInvalidL1Head
+		}
 	}
 	return &Config{
 		Rollup:              rollupCfg,
 		DataDir:             ctx.String(flags.DataDir.Name),
+		DataFormat:          types.DataFormat(ctx.String(flags.DataFormat.Name)),
 		L2URL:               ctx.String(flags.L2NodeAddr.Name),
 		L2ChainConfig:       l2ChainConfig,
-		L2Head:              l2Head,
-		L2OutputRoot:        l2OutputRoot,
-		L2Claim:             l2Claim,
-		L2ClaimBlockNumber:  l2ClaimBlockNum,
 		L1Head:              l1Head,
 		L1URL:               ctx.String(flags.L1NodeAddr.Name),
+		L1BeaconURL:         ctx.String(flags.L1BeaconAddr.Name),
 		L1TrustRPC:          ctx.Bool(flags.L1TrustRPC.Name),
 		L1RPCKind:           sources.RPCProviderKind(ctx.String(flags.L1RPCProviderKind.Name)),
-		ExecCmd:             ctx.String(flags.Exec.Name),
-		ServerMode:          ctx.Bool(flags.Server.Name),
 		IsCustomChainConfig: isCustomConfig,
 	}, nil
 }
 
+// loadL2ChainConfig loads the L2 chain config from the l2.genesis file if set, or else the predefined chain config
+// of the network. Returns true if the chain config is loaded from a genesis file.
+func loadL2ChainConfig(ctx *cli.Context) (*params.ChainConfig, bool, error) {
+	l2GenesisPath := ctx.String(flags.L2GenesisPath.Name)
+	if l2GenesisPath == "" {
+		networkName := ctx.String(flags.Network.Name)
+		ch := chaincfg.ChainByName(networkName)
+		if ch == nil {
+			return nil, false, fmt.Errorf("flag %s is required for network %s", flags.L2GenesisPath.Name, networkName)
+		}
+		cfg, err := params.LoadOPStackChainConfig(ch.ChainID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to load chain config for chain %d: %w", ch.ChainID, err)
+		}
+		return cfg, false, nil
+	}
+	l2ChainConfig, err := loadChainConfigFromGenesis(l2GenesisPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid genesis: %w", err)
+	}
+	return l2ChainConfig, true, nil
+}
+
+func newConfigFromBundle(ctx *cli.Context, path string) (*Config, error) {
+	r, err := bundle.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	boot := r.BootInfo()
+	return &Config{
+		Rollup:               boot.Rollup,
+		L2ChainConfig:        boot.L2ChainConfig,
+		L2Head:               boot.L2Head,
+		L2OutputRoot:         boot.L2OutputRoot,
+		L2Claim:              boot.L2Claim,
+		L2ClaimBlockNumber:   boot.L2ClaimBlockNumber,
+		L2IntermediateClaims: boot.L2IntermediateClaims,
+		L1Head:               boot.L1Head,
+		L1RPCKind:            sources.RPCProviderKind(ctx.String(flags.L1RPCProviderKind.Name)),
+		DataFormat:           types.DataFormat(ctx.String(flags.DataFormat.Name)),
+		ExecCmd:              ctx.String(flags.Exec.Name),
+		ServerMode:           ctx.Bool(flags.Server.Name),
+		ServerAddr:           ctx.String(flags.ServerAddr.Name),
+		IsCustomChainConfig:  boot.IsCustomChainConfig,
+		Bundle:               path,
+	}, nil
+}
+
+// BootInfo returns the local inputs of the program, as stored in a bundle.
+func (c *Config) BootInfo() *bundle.BootInfo {
+	return &bundle.BootInfo{
+		L1Head:               c.L1Head,
+		L2Head:               c.L2Head,
+		L2OutputRoot:         c.L2OutputRoot,
+		L2Claim:              c.L2Claim,
+		L2ClaimBlockNumber:   c.L2ClaimBlockNumber,
+		L2IntermediateClaims: c.L2IntermediateClaims,
+		Rollup:               c.Rollup,
+		L2ChainConfig:        c.L2ChainConfig,
+		IsCustomChainConfig:  c.IsCustomChainConfig,
+	}
+}
+
+// parseOutputClaims parses output claims in the form <blocknumber>:<outputroot>
+func parseOutputClaims(values []string) ([]client.OutputClaim, error) {
+	var claims []client.OutputClaim
+	for _, value := range values {
+		numStr, rootStr, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidIntermediateClaims, value)
+		}
+		num, err := strconv.ParseUint(numStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid block number %v", ErrInvalidIntermediateClaims, numStr)
+		}
+		var root common.Hash
+		if err := root.UnmarshalText([]byte(rootStr)); err != nil {
+			return nil, fmt.Errorf("%w: invalid output root %v", ErrInvalidIntermediateClaims, rootStr)
+		}
+		claims = append(claims, client.OutputClaim{BlockNumber: num, OutputRoot: root})
+	}
+	return claims, nil
+}
+
 func loadChainConfigFromGenesis(path string) (*params.ChainConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -1,12 +1,16 @@
 package config
 
 import (
+	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
+	"github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
@@ -152,6 +156,86 @@ func TestRequireDataDirInNonFetchingMode(t *testing.T) {
 	require.ErrorIs(t, err, ErrDataDirRequired)
 }
 
+func TestBundle(t *testing.T) {
+	t.Run("NoDataDirRequired", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DataDir = ""
+		cfg.Bundle = "/tmp/bundle.bin"
+		require.NoError(t, cfg.Check())
+	})
+	t.Run("RejectDataDir", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Bundle = "/tmp/bundle.bin"
+		require.ErrorIs(t, cfg.Check(), ErrBundleWithPreimages)
+	})
+	t.Run("RejectFetching", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DataDir = ""
+		cfg.L1URL = "https://example.com:1234"
+		cfg.L2URL = "https://example.com:5678"
+		cfg.Bundle = "/tmp/bundle.bin"
+		require.ErrorIs(t, cfg.Check(), ErrBundleWithPreimages)
+	})
+}
+
+func TestDataFormat(t *testing.T) {
+	for _, format := range types.SupportedDataFormats {
+		format := format
+		t.Run(fmt.Sprintf("Valid-%v", format), func(t *testing.T) {
+			cfg := validConfig()
+			cfg.DataFormat = format
+			require.NoError(t, cfg.Check())
+		})
+	}
+	t.Run("Invalid", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DataFormat = "foo"
+		require.ErrorIs(t, cfg.Check(), ErrInvalidDataFormat)
+	})
+}
+
+func TestIntermediateClaims(t *testing.T) {
+	claims := func(blocks ...uint64) []client.OutputClaim {
+		var out []client.OutputClaim
+		for _, block := range blocks {
+			out = append(out, client.OutputClaim{BlockNumber: block, OutputRoot: common.Hash{byte(block)}})
+		}
+		return out
+	}
+	t.Run("Valid", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L2IntermediateClaims = claims(1, 2, validL2ClaimBlockNum-1)
+		require.NoError(t, cfg.Check())
+	})
+	t.Run("NotAscending", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L2IntermediateClaims = claims(2, 1)
+		require.ErrorIs(t, cfg.Check(), ErrInvalidIntermediateClaims)
+	})
+	t.Run("Duplicate", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L2IntermediateClaims = claims(2, 2)
+		require.ErrorIs(t, cfg.Check(), ErrInvalidIntermediateClaims)
+	})
+	t.Run("AtClaimBlock", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L2IntermediateClaims = claims(validL2ClaimBlockNum)
+		require.ErrorIs(t, cfg.Check(), ErrInvalidIntermediateClaims)
+	})
+	t.Run("RejectExec", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L2IntermediateClaims = claims(1)
+		cfg.ExecCmd = "echo"
+		require.ErrorIs(t, cfg.Check(), ErrIntermediateClaimsNotInProcess)
+	})
+	t.Run("RejectServerMode", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L2IntermediateClaims = claims(1)
+		cfg.ServerMode = true
+		require.ErrorIs(t, cfg.Check(), ErrIntermediateClaimsNotInProcess)
+	})
+}
+
 func TestRejectExecAndServerMode(t *testing.T) {
 	cfg := validConfig()
 	cfg.ServerMode = true
@@ -160,6 +244,28 @@ func TestRejectExecAndServerMode(t *testing.T) {
 	require.ErrorIs(t, err, ErrNoExecInServerMode)
 }
 
+func TestServerAddr(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		for _, addr := range []string{"unix:///tmp/op-program.sock", "tcp://localhost:8888"} {
+			cfg := validConfig()
+			cfg.ServerMode = true
+			cfg.ServerAddr = addr
+			require.NoError(t, cfg.Check())
+		}
+	})
+	t.Run("RequireServerMode", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.ServerAddr = "tcp://localhost:8888"
+		require.ErrorIs(t, cfg.Check(), ErrServerAddrWithoutServerMode)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.ServerMode = true
+		cfg.ServerAddr = "localhost:8888"
+		require.ErrorIs(t, cfg.Check(), preimage.ErrInvalidAddress)
+	})
+}
+
 func TestIsCustomChainConfig(t *testing.T) {
 	t.Run("nonCustom", func(t *testing.T) {
 		cfg := validConfig()
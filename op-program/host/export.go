@@ -0,0 +1,80 @@
+package host
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-program/host/bundle"
+	"github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-program/host/flags"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var ErrExportInServerMode = errors.New("cannot export a bundle in server mode")
+
+// ExportBundle runs the fault proof program and writes a bundle containing its inputs and every pre-image it
+// requested to the output path. The bundle is written even if the program fails, so that the failure can be
+// reproduced from the bundle alone. The error from the program, if any, is returned after the bundle is written.
+func ExportBundle(logger log.Logger, cfg *config.Config, output string) error {
+	if err := cfg.Check(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if cfg.ServerMode {
+		return ErrExportInServerMode
+	}
+	opservice.ValidateEnvVars(flags.EnvVarPrefix, flags.Flags, logger)
+	cfg.Rollup.LogDescription(logger, chaincfg.L2ChainIDToNetworkDisplayName)
+
+	recorder := newPreimageRecorder()
+	runErr := faultProofProgram(context.Background(), logger, cfg, recorder)
+	if runErr != nil {
+		logger.Warn("Program failed, exporting bundle of the pre-images used", "err", runErr)
+	}
+	preimages := recorder.Preimages()
+	if err := bundle.Write(output, cfg.BootInfo(), preimages); err != nil {
+		return errors.Join(fmt.Errorf("failed to write bundle: %w", err), runErr)
+	}
+	logger.Info("Exported bundle", "path", output, "preimages", len(preimages))
+	return runErr
+}
+
+// preimageRecorder records every global pre-image successfully served to the client program.
+// Local pre-images are not recorded as they are derived from the boot info.
+type preimageRecorder struct {
+	mu        sync.Mutex
+	preimages map[common.Hash][]byte
+}
+
+func newPreimageRecorder() *preimageRecorder {
+	return &preimageRecorder{preimages: make(map[common.Hash][]byte)}
+}
+
+// Wrap returns a PreimageSource that records each pre-image returned by source.
+func (r *preimageRecorder) Wrap(source kvstore.PreimageSource) kvstore.PreimageSource {
+	return func(key common.Hash) ([]byte, error) {
+		value, err := source(key)
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.preimages[key] = value
+		return value, nil
+	}
+}
+
+func (r *preimageRecorder) Preimages() map[common.Hash][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	preimages := make(map[common.Hash][]byte, len(r.preimages))
+	for key, value := range r.preimages {
+		preimages[key] = value
+	}
+	return preimages
+}
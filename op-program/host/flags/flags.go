@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	service "github.com/ethereum-optimism/optimism/op-service"
 	openum "github.com/ethereum-optimism/optimism/op-service/enum"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
@@ -35,6 +36,15 @@ var (
 		Usage:   "Directory to use for preimage data storage. Default uses in-memory storage",
 		EnvVars: prefixEnvVars("DATADIR"),
 	}
+	DataFormat = &cli.GenericFlag{
+		Name:    "data.format",
+		Usage:   fmt.Sprintf("Format to use for preimage data storage. Available formats: %s", openum.EnumString(types.SupportedDataFormats)),
+		EnvVars: prefixEnvVars("DATA_FORMAT"),
+		Value: func() *types.DataFormat {
+			out := types.DataFormatDirectory
+			return &out
+		}(),
+	}
 	L2NodeAddr = &cli.StringFlag{
 		Name:    "l2",
 		Usage:   "Address of L2 JSON-RPC endpoint to use (eth and debug namespace required)",
@@ -65,6 +75,12 @@ var (
 		Usage:   "Number of the L2 block that the claim is from",
 		EnvVars: prefixEnvVars("L2_BLOCK_NUM"),
 	}
+	L2IntermediateClaims = &cli.StringSliceFlag{
+		Name: "l2.intermediateclaims",
+		Usage: "Claimed L2 output roots of blocks between l2.head and l2.blocknumber to validate, as <blocknumber>:<outputroot>. " +
+			"Claims are validated in block order and the first invalid claim is reported. Requires the client to run in-process.",
+		EnvVars: prefixEnvVars("L2_INTERMEDIATE_CLAIMS"),
+	}
 	L2GenesisPath = &cli.StringFlag{
 		Name:    "l2.genesis",
 		Usage:   "Path to the op-geth genesis file",
@@ -75,6 +91,11 @@ var (
 		Usage:   "Address of L1 JSON-RPC endpoint to use (eth namespace required)",
 		EnvVars: prefixEnvVars("L1_RPC"),
 	}
+	L1BeaconAddr = &cli.StringFlag{
+		Name:    "l1.beacon",
+		Usage:   "Address of L1 Beacon API endpoint to use, required to fetch blobs",
+		EnvVars: prefixEnvVars("L1_BEACON_API"),
+	}
 	L1TrustRPC = &cli.BoolFlag{
 		Name:    "l1.trustrpc",
 		Usage:   "Trust the L1 RPC, sync faster at risk of malicious/buggy RPC providing bad or inconsistent L1 data",
@@ -100,6 +121,16 @@ var (
 		Usage:   "Run in pre-image server mode without executing any client program.",
 		EnvVars: prefixEnvVars("SERVER"),
 	}
+	ServerAddr = &cli.StringFlag{
+		Name:    "server.addr",
+		Usage:   "Address to serve pre-images on in server mode, instead of stdin/stdout file descriptors. Either unix://<path> or tcp://<host>:<port>. Multiple client programs can connect concurrently.",
+		EnvVars: prefixEnvVars("SERVER_ADDR"),
+	}
+	Bundle = &cli.StringFlag{
+		Name:    "bundle",
+		Usage:   "Path to a pre-image bundle created by the export-bundle command. The program inputs and all pre-images are read from the bundle, without fetching any data.",
+		EnvVars: prefixEnvVars("BUNDLE"),
+	}
 )
 
 // Flags contains the list of configuration options available to the binary.
@@ -116,13 +147,28 @@ var programFlags = []cli.Flag{
 	RollupConfig,
 	Network,
 	DataDir,
+	DataFormat,
 	L2NodeAddr,
 	L2GenesisPath,
+	L2IntermediateClaims,
 	L1NodeAddr,
+	L1BeaconAddr,
 	L1TrustRPC,
 	L1RPCProviderKind,
 	Exec,
 	Server,
+	ServerAddr,
+	Bundle,
+}
+
+// bundleInputFlags are the flags that provide inputs which are read from the bundle instead when a bundle is used.
+var bundleInputFlags = []cli.Flag{
+	RollupConfig,
+	Network,
+	L2GenesisPath,
+	DataDir,
+	L1NodeAddr,
+	L2NodeAddr,
 }
 
 func init() {
@@ -132,6 +178,27 @@ func init() {
 }
 
 func CheckRequired(ctx *cli.Context) error {
+	if ctx.IsSet(Bundle.Name) {
+		for _, flag := range append(requiredFlags, bundleInputFlags...) {
+			if ctx.IsSet(flag.Names()[0]) {
+				return fmt.Errorf("flag %s cannot be used with %s", flag.Names()[0], Bundle.Name)
+			}
+		}
+		return nil
+	}
+	if err := CheckChainConfig(ctx); err != nil {
+		return err
+	}
+	for _, flag := range requiredFlags {
+		if !ctx.IsSet(flag.Names()[0]) {
+			return fmt.Errorf("flag %s is required", flag.Names()[0])
+		}
+	}
+	return nil
+}
+
+// CheckChainConfig checks that the flags to select the rollup and L2 chain configuration are consistent.
+func CheckChainConfig(ctx *cli.Context) error {
 	rollupConfig := ctx.String(RollupConfig.Name)
 	network := ctx.String(Network.Name)
 	if rollupConfig == "" && network == "" {
@@ -143,10 +210,5 @@ func CheckRequired(ctx *cli.Context) error {
 	if network == "" && ctx.String(L2GenesisPath.Name) == "" {
 		return fmt.Errorf("flag %s is required for custom networks", L2GenesisPath.Name)
 	}
-	for _, flag := range requiredFlags {
-		if !ctx.IsSet(flag.Names()[0]) {
-			return fmt.Errorf("flag %s is required", flag.Names()[0])
-		}
-	}
 	return nil
 }
@@ -6,21 +6,27 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"net"
 	"os"
 	"os/exec"
+	"sync"
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	cl "github.com/ethereum-optimism/optimism/op-program/client"
+	"github.com/ethereum-optimism/optimism/op-program/host/bundle"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum-optimism/optimism/op-program/host/flags"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
 	"github.com/ethereum-optimism/optimism/op-program/host/prefetcher"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	oppio "github.com/ethereum-optimism/optimism/op-program/io"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/opio"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -37,6 +43,9 @@ func Main(logger log.Logger, cfg *config.Config) error {
 	cfg.Rollup.LogDescription(logger, chaincfg.L2ChainIDToNetworkDisplayName)
 
 	ctx := context.Background()
+	if cfg.ServerMode && cfg.ServerAddr != "" {
+		return SocketServer(opio.CancelOnInterrupt(ctx), logger, cfg)
+	}
 	if cfg.ServerMode {
 		preimageChan := cl.CreatePreimageChannel()
 		hinterChan := cl.CreateHinterChannel()
@@ -52,6 +61,10 @@ func Main(logger log.Logger, cfg *config.Config) error {
 
 // FaultProofProgram is the programmatic entry-point for the fault proof program
 func FaultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Config) error {
+	return faultProofProgram(ctx, logger, cfg, nil)
+}
+
+func faultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Config, wrapper preimageSourceWrapper) error {
 	var (
 		serverErr chan error
 		pClientRW oppio.FileChannel
@@ -88,7 +101,7 @@ func FaultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Confi
 	serverErr = make(chan error)
 	go func() {
 		defer close(serverErr)
-		serverErr <- PreimageServer(ctx, logger, cfg, pHostRW, hHostRW)
+		serverErr <- preimageServer(ctx, logger, cfg, pHostRW, hHostRW, wrapper)
 	}()
 
 	var cmd *exec.Cmd
@@ -116,11 +129,91 @@ func FaultProofProgram(ctx context.Context, logger log.Logger, cfg *config.Confi
 	}
 }
 
+// preimageSourceWrapper wraps the source of the global pre-images served to the client program,
+// to observe the pre-images used by the program.
+type preimageSourceWrapper interface {
+	Wrap(source kvstore.PreimageSource) kvstore.PreimageSource
+}
+
+// hintHandlerWrapper is optionally implemented by a preimageSourceWrapper to also observe the hints of the program.
+type hintHandlerWrapper interface {
+	WrapHinter(hinter preimage.HintHandler) preimage.HintHandler
+}
+
 // PreimageServer reads hints and preimage requests from the provided channels and processes those requests.
 // This method will block until both the hinter and preimage handlers complete.
 // If either returns an error both handlers are stopped.
 // The supplied preimageChannel and hintChannel will be closed before this function returns.
 func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config, preimageChannel oppio.FileChannel, hintChannel oppio.FileChannel) error {
+	return preimageServer(ctx, logger, cfg, preimageChannel, hintChannel, nil)
+}
+
+func preimageServer(ctx context.Context, logger log.Logger, cfg *config.Config, preimageChannel oppio.FileChannel, hintChannel oppio.FileChannel, wrapper preimageSourceWrapper) error {
+	logger.Info("Starting preimage server")
+	store, err := newPreimageStore(ctx, logger, cfg)
+	if err != nil {
+		preimageChannel.Close()
+		hintChannel.Close()
+		return err
+	}
+	defer store.Close()
+	return serveSession(ctx, logger, kvstore.NewLocalPreimageSource(cfg).Get, store, preimageChannel, hintChannel, wrapper)
+}
+
+// SocketServer listens for client programs at cfg.ServerAddr and serves the pre-image requests and hints of each
+// connected client concurrently, until ctx is done. Each client may provide its own local inputs (L1 head, claim etc.)
+// when connecting, with any it does not provide served from cfg. All clients share a single pre-image store so that
+// pre-images fetched for one client are available to all others.
+func SocketServer(ctx context.Context, logger log.Logger, cfg *config.Config) error {
+	store, err := newPreimageStore(ctx, logger, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	listener, err := preimage.Listen(cfg.ServerAddr)
+	if err != nil {
+		return err
+	}
+	logger.Info("Listening for client programs", "addr", cfg.ServerAddr)
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		session, err := listener.Accept()
+		if errors.Is(err, preimage.ErrListenerClosed) {
+			logger.Info("Stopped listening for client programs")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		sessionLogger := logger.New("session", hexutil.Encode(session.ID[:]))
+		sessionLogger.Info("Client program connected")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Disconnect the client when the server is stopped
+			stop := context.AfterFunc(ctx, func() { _ = session.Close() })
+			defer stop()
+			localSource := kvstore.NewSessionPreimageSource(session.Local, kvstore.NewLocalPreimageSource(cfg).Get)
+			if err := serveSession(ctx, sessionLogger, localSource, store, session.Oracle, session.Hint, nil); err != nil {
+				sessionLogger.Error("Failed to serve client program", "err", err)
+				return
+			}
+			sessionLogger.Info("Client program disconnected")
+		}()
+	}
+}
+
+// serveSession serves the pre-image requests and hints of a single client program,
+// with local keys served from localSource and all other pre-images from the store.
+// This method will block until both the hinter and preimage handlers complete.
+// If either returns an error both handlers are stopped.
+// The supplied preimageChannel and hintChannel will be closed before this function returns.
+func serveSession(ctx context.Context, logger log.Logger, localSource kvstore.PreimageSource, store *preimageStore, preimageChannel io.ReadWriteCloser, hintChannel io.ReadWriteCloser, wrapper preimageSourceWrapper) error {
 	var serverDone chan error
 	var hinterDone chan error
 	defer func() {
@@ -135,54 +228,120 @@ func PreimageServer(ctx context.Context, logger log.Logger, cfg *config.Config,
 			<-hinterDone
 		}
 	}()
-	logger.Info("Starting preimage server")
-	var kv kvstore.KV
+	getPreimage, hinter := store.session(ctx)
+	if wrapper != nil {
+		getPreimage = wrapper.Wrap(getPreimage)
+		if hw, ok := wrapper.(hintHandlerWrapper); ok {
+			hinter = hw.WrapHinter(hinter)
+		}
+	}
+
+	splitter := kvstore.NewPreimageSourceSplitter(localSource, getPreimage)
+	preimageGetter := preimage.WithVerification(splitter.Get)
+
+	serverDone = launchOracleServer(logger, preimageChannel, preimageGetter)
+	hinterDone = routeHints(logger, hintChannel, hinter)
+	select {
+	case err := <-serverDone:
+		return err
+	case err := <-hinterDone:
+		return err
+	}
+}
+
+// preimageStore is the source of the global pre-images served to client programs.
+// It is safe to serve multiple client programs from the same store concurrently.
+type preimageStore struct {
+	logger  log.Logger
+	kv      kvstore.KV
+	bundle  *bundle.Reader
+	sources *prefetchSources
+	// closer releases the underlying storage once all sessions have completed
+	closer io.Closer
+}
+
+func newPreimageStore(ctx context.Context, logger log.Logger, cfg *config.Config) (*preimageStore, error) {
+	store := &preimageStore{logger: logger}
+	if cfg.Bundle != "" {
+		logger.Info("Using pre-images from bundle", "bundle", cfg.Bundle)
+		r, err := bundle.Open(cfg.Bundle)
+		if err != nil {
+			return nil, err
+		}
+		store.bundle = r
+		store.closer = r
+		return store, nil
+	}
 	if cfg.DataDir == "" {
 		logger.Info("Using in-memory storage")
-		kv = kvstore.NewMemKV()
+		store.kv = kvstore.NewMemKV()
 	} else {
-		logger.Info("Creating disk storage", "datadir", cfg.DataDir)
+		logger.Info("Creating disk storage", "datadir", cfg.DataDir, "format", cfg.DataFormat)
 		if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
-			return fmt.Errorf("creating datadir: %w", err)
+			return nil, fmt.Errorf("creating datadir: %w", err)
+		}
+		switch cfg.DataFormat {
+		case types.DataFormatDirectory:
+			store.kv = kvstore.NewDiskKV(cfg.DataDir)
+		case types.DataFormatPebble:
+			pebbleKV, err := kvstore.NewPebbleKV(cfg.DataDir)
+			if err != nil {
+				return nil, err
+			}
+			store.closer = pebbleKV
+			store.kv = pebbleKV
+		default:
+			return nil, fmt.Errorf("invalid data format: %s", cfg.DataFormat)
 		}
-		kv = kvstore.NewDiskKV(cfg.DataDir)
 	}
 
-	var (
-		getPreimage kvstore.PreimageSource
-		hinter      preimage.HintHandler
-	)
 	if cfg.FetchingEnabled() {
-		prefetch, err := makePrefetcher(ctx, logger, kv, cfg)
+		sources, err := makePrefetchSources(ctx, logger, cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create prefetcher: %w", err)
+			store.Close()
+			return nil, fmt.Errorf("failed to create prefetcher: %w", err)
 		}
-		getPreimage = func(key common.Hash) ([]byte, error) { return prefetch.GetPreimage(ctx, key) }
-		hinter = prefetch.Hint
+		store.sources = sources
 	} else {
 		logger.Info("Using offline mode. All required pre-images must be pre-populated.")
-		getPreimage = kv.Get
-		hinter = func(hint string) error {
-			logger.Debug("ignoring prefetch hint", "hint", hint)
-			return nil
-		}
 	}
+	return store, nil
+}
 
-	localPreimageSource := kvstore.NewLocalPreimageSource(cfg)
-	splitter := kvstore.NewPreimageSourceSplitter(localPreimageSource.Get, getPreimage)
-	preimageGetter := preimage.WithVerification(splitter.Get)
+// session returns the pre-image source and hint handler for a single client program.
+// Hints are tracked per session, so each client program must use its own source and hint handler.
+func (s *preimageStore) session(ctx context.Context) (kvstore.PreimageSource, preimage.HintHandler) {
+	ignoreHints := func(hint string) error {
+		s.logger.Debug("ignoring prefetch hint", "hint", hint)
+		return nil
+	}
+	if s.bundle != nil {
+		return s.bundle.Get, ignoreHints
+	}
+	if s.sources == nil {
+		return s.kv.Get, ignoreHints
+	}
+	prefetch := prefetcher.NewPrefetcher(s.logger, s.sources.l1, s.sources.l1Blobs, s.sources.l2, s.kv)
+	return func(key common.Hash) ([]byte, error) { return prefetch.GetPreimage(ctx, key) }, prefetch.Hint
+}
 
-	serverDone = launchOracleServer(logger, preimageChannel, preimageGetter)
-	hinterDone = routeHints(logger, hintChannel, hinter)
-	select {
-	case err := <-serverDone:
-		return err
-	case err := <-hinterDone:
-		return err
+func (s *preimageStore) Close() {
+	if s.closer != nil {
+		if err := s.closer.Close(); err != nil {
+			s.logger.Error("Failed to close pre-image store", "err", err)
+		}
 	}
 }
 
-func makePrefetcher(ctx context.Context, logger log.Logger, kv kvstore.KV, cfg *config.Config) (*prefetcher.Prefetcher, error) {
+// prefetchSources are the L1 and L2 sources used to fetch pre-images.
+// The sources cache responses, so they are shared by all client programs served from the same store.
+type prefetchSources struct {
+	l1      *sources.L1Client
+	l1Blobs prefetcher.L1BlobSource
+	l2      *L2Source
+}
+
+func makePrefetchSources(ctx context.Context, logger log.Logger, cfg *config.Config) (*prefetchSources, error) {
 	logger.Info("Connecting to L1 node", "l1", cfg.L1URL)
 	l1RPC, err := client.NewRPC(ctx, logger, cfg.L1URL, client.WithDialBackoff(10))
 	if err != nil {
@@ -205,8 +364,15 @@ func makePrefetcher(ctx context.Context, logger log.Logger, kv kvstore.KV, cfg *
 	if err != nil {
 		return nil, fmt.Errorf("failed to create L2 client: %w", err)
 	}
+	var l1BlobFetcher prefetcher.L1BlobSource
+	if cfg.L1BeaconURL != "" {
+		logger.Info("Connecting to L1 beacon", "l1", cfg.L1BeaconURL)
+		l1BlobFetcher = sources.NewL1BeaconClient(client.NewBasicHTTPClient(cfg.L1BeaconURL, logger))
+	} else {
+		logger.Warn("No L1 beacon endpoint configured, blobs cannot be fetched")
+	}
 	l2DebugCl := &L2Source{L2Client: l2Cl, DebugClient: sources.NewDebugClient(l2RPC.CallContext)}
-	return prefetcher.NewPrefetcher(logger, l1Cl, l2DebugCl, kv), nil
+	return &prefetchSources{l1: l1Cl, l1Blobs: l1BlobFetcher, l2: l2DebugCl}, nil
 }
 
 func routeHints(logger log.Logger, hHostRW io.ReadWriter, hinter preimage.HintHandler) chan error {
@@ -216,7 +382,7 @@ func routeHints(logger log.Logger, hHostRW io.ReadWriter, hinter preimage.HintHa
 		defer close(chErr)
 		for {
 			if err := hintReader.NextHint(hinter); err != nil {
-				if err == io.EOF || errors.Is(err, fs.ErrClosed) {
+				if err == io.EOF || errors.Is(err, fs.ErrClosed) || errors.Is(err, net.ErrClosed) {
 					logger.Debug("closing pre-image hint handler")
 					return
 				}
@@ -236,7 +402,7 @@ func launchOracleServer(logger log.Logger, pHostRW io.ReadWriteCloser, getter pr
 		defer close(chErr)
 		for {
 			if err := server.NextPreimageRequest(getter); err != nil {
-				if err == io.EOF || errors.Is(err, fs.ErrClosed) {
+				if err == io.EOF || errors.Is(err, fs.ErrClosed) || errors.Is(err, net.ErrClosed) {
 					logger.Debug("closing pre-image server")
 					return
 				}
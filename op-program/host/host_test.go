@@ -2,7 +2,11 @@ package host
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
+	"net"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,11 +15,14 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum-optimism/optimism/op-program/client"
 	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-program/host/bundle"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	"github.com/ethereum-optimism/optimism/op-program/io"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/stretchr/testify/require"
 )
@@ -55,6 +62,224 @@ func TestServerMode(t *testing.T) {
 	require.ErrorIs(t, waitFor(result), kvstore.ErrNotFound)
 }
 
+func TestServerModeWithPebble(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("hello world")
+	key := preimage.Keccak256Key(crypto.Keccak256Hash(data))
+	kv, err := kvstore.NewPebbleKV(dir)
+	require.NoError(t, err)
+	require.NoError(t, kv.Put(key.PreimageKey(), data))
+	require.NoError(t, kv.Close())
+
+	cfg := config.NewConfig(chaincfg.Goerli, chainconfig.OPGoerliChainConfig, common.Hash{0x11}, common.Hash{0x22}, common.Hash{0x33}, common.Hash{0x44}, 1000)
+	cfg.DataDir = dir
+	cfg.DataFormat = types.DataFormatPebble
+	cfg.ServerMode = true
+
+	pClient, result := startServer(t, cfg, nil)
+	require.Equal(t, data, pClient.Get(key), "Should get preimage from pebble db")
+	require.Panics(t, func() {
+		pClient.Get(preimage.Keccak256Key(common.Hash{0xaa}))
+	}, "Preimage should not be available")
+	require.ErrorIs(t, waitFor(result), kvstore.ErrNotFound)
+}
+
+func TestServerModeWithBundle(t *testing.T) {
+	l1Head := common.Hash{0x11}
+	l2OutputRoot := common.Hash{0x33}
+	data := []byte("hello world")
+	key := preimage.Keccak256Key(crypto.Keccak256Hash(data))
+	cfg := config.NewConfig(chaincfg.Goerli, chainconfig.OPGoerliChainConfig, l1Head, common.Hash{0x22}, l2OutputRoot, common.Hash{0x44}, 1000)
+	cfg.Bundle = filepath.Join(t.TempDir(), "bundle.bin")
+	cfg.ServerMode = true
+	require.NoError(t, bundle.Write(cfg.Bundle, cfg.BootInfo(), map[common.Hash][]byte{key.PreimageKey(): data}))
+
+	pClient, result := startServer(t, cfg, nil)
+	require.Equal(t, l1Head.Bytes(), pClient.Get(client.L1HeadLocalIndex), "Should get l1 head preimages")
+	require.Equal(t, l2OutputRoot.Bytes(), pClient.Get(client.L2OutputRootLocalIndex), "Should get l2 output root preimages")
+	require.Equal(t, data, pClient.Get(key), "Should get preimage from bundle")
+
+	// Should exit when a preimage is not in the bundle
+	require.Panics(t, func() {
+		pClient.Get(preimage.Keccak256Key(common.Hash{0xaa}))
+	}, "Preimage should not be available")
+	require.ErrorIs(t, waitFor(result), bundle.ErrNotFound)
+}
+
+func TestSocketServer(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("hello world")
+	key := preimage.Keccak256Key(crypto.Keccak256Hash(data))
+	kv := kvstore.NewDiskKV(dir)
+	require.NoError(t, kv.Put(key.PreimageKey(), data))
+	cfg := config.NewConfig(chaincfg.Goerli, chainconfig.OPGoerliChainConfig, common.Hash{0x11}, common.Hash{0x22}, common.Hash{0x33}, common.Hash{0x44}, 1000)
+	cfg.DataDir = dir
+	cfg.ServerMode = true
+	cfg.ServerAddr = "unix://" + filepath.Join(t.TempDir(), "server.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := make(chan error, 1)
+	go func() {
+		result <- SocketServer(ctx, testlog.Logger(t, log.LvlTrace), cfg)
+	}()
+	connect := func(local preimage.LocalPreimages) (*preimage.OracleClient, *preimage.HintWriter) {
+		return connectSocketServer(t, ctx, cfg.ServerAddr, local)
+	}
+
+	// A client failing to find a pre-image should not affect other clients
+	failingClient, _ := connect(nil)
+	require.Panics(t, func() {
+		failingClient.Get(preimage.Keccak256Key(common.Hash{0xaa}))
+	}, "Preimage should not be available")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		pClient, hClient := connect(nil)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hClient.Hint(l1.BlockHeaderHint(common.Hash{0xbb}))
+			require.Equal(t, common.Hash{0x11}.Bytes(), pClient.Get(client.L1HeadLocalIndex))
+			require.Equal(t, data, pClient.Get(key))
+		}()
+	}
+	wg.Wait()
+
+	cancel()
+	require.NoError(t, waitFor(result))
+}
+
+func TestSocketServerSessionBootInputs(t *testing.T) {
+	cfg := config.NewConfig(chaincfg.Goerli, chainconfig.OPGoerliChainConfig, common.Hash{0x11}, common.Hash{0x22}, common.Hash{0x33}, common.Hash{0x44}, 1000)
+	cfg.DataDir = t.TempDir()
+	cfg.ServerMode = true
+	cfg.ServerAddr = "unix://" + filepath.Join(t.TempDir(), "server.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := make(chan error, 1)
+	go func() {
+		result <- SocketServer(ctx, testlog.Logger(t, log.LvlTrace), cfg)
+	}()
+
+	// Two concurrent executions verifying different claims from the same server
+	claimA, claimB := common.Hash{0xaa}, common.Hash{0xbb}
+	clientA, _ := connectSocketServer(t, ctx, cfg.ServerAddr, preimage.LocalPreimages{
+		client.L2ClaimLocalIndex:            claimA.Bytes(),
+		client.L2ClaimBlockNumberLocalIndex: binary.BigEndian.AppendUint64(nil, 2000),
+	})
+	clientB, _ := connectSocketServer(t, ctx, cfg.ServerAddr, preimage.LocalPreimages{
+		client.L2ClaimLocalIndex: claimB.Bytes(),
+	})
+
+	require.Equal(t, claimA.Bytes(), clientA.Get(client.L2ClaimLocalIndex))
+	require.Equal(t, claimB.Bytes(), clientB.Get(client.L2ClaimLocalIndex))
+	require.Equal(t, binary.BigEndian.AppendUint64(nil, 2000), clientA.Get(client.L2ClaimBlockNumberLocalIndex))
+	// Inputs not provided by the session are served from the server config
+	require.Equal(t, binary.BigEndian.AppendUint64(nil, 1000), clientB.Get(client.L2ClaimBlockNumberLocalIndex))
+	require.Equal(t, common.Hash{0x11}.Bytes(), clientA.Get(client.L1HeadLocalIndex))
+	require.Equal(t, common.Hash{0x11}.Bytes(), clientB.Get(client.L1HeadLocalIndex))
+
+	cancel()
+	require.NoError(t, waitFor(result))
+}
+
+func connectSocketServer(t *testing.T, ctx context.Context, addr string, local preimage.LocalPreimages) (*preimage.OracleClient, *preimage.HintWriter) {
+	var oracleConn, hintConn net.Conn
+	require.Eventually(t, func() bool {
+		var err error
+		oracleConn, hintConn, err = preimage.Dial(ctx, addr, local)
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+	t.Cleanup(func() {
+		_ = oracleConn.Close()
+		_ = hintConn.Close()
+	})
+	return preimage.NewOracleClient(oracleConn), preimage.NewHintWriter(hintConn)
+}
+
+func TestRecordPreimages(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("hello world")
+	key := preimage.Keccak256Key(crypto.Keccak256Hash(data))
+	kv := kvstore.NewDiskKV(dir)
+	require.NoError(t, kv.Put(key.PreimageKey(), data))
+	cfg := config.NewConfig(chaincfg.Goerli, chainconfig.OPGoerliChainConfig, common.Hash{0x11}, common.Hash{0x22}, common.Hash{0x33}, common.Hash{0x44}, 1000)
+	cfg.DataDir = dir
+	cfg.ServerMode = true
+
+	recorder := newPreimageRecorder()
+	pClient, result := startServer(t, cfg, recorder)
+	require.Equal(t, common.Hash{0x11}.Bytes(), pClient.Get(client.L1HeadLocalIndex))
+	require.Equal(t, data, pClient.Get(key))
+	require.Panics(t, func() {
+		pClient.Get(preimage.Keccak256Key(common.Hash{0xaa}))
+	}, "Preimage should not be available")
+	require.ErrorIs(t, waitFor(result), kvstore.ErrNotFound)
+
+	// Only the global pre-image that was found should be recorded
+	require.Equal(t, map[common.Hash][]byte{key.PreimageKey(): data}, recorder.Preimages())
+}
+
+func TestCountPreimages(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("hello world")
+	key := preimage.Keccak256Key(crypto.Keccak256Hash(data))
+	kv := kvstore.NewDiskKV(dir)
+	require.NoError(t, kv.Put(key.PreimageKey(), data))
+	cfg := config.NewConfig(chaincfg.Goerli, chainconfig.OPGoerliChainConfig, common.Hash{0x11}, common.Hash{0x22}, common.Hash{0x33}, common.Hash{0x44}, 1000)
+	cfg.DataDir = dir
+	cfg.ServerMode = true
+
+	counter := newPreimageCounter()
+	pClient, result := startServer(t, cfg, counter)
+	require.Equal(t, common.Hash{0x11}.Bytes(), pClient.Get(client.L1HeadLocalIndex))
+	require.Equal(t, data, pClient.Get(key))
+	require.Equal(t, data, pClient.Get(key))
+	require.Panics(t, func() {
+		pClient.Get(preimage.Keccak256Key(common.Hash{0xaa}))
+	}, "Preimage should not be available")
+	require.ErrorIs(t, waitFor(result), kvstore.ErrNotFound)
+
+	// Only global pre-images that were found should be counted
+	require.Equal(t, RunStats{PreimageRequests: 2, UniquePreimages: 1}, counter.Stats())
+}
+
+func TestCountL1BlockHints(t *testing.T) {
+	counter := newPreimageCounter()
+	var hints []string
+	hinter := counter.WrapHinter(func(hint string) error {
+		hints = append(hints, hint)
+		return nil
+	})
+	blockA := l1.BlockHeaderHint(common.Hash{0xaa}).Hint()
+	blockB := l1.BlockHeaderHint(common.Hash{0xbb}).Hint()
+	txs := l1.TransactionsHint(common.Hash{0xcc}).Hint()
+	for _, hint := range []string{blockA, txs, blockB, blockA} {
+		require.NoError(t, hinter(hint))
+	}
+
+	// Hints are still passed on, and only distinct L1 block headers are counted
+	require.Equal(t, []string{blockA, txs, blockB, blockA}, hints)
+	require.Equal(t, RunStats{L1Blocks: 2}, counter.Stats())
+}
+
+func startServer(t *testing.T, cfg *config.Config, wrapper preimageSourceWrapper) (*preimage.OracleClient, chan error) {
+	preimageServerRW, preimageClientRW, err := io.CreateBidirectionalChannel()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = preimageClientRW.Close() })
+	hintServerRW, hintClientRW, err := io.CreateBidirectionalChannel()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = hintClientRW.Close() })
+	logger := testlog.Logger(t, log.LvlTrace)
+	result := make(chan error)
+	go func() {
+		result <- preimageServer(context.Background(), logger, cfg, preimageServerRW, hintServerRW, wrapper)
+	}()
+	return preimage.NewOracleClient(preimageClientRW), result
+}
+
 func waitFor(ch chan error) error {
 	timeout := time.After(30 * time.Second)
 	select {
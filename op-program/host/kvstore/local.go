@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
 	"github.com/ethereum-optimism/optimism/op-program/client"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum/go-ethereum/common"
@@ -18,13 +19,14 @@ func NewLocalPreimageSource(config *config.Config) *LocalPreimageSource {
 }
 
 var (
-	l1HeadKey             = client.L1HeadLocalIndex.PreimageKey()
-	l2OutputRootKey       = client.L2OutputRootLocalIndex.PreimageKey()
-	l2ClaimKey            = client.L2ClaimLocalIndex.PreimageKey()
-	l2ClaimBlockNumberKey = client.L2ClaimBlockNumberLocalIndex.PreimageKey()
-	l2ChainIDKey          = client.L2ChainIDLocalIndex.PreimageKey()
-	l2ChainConfigKey      = client.L2ChainConfigLocalIndex.PreimageKey()
-	rollupKey             = client.RollupConfigLocalIndex.PreimageKey()
+	l1HeadKey               = client.L1HeadLocalIndex.PreimageKey()
+	l2OutputRootKey         = client.L2OutputRootLocalIndex.PreimageKey()
+	l2ClaimKey              = client.L2ClaimLocalIndex.PreimageKey()
+	l2ClaimBlockNumberKey   = client.L2ClaimBlockNumberLocalIndex.PreimageKey()
+	l2ChainIDKey            = client.L2ChainIDLocalIndex.PreimageKey()
+	l2ChainConfigKey        = client.L2ChainConfigLocalIndex.PreimageKey()
+	rollupKey               = client.RollupConfigLocalIndex.PreimageKey()
+	l2IntermediateClaimsKey = client.L2IntermediateClaimsLocalIndex.PreimageKey()
 )
 
 func (s *LocalPreimageSource) Get(key common.Hash) ([]byte, error) {
@@ -51,7 +53,27 @@ func (s *LocalPreimageSource) Get(key common.Hash) ([]byte, error) {
 		return json.Marshal(s.config.L2ChainConfig)
 	case rollupKey:
 		return json.Marshal(s.config.Rollup)
+	case l2IntermediateClaimsKey:
+		return client.EncodeOutputClaims(s.config.L2IntermediateClaims), nil
 	default:
 		return nil, ErrNotFound
 	}
 }
+
+// NewSessionPreimageSource returns the source of the local pre-images of a single client session.
+// Pre-images provided by the client for its session take precedence over those from the fallback source.
+func NewSessionPreimageSource(provided preimage.LocalPreimages, fallback PreimageSource) PreimageSource {
+	if len(provided) == 0 {
+		return fallback
+	}
+	byKey := make(map[common.Hash][]byte, len(provided))
+	for index, value := range provided {
+		byKey[index.PreimageKey()] = value
+	}
+	return func(key common.Hash) ([]byte, error) {
+		if value, ok := byKey[key]; ok {
+			return value, nil
+		}
+		return fallback(key)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client"
 	"github.com/ethereum-optimism/optimism/op-program/host/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
@@ -21,6 +22,9 @@ func TestLocalPreimageSource(t *testing.T) {
 		L2Claim:            common.HexToHash("0x3333"),
 		L2ClaimBlockNumber: 1234,
 		L2ChainConfig:      params.GoerliChainConfig,
+		L2IntermediateClaims: []client.OutputClaim{
+			{BlockNumber: 1000, OutputRoot: common.HexToHash("0x4444")},
+		},
 	}
 	source := NewLocalPreimageSource(cfg)
 	tests := []struct {
@@ -35,6 +39,7 @@ func TestLocalPreimageSource(t *testing.T) {
 		{"L2ChainID", l2ChainIDKey, binary.BigEndian.AppendUint64(nil, cfg.L2ChainConfig.ChainID.Uint64())},
 		{"Rollup", rollupKey, asJson(t, cfg.Rollup)},
 		{"ChainConfig", l2ChainConfigKey, asJson(t, cfg.L2ChainConfig)},
+		{"L2IntermediateClaims", l2IntermediateClaimsKey, client.EncodeOutputClaims(cfg.L2IntermediateClaims)},
 		{"Unknown", preimage.LocalIndexKey(1000).PreimageKey(), nil},
 	}
 	for _, test := range tests {
@@ -55,3 +60,35 @@ func asJson(t *testing.T, v any) []byte {
 	require.NoError(t, err)
 	return d
 }
+
+func TestSessionPreimageSource(t *testing.T) {
+	cfg := &config.Config{
+		L1Head:             common.HexToHash("0x1111"),
+		L2Claim:            common.HexToHash("0x3333"),
+		L2ClaimBlockNumber: 1234,
+	}
+	fallback := NewLocalPreimageSource(cfg).Get
+
+	t.Run("NoneProvided", func(t *testing.T) {
+		source := NewSessionPreimageSource(nil, fallback)
+		actual, err := source(l2ClaimKey)
+		require.NoError(t, err)
+		require.Equal(t, cfg.L2Claim.Bytes(), actual)
+	})
+
+	t.Run("Provided", func(t *testing.T) {
+		claim := common.HexToHash("0x5555")
+		source := NewSessionPreimageSource(preimage.LocalPreimages{client.L2ClaimLocalIndex: claim.Bytes()}, fallback)
+		actual, err := source(l2ClaimKey)
+		require.NoError(t, err)
+		require.Equal(t, claim.Bytes(), actual)
+
+		// Local keys not provided by the session are served from the fallback
+		actual, err = source(l1HeadKey)
+		require.NoError(t, err)
+		require.Equal(t, cfg.L1Head.Bytes(), actual)
+
+		_, err = source(preimage.LocalIndexKey(1000).PreimageKey())
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,83 @@
+package kvstore
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// migrationBatchSize is the number of pre-images written to the destination database in each batch.
+const migrationBatchSize = 10_000
+
+// MigrateDiskKV copies all pre-images stored in the DiskKV directory layout at srcDir to dst, then compacts dst.
+// The source directory is not modified. Files that are not pre-images, such as temporary files left by an interrupted
+// write, are skipped. Returns the number of pre-images copied.
+func MigrateDiskKV(logger log.Logger, srcDir string, dst *PebbleKV) (int, error) {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read source directory %v: %w", srcDir, err)
+	}
+	copied := 0
+	batch := make(map[common.Hash][]byte, migrationBatchSize)
+	flush := func() error {
+		if err := dst.PutAll(batch); err != nil {
+			return err
+		}
+		copied += len(batch)
+		clear(batch)
+		logger.Info("Migrated pre-images", "count", copied, "total", len(entries))
+		return nil
+	}
+	for _, entry := range entries {
+		key, ok := diskKVKey(entry)
+		if !ok {
+			logger.Debug("Skipping non pre-image file", "name", entry.Name())
+			continue
+		}
+		dat, err := os.ReadFile(filepath.Join(srcDir, entry.Name()))
+		if err != nil {
+			return copied, fmt.Errorf("failed to read pre-image %s: %w", key, err)
+		}
+		value, err := hex.DecodeString(string(dat))
+		if err != nil {
+			return copied, fmt.Errorf("failed to decode pre-image %s: %w", key, err)
+		}
+		batch[key] = value
+		if len(batch) >= migrationBatchSize {
+			if err := flush(); err != nil {
+				return copied, err
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return copied, err
+		}
+	}
+	logger.Info("Compacting database")
+	if err := dst.Compact(); err != nil {
+		return copied, fmt.Errorf("failed to compact database: %w", err)
+	}
+	return copied, nil
+}
+
+// diskKVKey returns the pre-image key of a file written by DiskKV.
+func diskKVKey(entry os.DirEntry) (common.Hash, bool) {
+	if !entry.Type().IsRegular() {
+		return common.Hash{}, false
+	}
+	name, ok := strings.CutSuffix(entry.Name(), ".txt")
+	if !ok {
+		return common.Hash{}, false
+	}
+	var key common.Hash
+	if err := key.UnmarshalText([]byte(name)); err != nil {
+		return common.Hash{}, false
+	}
+	return key, true
+}
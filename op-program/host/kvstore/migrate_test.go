@@ -0,0 +1,62 @@
+package kvstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDiskKV(t *testing.T) {
+	srcDir := t.TempDir()
+	src := NewDiskKV(srcDir)
+	preimages := map[common.Hash][]byte{
+		{0xaa}: []byte("hello world"),
+		{0xbb}: {},
+		{}:     {4, 2},
+	}
+	for k, v := range preimages {
+		require.NoError(t, src.Put(k, v))
+	}
+	// Files that are not pre-images are skipped
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, common.Hash{0xcc}.String()+".txt.1234"), []byte("00"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "other.txt"), []byte("00"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(srcDir, common.Hash{0xdd}.String()+".txt"), 0o755))
+
+	dst, err := NewPebbleKV(filepath.Join(t.TempDir(), "pebble"))
+	require.NoError(t, err)
+	defer dst.Close()
+	count, err := MigrateDiskKV(testlog.Logger(t, log.LvlInfo), srcDir, dst)
+	require.NoError(t, err)
+	require.Equal(t, len(preimages), count)
+	for k, v := range preimages {
+		actual, err := dst.Get(k)
+		require.NoError(t, err)
+		require.Equal(t, v, actual)
+	}
+	_, err = dst.Get(common.Hash{0xcc})
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = dst.Get(common.Hash{0xdd})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Source is unchanged
+	for k, v := range preimages {
+		actual, err := src.Get(k)
+		require.NoError(t, err)
+		require.Equal(t, v, actual)
+	}
+}
+
+func TestMigrateDiskKVInvalidPreimage(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, common.Hash{0xaa}.String()+".txt"), []byte("zz"), 0o644))
+	dst, err := NewPebbleKV(filepath.Join(t.TempDir(), "pebble"))
+	require.NoError(t, err)
+	defer dst.Close()
+	_, err = MigrateDiskKV(testlog.Logger(t, log.LvlInfo), srcDir, dst)
+	require.ErrorContains(t, err, "failed to decode pre-image")
+}
@@ -0,0 +1,101 @@
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// pebbleCacheSize is the size of the PebbleDB block cache.
+const pebbleCacheSize = 32 * 1024 * 1024
+
+// PebbleKV is a disk-backed key-value store, with PebbleDB as the underlying database.
+// All pre-images are stored in a small number of files, which are compacted by PebbleDB in the background.
+// PebbleKV is safe for concurrent use, but the database directory can only be opened by a single PebbleKV at a time.
+type PebbleKV struct {
+	db *pebble.DB
+}
+
+// NewPebbleKV creates a PebbleKV that puts/gets pre-images in the database in the given directory path.
+// The directory is created if it does not exist.
+// The PebbleKV must be closed once it is no longer required to flush all writes to disk.
+func NewPebbleKV(path string) (*PebbleKV, error) {
+	cache := pebble.NewCache(pebbleCacheSize)
+	defer cache.Unref()
+	opts := &pebble.Options{
+		Cache:                    cache,
+		MaxConcurrentCompactions: runtime.NumCPU,
+		Levels: []pebble.LevelOptions{
+			{Compression: pebble.SnappyCompression},
+		},
+	}
+	db, err := pebble.Open(path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pebble db at %v: %w", path, err)
+	}
+	return &PebbleKV{db: db}, nil
+}
+
+func (d *PebbleKV) Put(k common.Hash, v []byte) error {
+	// Writes are still added to the write-ahead log, but not synced to disk until the database is closed.
+	// Pre-images can always be fetched again so there is no need to pay the cost of syncing each write.
+	return d.db.Set(k.Bytes(), v, pebble.NoSync)
+}
+
+// PutAll writes all the supplied pre-images in a single batch.
+// This is significantly faster than calling Put for each pre-image when writing large numbers of pre-images.
+func (d *PebbleKV) PutAll(preimages map[common.Hash][]byte) error {
+	b := d.db.NewBatch()
+	defer b.Close()
+	for k, v := range preimages {
+		if err := b.Set(k.Bytes(), v, nil); err != nil {
+			return fmt.Errorf("failed to add pre-image %s to batch: %w", k, err)
+		}
+	}
+	if err := b.Commit(pebble.NoSync); err != nil {
+		return fmt.Errorf("failed to write batch of %v pre-images: %w", len(preimages), err)
+	}
+	return nil
+}
+
+func (d *PebbleKV) Get(k common.Hash) ([]byte, error) {
+	dat, closer, err := d.db.Get(k.Bytes())
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read pre-image %s: %w", k, err)
+	}
+	// The returned slice is only valid until closer is closed.
+	ret := slices.Clone(dat)
+	if err := closer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to release pre-image %s: %w", k, err)
+	}
+	return ret, nil
+}
+
+// Compact compacts the entire key range of the database.
+// PebbleDB compacts automatically as data is written, but an explicit compaction after writing a large number of
+// pre-images, e.g. after a migration, reduces the size of the database and improves read performance.
+func (d *PebbleKV) Compact() error {
+	start := make([]byte, common.HashLength)
+	end := make([]byte, common.HashLength+1)
+	for i := range end {
+		end[i] = 0xff
+	}
+	return d.db.Compact(start, end, true)
+}
+
+// Close flushes all writes to disk and closes the database.
+func (d *PebbleKV) Close() error {
+	if err := d.db.Flush(); err != nil {
+		_ = d.db.Close()
+		return fmt.Errorf("failed to flush pebble db: %w", err)
+	}
+	return d.db.Close()
+}
+
+var _ KV = (*PebbleKV)(nil)
@@ -0,0 +1,41 @@
+package kvstore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPebbleKV(t *testing.T) {
+	tmp := t.TempDir() // automatically removed by testing cleanup
+	kv, err := NewPebbleKV(tmp)
+	require.NoError(t, err)
+	t.Cleanup(func() { // Can't use defer because kvTest runs tests in parallel.
+		require.NoError(t, kv.Close())
+	})
+	kvTest(t, kv)
+}
+
+func TestPebbleKVPersisted(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	kv, err := NewPebbleKV(dir)
+	require.NoError(t, err)
+	require.NoError(t, kv.Put(common.Hash{0xaa}, []byte{1, 2, 3}))
+	require.NoError(t, kv.PutAll(map[common.Hash][]byte{
+		{0xbb}: {4, 5},
+		{0xcc}: {},
+	}))
+	require.NoError(t, kv.Compact())
+	require.NoError(t, kv.Close())
+
+	kv, err = NewPebbleKV(dir)
+	require.NoError(t, err)
+	defer kv.Close()
+	for key, expected := range map[common.Hash][]byte{{0xaa}: {1, 2, 3}, {0xbb}: {4, 5}, {0xcc}: {}} {
+		actual, err := kv.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+}
@@ -2,8 +2,10 @@ package prefetcher
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	preimage "github.com/ethereum-optimism/optimism/op-preimage"
@@ -15,8 +17,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	ErrNoL1BlobSource = errors.New("no L1 blob source configured")
 )
 
 type L1Source interface {
@@ -25,6 +34,10 @@ type L1Source interface {
 	FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error)
 }
 
+type L1BlobSource interface {
+	GetBlobSidecars(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.BlobSidecar, error)
+}
+
 type L2Source interface {
 	InfoAndTxsByHash(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Transactions, error)
 	NodeByHash(ctx context.Context, hash common.Hash) ([]byte, error)
@@ -33,19 +46,27 @@ type L2Source interface {
 }
 
 type Prefetcher struct {
-	logger    log.Logger
-	l1Fetcher L1Source
-	l2Fetcher L2Source
-	lastHint  string
-	kvStore   kvstore.KV
+	logger        log.Logger
+	l1Fetcher     L1Source
+	l1BlobFetcher L1BlobSource
+	l2Fetcher     L2Source
+	lastHint      string
+	kvStore       kvstore.KV
 }
 
-func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l2Fetcher L2Source, kvStore kvstore.KV) *Prefetcher {
+// NewPrefetcher creates a Prefetcher that fetches pre-images from the supplied sources.
+// The l1BlobFetcher may be nil, in which case requests for blobs fail.
+func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l1BlobFetcher L1BlobSource, l2Fetcher L2Source, kvStore kvstore.KV) *Prefetcher {
+	var blobFetcher L1BlobSource
+	if l1BlobFetcher != nil {
+		blobFetcher = NewRetryingL1BlobSource(logger, l1BlobFetcher)
+	}
 	return &Prefetcher{
-		logger:    logger,
-		l1Fetcher: NewRetryingL1Source(logger, l1Fetcher),
-		l2Fetcher: NewRetryingL2Source(logger, l2Fetcher),
-		kvStore:   kvStore,
+		logger:        logger,
+		l1Fetcher:     NewRetryingL1Source(logger, l1Fetcher),
+		l1BlobFetcher: blobFetcher,
+		l2Fetcher:     NewRetryingL2Source(logger, l2Fetcher),
+		kvStore:       kvStore,
 	}
 }
 
@@ -75,11 +96,21 @@ func (p *Prefetcher) GetPreimage(ctx context.Context, key common.Hash) ([]byte,
 }
 
 func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
-	hintType, hash, err := parseHint(hint)
+	hintType, hintBytes, err := parseHint(hint)
 	if err != nil {
 		return err
 	}
-	p.logger.Debug("Prefetching", "type", hintType, "hash", hash)
+	p.logger.Debug("Prefetching", "type", hintType, "bytes", hexutil.Bytes(hintBytes))
+	switch hintType {
+	case l1.HintL1Blob:
+		return p.prefetchBlob(ctx, hintBytes)
+	case l2.HintL2Precompile:
+		return p.prefetchPrecompile(hintBytes)
+	}
+	if len(hintBytes) != common.HashLength || common.Hash(hintBytes) == (common.Hash{}) {
+		return fmt.Errorf("invalid hash: %x", hintBytes)
+	}
+	hash := common.Hash(hintBytes)
 	switch hintType {
 	case l1.HintL1BlockHeader:
 		header, err := p.l1Fetcher.InfoByHash(ctx, hash)
@@ -139,6 +170,89 @@ func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
 	return fmt.Errorf("unknown hint type: %v", hintType)
 }
 
+// prefetchBlob fetches the blob sidecar identified by the hint, and stores the blob's KZG commitment
+// by versioned hash and each of its field elements by the commitment and point they are for.
+func (p *Prefetcher) prefetchBlob(ctx context.Context, hintBytes []byte) error {
+	if p.l1BlobFetcher == nil {
+		return ErrNoL1BlobSource
+	}
+	if len(hintBytes) != 48 {
+		return fmt.Errorf("invalid blob hint: %x", hintBytes)
+	}
+	blobVersionHash := common.Hash(hintBytes[:32])
+	blobHashIndex := binary.BigEndian.Uint64(hintBytes[32:40])
+	refTimestamp := binary.BigEndian.Uint64(hintBytes[40:48])
+
+	// Only the timestamp is required to identify the beacon slot of the sidecar
+	indexedBlobHash := eth.IndexedBlobHash{Hash: blobVersionHash, Index: blobHashIndex}
+	sidecars, err := p.l1BlobFetcher.GetBlobSidecars(ctx, eth.L1BlockRef{Time: refTimestamp}, []eth.IndexedBlobHash{indexedBlobHash})
+	if err != nil {
+		return fmt.Errorf("failed to fetch blob sidecar for %s: %w", blobVersionHash, err)
+	}
+	if len(sidecars) != 1 {
+		return fmt.Errorf("expected 1 sidecar for blob %s but got %d", blobVersionHash, len(sidecars))
+	}
+	sidecar := sidecars[0]
+	commitment := kzg4844.Commitment(sidecar.KZGCommitment)
+	if eth.KZGToVersionedHash(commitment) != blobVersionHash {
+		return fmt.Errorf("sidecar commitment does not match blob versioned hash %s", blobVersionHash)
+	}
+	if err := eth.VerifyBlobProof(&sidecar.Blob, commitment, kzg4844.Proof(sidecar.KZGProof)); err != nil {
+		return fmt.Errorf("invalid blob %s: %w", blobVersionHash, err)
+	}
+
+	// Put the commitment into the KV store, keyed by the blob's versioned hash.
+	if err := p.kvStore.Put(preimage.Sha256Key(blobVersionHash).PreimageKey(), commitment[:]); err != nil {
+		return err
+	}
+
+	// Put each field element of the blob into the KV store, keyed by the hash of the commitment and point.
+	// The commitment and point are also stored, as the keccak256 pre-image of the key.
+	blobKey := make([]byte, 80)
+	copy(blobKey[:48], commitment[:])
+	for i := 0; i < params.BlobTxFieldElementsPerBlob; i++ {
+		rootOfUnity := l1.RootsOfUnity[i].Bytes()
+		copy(blobKey[48:], rootOfUnity[:])
+		blobKeyHash := crypto.Keccak256Hash(blobKey)
+		if err := p.kvStore.Put(preimage.Keccak256Key(blobKeyHash).PreimageKey(), blobKey); err != nil {
+			return err
+		}
+		if err := p.kvStore.Put(preimage.BlobKey(blobKeyHash).PreimageKey(), sidecar.Blob[i<<5:(i+1)<<5]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prefetchPrecompile executes the precompile identified by the hint natively and stores its result,
+// keyed by the hash of the precompile address and input.
+func (p *Prefetcher) prefetchPrecompile(hintBytes []byte) error {
+	if len(hintBytes) < common.AddressLength {
+		return fmt.Errorf("invalid precompile hint: %x", hintBytes)
+	}
+	address := common.BytesToAddress(hintBytes[:common.AddressLength])
+	input := hintBytes[common.AddressLength:]
+	if !slices.Contains(l2.AcceleratedPrecompiles, address) {
+		return fmt.Errorf("unsupported precompile address: %s", address)
+	}
+	precompile := vm.PrecompiledContractsCancun[address]
+	// Store the status byte followed by the output
+	result := []byte{1}
+	output, err := precompile.Run(input)
+	if err != nil {
+		result[0] = 0
+	} else {
+		result = append(result, output...)
+	}
+
+	inputHash := crypto.Keccak256Hash(hintBytes)
+	// Put the address and input into the KV store, so the inputs of the precompile call are available on-chain.
+	if err := p.kvStore.Put(preimage.Keccak256Key(inputHash).PreimageKey(), hintBytes); err != nil {
+		return err
+	}
+	return p.kvStore.Put(preimage.PrecompileKey(inputHash).PreimageKey(), result)
+}
+
 func (p *Prefetcher) storeReceipts(receipts types.Receipts) error {
 	opaqueReceipts, err := eth.EncodeReceipts(receipts)
 	if err != nil {
@@ -166,15 +280,15 @@ func (p *Prefetcher) storeTrieNodes(values []hexutil.Bytes) error {
 	return nil
 }
 
-// parseHint parses a hint string in wire protocol. Returns the hint type, requested hash and error (if any).
-func parseHint(hint string) (string, common.Hash, error) {
-	hintType, hashStr, found := strings.Cut(hint, " ")
+// parseHint parses a hint string in wire protocol. Returns the hint type, requested data and error (if any).
+func parseHint(hint string) (string, []byte, error) {
+	hintType, bytesStr, found := strings.Cut(hint, " ")
 	if !found {
-		return "", common.Hash{}, fmt.Errorf("unsupported hint: %s", hint)
+		return "", nil, fmt.Errorf("unsupported hint: %s", hint)
 	}
-	hash := common.HexToHash(hashStr)
-	if hash == (common.Hash{}) {
-		return "", common.Hash{}, fmt.Errorf("invalid hash: %s", hashStr)
+	hintBytes, err := hexutil.Decode(bytesStr)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid bytes: %s", bytesStr)
 	}
-	return hintType, hash, nil
+	return hintType, hintBytes, nil
 }
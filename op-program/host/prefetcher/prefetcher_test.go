@@ -2,13 +2,17 @@ package prefetcher
 
 import (
 	"context"
+	"encoding/binary"
 	"math/rand"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
 
@@ -18,6 +22,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/retry"
 	"github.com/ethereum-optimism/optimism/op-service/testlog"
 	"github.com/ethereum-optimism/optimism/op-service/testutils"
 )
@@ -153,6 +158,64 @@ func TestFetchL1Receipts(t *testing.T) {
 	})
 }
 
+func TestFetchL1Blob(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	var blob eth.Blob
+	require.NoError(t, blob.FromData(testutils.RandomData(rng, 1000)))
+	commitment, err := kzg4844.BlobToCommitment(*blob.KZGBlob())
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(*blob.KZGBlob(), commitment)
+	require.NoError(t, err)
+	versionedHash := eth.KZGToVersionedHash(commitment)
+	blobHash := eth.IndexedBlobHash{Hash: versionedHash, Index: 4}
+	ref := eth.L1BlockRef{Time: 1234}
+	sidecar := &eth.BlobSidecar{
+		Index:         eth.Uint64String(blobHash.Index),
+		Blob:          blob,
+		KZGCommitment: eth.Bytes48(commitment),
+		KZGProof:      eth.Bytes48(proof),
+	}
+
+	t.Run("AlreadyKnown", func(t *testing.T) {
+		prefetcher, blobSource, kv := createPrefetcherWithBlobSource(t)
+		storeBlob(t, kv, &blob, commitment)
+
+		oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result := oracle.GetBlob(ref, blobHash)
+		require.Equal(t, &blob, result)
+		blobSource.AssertExpectations(t)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		prefetcher, blobSource, _ := createPrefetcherWithBlobSource(t)
+		blobSource.ExpectGetBlobSidecars(eth.L1BlockRef{Time: ref.Time}, []eth.IndexedBlobHash{blobHash}, []*eth.BlobSidecar{sidecar}, nil)
+		defer blobSource.AssertExpectations(t)
+
+		oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result := oracle.GetBlob(ref, blobHash)
+		require.Equal(t, &blob, result)
+	})
+
+	t.Run("MismatchedCommitment", func(t *testing.T) {
+		prefetcher, blobSource, _ := createPrefetcherWithBlobSource(t)
+		invalidSidecar := *sidecar
+		invalidSidecar.KZGCommitment = eth.Bytes48{0xaa}
+		blobSource.ExpectGetBlobSidecars(eth.L1BlockRef{Time: ref.Time}, []eth.IndexedBlobHash{blobHash}, []*eth.BlobSidecar{&invalidSidecar}, nil)
+		defer blobSource.AssertExpectations(t)
+
+		require.NoError(t, prefetcher.Hint(blobHint(blobHash, ref).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), preimage.Sha256Key(versionedHash).PreimageKey())
+		require.ErrorContains(t, err, "does not match blob versioned hash")
+	})
+
+	t.Run("NoBlobSource", func(t *testing.T) {
+		prefetcher, _, _, _ := createPrefetcher(t)
+		require.NoError(t, prefetcher.Hint(blobHint(blobHash, ref).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), preimage.Sha256Key(versionedHash).PreimageKey())
+		require.ErrorIs(t, err, ErrNoL1BlobSource)
+	})
+}
+
 func TestFetchL2Block(t *testing.T) {
 	rng := rand.New(rand.NewSource(123))
 	block, rcpts := testutils.RandomBlock(rng, 10)
@@ -231,6 +294,58 @@ func TestFetchL2Node(t *testing.T) {
 	})
 }
 
+func TestFetchPrecompile(t *testing.T) {
+	// A valid ecrecover input: hash, v, r, s
+	ecrecoverInput := common.FromHex("0x456e9aea5e197a1f1af7a3e85a3212fa4049a3ba34c2289b4c860fc0b0c64ef3000000000000000000000000000000000000000000000000000000000000001c9242685bf161793cc25603c231bc2f568eb630ea16aa137d2664ac80388256084f8ae3bd7535248d0bd448298cc2e2071e56992d0774dc340c368ae950852ada")
+	ecrecoverAddr := common.BytesToAddress([]byte{0x1})
+	kzgPointEvalAddr := common.BytesToAddress([]byte{0xa})
+
+	t.Run("AlreadyKnown", func(t *testing.T) {
+		prefetcher, _, _, kv := createPrefetcher(t)
+		hintBytes := append(ecrecoverAddr.Bytes(), ecrecoverInput...)
+		require.NoError(t, kv.Put(preimage.PrecompileKey(crypto.Keccak256Hash(hintBytes)).PreimageKey(), []byte{1, 0xaa}))
+
+		oracle := l2.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result, ok := oracle.Precompile(ecrecoverAddr, ecrecoverInput)
+		require.True(t, ok)
+		require.Equal(t, []byte{0xaa}, result)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		prefetcher, _, _, kv := createPrefetcher(t)
+		expected, err := vm.PrecompiledContractsCancun[ecrecoverAddr].Run(ecrecoverInput)
+		require.NoError(t, err)
+
+		oracle := l2.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result, ok := oracle.Precompile(ecrecoverAddr, ecrecoverInput)
+		require.True(t, ok)
+		require.Equal(t, expected, result)
+
+		// The address and input must also be available
+		hintBytes := append(ecrecoverAddr.Bytes(), ecrecoverInput...)
+		input, err := kv.Get(preimage.Keccak256Key(crypto.Keccak256Hash(hintBytes)).PreimageKey())
+		require.NoError(t, err)
+		require.Equal(t, hintBytes, input)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		prefetcher, _, _, _ := createPrefetcher(t)
+		oracle := l2.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		result, ok := oracle.Precompile(kzgPointEvalAddr, []byte{1, 2, 3})
+		require.False(t, ok)
+		require.Empty(t, result)
+	})
+
+	t.Run("UnsupportedPrecompile", func(t *testing.T) {
+		prefetcher, _, _, _ := createPrefetcher(t)
+		identityAddr := common.BytesToAddress([]byte{0x4})
+		hintBytes := append(identityAddr.Bytes(), 1, 2, 3)
+		require.NoError(t, prefetcher.Hint(l2.PrecompileHint(hintBytes).Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), preimage.PrecompileKey(crypto.Keccak256Hash(hintBytes)).PreimageKey())
+		require.ErrorContains(t, err, "unsupported precompile address")
+	})
+}
+
 func TestFetchL2Code(t *testing.T) {
 	rng := rand.New(rand.NewSource(123))
 	code := testutils.RandomData(rng, 30)
@@ -275,6 +390,16 @@ func TestBadHints(t *testing.T) {
 		// Accept the hint
 		require.NoError(t, prefetcher.Hint(l1.HintL1BlockHeader+" asdfsadf"))
 
+		// But it will fail to prefetch when the pre-image isn't available
+		pre, err := prefetcher.GetPreimage(context.Background(), hash)
+		require.ErrorContains(t, err, "invalid bytes")
+		require.Nil(t, pre)
+	})
+
+	t.Run("WrongLengthHash", func(t *testing.T) {
+		// Accept the hint
+		require.NoError(t, prefetcher.Hint(l1.HintL1BlockHeader+" 0x1234"))
+
 		// But it will fail to prefetch when the pre-image isn't available
 		pre, err := prefetcher.GetPreimage(context.Background(), hash)
 		require.ErrorContains(t, err, "invalid hash")
@@ -314,7 +439,7 @@ func TestRetryWhenNotAvailableAfterPrefetching(t *testing.T) {
 	_, l1Source, l2Cl, kv := createPrefetcher(t)
 	putsToIgnore := 2
 	kv = &unreliableKvStore{KV: kv, putsToIgnore: putsToIgnore}
-	prefetcher := NewPrefetcher(testlog.Logger(t, log.LvlInfo), l1Source, l2Cl, kv)
+	prefetcher := NewPrefetcher(testlog.Logger(t, log.LvlInfo), l1Source, nil, l2Cl, kv)
 
 	// Expect one call for each ignored put, plus one more request for when the put succeeds
 	for i := 0; i < putsToIgnore+1; i++ {
@@ -365,10 +490,44 @@ func createPrefetcher(t *testing.T) (*Prefetcher, *testutils.MockL1Source, *l2Cl
 		MockDebugClient: new(testutils.MockDebugClient),
 	}
 
-	prefetcher := NewPrefetcher(logger, l1Source, l2Source, kv)
+	prefetcher := NewPrefetcher(logger, l1Source, nil, l2Source, kv)
 	return prefetcher, l1Source, l2Source, kv
 }
 
+func blobHint(blobHash eth.IndexedBlobHash, ref eth.L1BlockRef) l1.BlobHint {
+	hint := make([]byte, 48)
+	copy(hint[:32], blobHash.Hash[:])
+	binary.BigEndian.PutUint64(hint[32:40], blobHash.Index)
+	binary.BigEndian.PutUint64(hint[40:48], ref.Time)
+	return hint
+}
+
+func createPrefetcherWithBlobSource(t *testing.T) (*Prefetcher, *MockBlobSource, kvstore.KV) {
+	logger := testlog.Logger(t, log.LvlDebug)
+	kv := kvstore.NewMemKV()
+	blobSource := new(MockBlobSource)
+	prefetcher := NewPrefetcher(logger, new(testutils.MockL1Source), blobSource, new(l2Client), kv)
+	prefetcher.l1BlobFetcher.(*RetryingL1BlobSource).strategy = retry.Fixed(0)
+	return prefetcher, blobSource, kv
+}
+
+func storeBlob(t *testing.T, kv kvstore.KV, blob *eth.Blob, commitment kzg4844.Commitment) {
+	// Pre-store the commitment
+	versionedHash := eth.KZGToVersionedHash(commitment)
+	require.NoError(t, kv.Put(preimage.Sha256Key(versionedHash).PreimageKey(), commitment[:]))
+
+	// Pre-store the field elements
+	blobKey := make([]byte, 80)
+	copy(blobKey[:48], commitment[:])
+	for i := 0; i < params.BlobTxFieldElementsPerBlob; i++ {
+		rootOfUnity := l1.RootsOfUnity[i].Bytes()
+		copy(blobKey[48:], rootOfUnity[:])
+		blobKeyHash := crypto.Keccak256Hash(blobKey)
+		require.NoError(t, kv.Put(preimage.Keccak256Key(blobKeyHash).PreimageKey(), blobKey))
+		require.NoError(t, kv.Put(preimage.BlobKey(blobKeyHash).PreimageKey(), blob[i<<5:(i+1)<<5]))
+	}
+}
+
 func storeBlock(t *testing.T, kv kvstore.KV, block *types.Block, receipts types.Receipts) {
 	// Pre-store receipts
 	opaqueRcpts, err := eth.EncodeReceipts(receipts)
@@ -111,6 +111,59 @@ func createL1Source(t *testing.T) (*RetryingL1Source, *testutils.MockL1Source) {
 	return source, mock
 }
 
+func TestRetryingL1BlobSource(t *testing.T) {
+	ctx := context.Background()
+	ref := eth.L1BlockRef{Time: 1234}
+	hashes := []eth.IndexedBlobHash{{Hash: common.Hash{0xab}, Index: 2}}
+	sidecars := []*eth.BlobSidecar{{Index: 2}}
+
+	t.Run("GetBlobSidecars Success", func(t *testing.T) {
+		source, mock := createL1BlobSource(t)
+		defer mock.AssertExpectations(t)
+		mock.ExpectGetBlobSidecars(ref, hashes, sidecars, nil)
+
+		result, err := source.GetBlobSidecars(ctx, ref, hashes)
+		require.NoError(t, err)
+		require.Equal(t, sidecars, result)
+	})
+
+	t.Run("GetBlobSidecars Error", func(t *testing.T) {
+		source, mock := createL1BlobSource(t)
+		defer mock.AssertExpectations(t)
+		expectedErr := errors.New("boom")
+		mock.ExpectGetBlobSidecars(ref, hashes, nil, expectedErr)
+		mock.ExpectGetBlobSidecars(ref, hashes, sidecars, nil)
+
+		result, err := source.GetBlobSidecars(ctx, ref, hashes)
+		require.NoError(t, err)
+		require.Equal(t, sidecars, result)
+	})
+}
+
+func createL1BlobSource(t *testing.T) (*RetryingL1BlobSource, *MockBlobSource) {
+	logger := testlog.Logger(t, log.LvlDebug)
+	mock := &MockBlobSource{}
+	source := NewRetryingL1BlobSource(logger, mock)
+	// Avoid sleeping in tests by using a fixed retry strategy with no delay
+	source.strategy = retry.Fixed(0)
+	return source, mock
+}
+
+type MockBlobSource struct {
+	mock.Mock
+}
+
+func (m *MockBlobSource) GetBlobSidecars(ctx context.Context, ref eth.L1BlockRef, hashes []eth.IndexedBlobHash) ([]*eth.BlobSidecar, error) {
+	out := m.Mock.MethodCalled("GetBlobSidecars", ref, hashes)
+	return out[0].([]*eth.BlobSidecar), *out[1].(*error)
+}
+
+func (m *MockBlobSource) ExpectGetBlobSidecars(ref eth.L1BlockRef, hashes []eth.IndexedBlobHash, sidecars []*eth.BlobSidecar, err error) {
+	m.Mock.On("GetBlobSidecars", ref, hashes).Once().Return(sidecars, &err)
+}
+
+var _ L1BlobSource = (*MockBlobSource)(nil)
+
 func TestRetryingL2Source(t *testing.T) {
 	ctx := context.Background()
 	hash := common.Hash{0xab}
@@ -0,0 +1,81 @@
+package host
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	preimage "github.com/ethereum-optimism/optimism/op-preimage"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1"
+	"github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// RunStats reports the global pre-images served to the client program during a run.
+type RunStats struct {
+	// PreimageRequests is the total number of pre-image requests, including repeated requests for the same key.
+	PreimageRequests int
+	// UniquePreimages is the number of distinct pre-images served.
+	UniquePreimages int
+	// L1Blocks is the number of distinct L1 block headers hinted by the client, the L1 blocks traversed by derivation.
+	L1Blocks int
+}
+
+// FaultProofProgramWithStats runs the fault proof program in the same way as FaultProofProgram, and additionally
+// reports the pre-images served to, and the L1 blocks hinted by the client.
+// The stats are returned even if the program fails.
+func FaultProofProgramWithStats(ctx context.Context, logger log.Logger, cfg *config.Config) (RunStats, error) {
+	counter := newPreimageCounter()
+	err := faultProofProgram(ctx, logger, cfg, counter)
+	return counter.Stats(), err
+}
+
+// preimageCounter counts the global pre-images successfully served to the client program,
+// and the L1 block headers hinted by the client program.
+type preimageCounter struct {
+	mu       sync.Mutex
+	requests int
+	keys     map[common.Hash]struct{}
+	l1Blocks map[string]struct{}
+}
+
+func newPreimageCounter() *preimageCounter {
+	return &preimageCounter{keys: make(map[common.Hash]struct{}), l1Blocks: make(map[string]struct{})}
+}
+
+func (c *preimageCounter) Wrap(source kvstore.PreimageSource) kvstore.PreimageSource {
+	return func(key common.Hash) ([]byte, error) {
+		value, err := source(key)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.requests++
+		c.keys[key] = struct{}{}
+		return value, nil
+	}
+}
+
+func (c *preimageCounter) WrapHinter(hinter preimage.HintHandler) preimage.HintHandler {
+	return func(hint string) error {
+		if hintType, hash, ok := strings.Cut(hint, " "); ok && hintType == l1.HintL1BlockHeader {
+			c.mu.Lock()
+			c.l1Blocks[hash] = struct{}{}
+			c.mu.Unlock()
+		}
+		return hinter(hint)
+	}
+}
+
+func (c *preimageCounter) Stats() RunStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return RunStats{
+		PreimageRequests: c.requests,
+		UniquePreimages:  len(c.keys),
+		L1Blocks:         len(c.l1Blocks),
+	}
+}
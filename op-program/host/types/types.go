@@ -0,0 +1,41 @@
+package types
+
+import "fmt"
+
+// DataFormat is the format used to store pre-images in the data directory.
+type DataFormat string
+
+const (
+	// DataFormatDirectory stores each pre-image as a separate hex-encoded file in the data directory.
+	DataFormatDirectory DataFormat = "directory"
+	// DataFormatPebble stores pre-images in a PebbleDB database in the data directory.
+	DataFormatPebble DataFormat = "pebble"
+)
+
+var SupportedDataFormats = []DataFormat{DataFormatDirectory, DataFormatPebble}
+
+func (d DataFormat) String() string {
+	return string(d)
+}
+
+func (d *DataFormat) Set(value string) error {
+	if !ValidDataFormat(DataFormat(value)) {
+		return fmt.Errorf("unknown data format: %q", value)
+	}
+	*d = DataFormat(value)
+	return nil
+}
+
+func (d *DataFormat) Clone() any {
+	cpy := *d
+	return &cpy
+}
+
+func ValidDataFormat(value DataFormat) bool {
+	for _, format := range SupportedDataFormats {
+		if format == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-program/client/driver"
+	"github.com/ethereum-optimism/optimism/op-program/host"
+	"github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/sources"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	ErrInvalidRange       = errors.New("start block must be before end block")
+	ErrFetchingRequired   = errors.New("l1 and l2 must be specified to verify a range")
+	ErrSharedPebbleDB     = errors.New("pebble data format cannot be used with multiple workers")
+	ErrVerificationFailed = errors.New("verification failed")
+)
+
+type Status string
+
+const (
+	// StatusValid indicates the program successfully verified the claim.
+	StatusValid Status = "valid"
+	// StatusInvalid indicates the program determined the claim, taken from the L2 node, is invalid.
+	StatusInvalid Status = "invalid"
+	// StatusError indicates the program could not be run or failed without determining the claim validity.
+	StatusError Status = "error"
+)
+
+// BlockResult is the result of running the program to verify the output root of a single block.
+// The work to verify the block is reported as the number of L1 blocks traversed to derive it,
+// and the gas used to execute it.
+type BlockResult struct {
+	BlockNumber      uint64      `json:"blockNumber"`
+	Claim            common.Hash `json:"claim"`
+	Status           Status      `json:"status"`
+	Error            string      `json:"error,omitempty"`
+	DurationMs       int64       `json:"durationMs"`
+	PreimageRequests int         `json:"preimageRequests"`
+	UniquePreimages  int         `json:"uniquePreimages"`
+	L1Blocks         int         `json:"l1Blocks"`
+	GasUsed          uint64      `json:"gasUsed"`
+}
+
+// Report is the result of verifying the output roots of a range of blocks.
+type Report struct {
+	L1Head     common.Hash   `json:"l1Head"`
+	Start      uint64        `json:"start"`
+	End        uint64        `json:"end"`
+	Passed     int           `json:"passed"`
+	Failed     int           `json:"failed"`
+	DurationMs int64         `json:"durationMs"`
+	GasUsed    uint64        `json:"gasUsed"`
+	Results    []BlockResult `json:"results"`
+}
+
+// L2Source provides the L2 blocks and output roots the program is run with.
+type L2Source interface {
+	L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+	InfoByNumber(ctx context.Context, num uint64) (eth.BlockInfo, error)
+	OutputV0AtBlock(ctx context.Context, blockHash common.Hash) (*eth.OutputV0, error)
+}
+
+// ProgramRunner runs the program natively with the supplied config.
+type ProgramRunner func(ctx context.Context, logger log.Logger, cfg *config.Config) (host.RunStats, error)
+
+// Verifier runs the program for each block in a range, using the output root reported by an L2 node as the claim.
+type Verifier struct {
+	logger  log.Logger
+	cfg     *config.Config
+	l2      L2Source
+	run     ProgramRunner
+	workers int
+}
+
+func NewVerifier(logger log.Logger, cfg *config.Config, l2 L2Source, run ProgramRunner, workers int) *Verifier {
+	return &Verifier{
+		logger:  logger,
+		cfg:     cfg,
+		l2:      l2,
+		run:     run,
+		workers: workers,
+	}
+}
+
+// Run verifies the output root of each block after start up to and including end, using the chain configuration and
+// pre-image sources from cfg. Each block is verified by a separate run of the program, starting from the output root
+// of its parent block. If cfg.L1Head is not set, the current finalized L1 block is used as the L1 head for all runs.
+func Run(ctx context.Context, logger log.Logger, cfg *config.Config, start uint64, end uint64, workers int) (*Report, error) {
+	if start >= end {
+		return nil, ErrInvalidRange
+	}
+	if !cfg.FetchingEnabled() {
+		return nil, ErrFetchingRequired
+	}
+	if cfg.DataDir != "" && cfg.DataFormat == types.DataFormatPebble && workers > 1 {
+		return nil, ErrSharedPebbleDB
+	}
+	l2RPC, err := client.NewRPC(ctx, logger, cfg.L2URL, client.WithDialBackoff(10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup L2 RPC: %w", err)
+	}
+	defer l2RPC.Close()
+	l2Client, err := sources.NewL2Client(l2RPC, logger, nil, sources.L2ClientDefaultConfig(cfg.Rollup, true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create L2 client: %w", err)
+	}
+
+	if cfg.L1Head == (common.Hash{}) {
+		l1RPC, err := client.NewRPC(ctx, logger, cfg.L1URL, client.WithDialBackoff(10))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup L1 RPC: %w", err)
+		}
+		defer l1RPC.Close()
+		l1Client, err := sources.NewL1Client(l1RPC, logger, nil, sources.L1ClientDefaultConfig(cfg.Rollup, cfg.L1TrustRPC, cfg.L1RPCKind))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create L1 client: %w", err)
+		}
+		l1Head, err := l1Client.L1BlockRefByLabel(ctx, eth.Finalized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find finalized L1 head: %w", err)
+		}
+		logger.Info("Using finalized L1 head", "l1Head", l1Head)
+		cpy := *cfg
+		cpy.L1Head = l1Head.Hash
+		cfg = &cpy
+	}
+
+	return NewVerifier(logger, cfg, l2Client, host.FaultProofProgramWithStats, workers).Verify(ctx, start, end)
+}
+
+// Verify runs the program for each block after start up to and including end, using up to the configured number of
+// workers in parallel, and reports the results in block order.
+func (v *Verifier) Verify(ctx context.Context, start uint64, end uint64) (*Report, error) {
+	if start >= end {
+		return nil, ErrInvalidRange
+	}
+	startTime := time.Now()
+	results := make([]BlockResult, end-start)
+	blocks := make(chan uint64)
+	var wg sync.WaitGroup
+	for i := 0; i < max(v.workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for blockNum := range blocks {
+				results[blockNum-start-1] = v.verifyBlock(ctx, blockNum)
+			}
+		}()
+	}
+	for blockNum := start + 1; blockNum <= end; blockNum++ {
+		blocks <- blockNum
+	}
+	close(blocks)
+	wg.Wait()
+
+	report := &Report{
+		L1Head:     v.cfg.L1Head,
+		Start:      start,
+		End:        end,
+		DurationMs: time.Since(startTime).Milliseconds(),
+		Results:    results,
+	}
+	for _, result := range results {
+		report.GasUsed += result.GasUsed
+		if result.Status == StatusValid {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+func (v *Verifier) verifyBlock(ctx context.Context, blockNum uint64) BlockResult {
+	logger := v.logger.New("block", blockNum)
+	result := BlockResult{BlockNumber: blockNum}
+	if err := ctx.Err(); err != nil {
+		result.Status = StatusError
+		result.Error = err.Error()
+		return result
+	}
+	cfg, claimed, err := v.blockConfig(ctx, blockNum)
+	if err != nil {
+		logger.Error("Failed to load program inputs", "err", err)
+		result.Status = StatusError
+		result.Error = err.Error()
+		return result
+	}
+	result.Claim = cfg.L2Claim
+	result.GasUsed = claimed.GasUsed()
+
+	logger.Info("Verifying block", "claim", cfg.L2Claim)
+	runStart := time.Now()
+	stats, err := v.run(ctx, logger, cfg)
+	result.DurationMs = time.Since(runStart).Milliseconds()
+	result.PreimageRequests = stats.PreimageRequests
+	result.UniquePreimages = stats.UniquePreimages
+	result.L1Blocks = stats.L1Blocks
+	if errors.Is(err, driver.ErrClaimNotValid) {
+		logger.Error("Claim is invalid", "err", err)
+		result.Status = StatusInvalid
+		result.Error = err.Error()
+	} else if err != nil {
+		logger.Error("Program failed", "err", err)
+		result.Status = StatusError
+		result.Error = err.Error()
+	} else {
+		logger.Info("Claim successfully verified", "duration", time.Since(runStart))
+		result.Status = StatusValid
+	}
+	return result
+}
+
+// blockConfig creates the config to verify the output root of the block at blockNum, starting from its parent,
+// and returns the info of the block.
+func (v *Verifier) blockConfig(ctx context.Context, blockNum uint64) (*config.Config, eth.BlockInfo, error) {
+	agreed, err := v.l2.L2BlockRefByNumber(ctx, blockNum-1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch L2 block %v: %w", blockNum-1, err)
+	}
+	agreedOutput, err := v.l2.OutputV0AtBlock(ctx, agreed.Hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch output at block %v: %w", blockNum-1, err)
+	}
+	claimed, err := v.l2.InfoByNumber(ctx, blockNum)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch L2 block %v: %w", blockNum, err)
+	}
+	claimedOutput, err := v.l2.OutputV0AtBlock(ctx, claimed.Hash())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch output at block %v: %w", blockNum, err)
+	}
+	if claimed.ParentHash() != agreed.Hash {
+		return nil, nil, fmt.Errorf("block %v is not a child of %v, L2 chain may have reorged", eth.ToBlockID(claimed), agreed)
+	}
+	cfg := *v.cfg
+	cfg.L2Head = agreed.Hash
+	cfg.L2OutputRoot = common.Hash(eth.OutputRoot(agreedOutput))
+	cfg.L2Claim = common.Hash(eth.OutputRoot(claimedOutput))
+	cfg.L2ClaimBlockNumber = blockNum
+	return &cfg, claimed, nil
+}
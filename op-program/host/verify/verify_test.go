@@ -0,0 +1,140 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
+	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
+	"github.com/ethereum-optimism/optimism/op-program/client/driver"
+	"github.com/ethereum-optimism/optimism/op-program/host"
+	"github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	t.Run("InvalidRange", func(t *testing.T) {
+		verifier, _, _ := setupVerifier(t, 1)
+		_, err := verifier.Verify(context.Background(), 10, 10)
+		require.ErrorIs(t, err, ErrInvalidRange)
+	})
+
+	for _, workers := range []int{1, 4} {
+		workers := workers
+		t.Run(fmt.Sprintf("AllValid-%v", workers), func(t *testing.T) {
+			verifier, l2, runner := setupVerifier(t, workers)
+			report, err := verifier.Verify(context.Background(), 100, 110)
+			require.NoError(t, err)
+			require.Equal(t, 10, report.Passed)
+			require.Zero(t, report.Failed)
+			require.Len(t, report.Results, 10)
+			var gasUsed uint64
+			for i, result := range report.Results {
+				blockNum := uint64(101 + i)
+				require.Equal(t, blockNum, result.BlockNumber)
+				require.Equal(t, StatusValid, result.Status)
+				require.Equal(t, l2.outputRoot(blockNum), result.Claim)
+				require.Equal(t, int(blockNum), result.PreimageRequests)
+				require.Equal(t, 3, result.L1Blocks)
+				require.Equal(t, l2.gasUsed(blockNum), result.GasUsed)
+				gasUsed += result.GasUsed
+
+				cfg := runner.configs[blockNum]
+				require.Equal(t, l2.blockHash(blockNum-1), cfg.L2Head)
+				require.Equal(t, l2.outputRoot(blockNum-1), cfg.L2OutputRoot)
+				require.Equal(t, l2.outputRoot(blockNum), cfg.L2Claim)
+				require.Equal(t, blockNum, cfg.L2ClaimBlockNumber)
+				require.Equal(t, verifier.cfg.L1Head, cfg.L1Head)
+			}
+			require.Equal(t, gasUsed, report.GasUsed)
+		})
+	}
+
+	t.Run("ReportsFailures", func(t *testing.T) {
+		verifier, l2, runner := setupVerifier(t, 2)
+		runner.errs[102] = &driver.InvalidClaimError{BlockNumber: 102}
+		runner.errs[104] = errors.New("boom")
+		l2.errs[106] = errors.New("no output")
+		report, err := verifier.Verify(context.Background(), 100, 106)
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Passed)
+		require.Equal(t, 3, report.Failed)
+		require.Equal(t, StatusInvalid, report.Results[1].Status)
+		require.Equal(t, runner.errs[102].Error(), report.Results[1].Error)
+		require.Equal(t, StatusError, report.Results[3].Status)
+		require.Equal(t, "boom", report.Results[3].Error)
+		require.Equal(t, StatusError, report.Results[5].Status)
+		require.Contains(t, report.Results[5].Error, "no output")
+		require.NotContains(t, runner.configs, uint64(106), "should not run program without inputs")
+	})
+}
+
+func setupVerifier(t *testing.T, workers int) (*Verifier, *stubL2Source, *stubRunner) {
+	cfg := config.NewConfig(chaincfg.Goerli, chainconfig.OPGoerliChainConfig, common.Hash{0x11}, common.Hash{}, common.Hash{}, common.Hash{}, 0)
+	l2 := &stubL2Source{errs: make(map[uint64]error)}
+	runner := &stubRunner{configs: make(map[uint64]*config.Config), errs: make(map[uint64]error)}
+	return NewVerifier(testlog.Logger(t, log.LvlInfo), cfg, l2, runner.Run, workers), l2, runner
+}
+
+type stubL2Source struct {
+	errs map[uint64]error
+}
+
+func (s *stubL2Source) blockHash(num uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(num))
+}
+
+func (s *stubL2Source) outputRoot(num uint64) common.Hash {
+	return common.Hash(eth.OutputRoot(s.output(num)))
+}
+
+func (s *stubL2Source) output(num uint64) *eth.OutputV0 {
+	return &eth.OutputV0{BlockHash: s.blockHash(num), StateRoot: eth.Bytes32{byte(num)}}
+}
+
+func (s *stubL2Source) L2BlockRefByNumber(_ context.Context, num uint64) (eth.L2BlockRef, error) {
+	return eth.L2BlockRef{Hash: s.blockHash(num), Number: num, ParentHash: s.blockHash(num - 1)}, nil
+}
+
+func (s *stubL2Source) InfoByNumber(_ context.Context, num uint64) (eth.BlockInfo, error) {
+	return &testutils.MockBlockInfo{
+		InfoHash:       s.blockHash(num),
+		InfoParentHash: s.blockHash(num - 1),
+		InfoNum:        num,
+		InfoGasUsed:    s.gasUsed(num),
+	}, nil
+}
+
+func (s *stubL2Source) gasUsed(num uint64) uint64 {
+	return num * 1000
+}
+
+func (s *stubL2Source) OutputV0AtBlock(_ context.Context, blockHash common.Hash) (*eth.OutputV0, error) {
+	num := blockHash.Big().Uint64()
+	if err := s.errs[num]; err != nil {
+		return nil, err
+	}
+	return s.output(num), nil
+}
+
+type stubRunner struct {
+	mu      sync.Mutex
+	configs map[uint64]*config.Config
+	errs    map[uint64]error
+}
+
+func (r *stubRunner) Run(_ context.Context, _ log.Logger, cfg *config.Config) (host.RunStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[cfg.L2ClaimBlockNumber] = cfg
+	return host.RunStats{PreimageRequests: int(cfg.L2ClaimBlockNumber), L1Blocks: 3}, r.errs[cfg.L2ClaimBlockNumber]
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/chainconfig"
 	"github.com/ethereum-optimism/optimism/op-program/host"
 	config "github.com/ethereum-optimism/optimism/op-program/host/config"
+	"github.com/ethereum-optimism/optimism/op-program/host/types"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	"github.com/ethereum-optimism/optimism/op-service/sources"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -148,6 +149,7 @@ func Run(l1RpcUrl string, l1RpcKind string, l2RpcUrl string, l2OracleAddr common
 	offlineCfg := config.Config{
 		Rollup:             rollupCfg,
 		DataDir:            dataDir,
+		DataFormat:         types.DataFormatDirectory,
 		L2ChainConfig:      chainconfig.OPGoerliChainConfig,
 		L2Head:             l2Head,
 		L2OutputRoot:       agreedOutput.OutputRoot,
@@ -34,4 +34,12 @@ type SyncStatus struct {
 	FinalizedL2 L2BlockRef `json:"finalized_l2"`
 	// PendingSafeL2 points to the L2 block processed from the batch, but not consolidated to the safe block yet.
 	PendingSafeL2 L2BlockRef `json:"pending_safe_l2"`
+	// CrossUnsafeL2 points to the latest unsafe L2 block of which all executing messages,
+	// and those of its ancestors, refer to initiating messages in the unsafe chains of the dependency set.
+	// This matches UnsafeL2 if the Interop fork is not scheduled.
+	CrossUnsafeL2 L2BlockRef `json:"cross_unsafe_l2"`
+	// CrossSafeL2 points to the latest safe L2 block of which all executing messages,
+	// and those of its ancestors, refer to initiating messages in the safe chains of the dependency set.
+	// This matches SafeL2 if the Interop fork is not scheduled.
+	CrossSafeL2 L2BlockRef `json:"cross_safe_l2"`
 }
@@ -0,0 +1,38 @@
+package sources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+)
+
+var ErrNoBuilderPayload = errors.New("builder returned no payload")
+
+// BuilderClient requests execution payloads from an external block builder.
+type BuilderClient struct {
+	rpc client.RPC
+}
+
+func NewBuilderClient(rpc client.RPC) *BuilderClient {
+	return &BuilderClient{rpc}
+}
+
+// GetPayload requests a payload built on top of the given parent with the given attributes.
+func (b *BuilderClient) GetPayload(ctx context.Context, parent common.Hash, attrs *eth.PayloadAttributes) (*eth.ExecutionPayload, error) {
+	var payload *eth.ExecutionPayload
+	if err := b.rpc.CallContext(ctx, &payload, "builder_getPayload", parent, attrs); err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, ErrNoBuilderPayload
+	}
+	return payload, nil
+}
+
+func (b *BuilderClient) Close() {
+	b.rpc.Close()
+}
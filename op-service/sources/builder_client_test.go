@@ -0,0 +1,53 @@
+package sources
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+)
+
+type mockBuilderAPI struct {
+	payloads map[common.Hash]*eth.ExecutionPayload
+	attrs    *eth.PayloadAttributes
+}
+
+func (m *mockBuilderAPI) GetPayload(parent common.Hash, attrs *eth.PayloadAttributes) (*eth.ExecutionPayload, error) {
+	m.attrs = attrs
+	return m.payloads[parent], nil
+}
+
+func TestBuilderClient(t *testing.T) {
+	parent := common.Hash{0xaa}
+	payload := &eth.ExecutionPayload{ParentHash: parent, BlockNumber: 11, BlockHash: common.Hash{0xbb}, Transactions: []eth.Data{{0x7e, 0x01}}}
+	api := &mockBuilderAPI{payloads: map[common.Hash]*eth.ExecutionPayload{parent: payload}}
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("builder", api))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	ctx := context.Background()
+	rpcClient, err := client.NewRPC(ctx, testlog.Logger(t, log.LvlError), httpServer.URL)
+	require.NoError(t, err)
+	builder := NewBuilderClient(rpcClient)
+	t.Cleanup(builder.Close)
+
+	attrs := &eth.PayloadAttributes{Timestamp: 1002, Transactions: []eth.Data{{0x7e, 0x01}}, NoTxPool: true}
+	result, err := builder.GetPayload(ctx, parent, attrs)
+	require.NoError(t, err)
+	require.Equal(t, payload.BlockHash, result.BlockHash)
+	require.Equal(t, payload.Transactions, result.Transactions)
+	require.Equal(t, attrs, api.attrs)
+
+	_, err = builder.GetPayload(ctx, common.Hash{0xcc}, attrs)
+	require.ErrorIs(t, err, ErrNoBuilderPayload)
+}
@@ -86,6 +86,15 @@ func ParseAddress(address string) (common.Address, error) {
 	return common.Address{}, fmt.Errorf("invalid address: %v", address)
 }
 
+// ParseHash parses a 0x prefixed, hex encoded 32 byte hash.
+func ParseHash(hash string) (common.Hash, error) {
+	var h common.Hash
+	if err := h.UnmarshalText([]byte(hash)); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid hash: %v", hash)
+	}
+	return h, nil
+}
+
 // CloseAction runs the function in the background, until it finishes or until it is closed by the user with an interrupt.
 func CloseAction(fn func(ctx context.Context, shutdown <-chan struct{}) error) error {
 	stopped := make(chan error, 1)
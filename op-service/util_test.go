@@ -3,6 +3,7 @@ package op_service
 import (
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
 )
@@ -30,3 +31,16 @@ func TestValidateEnvVars(t *testing.T) {
 	invalids := validateEnvVars("OP_BATCHER", provided, defined)
 	require.ElementsMatch(t, invalids, []string{"OP_BATCHER_FAKE=false"})
 }
+
+func TestParseHash(t *testing.T) {
+	expected := common.HexToHash("0x1234567890123456789012345678901234567890123456789012345678901234")
+	actual, err := ParseHash(expected.Hex())
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	_, err = ParseHash("0x1234")
+	require.ErrorContains(t, err, "invalid hash")
+
+	_, err = ParseHash("foo")
+	require.ErrorContains(t, err, "invalid hash")
+}